@@ -72,17 +72,12 @@ func (e *Engine) Query(
 		return nil, nil, err
 	}
 
-	var perm = auth.ReadPerm
-	var typ = sql.QueryProcess
-	switch parsed.(type) {
-	case *plan.CreateIndex:
-		typ = sql.CreateIndexProcess
-		perm = auth.ReadPerm | auth.WritePerm
-	case *plan.InsertInto, *plan.DropIndex, *plan.UnlockTables, *plan.LockTables:
-		perm = auth.ReadPerm | auth.WritePerm
+	typ, err := e.checkPermissions(ctx, parsed)
+	if err != nil {
+		return nil, nil, err
 	}
 
-	err = e.Auth.Allowed(ctx, perm)
+	err = e.beginImplicitTransaction(ctx, parsed)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -111,6 +106,139 @@ func (e *Engine) Query(
 	return analyzed.Schema(), iter, nil
 }
 
+// Prepare parses and analyzes the given query, which may contain `?`
+// placeholders, so it can be executed later as many times as needed with
+// QueryPrepared. The returned node must be released with ClosePrepared
+// once it's not going to be executed anymore.
+func (e *Engine) Prepare(ctx *sql.Context, query string) (sql.Node, error) {
+	span, ctx := ctx.Span("prepare", opentracing.Tag{Key: "query", Value: query})
+	defer span.Finish()
+
+	logrus.WithField("query", query).Debug("preparing query")
+
+	parsed, err := parse.Parse(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+
+	typ, err := e.checkPermissions(ctx, parsed)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, err = e.Catalog.AddProcess(ctx, typ, query)
+	if err != nil {
+		return nil, err
+	}
+	defer e.Catalog.Done(ctx.Pid())
+
+	return e.Analyzer.Analyze(ctx, parsed)
+}
+
+// QueryPrepared executes a query prepared with Prepare, binding the given
+// values to its placeholders. Placeholders are named after their position
+// in the query, starting with v1.
+func (e *Engine) QueryPrepared(
+	ctx *sql.Context,
+	query string,
+	prepared sql.Node,
+	bindings map[string]sql.Expression,
+) (sql.Schema, sql.RowIter, error) {
+	span, ctx := ctx.Span("query_prepared", opentracing.Tag{Key: "query", Value: query})
+	defer span.Finish()
+
+	logrus.WithField("query", query).Debug("executing prepared query")
+
+	err := e.beginImplicitTransaction(ctx, prepared)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	ctx, err = e.Catalog.AddProcess(ctx, sql.QueryProcess, query)
+	defer func() {
+		if err != nil && ctx != nil {
+			e.Catalog.Done(ctx.Pid())
+		}
+	}()
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	analyzed, err := e.Analyzer.AnalyzePrepared(ctx, prepared, bindings)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	iter, err := analyzed.RowIter(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return analyzed.Schema(), iter, nil
+}
+
+// ClosePrepared releases the resources held by a query prepared with
+// Prepare.
+func (e *Engine) ClosePrepared(prepared sql.Node) {
+	e.Analyzer.ReleasePrepared(prepared)
+}
+
+// checkPermissions checks the session is allowed to run the given node and
+// returns the type of process it must be run in.
+func (e *Engine) checkPermissions(ctx *sql.Context, n sql.Node) (sql.ProcessType, error) {
+	var perm = auth.ReadPerm
+	var typ = sql.QueryProcess
+	switch n.(type) {
+	case *plan.CreateIndex:
+		typ = sql.CreateIndexProcess
+		perm = auth.ReadPerm | auth.WritePerm
+	case *plan.InsertInto, *plan.Update, *plan.Delete,
+		*plan.DropTable, *plan.RenameTable, *plan.Truncate,
+		*plan.AddColumn, *plan.DropColumn, *plan.ModifyColumn,
+		*plan.DropIndex, *plan.UnlockTables, *plan.LockTables,
+		*plan.CreateDatabase, *plan.DropDatabase:
+		perm = auth.ReadPerm | auth.WritePerm
+	}
+
+	return typ, e.Auth.Allowed(ctx, perm)
+}
+
+// beginImplicitTransaction starts a new transaction in the session before
+// running the given node if autocommit is disabled and there is no open
+// transaction, so all statements until the next COMMIT or ROLLBACK are part
+// of the same transaction. As in MySQL, statements changing the definition
+// of databases and tables are not part of any transaction, and commit the
+// open one instead.
+func (e *Engine) beginImplicitTransaction(ctx *sql.Context, n sql.Node) error {
+	if qp, ok := n.(*plan.QueryProcess); ok {
+		n = qp.Child
+	}
+
+	switch n.(type) {
+	case *plan.BeginTransaction, *plan.Commit, *plan.Rollback, *plan.Set:
+		return nil
+	case *plan.CreateTable, *plan.DropTable, *plan.RenameTable, *plan.Truncate,
+		*plan.AddColumn, *plan.DropColumn, *plan.ModifyColumn,
+		*plan.CreateIndex, *plan.DropIndex, *plan.CreateView, *plan.DropView,
+		*plan.CreateDatabase, *plan.DropDatabase,
+		*plan.LockTables, *plan.UnlockTables:
+		return sql.CommitTransaction(ctx)
+	}
+
+	if sql.Autocommit(ctx) || ctx.Transaction() != nil {
+		return nil
+	}
+
+	tx, err := sql.BeginTransaction(ctx, e.Catalog.AllDatabases())
+	if err != nil {
+		return err
+	}
+
+	ctx.SetTransaction(tx)
+	return nil
+}
+
 // AddDatabase adds the given database to the catalog.
 func (e *Engine) AddDatabase(db sql.Database) {
 	e.Catalog.AddDatabase(db)
@@ -11,13 +11,16 @@ import (
 	"testing"
 	"time"
 
+	errors "gopkg.in/src-d/go-errors.v1"
 	"gopkg.in/src-d/go-mysql-server.v0"
 	"gopkg.in/src-d/go-mysql-server.v0/auth"
 	"gopkg.in/src-d/go-mysql-server.v0/mem"
 	"gopkg.in/src-d/go-mysql-server.v0/sql"
 	"gopkg.in/src-d/go-mysql-server.v0/sql/analyzer"
+	"gopkg.in/src-d/go-mysql-server.v0/sql/expression"
 	"gopkg.in/src-d/go-mysql-server.v0/sql/index/pilosa"
 	"gopkg.in/src-d/go-mysql-server.v0/sql/parse"
+	"gopkg.in/src-d/go-mysql-server.v0/sql/plan"
 	"gopkg.in/src-d/go-mysql-server.v0/test"
 
 	"github.com/stretchr/testify/require"
@@ -321,6 +324,176 @@ var queries = []struct {
 			{int64(3), "third row", "first", int64(3)},
 		},
 	},
+	{
+		`SELECT i, i2 FROM mytable LEFT JOIN othertable ON i = i2 + 1`,
+		[]sql.Row{
+			{int64(1), nil},
+			{int64(2), int64(1)},
+			{int64(3), int64(2)},
+		},
+	},
+	{
+		`SELECT i, i2 FROM mytable RIGHT JOIN othertable ON i = i2 + 1`,
+		[]sql.Row{
+			{nil, int64(3)},
+			{int64(3), int64(2)},
+			{int64(2), int64(1)},
+		},
+	},
+	{
+		`SELECT i, i2 FROM mytable LEFT JOIN othertable ON i = i2 + 1 WHERE i2 IS NULL`,
+		[]sql.Row{
+			{int64(1), nil},
+		},
+	},
+	{
+		`SELECT i, s2 FROM mytable LEFT JOIN othertable ON i = i2 AND s2 = 'first'`,
+		[]sql.Row{
+			{int64(1), nil},
+			{int64(2), nil},
+			{int64(3), "first"},
+		},
+	},
+	{
+		`SELECT i FROM mytable WHERE i IN (SELECT i2 FROM othertable WHERE i2 > 1)`,
+		[]sql.Row{
+			{int64(2)},
+			{int64(3)},
+		},
+	},
+	{
+		`SELECT i FROM mytable WHERE i NOT IN (SELECT i2 FROM othertable WHERE i2 > 1)`,
+		[]sql.Row{
+			{int64(1)},
+		},
+	},
+	{
+		`SELECT i FROM mytable WHERE i = (SELECT MAX(i2) FROM othertable)`,
+		[]sql.Row{
+			{int64(3)},
+		},
+	},
+	{
+		`SELECT i, (SELECT s2 FROM othertable WHERE i2 = i) FROM mytable`,
+		[]sql.Row{
+			{int64(1), "third"},
+			{int64(2), "second"},
+			{int64(3), "first"},
+		},
+	},
+	{
+		`SELECT i FROM mytable mt WHERE EXISTS (SELECT * FROM othertable ot WHERE ot.i2 = mt.i AND ot.s2 = 'second')`,
+		[]sql.Row{
+			{int64(2)},
+		},
+	},
+	{
+		`SELECT i FROM mytable mt WHERE NOT EXISTS (SELECT * FROM othertable ot WHERE ot.i2 = mt.i + 1)`,
+		[]sql.Row{
+			{int64(3)},
+		},
+	},
+	{
+		`SELECT i FROM mytable mt WHERE EXISTS (SELECT * FROM othertable ot WHERE ot.i2 IN (SELECT tt.i FROM tabletest tt WHERE tt.i = mt.i AND tt.i < 3))`,
+		[]sql.Row{
+			{int64(1)},
+			{int64(2)},
+		},
+	},
+	{
+		`SELECT i FROM mytable mt WHERE EXISTS (SELECT * FROM othertable ot WHERE EXISTS (SELECT * FROM tabletest tt WHERE tt.i = ot.i2 + mt.i AND tt.i = 3))`,
+		[]sql.Row{
+			{int64(1)},
+			{int64(2)},
+		},
+	},
+	{
+		`SELECT s2 FROM othertable GROUP BY s2 HAVING MAX(i2) > 1 ORDER BY s2`,
+		[]sql.Row{
+			{"first"},
+			{"second"},
+		},
+	},
+	{
+		`SELECT i, COUNT(*) FROM mytable GROUP BY i HAVING COUNT(*) = 1 AND i > 1 ORDER BY i`,
+		[]sql.Row{
+			{int64(2), int32(1)},
+			{int64(3), int32(1)},
+		},
+	},
+	{
+		`SELECT COUNT(*) AS c FROM mytable HAVING c > 2`,
+		[]sql.Row{
+			{int32(3)},
+		},
+	},
+	{
+		`SELECT COUNT(*) FROM mytable GROUP BY s HAVING s = 'first row'`,
+		[]sql.Row{
+			{int32(1)},
+		},
+	},
+	{
+		`SELECT s2 AS x FROM othertable GROUP BY x HAVING MAX(i2) < 3 ORDER BY x`,
+		[]sql.Row{
+			{"second"},
+			{"third"},
+		},
+	},
+	{
+		`SELECT i FROM mytable UNION SELECT i2 FROM othertable ORDER BY i`,
+		[]sql.Row{
+			{int64(1)},
+			{int64(2)},
+			{int64(3)},
+		},
+	},
+	{
+		`SELECT i FROM mytable UNION ALL SELECT i2 FROM othertable ORDER BY i DESC LIMIT 4`,
+		[]sql.Row{
+			{int64(3)},
+			{int64(3)},
+			{int64(2)},
+			{int64(2)},
+		},
+	},
+	{
+		`SELECT s FROM mytable UNION SELECT i2 FROM othertable ORDER BY 1`,
+		[]sql.Row{
+			{"1"},
+			{"2"},
+			{"3"},
+			{"first row"},
+			{"second row"},
+			{"third row"},
+		},
+	},
+	{
+		`SELECT i FROM mytable INTERSECT SELECT i2 FROM othertable WHERE i2 > 1 ORDER BY i`,
+		[]sql.Row{
+			{int64(2)},
+			{int64(3)},
+		},
+	},
+	{
+		`SELECT i FROM mytable EXCEPT SELECT i2 FROM othertable WHERE i2 < 3 ORDER BY i`,
+		[]sql.Row{
+			{int64(3)},
+		},
+	},
+	{
+		`SELECT i FROM mytable WHERE i = 1 UNION SELECT i2 FROM othertable WHERE i2 = 2 INTERSECT SELECT i FROM mytable WHERE i > 2 ORDER BY i`,
+		[]sql.Row{
+			{int64(1)},
+		},
+	},
+	{
+		`SELECT i FROM mytable EXCEPT SELECT i2 FROM othertable WHERE i2 < 3 INTERSECT SELECT i FROM mytable WHERE i > 1 ORDER BY i`,
+		[]sql.Row{
+			{int64(1)},
+			{int64(3)},
+		},
+	},
 	{
 		`SELECT i as foo FROM mytable ORDER BY i DESC`,
 		[]sql.Row{
@@ -847,6 +1020,18 @@ func TestSessionSelectLimit(t *testing.T) {
 			"SELECT i FROM (SELECT i FROM mytable) t ORDER BY i LIMIT 2",
 			[]sql.Row{{int64(1)}},
 		},
+		{
+			"SELECT i FROM mytable UNION ALL SELECT i FROM mytable ORDER BY i LIMIT 3",
+			[]sql.Row{{int64(1)}, {int64(1)}, {int64(2)}},
+		},
+		{
+			"SELECT i FROM mytable EXCEPT SELECT i FROM mytable WHERE i = 1 ORDER BY i LIMIT 2",
+			[]sql.Row{{int64(2)}, {int64(3)}},
+		},
+		{
+			"SELECT i FROM mytable UNION SELECT i FROM mytable ORDER BY i",
+			[]sql.Row{{int64(1)}},
+		},
 	}
 	e := newEngine(t)
 	t.Run("sql_select_limit", func(t *testing.T) {
@@ -1069,6 +1254,75 @@ func TestInsertInto(t *testing.T) {
 	)
 }
 
+func TestUpdate(t *testing.T) {
+	e := newEngine(t)
+	testQuery(t, e,
+		"UPDATE mytable SET s = 'updated' WHERE i > 1",
+		[]sql.Row{{int64(2)}},
+	)
+
+	testQuery(t, e,
+		"SELECT i, s FROM mytable ORDER BY i",
+		[]sql.Row{
+			{int64(1), "first row"},
+			{int64(2), "updated"},
+			{int64(3), "updated"},
+		},
+	)
+
+	testQuery(t, e,
+		"UPDATE mytable SET i = i * 10, s = 'last' ORDER BY i DESC LIMIT 1",
+		[]sql.Row{{int64(1)}},
+	)
+
+	testQuery(t, e,
+		"SELECT i, s FROM mytable ORDER BY i",
+		[]sql.Row{
+			{int64(1), "first row"},
+			{int64(2), "updated"},
+			{int64(30), "last"},
+		},
+	)
+
+	testQuery(t, e,
+		"UPDATE mytable SET s = 'first row' WHERE i = 1",
+		[]sql.Row{{int64(0)}},
+	)
+}
+
+func TestDelete(t *testing.T) {
+	e := newEngine(t)
+	testQuery(t, e,
+		"DELETE FROM mytable WHERE i = 2",
+		[]sql.Row{{int64(1)}},
+	)
+
+	testQuery(t, e,
+		"SELECT i FROM mytable ORDER BY i",
+		[]sql.Row{{int64(1)}, {int64(3)}},
+	)
+
+	testQuery(t, e,
+		"DELETE FROM mytable ORDER BY i DESC LIMIT 1",
+		[]sql.Row{{int64(1)}},
+	)
+
+	testQuery(t, e,
+		"SELECT i FROM mytable",
+		[]sql.Row{{int64(1)}},
+	)
+
+	testQuery(t, e,
+		"DELETE FROM mytable",
+		[]sql.Row{{int64(1)}},
+	)
+
+	testQuery(t, e,
+		"SELECT i FROM mytable",
+		nil,
+	)
+}
+
 const testNumPartitions = 5
 
 func TestAmbiguousColumnResolution(t *testing.T) {
@@ -1151,79 +1405,801 @@ func TestDDL(t *testing.T) {
 		{Name: "b", Type: sql.Text, Nullable: true, Source: "t1"},
 		{Name: "c", Type: sql.Date, Nullable: true, Source: "t1"},
 		{Name: "d", Type: sql.Timestamp, Nullable: true, Source: "t1"},
-		{Name: "e", Type: sql.Text, Nullable: true, Source: "t1"},
+		{Name: "e", Type: sql.VarChar(20), Nullable: true, Source: "t1"},
 		{Name: "f", Type: sql.Blob, Source: "t1"},
 	}
 
 	require.Equal(s, testTable.Schema())
 }
 
-func TestNaturalJoin(t *testing.T) {
+func TestDecimal(t *testing.T) {
+	e := newEngine(t)
+
+	testQuery(t, e, "CREATE TABLE prices(p DECIMAL(10,2) NOT NULL)", []sql.Row(nil))
+	testQuery(t, e, "INSERT INTO prices (p) VALUES (0.1), (0.2), (10.25)", []sql.Row{{int64(3)}})
+	testQuery(t, e, "SELECT SUM(p) FROM prices", []sql.Row{{"10.55"}})
+	testQuery(t, e, "SELECT AVG(p) FROM prices", []sql.Row{{"3.516667"}})
+	testQuery(t, e, "SELECT p * p FROM prices WHERE p > 1", []sql.Row{{"105.0625"}})
+	testQuery(t, e, "SELECT p + p FROM prices WHERE p < 0.15", []sql.Row{{"0.20"}})
+}
+
+func TestSizedTypes(t *testing.T) {
 	require := require.New(t)
+	e := newEngine(t)
+	ctx := newCtx()
 
-	t1 := mem.NewPartitionedTable("t1", sql.Schema{
-		{Name: "a", Type: sql.Text, Source: "t1"},
-		{Name: "b", Type: sql.Text, Source: "t1"},
-		{Name: "c", Type: sql.Text, Source: "t1"},
-	}, testNumPartitions)
+	testQueryWithContext(ctx, t, e, "CREATE TABLE sized(s VARCHAR(3), n TINYINT UNSIGNED)", []sql.Row(nil))
+	testQueryWithContext(ctx, t, e, "INSERT INTO sized (s, n) VALUES ('abcd', 1000)", []sql.Row{{int64(1)}})
+	require.Equal(uint16(2), ctx.WarningCount())
+	testQueryWithContext(ctx, t, e, "SELECT s, n FROM sized", []sql.Row{{"abc", uint8(255)}})
 
-	insertRows(
-		t, t1,
-		sql.NewRow("a_1", "b_1", "c_1"),
-		sql.NewRow("a_2", "b_2", "c_2"),
-		sql.NewRow("a_3", "b_3", "c_3"),
+	testQueryWithContext(ctx, t, e, "SET sql_mode = 'STRICT_ALL_TABLES'", []sql.Row(nil))
+	_, _, err := e.Query(ctx, "INSERT INTO sized (s, n) VALUES ('abcd', 1)")
+	require.Error(err)
+	require.True(sql.ErrLengthTooLong.Is(err))
+}
+
+func TestDateAndTimeTypes(t *testing.T) {
+	require := require.New(t)
+	e := newEngine(t)
+	ctx := newCtx()
+
+	testQueryWithContext(ctx, t, e, "CREATE TABLE times(d DATETIME(3), t TIME, y YEAR)", []sql.Row(nil))
+	testQueryWithContext(
+		ctx, t, e,
+		"INSERT INTO times (d, t, y) VALUES ('2018-10-18 05:22:25.1235', '-1 10:11:12', '19')",
+		[]sql.Row{{int64(1)}},
 	)
 
-	t2 := mem.NewPartitionedTable("t2", sql.Schema{
-		{Name: "a", Type: sql.Text, Source: "t2"},
-		{Name: "b", Type: sql.Text, Source: "t2"},
-		{Name: "d", Type: sql.Text, Source: "t2"},
-	}, testNumPartitions)
+	testQueryWithContext(ctx, t, e, "SELECT d, t, y FROM times", []sql.Row{{
+		time.Date(2018, time.October, 18, 5, 22, 25, 124000000, time.UTC),
+		-(34*time.Hour + 11*time.Minute + 12*time.Second),
+		int16(2019),
+	}})
 
-	insertRows(
-		t, t2,
-		sql.NewRow("a_1", "b_1", "d_1"),
-		sql.NewRow("a_2", "b_2", "d_2"),
-		sql.NewRow("a_3", "b_3", "d_3"),
+	testQueryWithContext(
+		ctx, t, e,
+		"SELECT HOUR(t), MINUTE(t), SECOND(d), YEAR(y) FROM times",
+		[]sql.Row{{int32(34), int32(11), int32(25), int32(2019)}},
 	)
 
-	db := mem.NewDatabase("mydb")
-	db.AddTable("t1", t1)
-	db.AddTable("t2", t2)
+	testQueryWithContext(ctx, t, e, "INSERT INTO times (t) VALUES ('900:00:00')", []sql.Row{{int64(1)}})
+	require.Equal(uint16(1), ctx.WarningCount())
 
-	e := sqle.NewDefault()
-	e.AddDatabase(db)
+	testQueryWithContext(ctx, t, e, "SET sql_mode = 'STRICT_ALL_TABLES'", []sql.Row(nil))
+	_, _, err := e.Query(ctx, "INSERT INTO times (y) VALUES (1800)")
+	require.Error(err)
+	require.True(sql.ErrOutOfRange.Is(err))
+}
 
-	_, iter, err := e.Query(newCtx(), `SELECT * FROM t1 NATURAL JOIN t2`)
-	require.NoError(err)
+func TestEnumAndSet(t *testing.T) {
+	require := require.New(t)
+	e := newEngine(t)
+	ctx := newCtx()
+
+	testQueryWithContext(ctx, t, e, "CREATE TABLE shirts(size ENUM('small', 'medium', 'large'), colors SET('red', 'blue'))", []sql.Row(nil))
+	testQueryWithContext(
+		ctx, t, e,
+		"INSERT INTO shirts (size, colors) VALUES ('large', 'blue,red'), ('small', ''), ('MEDIUM', 'blue')",
+		[]sql.Row{{int64(3)}},
+	)
+
+	testQueryWithContext(ctx, t, e, "SELECT size, colors FROM shirts WHERE size = 'large'", []sql.Row{{uint16(3), uint64(3)}})
+	testQueryWithContext(ctx, t, e, "SELECT colors FROM shirts WHERE size > 1", []sql.Row{{uint64(3)}, {uint64(2)}})
 
+	// Values are sorted by their ordinal, not alphabetically.
+	_, iter, err := e.Query(ctx, "SELECT size FROM shirts ORDER BY size DESC")
+	require.NoError(err)
 	rows, err := sql.RowIterToRows(iter)
 	require.NoError(err)
+	require.Equal([]sql.Row{{uint16(3)}, {uint16(2)}, {uint16(1)}}, rows)
 
-	require.Equal(
+	testQueryWithContext(
+		ctx, t, e,
+		"SELECT DATA_TYPE, COLUMN_TYPE FROM information_schema.COLUMNS WHERE TABLE_NAME = 'shirts'",
 		[]sql.Row{
-			{"a_1", "b_1", "c_1", "d_1"},
-			{"a_2", "b_2", "c_2", "d_2"},
-			{"a_3", "b_3", "c_3", "d_3"},
+			{"ENUM", "ENUM('small','medium','large')"},
+			{"SET", "SET('red','blue')"},
 		},
-		rows,
 	)
+
+	testQueryWithContext(ctx, t, e, "INSERT INTO shirts (size, colors) VALUES ('huge', 'red,green')", []sql.Row{{int64(1)}})
+	require.Equal(uint16(2), ctx.WarningCount())
+
+	testQueryWithContext(ctx, t, e, "SET sql_mode = 'STRICT_ALL_TABLES'", []sql.Row(nil))
+	_, _, err = e.Query(ctx, "INSERT INTO shirts (size) VALUES ('huge')")
+	require.Error(err)
+	require.True(sql.ErrInvalidEnumValue.Is(err))
 }
 
-func TestNaturalJoinEqual(t *testing.T) {
+func TestCollations(t *testing.T) {
 	require := require.New(t)
+	e := newEngine(t)
+	ctx := newCtx()
 
-	t1 := mem.NewPartitionedTable("t1", sql.Schema{
-		{Name: "a", Type: sql.Text, Source: "t1"},
-		{Name: "b", Type: sql.Text, Source: "t1"},
-		{Name: "c", Type: sql.Text, Source: "t1"},
-	}, testNumPartitions)
-
-	insertRows(
-		t, t1,
-		sql.NewRow("a_1", "b_1", "c_1"),
-		sql.NewRow("a_2", "b_2", "c_2"),
-		sql.NewRow("a_3", "b_3", "c_3"),
+	testQueryWithContext(ctx, t, e, "CREATE TABLE people(name VARCHAR(20) COLLATE utf8mb4_general_ci, nick TEXT)", []sql.Row(nil))
+	testQueryWithContext(
+		ctx, t, e,
+		"INSERT INTO people (name, nick) VALUES ('John', 'jd'), ('JOHN', 'JD'), ('jane', 'Jane')",
+		[]sql.Row{{int64(3)}},
+	)
+
+	testQueryWithContext(ctx, t, e, "SELECT nick FROM people WHERE name = 'john'", []sql.Row{{"jd"}, {"JD"}})
+	testQueryWithContext(ctx, t, e, "SELECT nick FROM people WHERE name LIKE 'J%N'", []sql.Row{{"jd"}, {"JD"}, {"Jane"}})
+	testQueryWithContext(ctx, t, e, "SELECT name FROM people WHERE nick = 'jane'", []sql.Row(nil))
+	testQueryWithContext(ctx, t, e, "SELECT name FROM people WHERE nick COLLATE utf8_general_ci = 'jane'", []sql.Row{{"jane"}})
+	testQueryWithContext(ctx, t, e, "SELECT COUNT(*) FROM people GROUP BY name", []sql.Row{{int64(2)}, {int64(1)}})
+	testQueryWithContext(ctx, t, e, "SELECT DISTINCT name FROM people", []sql.Row{{"John"}, {"jane"}})
+
+	_, iter, err := e.Query(ctx, "SELECT name FROM people ORDER BY name COLLATE utf8_bin")
+	require.NoError(err)
+	rows, err := sql.RowIterToRows(iter)
+	require.NoError(err)
+	require.Equal([]sql.Row{{"JOHN"}, {"John"}, {"jane"}}, rows)
+
+	testQueryWithContext(
+		ctx, t, e,
+		"SHOW COLLATION LIKE 'utf8mb4_general%'",
+		[]sql.Row{{"utf8mb4_general_ci", "utf8mb4", uint64(45), "Yes", "Yes", uint32(1)}},
+	)
+	testQueryWithContext(
+		ctx, t, e,
+		"SHOW CHARACTER SET LIKE 'binary'",
+		[]sql.Row{{"binary", "Binary pseudo charset", "binary", uint32(1)}},
+	)
+}
+
+func TestKeyConstraints(t *testing.T) {
+	require := require.New(t)
+	e := newEngine(t)
+	ctx := newCtx()
+
+	testQueryWithContext(ctx, t, e, "CREATE TABLE users(id BIGINT PRIMARY KEY, email VARCHAR(50) UNIQUE, name TEXT NOT NULL)", []sql.Row(nil))
+	testQueryWithContext(
+		ctx, t, e,
+		"INSERT INTO users (id, email, name) VALUES (1, 'a@b.com', 'a'), (2, 'c@d.com', 'c')",
+		[]sql.Row{{int64(2)}},
+	)
+
+	_, _, err := e.Query(ctx, "INSERT INTO users (id, email, name) VALUES (1, 'e@f.com', 'e')")
+	require.Error(err)
+	require.True(sql.ErrDuplicateEntry.Is(err))
+
+	_, _, err = e.Query(ctx, "INSERT INTO users (id, email, name) VALUES (3, 'a@b.com', 'e')")
+	require.Error(err)
+	require.True(sql.ErrDuplicateEntry.Is(err))
+
+	_, _, err = e.Query(ctx, "INSERT INTO users (id, email, name) VALUES (3, 'e@f.com', NULL)")
+	require.Error(err)
+	require.True(sql.ErrColumnCannotBeNull.Is(err))
+
+	testQueryWithContext(
+		ctx, t, e,
+		"INSERT IGNORE INTO users (id, email, name) VALUES (1, 'e@f.com', 'e'), (3, 'g@h.com', 'g')",
+		[]sql.Row{{int64(1)}},
+	)
+	require.Equal(uint16(1), ctx.WarningCount())
+
+	testQueryWithContext(ctx, t, e, "REPLACE INTO users (id, email, name) VALUES (2, 'x@y.com', 'x')", []sql.Row{{int64(2)}})
+	testQueryWithContext(
+		ctx, t, e,
+		"SELECT id, email, name FROM users",
+		[]sql.Row{
+			{int64(1), "a@b.com", "a"},
+			{int64(2), "x@y.com", "x"},
+			{int64(3), "g@h.com", "g"},
+		},
+	)
+
+	_, _, err = e.Query(ctx, "UPDATE users SET id = 1 WHERE id = 3")
+	require.Error(err)
+	require.True(sql.ErrDuplicateEntry.Is(err))
+
+	testQueryWithContext(
+		ctx, t, e,
+		"SHOW COLUMNS FROM users",
+		[]sql.Row{
+			{"id", "INT64", "NO", "PRI", "", ""},
+			{"email", "VARCHAR(50)", "YES", "UNI", "", ""},
+			{"name", "TEXT", "NO", "", "", ""},
+		},
+	)
+}
+
+func TestCompositeUniqueKey(t *testing.T) {
+	require := require.New(t)
+	e := newEngine(t)
+	ctx := newCtx()
+
+	testQueryWithContext(ctx, t, e, "CREATE TABLE pairs(a INT, b INT, UNIQUE KEY ab (a, b))", []sql.Row(nil))
+	testQueryWithContext(
+		ctx, t, e,
+		"INSERT INTO pairs (a, b) VALUES (1, 1), (1, 2), (2, 1), (1, NULL), (1, NULL)",
+		[]sql.Row{{int64(5)}},
+	)
+
+	_, _, err := e.Query(ctx, "INSERT INTO pairs (a, b) VALUES (1, 2)")
+	require.Error(err)
+	require.True(sql.ErrDuplicateEntry.Is(err))
+	require.Equal("Duplicate entry '1-2' for key 'ab'", err.Error())
+
+	_, _, err = e.Query(ctx, "UPDATE pairs SET b = 1 WHERE a = 2")
+	require.NoError(err)
+
+	_, _, err = e.Query(ctx, "UPDATE pairs SET a = 1 WHERE a = 2")
+	require.Error(err)
+	require.True(sql.ErrDuplicateEntry.Is(err))
+
+	testQueryWithContext(
+		ctx, t, e,
+		"SHOW CREATE TABLE pairs",
+		[]sql.Row{{
+			"pairs",
+			"CREATE TABLE `pairs` (`a` INT,\n" +
+				"`b` INT,\n" +
+				"UNIQUE KEY `ab` (`a`,`b`)) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4",
+		}},
+	)
+}
+
+func TestAutoIncrement(t *testing.T) {
+	e := newEngine(t)
+	ctx := newCtx()
+
+	testQueryWithContext(ctx, t, e, "CREATE TABLE items(id BIGINT AUTO_INCREMENT PRIMARY KEY, name TEXT)", []sql.Row(nil))
+	testQueryWithContext(ctx, t, e, "SELECT LAST_INSERT_ID()", []sql.Row{{uint64(0)}})
+	testQueryWithContext(ctx, t, e, "INSERT INTO items (name) VALUES ('a'), ('b')", []sql.Row{{int64(2)}})
+	testQueryWithContext(ctx, t, e, "SELECT LAST_INSERT_ID()", []sql.Row{{uint64(1)}})
+	testQueryWithContext(ctx, t, e, "INSERT INTO items (id, name) VALUES (10, 'c')", []sql.Row{{int64(1)}})
+	testQueryWithContext(ctx, t, e, "SET auto_increment_increment = 5", []sql.Row(nil))
+	testQueryWithContext(ctx, t, e, "INSERT INTO items (id, name) VALUES (NULL, 'd')", []sql.Row{{int64(1)}})
+	testQueryWithContext(ctx, t, e, "SELECT LAST_INSERT_ID()", []sql.Row{{uint64(11)}})
+
+	testQueryWithContext(
+		ctx, t, e,
+		"SELECT id, name FROM items",
+		[]sql.Row{
+			{int64(1), "a"},
+			{int64(2), "b"},
+			{int64(10), "c"},
+			{int64(11), "d"},
+		},
+	)
+
+	testQueryWithContext(
+		ctx, t, e,
+		"SHOW COLUMNS FROM items",
+		[]sql.Row{
+			{"id", "INT64", "NO", "PRI", "", "auto_increment"},
+			{"name", "TEXT", "YES", "", "", ""},
+		},
+	)
+}
+
+func TestInsertOnDuplicateKeyUpdate(t *testing.T) {
+	e := newEngine(t)
+	ctx := newCtx()
+
+	testQueryWithContext(ctx, t, e, "CREATE TABLE counters(name VARCHAR(20) PRIMARY KEY, hits BIGINT)", []sql.Row(nil))
+	testQueryWithContext(
+		ctx, t, e,
+		"INSERT INTO counters (name, hits) VALUES ('a', 1), ('b', 1)",
+		[]sql.Row{{int64(2)}},
+	)
+	testQueryWithContext(
+		ctx, t, e,
+		"INSERT INTO counters (name, hits) VALUES ('a', 2), ('c', 1) ON DUPLICATE KEY UPDATE hits = hits + VALUES(hits)",
+		[]sql.Row{{int64(3)}},
+	)
+	testQueryWithContext(
+		ctx, t, e,
+		"INSERT INTO counters (name, hits) VALUES ('b', 5) ON DUPLICATE KEY UPDATE hits = hits",
+		[]sql.Row{{int64(0)}},
+	)
+
+	testQueryWithContext(
+		ctx, t, e,
+		"SELECT name, hits FROM counters",
+		[]sql.Row{
+			{"a", int64(3)},
+			{"b", int64(1)},
+			{"c", int64(1)},
+		},
+	)
+}
+
+func TestColumnDefaults(t *testing.T) {
+	require := require.New(t)
+	e := newEngine(t)
+	ctx := newCtx()
+
+	testQueryWithContext(
+		ctx, t, e,
+		"CREATE TABLE tasks(id BIGINT PRIMARY KEY, status VARCHAR(10) NOT NULL DEFAULT 'new', priority BIGINT DEFAULT 3, created DATETIME DEFAULT CURRENT_TIMESTAMP)",
+		[]sql.Row(nil),
+	)
+	testQueryWithContext(ctx, t, e, "INSERT INTO tasks (id) VALUES (1)", []sql.Row{{int64(1)}})
+	testQueryWithContext(ctx, t, e, "INSERT INTO tasks (id, status, priority) VALUES (2, DEFAULT, 1)", []sql.Row{{int64(1)}})
+
+	testQueryWithContext(
+		ctx, t, e,
+		"SELECT id, status, priority, created IS NOT NULL FROM tasks",
+		[]sql.Row{
+			{int64(1), "new", int64(3), true},
+			{int64(2), "new", int64(1), true},
+		},
+	)
+
+	testQueryWithContext(
+		ctx, t, e,
+		"SHOW COLUMNS FROM tasks",
+		[]sql.Row{
+			{"id", "INT64", "NO", "PRI", "", ""},
+			{"status", "VARCHAR(10)", "NO", "", "new", ""},
+			{"priority", "INT64", "YES", "", "3", ""},
+			{"created", "DATETIME", "YES", "", "CURRENT_TIMESTAMP", ""},
+		},
+	)
+
+	_, _, err := e.Query(ctx, "CREATE TABLE bad(n BIGINT DEFAULT 'x')")
+	require.Error(err)
+	require.True(parse.ErrInvalidDefaultValue.Is(err))
+}
+
+func TestConstraints(t *testing.T) {
+	require := require.New(t)
+	e := newEngine(t)
+	ctx := newCtx()
+
+	testQueryWithContext(
+		ctx, t, e,
+		"CREATE TABLE authors(id BIGINT PRIMARY KEY, name TEXT NOT NULL, CHECK (name <> ''))",
+		[]sql.Row(nil),
+	)
+	testQueryWithContext(
+		ctx, t, e,
+		`CREATE TABLE books(
+			id BIGINT PRIMARY KEY,
+			author_id BIGINT,
+			pages BIGINT CHECK (pages > 0),
+			CONSTRAINT fk_author FOREIGN KEY (author_id) REFERENCES authors (id) ON DELETE CASCADE
+		)`,
+		[]sql.Row(nil),
+	)
+	testQueryWithContext(ctx, t, e, "INSERT INTO authors VALUES (1, 'a'), (2, 'b')", []sql.Row{{int64(2)}})
+	testQueryWithContext(ctx, t, e, "INSERT INTO books VALUES (1, 1, 100), (2, 2, 200), (3, NULL, 50)", []sql.Row{{int64(3)}})
+
+	for query, kind := range map[string]*errors.Kind{
+		"INSERT INTO authors VALUES (3, '')":      sql.ErrCheckConstraintViolated,
+		"UPDATE books SET pages = 0 WHERE id = 1": sql.ErrCheckConstraintViolated,
+		"INSERT INTO books VALUES (4, 3, 10)":     sql.ErrForeignKeyChildViolation,
+		"UPDATE authors SET id = 3 WHERE id = 1":  sql.ErrForeignKeyParentViolation,
+		"DROP TABLE authors":                      sql.ErrTableReferenced,
+	} {
+		_, _, err := e.Query(ctx, query)
+		require.Error(err, query)
+		require.True(kind.Is(err), query)
+	}
+
+	testQueryWithContext(ctx, t, e, "DELETE FROM authors WHERE id = 1", []sql.Row{{int64(1)}})
+	testQueryWithContext(ctx, t, e, "SELECT id FROM books", []sql.Row{{int64(2)}, {int64(3)}})
+
+	testQueryWithContext(
+		ctx, t, e,
+		"SHOW CREATE TABLE books",
+		[]sql.Row{{
+			"books",
+			"CREATE TABLE `books` (`id` BIGINT NOT NULL,\n" +
+				"`author_id` BIGINT,\n" +
+				"`pages` BIGINT,\n" +
+				"PRIMARY KEY (`id`),\n" +
+				"CONSTRAINT `fk_author` FOREIGN KEY (`author_id`) REFERENCES `authors` (`id`) ON DELETE CASCADE,\n" +
+				"CONSTRAINT `books_chk_1` CHECK (pages > 0)) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4",
+		}},
+	)
+
+	testQueryWithContext(
+		ctx, t, e,
+		"SELECT constraint_name, constraint_type FROM information_schema.table_constraints WHERE table_name = 'books'",
+		[]sql.Row{
+			{"PRIMARY", "PRIMARY KEY"},
+			{"fk_author", "FOREIGN KEY"},
+			{"books_chk_1", "CHECK"},
+		},
+	)
+
+	testQueryWithContext(
+		ctx, t, e,
+		"SELECT constraint_name, unique_constraint_name, delete_rule, referenced_table_name FROM information_schema.referential_constraints",
+		[]sql.Row{{"fk_author", "PRIMARY", "CASCADE", "authors"}},
+	)
+}
+
+func TestViews(t *testing.T) {
+	require := require.New(t)
+	e := newEngine(t)
+	ctx := newCtx()
+
+	testQueryWithContext(ctx, t, e, "CREATE VIEW myview AS SELECT i, s FROM mytable WHERE i > 1", []sql.Row(nil))
+	testQueryWithContext(ctx, t, e, "CREATE VIEW otherview AS SELECT i FROM myview", []sql.Row(nil))
+
+	testQueryWithContext(
+		ctx, t, e,
+		"SELECT * FROM myview",
+		[]sql.Row{{int64(2), "second row"}, {int64(3), "third row"}},
+	)
+	testQueryWithContext(ctx, t, e, "SELECT s FROM myview WHERE i = 3", []sql.Row{{"third row"}})
+	testQueryWithContext(ctx, t, e, "SELECT i FROM otherview", []sql.Row{{int64(2)}, {int64(3)}})
+	testQueryWithContext(
+		ctx, t, e,
+		"SELECT myview.s, othertable.s2 FROM myview INNER JOIN othertable ON myview.i = othertable.i2",
+		[]sql.Row{{"second row", "second"}, {"third row", "first"}},
+	)
+
+	for query, kind := range map[string]*errors.Kind{
+		"CREATE VIEW myview AS SELECT 1":                           sql.ErrViewAlreadyExists,
+		"CREATE VIEW mytable AS SELECT 1":                          sql.ErrTableAlreadyExists,
+		"CREATE OR REPLACE VIEW myview AS SELECT * FROM otherview": sql.ErrViewRecursion,
+		"DROP VIEW myview, unknown":                                sql.ErrViewNotFound,
+		"SHOW CREATE VIEW mytable":                                 sql.ErrViewNotFound,
+	} {
+		_, _, err := e.Query(ctx, query)
+		require.Error(err, query)
+		require.True(kind.Is(err), query)
+	}
+
+	testQueryWithContext(ctx, t, e, "CREATE OR REPLACE VIEW myview AS SELECT i FROM mytable WHERE i = 1", []sql.Row(nil))
+	testQueryWithContext(ctx, t, e, "SELECT * FROM otherview", []sql.Row{{int64(1)}})
+
+	testQueryWithContext(
+		ctx, t, e,
+		"SHOW CREATE VIEW myview",
+		[]sql.Row{{
+			"myview",
+			"CREATE VIEW `myview` AS SELECT i FROM mytable WHERE i = 1",
+			"utf8mb4",
+			"utf8_bin",
+		}},
+	)
+
+	testQueryWithContext(
+		ctx, t, e,
+		"SHOW FULL TABLES",
+		[]sql.Row{
+			{"mytable", "BASE TABLE"},
+			{"myview", "VIEW"},
+			{"othertable", "BASE TABLE"},
+			{"otherview", "VIEW"},
+			{"tabletest", "BASE TABLE"},
+		},
+	)
+
+	testQueryWithContext(
+		ctx, t, e,
+		"SELECT table_name, view_definition FROM information_schema.views WHERE table_schema = 'mydb'",
+		[]sql.Row{
+			{"myview", "SELECT i FROM mytable WHERE i = 1"},
+			{"otherview", "SELECT i FROM myview"},
+		},
+	)
+
+	testQueryWithContext(ctx, t, e, "DROP VIEW myview, otherview", []sql.Row(nil))
+	testQueryWithContext(ctx, t, e, "DROP VIEW IF EXISTS myview", []sql.Row(nil))
+
+	_, _, err := e.Query(ctx, "SELECT * FROM myview")
+	require.Error(err)
+	require.True(sql.ErrTableNotFound.Is(err))
+
+	// The tables of recursive common table expressions are the ones of the
+	// database of the view, but the references to the expression are not.
+	testQueryWithContext(
+		ctx, t, e,
+		`CREATE VIEW recview AS WITH RECURSIVE cte (n) AS
+		(SELECT i FROM mytable WHERE i = 1 UNION ALL SELECT mytable.i FROM mytable INNER JOIN cte ON mytable.i = cte.n + 1)
+		SELECT n FROM cte`,
+		[]sql.Row(nil),
+	)
+	testQueryWithContext(ctx, t, e, "USE foo", []sql.Row(nil))
+	testQueryWithContext(
+		ctx, t, e,
+		"SELECT n FROM mydb.recview",
+		[]sql.Row{{int64(1)}, {int64(2)}, {int64(3)}},
+	)
+}
+
+func TestCommonTableExpressions(t *testing.T) {
+	require := require.New(t)
+	e := newEngine(t)
+
+	testQuery(
+		t, e,
+		"WITH t AS (SELECT i, s FROM mytable WHERE i > 1) SELECT s FROM t ORDER BY i DESC",
+		[]sql.Row{{"third row"}, {"second row"}},
+	)
+	testQuery(
+		t, e,
+		"WITH t (a, b) AS (SELECT i, s FROM mytable) SELECT b FROM t WHERE a = 2",
+		[]sql.Row{{"second row"}},
+	)
+	testQuery(
+		t, e,
+		`WITH t AS (SELECT i FROM mytable), u AS (SELECT i * 10 AS j FROM t)
+		SELECT t.i, u.j FROM t INNER JOIN u ON u.j = t.i * 10 WHERE t.i IN (SELECT i FROM t WHERE i < 3)`,
+		[]sql.Row{{int64(1), int64(10)}, {int64(2), int64(20)}},
+	)
+	testQuery(
+		t, e,
+		"WITH RECURSIVE cte (n) AS (SELECT 1 UNION ALL SELECT n + 1 FROM cte WHERE n < 5) SELECT n FROM cte",
+		[]sql.Row{{int64(1)}, {int64(2)}, {int64(3)}, {int64(4)}, {int64(5)}},
+	)
+	testQuery(
+		t, e,
+		`WITH RECURSIVE cte AS (SELECT i FROM mytable WHERE i = 1 UNION SELECT mytable.i FROM mytable INNER JOIN cte ON mytable.i = cte.i + 1)
+		SELECT * FROM cte`,
+		[]sql.Row{{int64(1)}, {int64(2)}, {int64(3)}},
+	)
+
+	ctx := newCtx()
+	require.NoError(ctx.Session.Set("cte_max_recursion_depth", sql.Int64, int64(2)))
+	testQueryWithContext(
+		ctx, t, e,
+		"WITH RECURSIVE cte (n) AS (SELECT 1 UNION ALL SELECT n + 1 FROM cte) SELECT n FROM cte",
+		[]sql.Row{{int64(1)}, {int64(2)}, {int64(3)}},
+	)
+	require.Equal(uint16(1), ctx.WarningCount())
+
+	_, _, err := e.Query(newCtx(), "WITH t (a, b) AS (SELECT i FROM mytable) SELECT * FROM t")
+	require.Error(err)
+	require.True(plan.ErrCTEColumnCount.Is(err))
+}
+
+func TestWindowFunctions(t *testing.T) {
+	e := newEngine(t)
+
+	testQuery(
+		t, e,
+		"SELECT i, ROW_NUMBER() OVER (ORDER BY i DESC) FROM mytable ORDER BY i",
+		[]sql.Row{{int64(1), uint64(3)}, {int64(2), uint64(2)}, {int64(3), uint64(1)}},
+	)
+	testQuery(
+		t, e,
+		"SELECT i, RANK() OVER (PARTITION BY i % 2 ORDER BY i) AS r FROM mytable ORDER BY i",
+		[]sql.Row{{int64(1), uint64(1)}, {int64(2), uint64(1)}, {int64(3), uint64(2)}},
+	)
+	testQuery(
+		t, e,
+		"SELECT i, SUM(i) OVER (ORDER BY i ROWS BETWEEN 1 PRECEDING AND CURRENT ROW) FROM mytable ORDER BY i",
+		[]sql.Row{{int64(1), float64(1)}, {int64(2), float64(3)}, {int64(3), float64(5)}},
+	)
+	testQuery(
+		t, e,
+		"SELECT i, LAG(s) OVER (ORDER BY i) FROM mytable ORDER BY i",
+		[]sql.Row{{int64(1), nil}, {int64(2), "first row"}, {int64(3), "second row"}},
+	)
+	testQuery(
+		t, e,
+		"SELECT i, ROW_NUMBER() OVER (ORDER BY i) * 10 FROM mytable ORDER BY i",
+		[]sql.Row{{int64(1), int64(10)}, {int64(2), int64(20)}, {int64(3), int64(30)}},
+	)
+}
+
+func TestDistinctAggregationsAndGroupConcat(t *testing.T) {
+	require := require.New(t)
+	e := newEngine(t)
+
+	testQuery(
+		t, e,
+		"SELECT COUNT(DISTINCT i % 2), SUM(DISTINCT i % 2), AVG(DISTINCT i) FROM mytable",
+		[]sql.Row{{int32(2), float64(1), float64(2)}},
+	)
+	testQuery(
+		t, e,
+		"SELECT GROUP_CONCAT(s ORDER BY i DESC SEPARATOR '|') FROM mytable",
+		[]sql.Row{{"third row|second row|first row"}},
+	)
+	testQuery(
+		t, e,
+		"SELECT GROUP_CONCAT(DISTINCT i % 2 ORDER BY i % 2) FROM mytable",
+		[]sql.Row{{"0,1"}},
+	)
+
+	ctx := newCtx()
+	require.NoError(ctx.Session.Set("group_concat_max_len", sql.Int64, int64(10)))
+	testQueryWithContext(
+		ctx, t, e,
+		"SELECT GROUP_CONCAT(s ORDER BY i) FROM mytable",
+		[]sql.Row{{"first row,"}},
+	)
+	require.Equal(uint16(1), ctx.WarningCount())
+}
+
+func TestStatisticalAndBitAggregations(t *testing.T) {
+	e := newEngine(t)
+
+	testQuery(
+		t, e,
+		"SELECT VAR_SAMP(i), STDDEV_SAMP(i), BIT_AND(i), BIT_OR(i), BIT_XOR(i) FROM mytable",
+		[]sql.Row{{float64(1), float64(1), uint64(0), uint64(3), uint64(0)}},
+	)
+	testQuery(
+		t, e,
+		"SELECT JSON_OBJECTAGG(s, i) FROM mytable",
+		[]sql.Row{{map[string]interface{}{
+			"first row":  int64(1),
+			"second row": int64(2),
+			"third row":  int64(3),
+		}}},
+	)
+}
+
+func TestApproximateAggregations(t *testing.T) {
+	e := newEngine(t)
+
+	testQuery(
+		t, e,
+		"SELECT APPROX_COUNT_DISTINCT(i % 2), APPROX_PERCENTILE(i, 0.5) FROM mytable",
+		[]sql.Row{{int64(2), float64(2)}},
+	)
+}
+
+func TestDropRenameAndTruncateTable(t *testing.T) {
+	require := require.New(t)
+
+	e := newEngine(t)
+	db, err := e.Catalog.Database("mydb")
+	require.NoError(err)
+
+	testQuery(t, e, "TRUNCATE TABLE othertable", []sql.Row(nil))
+	testQuery(t, e, "SELECT s2 FROM othertable", []sql.Row(nil))
+
+	testQuery(t, e, "RENAME TABLE othertable TO renamed", []sql.Row(nil))
+	_, ok := db.Tables()["othertable"]
+	require.False(ok)
+	_, ok = db.Tables()["renamed"]
+	require.True(ok)
+
+	testQuery(t, e, "ALTER TABLE renamed RENAME TO othertable", []sql.Row(nil))
+	_, ok = db.Tables()["othertable"]
+	require.True(ok)
+
+	testQuery(t, e, "DROP TABLE othertable", []sql.Row(nil))
+	_, ok = db.Tables()["othertable"]
+	require.False(ok)
+
+	testQuery(t, e, "DROP TABLE IF EXISTS othertable", []sql.Row(nil))
+
+	_, _, err = e.Query(newCtx(), "DROP TABLE othertable")
+	require.Error(err)
+	require.True(sql.ErrTableNotFound.Is(err))
+}
+
+func TestCreateAndDropDatabase(t *testing.T) {
+	require := require.New(t)
+
+	e := newEngine(t)
+	testQuery(t, e, "CREATE DATABASE newdb", []sql.Row(nil))
+	testQuery(t, e, "CREATE DATABASE IF NOT EXISTS newdb", []sql.Row(nil))
+
+	_, _, err := e.Query(newCtx(), "CREATE DATABASE newdb")
+	require.Error(err)
+	require.True(sql.ErrDatabaseExists.Is(err))
+
+	testQuery(t, e, "CREATE TABLE newdb.t (a BIGINT)", []sql.Row(nil))
+	testQuery(t, e, "INSERT INTO newdb.t (a) VALUES (1)", []sql.Row{{int64(1)}})
+	testQuery(t, e, "SELECT a FROM newdb.t", []sql.Row{{int64(1)}})
+
+	testQuery(t, e, "DROP DATABASE newdb", []sql.Row(nil))
+	testQuery(t, e, "DROP DATABASE IF EXISTS newdb", []sql.Row(nil))
+
+	_, err = e.Catalog.Database("newdb")
+	require.True(sql.ErrDatabaseNotFound.Is(err))
+
+	_, _, err = e.Query(newCtx(), "DROP DATABASE newdb")
+	require.Error(err)
+	require.True(sql.ErrDatabaseNotFound.Is(err))
+
+	// Databases can't be created without a database provider.
+	_, _, err = sqle.NewDefault().Query(newCtx(), "CREATE DATABASE newdb")
+	require.Error(err)
+	require.True(sql.ErrNoDatabaseProvider.Is(err))
+}
+
+func TestAlterTable(t *testing.T) {
+	require := require.New(t)
+
+	e := newEngine(t)
+	testQuery(t, e, "ALTER TABLE mytable ADD COLUMN n BIGINT NOT NULL", []sql.Row(nil))
+	testQuery(t, e, "ALTER TABLE mytable MODIFY i TEXT", []sql.Row(nil))
+	testQuery(t, e, "ALTER TABLE mytable DROP COLUMN s", []sql.Row(nil))
+
+	db, err := e.Catalog.Database("mydb")
+	require.NoError(err)
+
+	require.Equal(sql.Schema{
+		{Name: "i", Type: sql.Text, Nullable: true, Source: "mytable"},
+		{Name: "n", Type: sql.Int64, Source: "mytable"},
+	}, db.Tables()["mytable"].Schema())
+
+	testQuery(t, e,
+		"SELECT i, n FROM mytable",
+		[]sql.Row{
+			{"1", int64(0)},
+			{"2", int64(0)},
+			{"3", int64(0)},
+		},
+	)
+
+	_, _, err = e.Query(newCtx(), "ALTER TABLE mytable DROP COLUMN s")
+	require.Error(err)
+	require.True(sql.ErrColumnNotFound.Is(err))
+}
+
+func TestNaturalJoin(t *testing.T) {
+	require := require.New(t)
+
+	t1 := mem.NewPartitionedTable("t1", sql.Schema{
+		{Name: "a", Type: sql.Text, Source: "t1"},
+		{Name: "b", Type: sql.Text, Source: "t1"},
+		{Name: "c", Type: sql.Text, Source: "t1"},
+	}, testNumPartitions)
+
+	insertRows(
+		t, t1,
+		sql.NewRow("a_1", "b_1", "c_1"),
+		sql.NewRow("a_2", "b_2", "c_2"),
+		sql.NewRow("a_3", "b_3", "c_3"),
+	)
+
+	t2 := mem.NewPartitionedTable("t2", sql.Schema{
+		{Name: "a", Type: sql.Text, Source: "t2"},
+		{Name: "b", Type: sql.Text, Source: "t2"},
+		{Name: "d", Type: sql.Text, Source: "t2"},
+	}, testNumPartitions)
+
+	insertRows(
+		t, t2,
+		sql.NewRow("a_1", "b_1", "d_1"),
+		sql.NewRow("a_2", "b_2", "d_2"),
+		sql.NewRow("a_3", "b_3", "d_3"),
+	)
+
+	db := mem.NewDatabase("mydb")
+	db.AddTable("t1", t1)
+	db.AddTable("t2", t2)
+
+	e := sqle.NewDefault()
+	e.AddDatabase(db)
+
+	_, iter, err := e.Query(newCtx(), `SELECT * FROM t1 NATURAL JOIN t2`)
+	require.NoError(err)
+
+	rows, err := sql.RowIterToRows(iter)
+	require.NoError(err)
+
+	require.Equal(
+		[]sql.Row{
+			{"a_1", "b_1", "c_1", "d_1"},
+			{"a_2", "b_2", "c_2", "d_2"},
+			{"a_3", "b_3", "c_3", "d_3"},
+		},
+		rows,
+	)
+}
+
+func TestNaturalJoinEqual(t *testing.T) {
+	require := require.New(t)
+
+	t1 := mem.NewPartitionedTable("t1", sql.Schema{
+		{Name: "a", Type: sql.Text, Source: "t1"},
+		{Name: "b", Type: sql.Text, Source: "t1"},
+		{Name: "c", Type: sql.Text, Source: "t1"},
+	}, testNumPartitions)
+
+	insertRows(
+		t, t1,
+		sql.NewRow("a_1", "b_1", "c_1"),
+		sql.NewRow("a_2", "b_2", "c_2"),
+		sql.NewRow("a_3", "b_3", "c_3"),
 	)
 
 	t2 := mem.NewPartitionedTable("t2", sql.Schema{
@@ -1464,6 +2440,7 @@ func newEngineWithParallelism(t *testing.T, parallelism int) *sqle.Engine {
 	catalog.AddDatabase(db)
 	catalog.AddDatabase(db2)
 	catalog.AddDatabase(sql.NewInformationSchemaDatabase(catalog))
+	catalog.DatabaseProvider = mem.NewDatabaseProvider()
 
 	var a *analyzer.Analyzer
 	if parallelism > 1 {
@@ -1632,6 +2609,64 @@ func TestIndexes(t *testing.T) {
 	}
 }
 
+func TestDeleteAndUpdateWithIndexes(t *testing.T) {
+	require := require.New(t)
+	e := newEngine(t)
+
+	tmpDir, err := ioutil.TempDir(os.TempDir(), "pilosa-test")
+	require.NoError(err)
+
+	require.NoError(os.MkdirAll(tmpDir, 0644))
+	e.Catalog.RegisterIndexDriver(pilosa.NewDriver(tmpDir))
+
+	testQuery(t, e, "CREATE INDEX myidx ON mytable USING pilosa (i) WITH (async = false)", []sql.Row(nil))
+	idx := e.Catalog.Index("mydb", "myidx")
+	require.NotNil(idx)
+	e.Catalog.ReleaseIndex(idx)
+
+	// The deleted row moves the position of the next ones.
+	testQuery(t, e, "DELETE FROM mytable WHERE i = 1", []sql.Row{{int64(1)}})
+	require.False(e.Catalog.CanUseIndex(idx))
+	testQuery(t, e, "SELECT s FROM mytable WHERE i = 3", []sql.Row{{"third row"}})
+
+	testQuery(t, e, "DROP INDEX myidx ON mytable", []sql.Row(nil))
+	testQuery(t, e, "CREATE INDEX myidx ON mytable USING pilosa (i) WITH (async = false)", []sql.Row(nil))
+	idx = e.Catalog.Index("mydb", "myidx")
+	require.NotNil(idx)
+	e.Catalog.ReleaseIndex(idx)
+
+	testQuery(t, e, "UPDATE mytable SET i = 4 WHERE i = 3", []sql.Row{{int64(1)}})
+	require.False(e.Catalog.CanUseIndex(idx))
+	testQuery(t, e, "SELECT s FROM mytable WHERE i = 3", []sql.Row(nil))
+	testQuery(t, e, "SELECT s FROM mytable WHERE i = 4", []sql.Row{{"third row"}})
+}
+
+func TestTruncateAndRenameTableWithIndexes(t *testing.T) {
+	require := require.New(t)
+	e := newEngine(t)
+
+	tmpDir, err := ioutil.TempDir(os.TempDir(), "pilosa-test")
+	require.NoError(err)
+
+	require.NoError(os.MkdirAll(tmpDir, 0644))
+	e.Catalog.RegisterIndexDriver(pilosa.NewDriver(tmpDir))
+
+	testQuery(t, e, "CREATE INDEX myidx ON mytable USING pilosa (i) WITH (async = false)", []sql.Row(nil))
+	testQuery(t, e, "CREATE INDEX otheridx ON othertable USING pilosa (i2) WITH (async = false)", []sql.Row(nil))
+
+	testQuery(t, e, "TRUNCATE TABLE mytable", []sql.Row(nil))
+	require.Empty(e.Catalog.IndexesByTable("mydb", "mytable"))
+	testQuery(t, e, "SELECT s FROM mytable WHERE i = 2", []sql.Row(nil))
+
+	testQuery(t, e, "INSERT INTO mytable (i, s) VALUES (2, 'new row')", []sql.Row{{int64(1)}})
+	testQuery(t, e, "SELECT s FROM mytable WHERE i = 2", []sql.Row{{"new row"}})
+
+	testQuery(t, e, "RENAME TABLE othertable TO renamed", []sql.Row(nil))
+	require.Empty(e.Catalog.IndexesByTable("mydb", "othertable"))
+	require.Empty(e.Catalog.IndexesByTable("mydb", "renamed"))
+	testQuery(t, e, "SELECT s2 FROM renamed WHERE i2 = 2", []sql.Row{{"second"}})
+}
+
 func TestCreateIndex(t *testing.T) {
 	require := require.New(t)
 	e := newEngine(t)
@@ -1786,6 +2821,167 @@ func TestReadOnly(t *testing.T) {
 	_, _, err = e.Query(newCtx(), `INSERT INTO mytable (i, s) VALUES(42, 'yolo')`)
 	require.Error(err)
 	require.True(auth.ErrNotAuthorized.Is(err))
+
+	_, _, err = e.Query(newCtx(), `UPDATE mytable SET s = 'yolo' WHERE i = 1`)
+	require.Error(err)
+	require.True(auth.ErrNotAuthorized.Is(err))
+
+	_, _, err = e.Query(newCtx(), `DELETE FROM mytable WHERE i = 1`)
+	require.Error(err)
+	require.True(auth.ErrNotAuthorized.Is(err))
+
+	writingQueries := []string{
+		`DROP TABLE mytable`,
+		`RENAME TABLE mytable TO foo`,
+		`TRUNCATE TABLE mytable`,
+		`ALTER TABLE mytable ADD COLUMN foo TEXT`,
+		`ALTER TABLE mytable DROP COLUMN s`,
+		`ALTER TABLE mytable MODIFY COLUMN s BLOB`,
+		`CREATE DATABASE foo`,
+		`DROP DATABASE mydb`,
+	}
+
+	for _, query := range writingQueries {
+		_, _, err = e.Query(newCtx(), query)
+		require.Error(err)
+		require.True(auth.ErrNotAuthorized.Is(err))
+	}
+}
+
+func TestTransactions(t *testing.T) {
+	e := newEngine(t)
+	session := sql.NewBaseSession()
+	ctx := func() *sql.Context {
+		return sql.NewContext(
+			context.Background(),
+			sql.WithPid(atomic.AddUint64(&pid, 1)),
+			sql.WithSession(session),
+		)
+	}
+
+	all := []sql.Row{{int64(1)}, {int64(2)}, {int64(3)}}
+
+	testQueryWithContext(ctx(), t, e, "BEGIN", []sql.Row(nil))
+	testQueryWithContext(ctx(), t, e, "INSERT INTO mytable (i, s) VALUES (4, 'fourth row')", []sql.Row{{int64(1)}})
+	testQueryWithContext(ctx(), t, e, "DELETE FROM mytable WHERE i = 1", []sql.Row{{int64(1)}})
+	testQueryWithContext(ctx(), t, e, "SELECT i FROM mytable", []sql.Row{{int64(2)}, {int64(3)}, {int64(4)}})
+	testQueryWithContext(ctx(), t, e, "ROLLBACK", []sql.Row(nil))
+	testQueryWithContext(ctx(), t, e, "SELECT i FROM mytable", all)
+
+	testQueryWithContext(ctx(), t, e, "START TRANSACTION", []sql.Row(nil))
+	testQueryWithContext(ctx(), t, e, "DELETE FROM mytable WHERE i = 3", []sql.Row{{int64(1)}})
+	testQueryWithContext(ctx(), t, e, "COMMIT", []sql.Row(nil))
+	testQueryWithContext(ctx(), t, e, "ROLLBACK", []sql.Row(nil))
+	testQueryWithContext(ctx(), t, e, "SELECT i FROM mytable", []sql.Row{{int64(1)}, {int64(2)}})
+
+	testQueryWithContext(ctx(), t, e, "SET autocommit = 0", []sql.Row(nil))
+	testQueryWithContext(ctx(), t, e, "DELETE FROM mytable", []sql.Row{{int64(2)}})
+	testQueryWithContext(ctx(), t, e, "ROLLBACK", []sql.Row(nil))
+	testQueryWithContext(ctx(), t, e, "SELECT i FROM mytable", []sql.Row{{int64(1)}, {int64(2)}})
+
+	testQueryWithContext(ctx(), t, e, "DELETE FROM mytable WHERE i = 2", []sql.Row{{int64(1)}})
+	testQueryWithContext(ctx(), t, e, "SET autocommit = 1", []sql.Row(nil))
+	testQueryWithContext(ctx(), t, e, "ROLLBACK", []sql.Row(nil))
+	testQueryWithContext(ctx(), t, e, "SELECT i FROM mytable", []sql.Row{{int64(1)}})
+}
+
+func TestConcurrentTransactions(t *testing.T) {
+	e := newEngine(t)
+	sessionA, sessionB := sql.NewBaseSession(), sql.NewBaseSession()
+	ctx := func(session sql.Session) *sql.Context {
+		return sql.NewContext(
+			context.Background(),
+			sql.WithPid(atomic.AddUint64(&pid, 1)),
+			sql.WithSession(session),
+		)
+	}
+
+	testQueryWithContext(ctx(sessionA), t, e, "BEGIN", []sql.Row(nil))
+	testQueryWithContext(ctx(sessionA), t, e, "DELETE FROM mytable WHERE i = 1", []sql.Row{{int64(1)}})
+
+	testQueryWithContext(ctx(sessionB), t, e, "BEGIN", []sql.Row(nil))
+	testQueryWithContext(ctx(sessionB), t, e, "INSERT INTO mytable (i, s) VALUES (4, 'fourth row')", []sql.Row{{int64(1)}})
+	testQueryWithContext(ctx(sessionB), t, e, "UPDATE mytable SET s = 'updated' WHERE i = 2", []sql.Row{{int64(1)}})
+	testQueryWithContext(ctx(sessionB), t, e, "COMMIT", []sql.Row(nil))
+
+	testQueryWithContext(ctx(sessionA), t, e, "ROLLBACK", []sql.Row(nil))
+	testQueryWithContext(ctx(sessionA), t, e, "SELECT i, s FROM mytable ORDER BY i", []sql.Row{
+		{int64(1), "first row"},
+		{int64(2), "updated"},
+		{int64(3), "third row"},
+		{int64(4), "fourth row"},
+	})
+
+	// Statements changing the tables commit the open transaction.
+	testQueryWithContext(ctx(sessionA), t, e, "BEGIN", []sql.Row(nil))
+	testQueryWithContext(ctx(sessionA), t, e, "DELETE FROM mytable WHERE i = 4", []sql.Row{{int64(1)}})
+	testQueryWithContext(ctx(sessionA), t, e, "CREATE TABLE t_commit (a INTEGER)", []sql.Row(nil))
+	testQueryWithContext(ctx(sessionA), t, e, "ROLLBACK", []sql.Row(nil))
+	testQueryWithContext(ctx(sessionA), t, e, "SELECT i FROM mytable ORDER BY i", []sql.Row{
+		{int64(1)},
+		{int64(2)},
+		{int64(3)},
+	})
+}
+
+func TestPreparedStatements(t *testing.T) {
+	require := require.New(t)
+	e := newEngine(t)
+
+	query := "SELECT i FROM mytable WHERE i > ? ORDER BY i"
+	prepared, err := e.Prepare(newCtx(), query)
+	require.NoError(err)
+	defer e.ClosePrepared(prepared)
+
+	execute := func(n sql.Node, q string, bindings map[string]sql.Expression) ([]sql.Row, error) {
+		_, iter, err := e.QueryPrepared(newCtx(), q, n, bindings)
+		if err != nil {
+			return nil, err
+		}
+
+		return sql.RowIterToRows(iter)
+	}
+
+	rows, err := execute(prepared, query, map[string]sql.Expression{
+		"v1": expression.NewLiteral(int64(1), sql.Int64),
+	})
+	require.NoError(err)
+	require.Equal([]sql.Row{{int64(2)}, {int64(3)}}, rows)
+
+	rows, err = execute(prepared, query, map[string]sql.Expression{
+		"v1": expression.NewLiteral(int64(2), sql.Int64),
+	})
+	require.NoError(err)
+	require.Equal([]sql.Row{{int64(3)}}, rows)
+
+	_, err = execute(prepared, query, nil)
+	require.Error(err)
+	require.True(expression.ErrUnboundBindVar.Is(err))
+
+	lookup := "SELECT s FROM mytable WHERE i = ?"
+	prepared, err = e.Prepare(newCtx(), lookup)
+	require.NoError(err)
+	defer e.ClosePrepared(prepared)
+
+	rows, err = execute(prepared, lookup, map[string]sql.Expression{
+		"v1": expression.NewLiteral(int64(2), sql.Int64),
+	})
+	require.NoError(err)
+	require.Equal([]sql.Row{{"second row"}}, rows)
+
+	insert := "INSERT INTO mytable (i, s) VALUES (?, ?)"
+	prepared, err = e.Prepare(newCtx(), insert)
+	require.NoError(err)
+	defer e.ClosePrepared(prepared)
+
+	rows, err = execute(prepared, insert, map[string]sql.Expression{
+		"v1": expression.NewLiteral(int64(4), sql.Int64),
+		"v2": expression.NewLiteral("fourth row", sql.Text),
+	})
+	require.NoError(err)
+	require.Equal([]sql.Row{{int64(1)}}, rows)
+
+	testQuery(t, e, "SELECT s FROM mytable WHERE i = 4", []sql.Row{{"fourth row"}})
 }
 
 func TestSessionVariables(t *testing.T) {
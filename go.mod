@@ -49,7 +49,7 @@ require (
 	github.com/uber/jaeger-lib v2.0.0+incompatible // indirect
 	google.golang.org/grpc v1.16.0 // indirect
 	gopkg.in/src-d/go-errors.v1 v1.0.0
-	gopkg.in/src-d/go-vitess.v1 v1.4.0
+	gopkg.in/src-d/go-vitess.v1 v1.8.0
 	gopkg.in/vmihailenco/msgpack.v2 v2.9.1 // indirect
 	gopkg.in/yaml.v2 v2.2.2
 )
@@ -0,0 +1,376 @@
+package mem
+
+import (
+	"strings"
+
+	errors "gopkg.in/src-d/go-errors.v1"
+	"gopkg.in/src-d/go-mysql-server.v0/sql"
+	"gopkg.in/src-d/go-mysql-server.v0/sql/expression"
+)
+
+var errColumnInConstraint = errors.NewKind("column %s is used by constraint %s")
+
+var _ sql.ConstraintTable = (*Table)(nil)
+
+// Checks implements the sql.ConstraintTable interface.
+func (t *Table) Checks() []sql.CheckConstraint {
+	return t.checks
+}
+
+// ForeignKeys implements the sql.ConstraintTable interface.
+func (t *Table) ForeignKeys() []sql.ForeignKey {
+	return t.foreignKeys
+}
+
+// childKey is a foreign key of a table referencing another one.
+type childKey struct {
+	table *Table
+	fk    sql.ForeignKey
+}
+
+// children returns the foreign keys of the tables of the database
+// referencing the table, including the table itself.
+func (t *Table) children() []childKey {
+	if t.db == nil {
+		return nil
+	}
+
+	var keys []childKey
+	for _, table := range t.db.tables {
+		child, ok := table.(*Table)
+		if !ok {
+			continue
+		}
+
+		for _, fk := range child.foreignKeys {
+			if fk.References(t.name) {
+				keys = append(keys, childKey{child, fk})
+			}
+		}
+	}
+
+	return keys
+}
+
+// referencedTable returns the table referenced by the given foreign key,
+// or nil if it does not exist.
+func (t *Table) referencedTable(fk sql.ForeignKey) *Table {
+	if fk.References(t.name) {
+		return t
+	}
+
+	if t.db == nil {
+		return nil
+	}
+
+	for name, table := range t.db.tables {
+		if strings.EqualFold(name, fk.ReferencedTable) {
+			parent, _ := table.(*Table)
+			return parent
+		}
+	}
+
+	return nil
+}
+
+// checkConstraints returns an error if the given row violates any CHECK
+// constraint of the table or references values that don't exist in the
+// referenced table of any foreign key.
+func (t *Table) checkConstraints(ctx *sql.Context, row sql.Row) error {
+	for _, check := range t.checks {
+		if err := check.Check(ctx, row); err != nil {
+			return err
+		}
+	}
+
+	for _, fk := range t.foreignKeys {
+		values, ok := columnValues(row, t.columnIndexes(fk.Columns))
+		if !ok {
+			continue
+		}
+
+		parent := t.referencedTable(fk)
+		if parent == nil {
+			return sql.ErrForeignKeyChildViolation.New(t.name, fk.Definition())
+		}
+
+		idxs := parent.columnIndexes(fk.ReferencedColumns)
+		rows, err := parent.findRows(idxs, values)
+		if err != nil {
+			return err
+		}
+
+		if len(rows) > 0 {
+			continue
+		}
+
+		// A row may reference itself.
+		if parent == t {
+			if self, err := matches(t.schema, row, idxs, values); err != nil || self {
+				return err
+			}
+		}
+
+		return sql.ErrForeignKeyChildViolation.New(t.name, fk.Definition())
+	}
+
+	return nil
+}
+
+// checkReferencingRows returns an error if the values of the given row
+// that are referenced by foreign keys change and there are rows
+// referencing the old ones.
+func (t *Table) checkReferencingRows(ctx *sql.Context, old, new sql.Row) error {
+	for _, child := range t.children() {
+		idxs := t.columnIndexes(child.fk.ReferencedColumns)
+		values, ok := columnValues(old, idxs)
+		if !ok {
+			continue
+		}
+
+		unchanged, err := matches(t.schema, new, idxs, values)
+		if err != nil {
+			return err
+		}
+
+		if unchanged {
+			continue
+		}
+
+		rows, err := child.referencingRows(old, values)
+		if err != nil {
+			return err
+		}
+
+		if len(rows) > 0 {
+			return sql.ErrForeignKeyParentViolation.New(child.table.name, child.fk.Definition())
+		}
+	}
+
+	return nil
+}
+
+// deleteReferencingRows applies the ON DELETE action of the foreign keys
+// referencing the given row, which is going to be deleted.
+func (t *Table) deleteReferencingRows(ctx *sql.Context, row sql.Row) error {
+	for _, child := range t.children() {
+		values, ok := columnValues(row, t.columnIndexes(child.fk.ReferencedColumns))
+		if !ok {
+			continue
+		}
+
+		rows, err := child.referencingRows(row, values)
+		if err != nil {
+			return err
+		}
+
+		if len(rows) == 0 {
+			continue
+		}
+
+		switch child.fk.OnDelete {
+		case sql.ForeignKeyCascade:
+			for _, r := range rows {
+				if err := child.table.Delete(ctx, r); err != nil {
+					return err
+				}
+			}
+		case sql.ForeignKeySetNull:
+			idxs := child.table.columnIndexes(child.fk.Columns)
+			for _, r := range rows {
+				nr := r.Copy()
+				for _, idx := range idxs {
+					nr[idx] = nil
+				}
+
+				if err := child.table.Update(ctx, r, nr); err != nil {
+					return err
+				}
+			}
+		default:
+			return sql.ErrForeignKeyParentViolation.New(child.table.name, child.fk.Definition())
+		}
+	}
+
+	return nil
+}
+
+// referencingRows returns the rows of the child table referencing the
+// given values of the parent row, except the parent row itself if the
+// foreign key references its own table.
+func (c childKey) referencingRows(parent sql.Row, values sql.Row) ([]sql.Row, error) {
+	rows, err := c.table.findRows(c.table.columnIndexes(c.fk.Columns), values)
+	if err != nil {
+		return nil, err
+	}
+
+	var result []sql.Row
+	for _, r := range rows {
+		equal, err := r.Equals(parent, c.table.schema)
+		if err != nil {
+			return nil, err
+		}
+
+		if !equal {
+			result = append(result, r)
+		}
+	}
+
+	return result, nil
+}
+
+// checkNotReferenced returns an error if any other table has a foreign key
+// referencing the table.
+func (t *Table) checkNotReferenced() error {
+	for _, child := range t.children() {
+		if child.table != t {
+			return sql.ErrTableReferenced.New(t.name, child.fk.Name, child.table.name)
+		}
+	}
+
+	return nil
+}
+
+// columnConstraint returns the name of a constraint using the column with
+// the given name, if any. Columns are used by the CHECK constraints whose
+// expressions contain them, the foreign keys of the table and the foreign
+// keys referencing them from other tables.
+func (t *Table) columnConstraint(name string) (string, bool) {
+	idx := t.schema.IndexOf(name, t.name)
+	for _, check := range t.checks {
+		var used bool
+		expression.Inspect(check.Expr, func(e sql.Expression) bool {
+			if gf, ok := e.(*expression.GetField); ok && gf.Index() == idx {
+				used = true
+			}
+			return !used
+		})
+
+		if used {
+			return check.Name, true
+		}
+	}
+
+	for _, fk := range t.foreignKeys {
+		if containsColumn(fk.Columns, name) {
+			return fk.Name, true
+		}
+	}
+
+	for _, child := range t.children() {
+		if containsColumn(child.fk.ReferencedColumns, name) {
+			return child.fk.Name, true
+		}
+	}
+
+	return "", false
+}
+
+// removeCheckColumn changes the CHECK constraints of the table so they can
+// be evaluated on rows without the column at the given position, which is
+// not used by any of them.
+func (t *Table) removeCheckColumn(idx int) error {
+	var checks = make([]sql.CheckConstraint, len(t.checks))
+	for i, check := range t.checks {
+		e, err := check.Expr.TransformUp(func(e sql.Expression) (sql.Expression, error) {
+			if gf, ok := e.(*expression.GetField); ok && gf.Index() > idx {
+				return gf.WithIndex(gf.Index() - 1), nil
+			}
+			return e, nil
+		})
+		if err != nil {
+			return err
+		}
+
+		checks[i] = sql.CheckConstraint{Name: check.Name, Expr: e}
+	}
+
+	t.checks = checks
+	return nil
+}
+
+// renameReferences changes the referenced table of the foreign keys of the
+// table that reference the table with the old name.
+func (t *Table) renameReferences(oldName, newName string) {
+	var fks = make([]sql.ForeignKey, len(t.foreignKeys))
+	for i, fk := range t.foreignKeys {
+		if fk.References(oldName) {
+			fk.ReferencedTable = newName
+		}
+		fks[i] = fk
+	}
+	t.foreignKeys = fks
+}
+
+// findRows returns the rows of the table with the given values in the
+// columns at the given positions.
+func (t *Table) findRows(idxs []int, values sql.Row) ([]sql.Row, error) {
+	var rows []sql.Row
+	for _, k := range t.keys {
+		for _, r := range t.partitions[string(k)] {
+			ok, err := matches(t.schema, r, idxs, values)
+			if err != nil {
+				return nil, err
+			}
+
+			if ok {
+				rows = append(rows, r)
+			}
+		}
+	}
+
+	return rows, nil
+}
+
+// columnIndexes returns the positions in the schema of the columns with the
+// given names.
+func (t *Table) columnIndexes(names []string) []int {
+	var idxs = make([]int, len(names))
+	for i, name := range names {
+		idxs[i] = t.schema.IndexOf(name, t.name)
+	}
+	return idxs
+}
+
+// columnValues returns the values of the row at the given positions. The
+// second value is false if any of them is NULL.
+func columnValues(row sql.Row, idxs []int) (sql.Row, bool) {
+	var values = make(sql.Row, len(idxs))
+	for i, idx := range idxs {
+		if idx < 0 || row[idx] == nil {
+			return nil, false
+		}
+		values[i] = row[idx]
+	}
+	return values, true
+}
+
+// matches reports whether the row has the given values at the given
+// positions.
+func matches(schema sql.Schema, row sql.Row, idxs []int, values sql.Row) (bool, error) {
+	for i, idx := range idxs {
+		if idx < 0 || row[idx] == nil {
+			return false, nil
+		}
+
+		cmp, err := schema[idx].Type.Compare(row[idx], values[i])
+		if err != nil {
+			return false, err
+		}
+
+		if cmp != 0 {
+			return false, nil
+		}
+	}
+
+	return true, nil
+}
+
+func containsColumn(columns []string, name string) bool {
+	for _, c := range columns {
+		if strings.EqualFold(c, name) {
+			return true
+		}
+	}
+	return false
+}
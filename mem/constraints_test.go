@@ -0,0 +1,197 @@
+package mem
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"gopkg.in/src-d/go-mysql-server.v0/sql"
+	"gopkg.in/src-d/go-mysql-server.v0/sql/expression"
+)
+
+func TestTableCheckConstraints(t *testing.T) {
+	require := require.New(t)
+	ctx := sql.NewEmptyContext()
+
+	db := NewDatabase("db")
+	require.NoError(db.CreateWithConstraints("foo", sql.Schema{
+		{Name: "a", Type: sql.Int64, Source: "foo", Nullable: true},
+	}, []sql.CheckConstraint{{
+		Name: "positive",
+		Expr: expression.NewGreaterThan(
+			expression.NewGetField(0, sql.Int64, "a", true),
+			expression.NewLiteral(int64(0), sql.Int64),
+		),
+	}}, nil))
+	table := db.Tables()["foo"].(*Table)
+
+	require.NoError(table.Insert(ctx, sql.NewRow(int64(1))))
+	// NULL values don't violate the constraint.
+	require.NoError(table.Insert(ctx, sql.NewRow(nil)))
+
+	err := table.Insert(ctx, sql.NewRow(int64(0)))
+	require.Error(err)
+	require.True(sql.ErrCheckConstraintViolated.Is(err))
+
+	err = table.Update(ctx, sql.NewRow(int64(1)), sql.NewRow(int64(-1)))
+	require.Error(err)
+	require.True(sql.ErrCheckConstraintViolated.Is(err))
+
+	require.ElementsMatch([]sql.Row{{int64(1)}, {nil}}, testFlatRows(t, table))
+
+	err = table.DropColumn(ctx, "a")
+	require.Error(err)
+	require.True(errColumnInConstraint.Is(err))
+}
+
+func TestTableForeignKeys(t *testing.T) {
+	require := require.New(t)
+	ctx := sql.NewEmptyContext()
+
+	db := NewDatabase("db")
+	require.NoError(db.Create("parent", sql.Schema{
+		{Name: "id", Type: sql.Int64, Source: "parent", PrimaryKey: true},
+	}))
+
+	for _, action := range []sql.ForeignKeyAction{
+		sql.ForeignKeyRestrict,
+		sql.ForeignKeyCascade,
+		sql.ForeignKeySetNull,
+	} {
+		name := "child_" + string(action)
+		require.NoError(db.CreateWithConstraints(name, sql.Schema{
+			{Name: "a", Type: sql.Int64, Source: name},
+			{Name: "parent_id", Type: sql.Int64, Source: name, Nullable: true},
+		}, nil, []sql.ForeignKey{{
+			Name:              name + "_fk",
+			Columns:           []string{"parent_id"},
+			ReferencedTable:   "parent",
+			ReferencedColumns: []string{"id"},
+			OnDelete:          action,
+		}}))
+	}
+
+	tables := db.Tables()
+	parent := tables["parent"].(*Table)
+	restrict := tables["child_RESTRICT"].(*Table)
+	cascade := tables["child_CASCADE"].(*Table)
+	setNull := tables["child_SET NULL"].(*Table)
+
+	require.NoError(parent.Insert(ctx, sql.NewRow(int64(1))))
+	require.NoError(parent.Insert(ctx, sql.NewRow(int64(2))))
+	require.NoError(parent.Insert(ctx, sql.NewRow(int64(3))))
+
+	err := cascade.Insert(ctx, sql.NewRow(int64(1), int64(4)))
+	require.Error(err)
+	require.True(sql.ErrForeignKeyChildViolation.Is(err))
+
+	require.NoError(restrict.Insert(ctx, sql.NewRow(int64(1), int64(1))))
+	require.NoError(restrict.Insert(ctx, sql.NewRow(int64(2), nil)))
+	require.NoError(cascade.Insert(ctx, sql.NewRow(int64(1), int64(2))))
+	require.NoError(cascade.Insert(ctx, sql.NewRow(int64(2), int64(2))))
+	require.NoError(setNull.Insert(ctx, sql.NewRow(int64(1), int64(3))))
+
+	err = parent.Delete(ctx, sql.NewRow(int64(1)))
+	require.Error(err)
+	require.True(sql.ErrForeignKeyParentViolation.Is(err))
+
+	err = parent.Update(ctx, sql.NewRow(int64(1)), sql.NewRow(int64(5)))
+	require.Error(err)
+	require.True(sql.ErrForeignKeyParentViolation.Is(err))
+
+	require.NoError(parent.Delete(ctx, sql.NewRow(int64(2))))
+	require.Empty(testFlatRows(t, cascade))
+
+	require.NoError(parent.Delete(ctx, sql.NewRow(int64(3))))
+	require.Equal([]sql.Row{{int64(1), nil}}, testFlatRows(t, setNull))
+
+	require.Equal([]sql.Row{{int64(1)}}, testFlatRows(t, parent))
+
+	err = parent.Truncate(ctx)
+	require.Error(err)
+	require.True(sql.ErrTableReferenced.Is(err))
+
+	require.NoError(db.RenameTable("parent", "parent2"))
+	require.Equal("parent2", restrict.ForeignKeys()[0].ReferencedTable)
+}
+
+func TestTableForeignKeysFailedCascade(t *testing.T) {
+	require := require.New(t)
+	ctx := sql.NewEmptyContext()
+
+	db := NewDatabase("db")
+	require.NoError(db.Create("parent", sql.Schema{
+		{Name: "id", Type: sql.Int64, Source: "parent", PrimaryKey: true},
+	}))
+	require.NoError(db.CreateWithConstraints("child", sql.Schema{
+		{Name: "id", Type: sql.Int64, Source: "child", PrimaryKey: true},
+		{Name: "parent_id", Type: sql.Int64, Source: "child", Nullable: true},
+	}, nil, []sql.ForeignKey{{
+		Name:              "child_fk",
+		Columns:           []string{"parent_id"},
+		ReferencedTable:   "parent",
+		ReferencedColumns: []string{"id"},
+		OnDelete:          sql.ForeignKeyCascade,
+	}}))
+	require.NoError(db.CreateWithConstraints("nullable", sql.Schema{
+		{Name: "parent_id", Type: sql.Int64, Source: "nullable", Nullable: true},
+	}, nil, []sql.ForeignKey{{
+		Name:              "nullable_fk",
+		Columns:           []string{"parent_id"},
+		ReferencedTable:   "parent",
+		ReferencedColumns: []string{"id"},
+		OnDelete:          sql.ForeignKeySetNull,
+	}}))
+	require.NoError(db.CreateWithConstraints("grandchild", sql.Schema{
+		{Name: "child_id", Type: sql.Int64, Source: "grandchild", Nullable: true},
+	}, nil, []sql.ForeignKey{{
+		Name:              "grandchild_fk",
+		Columns:           []string{"child_id"},
+		ReferencedTable:   "child",
+		ReferencedColumns: []string{"id"},
+		OnDelete:          sql.ForeignKeyRestrict,
+	}}))
+
+	tables := db.Tables()
+	parent := tables["parent"].(*Table)
+	child := tables["child"].(*Table)
+	nullable := tables["nullable"].(*Table)
+	grandchild := tables["grandchild"].(*Table)
+
+	require.NoError(parent.Insert(ctx, sql.NewRow(int64(1))))
+	require.NoError(child.Insert(ctx, sql.NewRow(int64(1), int64(1))))
+	require.NoError(child.Insert(ctx, sql.NewRow(int64(2), int64(1))))
+	require.NoError(nullable.Insert(ctx, sql.NewRow(int64(1))))
+	require.NoError(grandchild.Insert(ctx, sql.NewRow(int64(2))))
+
+	// There is no transaction, but the rows already deleted or updated by
+	// the cascade must be restored when the grandchild restricts it.
+	err := parent.Delete(ctx, sql.NewRow(int64(1)))
+	require.Error(err)
+	require.True(sql.ErrForeignKeyParentViolation.Is(err))
+
+	require.Equal([]sql.Row{{int64(1)}}, testFlatRows(t, parent))
+	require.ElementsMatch(
+		[]sql.Row{{int64(1), int64(1)}, {int64(2), int64(1)}},
+		testFlatRows(t, child),
+	)
+	require.Equal([]sql.Row{{int64(1)}}, testFlatRows(t, nullable))
+	require.Empty(db.scopes)
+
+	// Changes of a successful cascade are still undone by a rollback.
+	tx, err := db.BeginTransaction(ctx)
+	require.NoError(err)
+	require.NoError(grandchild.Delete(ctx, sql.NewRow(int64(2))))
+	require.NoError(parent.Delete(ctx, sql.NewRow(int64(1))))
+	require.Empty(testFlatRows(t, parent))
+	require.Empty(testFlatRows(t, child))
+	require.Equal([]sql.Row{{nil}}, testFlatRows(t, nullable))
+
+	require.NoError(tx.Rollback(ctx))
+	require.Equal([]sql.Row{{int64(1)}}, testFlatRows(t, parent))
+	require.ElementsMatch(
+		[]sql.Row{{int64(1), int64(1)}, {int64(2), int64(1)}},
+		testFlatRows(t, child),
+	)
+	require.Equal([]sql.Row{{int64(1)}}, testFlatRows(t, nullable))
+	require.Equal([]sql.Row{{int64(2)}}, testFlatRows(t, grandchild))
+}
@@ -1,6 +1,8 @@
 package mem // import "gopkg.in/src-d/go-mysql-server.v0/mem"
 
 import (
+	"sync"
+
 	"gopkg.in/src-d/go-mysql-server.v0/sql"
 )
 
@@ -8,13 +10,27 @@ import (
 type Database struct {
 	name   string
 	tables map[string]sql.Table
+
+	mu sync.Mutex
+	// transactions are the transactions open in each session.
+	transactions map[sql.Session]*transaction
+	// scopes are the innermost undo scopes open in each session.
+	scopes map[sql.Session]*undoScope
 }
 
+var _ sql.Database = (*Database)(nil)
+var _ sql.Alterable = (*Database)(nil)
+var _ sql.ConstraintAlterable = (*Database)(nil)
+var _ sql.TableDropper = (*Database)(nil)
+var _ sql.TableRenamer = (*Database)(nil)
+
 // NewDatabase creates a new database with the given name.
 func NewDatabase(name string) *Database {
 	return &Database{
-		name:   name,
-		tables: map[string]sql.Table{},
+		name:         name,
+		tables:       map[string]sql.Table{},
+		transactions: map[sql.Session]*transaction{},
+		scopes:       map[sql.Session]*undoScope{},
 	}
 }
 
@@ -30,16 +46,71 @@ func (d *Database) Tables() map[string]sql.Table {
 
 // AddTable adds a new table to the database.
 func (d *Database) AddTable(name string, t sql.Table) {
+	if table, ok := t.(*Table); ok {
+		table.db = d
+	}
 	d.tables[name] = t
 }
 
 // Create creates a table with the given name and schema
 func (d *Database) Create(name string, schema sql.Schema) error {
+	return d.CreateWithConstraints(name, schema, nil, nil)
+}
+
+// CreateWithConstraints creates a table with the given name, schema and
+// constraints, which are enforced by the table.
+func (d *Database) CreateWithConstraints(
+	name string,
+	schema sql.Schema,
+	checks []sql.CheckConstraint,
+	foreignKeys []sql.ForeignKey,
+) error {
 	_, ok := d.tables[name]
 	if ok {
 		return sql.ErrTableAlreadyExists.New(name)
 	}
 
-	d.tables[name] = NewTable(name, schema)
+	table := NewTable(name, schema)
+	table.checks = checks
+	table.foreignKeys = foreignKeys
+	d.AddTable(name, table)
+	return nil
+}
+
+// DropTable drops the table with the given name.
+func (d *Database) DropTable(name string) error {
+	if _, ok := d.tables[name]; !ok {
+		return sql.ErrTableNotFound.New(name)
+	}
+
+	delete(d.tables, name)
+	return nil
+}
+
+// RenameTable renames the table with the given old name.
+func (d *Database) RenameTable(oldName, newName string) error {
+	t, ok := d.tables[oldName]
+	if !ok {
+		return sql.ErrTableNotFound.New(oldName)
+	}
+
+	if _, ok := d.tables[newName]; ok {
+		return sql.ErrTableAlreadyExists.New(newName)
+	}
+
+	if table, ok := t.(*Table); ok {
+		table.rename(newName)
+	}
+
+	// Foreign keys reference tables by name, so they must reference the
+	// new one.
+	for _, other := range d.tables {
+		if table, ok := other.(*Table); ok {
+			table.renameReferences(oldName, newName)
+		}
+	}
+
+	delete(d.tables, oldName)
+	d.tables[newName] = t
 	return nil
 }
@@ -1,6 +1,7 @@
 package mem
 
 import (
+	"context"
 	"testing"
 
 	"github.com/stretchr/testify/require"
@@ -32,3 +33,134 @@ func TestDatabase_AddTable(t *testing.T) {
 	err = altDb.Create("test_table", nil)
 	require.Error(err)
 }
+
+func TestDatabase_DropTable(t *testing.T) {
+	require := require.New(t)
+	db := NewDatabase("test")
+	require.NoError(db.Create("test_table", nil))
+
+	require.NoError(db.DropTable("test_table"))
+	require.Equal(0, len(db.Tables()))
+
+	err := db.DropTable("test_table")
+	require.Error(err)
+	require.True(sql.ErrTableNotFound.Is(err))
+}
+
+func TestDatabase_RenameTable(t *testing.T) {
+	require := require.New(t)
+	db := NewDatabase("test")
+	require.NoError(db.Create("foo", sql.Schema{
+		{Name: "a", Type: sql.Int64, Source: "foo"},
+	}))
+	require.NoError(db.Create("bar", nil))
+
+	err := db.RenameTable("foo", "bar")
+	require.Error(err)
+	require.True(sql.ErrTableAlreadyExists.Is(err))
+
+	require.NoError(db.RenameTable("foo", "baz"))
+
+	tables := db.Tables()
+	_, ok := tables["foo"]
+	require.False(ok)
+
+	table, ok := tables["baz"]
+	require.True(ok)
+	require.Equal("baz", table.Name())
+	require.Equal("baz", table.Schema()[0].Source)
+
+	err = db.RenameTable("foo", "qux")
+	require.Error(err)
+	require.True(sql.ErrTableNotFound.Is(err))
+}
+
+func TestDatabase_Transaction(t *testing.T) {
+	require := require.New(t)
+	ctx := sql.NewEmptyContext()
+
+	db := NewDatabase("test")
+	table := NewPartitionedTable("foo", sql.Schema{
+		{Name: "a", Type: sql.Int64, Source: "foo", PrimaryKey: true},
+	}, 2)
+	require.NoError(table.Insert(ctx, sql.NewRow(int64(1))))
+	require.NoError(table.Insert(ctx, sql.NewRow(int64(2))))
+	db.AddTable("foo", table)
+
+	tx, err := db.BeginTransaction(ctx)
+	require.NoError(err)
+
+	require.NoError(table.Insert(ctx, sql.NewRow(int64(3))))
+	require.NoError(table.Delete(ctx, sql.NewRow(int64(1))))
+	require.NoError(table.Update(ctx, sql.NewRow(int64(2)), sql.NewRow(int64(4))))
+	require.NoError(table.Insert(ctx, sql.NewRow(int64(5))))
+	require.NoError(table.Update(ctx, sql.NewRow(int64(5)), sql.NewRow(int64(6))))
+	require.NoError(db.Create("bar", nil))
+
+	require.NoError(tx.Rollback(ctx))
+
+	// Changes of the tables themselves are not part of the transaction.
+	require.Len(db.Tables(), 2)
+	require.ElementsMatch([]sql.Row{
+		{int64(1)},
+		{int64(2)},
+	}, testFlatRows(t, table))
+
+	// The keys of the rows are restored too.
+	err = table.Insert(ctx, sql.NewRow(int64(1)))
+	require.Error(err)
+	require.True(sql.ErrDuplicateEntry.Is(err))
+
+	tx, err = db.BeginTransaction(ctx)
+	require.NoError(err)
+
+	require.NoError(table.Insert(ctx, sql.NewRow(int64(3))))
+	require.NoError(tx.Commit(ctx))
+	require.NoError(tx.Rollback(ctx))
+
+	require.ElementsMatch([]sql.Row{
+		{int64(1)},
+		{int64(2)},
+		{int64(3)},
+	}, testFlatRows(t, table))
+}
+
+func TestDatabase_ConcurrentTransactions(t *testing.T) {
+	require := require.New(t)
+	ctxA := sql.NewContext(context.Background(), sql.WithSession(sql.NewBaseSession()))
+	ctxB := sql.NewContext(context.Background(), sql.WithSession(sql.NewBaseSession()))
+
+	db := NewDatabase("test")
+	table := NewTable("foo", sql.Schema{
+		{Name: "a", Type: sql.Int64, Source: "foo"},
+	})
+	require.NoError(table.Insert(ctxA, sql.NewRow(int64(1))))
+	require.NoError(table.Insert(ctxA, sql.NewRow(int64(2))))
+	db.AddTable("foo", table)
+
+	txA, err := db.BeginTransaction(ctxA)
+	require.NoError(err)
+	require.NoError(table.Insert(ctxA, sql.NewRow(int64(3))))
+	require.NoError(table.Delete(ctxA, sql.NewRow(int64(1))))
+
+	txB, err := db.BeginTransaction(ctxB)
+	require.NoError(err)
+	require.NoError(table.Insert(ctxB, sql.NewRow(int64(4))))
+	require.NoError(table.Update(ctxB, sql.NewRow(int64(2)), sql.NewRow(int64(5))))
+	require.NoError(txB.Commit(ctxB))
+
+	// Rows changed without a transaction are not undone either.
+	require.NoError(table.Insert(ctxB, sql.NewRow(int64(6))))
+	require.NoError(db.Create("bar", nil))
+	require.NoError(db.DropTable("bar"))
+
+	require.NoError(txA.Rollback(ctxA))
+
+	require.Len(db.Tables(), 1)
+	require.ElementsMatch([]sql.Row{
+		{int64(1)},
+		{int64(4)},
+		{int64(5)},
+		{int64(6)},
+	}, testFlatRows(t, table))
+}
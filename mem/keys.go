@@ -0,0 +1,128 @@
+package mem
+
+import "gopkg.in/src-d/go-mysql-server.v0/sql"
+
+// keyIndex keeps the rows of a table by the hash of their values in the
+// columns of one of its keys, so the row with the same values as another
+// one is found without scanning the whole table. Rows with NULL in any of
+// the columns of the key are not kept, as they can't be duplicated.
+type keyIndex struct {
+	key    sql.Key
+	schema sql.Schema
+	rows   map[uint64][]sql.Row
+}
+
+// newKeyIndexes returns the indexes of the keys of the given schema with
+// the given rows, or the duplicate error of the first key two of the rows
+// have the same values in.
+func newKeyIndexes(schema sql.Schema, partitions map[string][]sql.Row) ([]*keyIndex, error) {
+	var indexes []*keyIndex
+	for _, key := range sql.SchemaKeys(schema) {
+		idx := &keyIndex{key: key, schema: schema, rows: make(map[uint64][]sql.Row)}
+		for _, rows := range partitions {
+			for _, row := range rows {
+				dup, err := idx.find(row)
+				if err != nil {
+					return nil, err
+				}
+
+				if dup != nil {
+					return nil, key.DuplicateError(row)
+				}
+
+				if err := idx.add(row); err != nil {
+					return nil, err
+				}
+			}
+		}
+
+		indexes = append(indexes, idx)
+	}
+
+	return indexes, nil
+}
+
+// hash returns the hash of the values of the given row in the columns of
+// the key, or false if any of them is NULL. Values equal for the type of
+// their column have the same hash.
+func (k *keyIndex) hash(row sql.Row) (uint64, bool, error) {
+	types := make([]sql.Type, len(k.key.Columns))
+	vals := make([]interface{}, len(k.key.Columns))
+	for i, idx := range k.key.Columns {
+		if row[idx] == nil {
+			return 0, false, nil
+		}
+
+		v, err := k.schema[idx].Type.Convert(row[idx])
+		if err != nil {
+			return 0, false, err
+		}
+
+		types[i] = k.schema[idx].Type
+		vals[i] = v
+	}
+
+	return sql.HashValues(types, vals), true, nil
+}
+
+// find returns the row with the same values in the columns of the key as
+// the given one, or nil if there is none.
+func (k *keyIndex) find(row sql.Row) (sql.Row, error) {
+	h, ok, err := k.hash(row)
+	if err != nil || !ok {
+		return nil, err
+	}
+
+	for _, r := range k.rows[h] {
+		dup, err := k.key.Duplicates(k.schema, r, row)
+		if err != nil {
+			return nil, err
+		}
+
+		if dup {
+			return r, nil
+		}
+	}
+
+	return nil, nil
+}
+
+// add adds the given row to the index.
+func (k *keyIndex) add(row sql.Row) error {
+	h, ok, err := k.hash(row)
+	if err != nil || !ok {
+		return err
+	}
+
+	k.rows[h] = append(k.rows[h], row)
+	return nil
+}
+
+// remove removes the row with the same values in the columns of the key as
+// the given one from the index.
+func (k *keyIndex) remove(row sql.Row) error {
+	h, ok, err := k.hash(row)
+	if err != nil || !ok {
+		return err
+	}
+
+	rows := k.rows[h]
+	for i, r := range rows {
+		dup, err := k.key.Duplicates(k.schema, r, row)
+		if err != nil {
+			return err
+		}
+
+		if dup {
+			rows = append(rows[:i:i], rows[i+1:]...)
+			break
+		}
+	}
+
+	if len(rows) == 0 {
+		delete(k.rows, h)
+	} else {
+		k.rows[h] = rows
+	}
+	return nil
+}
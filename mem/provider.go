@@ -0,0 +1,24 @@
+package mem
+
+import "gopkg.in/src-d/go-mysql-server.v0/sql"
+
+// DatabaseProvider is a database provider that creates in-memory databases.
+type DatabaseProvider struct{}
+
+var _ sql.DatabaseProvider = (*DatabaseProvider)(nil)
+
+// NewDatabaseProvider creates a new in-memory database provider.
+func NewDatabaseProvider() *DatabaseProvider {
+	return new(DatabaseProvider)
+}
+
+// CreateDatabase implements the sql.DatabaseProvider interface.
+func (p *DatabaseProvider) CreateDatabase(ctx *sql.Context, name string) (sql.Database, error) {
+	return NewDatabase(name), nil
+}
+
+// DropDatabase implements the sql.DatabaseProvider interface. In-memory
+// databases have nothing to clean up, so it does nothing.
+func (p *DatabaseProvider) DropDatabase(ctx *sql.Context, db sql.Database) error {
+	return nil
+}
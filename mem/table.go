@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"io"
 	"strconv"
+	"strings"
 
 	errors "gopkg.in/src-d/go-errors.v1"
 	"gopkg.in/src-d/go-mysql-server.v0/sql"
@@ -18,8 +19,19 @@ type Table struct {
 	schema     sql.Schema
 	partitions map[string][]sql.Row
 	keys       [][]byte
+	// keyIndexes are the indexes of the primary and unique keys of the
+	// table, used to find duplicated rows.
+	keyIndexes []*keyIndex
 
 	insert int
+	// autoIncrement is the greatest value the AUTO_INCREMENT column had.
+	autoIncrement uint64
+
+	// db is the database of the table, used to find the tables referenced
+	// by foreign keys and the ones referencing the table.
+	db          *Database
+	checks      []sql.CheckConstraint
+	foreignKeys []sql.ForeignKey
 
 	filters    []sql.Expression
 	projection []string
@@ -29,6 +41,12 @@ type Table struct {
 
 var _ sql.Table = (*Table)(nil)
 var _ sql.Inserter = (*Table)(nil)
+var _ sql.PrimaryKeyTable = (*Table)(nil)
+var _ sql.AutoIncrementTable = (*Table)(nil)
+var _ sql.Updater = (*Table)(nil)
+var _ sql.Deleter = (*Table)(nil)
+var _ sql.Truncater = (*Table)(nil)
+var _ sql.TableAlterer = (*Table)(nil)
 var _ sql.FilteredTable = (*Table)(nil)
 var _ sql.ProjectedTable = (*Table)(nil)
 var _ sql.IndexableTable = (*Table)(nil)
@@ -53,11 +71,15 @@ func NewPartitionedTable(name string, schema sql.Schema, numPartitions int) *Tab
 		partitions[key] = []sql.Row{}
 	}
 
+	// The table has no rows yet, so there can't be duplicated ones.
+	keyIndexes, _ := newKeyIndexes(schema, partitions)
+
 	return &Table{
 		name:       name,
 		schema:     schema,
 		partitions: partitions,
 		keys:       keys,
+		keyIndexes: keyIndexes,
 	}
 }
 
@@ -247,16 +269,395 @@ func (t *Table) Insert(ctx *sql.Context, row sql.Row) error {
 		return err
 	}
 
+	if err := t.checkKeys(row, nil); err != nil {
+		return err
+	}
+
+	if err := t.checkConstraints(ctx, row); err != nil {
+		return err
+	}
+
 	key := string(t.keys[t.insert])
 	t.insert++
 	if t.insert == len(t.keys) {
 		t.insert = 0
 	}
 
+	if err := t.indexRow(row); err != nil {
+		return err
+	}
+
 	t.partitions[key] = append(t.partitions[key], row)
+	t.updateAutoIncrement(row)
+	t.logUndo(ctx, func() { t.replaceRow(key, row, nil) })
+	return nil
+}
+
+// NextAutoIncrementValue implements the sql.AutoIncrementTable interface.
+// Values are the smallest ones greater than the last value of the column
+// that are 1 plus a multiple of the increment.
+func (t *Table) NextAutoIncrementValue(ctx *sql.Context, increment uint64) (uint64, error) {
+	if t.autoIncrementColumn() < 0 {
+		return 0, errNoAutoIncrementColumn.New(t.name)
+	}
+
+	if increment == 0 {
+		increment = 1
+	}
+
+	next := uint64(1)
+	if t.autoIncrement > 0 {
+		next = t.autoIncrement - (t.autoIncrement-1)%increment + increment
+	}
+
+	t.autoIncrement = next
+	return next, nil
+}
+
+// autoIncrementColumn returns the position of the AUTO_INCREMENT column in
+// the schema, or -1 if the table has none.
+func (t *Table) autoIncrementColumn() int {
+	for i, col := range t.schema {
+		if col.AutoIncrement {
+			return i
+		}
+	}
+	return -1
+}
+
+// updateAutoIncrement makes the last value of the AUTO_INCREMENT column the
+// one in the given row if it's greater, so generated values are always
+// greater than the ones inserted explicitly.
+func (t *Table) updateAutoIncrement(row sql.Row) {
+	idx := t.autoIncrementColumn()
+	if idx < 0 || row[idx] == nil {
+		return
+	}
+
+	n, err := sql.Int64.Convert(row[idx])
+	if err != nil {
+		return
+	}
+
+	if v := n.(int64); v > 0 && uint64(v) > t.autoIncrement {
+		t.autoIncrement = uint64(v)
+	}
+}
+
+// Update replaces the first row of the table that is equal to the old row
+// with the new one.
+func (t *Table) Update(ctx *sql.Context, old, new sql.Row) error {
+	if err := checkRow(t.schema, new); err != nil {
+		return err
+	}
+
+	key, pos, err := t.findRow(old)
+	if err != nil {
+		return err
+	}
+
+	if err := t.checkKeys(new, old); err != nil {
+		return err
+	}
+
+	if err := t.checkConstraints(ctx, new); err != nil {
+		return err
+	}
+
+	if err := t.checkReferencingRows(ctx, old, new); err != nil {
+		return err
+	}
+
+	// Rows are copied so the iterators that are already reading the
+	// partition are not affected by the update.
+	if err := t.unindexRow(t.partitions[key][pos]); err != nil {
+		return err
+	}
+
+	if err := t.indexRow(new); err != nil {
+		return err
+	}
+
+	rows := make([]sql.Row, len(t.partitions[key]))
+	copy(rows, t.partitions[key])
+	rows[pos] = new
+	t.partitions[key] = rows
+	t.updateAutoIncrement(new)
+	t.logUndo(ctx, func() { t.replaceRow(key, new, old) })
+	return nil
+}
+
+// Keys implements the sql.PrimaryKeyTable interface. The keys are the ones
+// defined in the schema of the table.
+func (t *Table) Keys() []sql.Key {
+	return sql.SchemaKeys(t.schema)
+}
+
+// checkKeys returns an error if the given row has the same values in the
+// columns of any key as a row of the table. When a row is updated, the old
+// row is given, so the keys whose values don't change are not checked.
+func (t *Table) checkKeys(row, old sql.Row) error {
+	for _, idx := range t.keyIndexes {
+		if old != nil {
+			same, err := idx.key.Duplicates(t.schema, old, row)
+			if err != nil {
+				return err
+			}
+
+			if same {
+				continue
+			}
+		}
+
+		dup, err := idx.find(row)
+		if err != nil {
+			return err
+		}
+
+		if dup != nil {
+			return idx.key.DuplicateError(row)
+		}
+	}
+
+	return nil
+}
+
+// indexRow adds the given row to the indexes of the keys of the table.
+func (t *Table) indexRow(row sql.Row) error {
+	for _, idx := range t.keyIndexes {
+		if err := idx.add(row); err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
+// unindexRow removes the given row from the indexes of the keys of the
+// table.
+func (t *Table) unindexRow(row sql.Row) error {
+	for _, idx := range t.keyIndexes {
+		if err := idx.remove(row); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Delete removes the first row of the table that is equal to the given row.
+// The rows referencing it are deleted or updated first, as the foreign keys
+// referencing the table specify. If any of them cannot be, the changes
+// already made are undone and nothing is deleted.
+func (t *Table) Delete(ctx *sql.Context, row sql.Row) error {
+	if err := checkRow(t.schema, row); err != nil {
+		return err
+	}
+
+	if t.db == nil {
+		return t.deleteRow(ctx, row)
+	}
+
+	return t.db.atomically(ctx, func() error {
+		return t.deleteRow(ctx, row)
+	})
+}
+
+// deleteRow removes the first row of the table that is equal to the given
+// row after deleting or updating the rows referencing it.
+func (t *Table) deleteRow(ctx *sql.Context, row sql.Row) error {
+
+	if _, _, err := t.findRow(row); err != nil {
+		return err
+	}
+
+	if err := t.deleteReferencingRows(ctx, row); err != nil {
+		return err
+	}
+
+	// The rows of the table may have changed if it references itself.
+	key, pos, err := t.findRow(row)
+	if err != nil {
+		return err
+	}
+
+	rows := t.partitions[key]
+	if err := t.unindexRow(rows[pos]); err != nil {
+		return err
+	}
+
+	t.partitions[key] = append(rows[:pos:pos], rows[pos+1:]...)
+	t.logUndo(ctx, func() { t.insertRow(key, pos, row) })
+	return nil
+}
+
+func (t *Table) findRow(row sql.Row) (string, int, error) {
+	for _, k := range t.keys {
+		key := string(k)
+		for i, r := range t.partitions[key] {
+			equal, err := r.Equals(row, t.schema)
+			if err != nil {
+				return "", 0, err
+			}
+
+			if equal {
+				return key, i, nil
+			}
+		}
+	}
+
+	return "", 0, errRowNotFound.New()
+}
+
+// Truncate removes all the rows of the table. Tables referenced by other
+// tables can't be truncated.
+func (t *Table) Truncate(ctx *sql.Context) error {
+	if err := t.checkNotReferenced(); err != nil {
+		return err
+	}
+
+	for _, k := range t.keys {
+		t.partitions[string(k)] = []sql.Row{}
+	}
+	t.keyIndexes, _ = newKeyIndexes(t.schema, t.partitions)
+	t.insert = 0
+	t.autoIncrement = 0
+	return nil
+}
+
+// AddColumn adds the given column at the end of the table. Existing rows
+// get the default value of the column, or the zero value of its type if it
+// is not nullable and has no default.
+func (t *Table) AddColumn(ctx *sql.Context, column *sql.Column) error {
+	if t.schema.Contains(column.Name, t.name) {
+		return sql.ErrColumnAlreadyExists.New(column.Name, t.name)
+	}
+
+	col := *column
+	col.Source = t.name
+
+	value := col.Default
+	if value == nil && !col.Nullable {
+		value, _ = col.Type.Convert(nil)
+	}
+
+	schema := append(append(sql.Schema{}, t.schema...), &col)
+	return t.rewriteRows(schema, func(row sql.Row) (sql.Row, error) {
+		return append(row.Copy(), value), nil
+	})
+}
+
+// DropColumn removes the column with the given name from the table and
+// from all its rows. Columns used by constraints can't be dropped.
+func (t *Table) DropColumn(ctx *sql.Context, name string) error {
+	idx := t.schema.IndexOf(name, t.name)
+	if idx < 0 {
+		return sql.ErrColumnNotFound.New(name, t.name)
+	}
+
+	if constraint, ok := t.columnConstraint(name); ok {
+		return errColumnInConstraint.New(name, constraint)
+	}
+
+	schema := append(append(sql.Schema{}, t.schema[:idx]...), t.schema[idx+1:]...)
+	err := t.rewriteRows(schema, func(row sql.Row) (sql.Row, error) {
+		return append(append(sql.Row{}, row[:idx]...), row[idx+1:]...), nil
+	})
+	if err != nil {
+		return err
+	}
+
+	return t.removeCheckColumn(idx)
+}
+
+// ModifyColumn replaces the column with the given name, converting the
+// values of all rows to the type of the new column. Columns used by
+// constraints can't be modified.
+func (t *Table) ModifyColumn(ctx *sql.Context, name string, column *sql.Column) error {
+	idx := t.schema.IndexOf(name, t.name)
+	if idx < 0 {
+		return sql.ErrColumnNotFound.New(name, t.name)
+	}
+
+	if i := t.schema.IndexOf(column.Name, t.name); i >= 0 && i != idx {
+		return sql.ErrColumnAlreadyExists.New(column.Name, t.name)
+	}
+
+	if constraint, ok := t.columnConstraint(name); ok {
+		return errColumnInConstraint.New(name, constraint)
+	}
+
+	col := *column
+	col.Source = t.name
+	// The column is still part of the unique keys of several columns, as
+	// they can't be defined along with the column.
+	col.UniqueKeys = t.schema[idx].UniqueKeys
+
+	schema := append(sql.Schema{}, t.schema...)
+	schema[idx] = &col
+	return t.rewriteRows(schema, func(row sql.Row) (sql.Row, error) {
+		if row[idx] == nil {
+			return row, nil
+		}
+
+		v, err := col.Type.Convert(row[idx])
+		if err != nil {
+			return nil, err
+		}
+
+		row = row.Copy()
+		row[idx] = v
+		return row, nil
+	})
+}
+
+// rewriteRows changes the schema of the table to the given one and applies
+// the given function to all its rows. The table is left untouched if any of
+// the rows can't be rewritten or two of them end up with the same values in
+// the columns of a key.
+func (t *Table) rewriteRows(schema sql.Schema, f func(sql.Row) (sql.Row, error)) error {
+	var partitions = make(map[string][]sql.Row, len(t.partitions))
+	for key, rows := range t.partitions {
+		newRows := make([]sql.Row, len(rows))
+		for i, row := range rows {
+			newRow, err := f(row)
+			if err != nil {
+				return err
+			}
+
+			if err := checkRow(schema, newRow); err != nil {
+				return err
+			}
+
+			newRows[i] = newRow
+		}
+		partitions[key] = newRows
+	}
+
+	keyIndexes, err := newKeyIndexes(schema, partitions)
+	if err != nil {
+		return err
+	}
+
+	t.schema = schema
+	t.partitions = partitions
+	t.keyIndexes = keyIndexes
+	return nil
+}
+
+// rename changes the name of the table and the source of its columns.
+func (t *Table) rename(name string) {
+	schema := make(sql.Schema, len(t.schema))
+	for i, col := range t.schema {
+		c := *col
+		if strings.EqualFold(c.Source, t.name) {
+			c.Source = name
+		}
+		schema[i] = &c
+	}
+
+	t.name = name
+	t.schema = schema
+}
+
 func checkRow(schema sql.Schema, row sql.Row) error {
 	if len(row) != len(schema) {
 		return sql.ErrUnexpectedRowLength.New(len(schema), len(row))
@@ -463,6 +864,10 @@ func (i *partitionIndexKeyValueIter) Close() error {
 
 var errColumnNotFound = errors.NewKind("could not find column %s")
 
+var errRowNotFound = errors.NewKind("row not found")
+
+var errNoAutoIncrementColumn = errors.NewKind("table %s has no AUTO_INCREMENT column")
+
 type indexKeyValueIter struct {
 	key     string
 	iter    sql.RowIter
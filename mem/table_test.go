@@ -218,6 +218,261 @@ func TestTable(t *testing.T) {
 	}
 }
 
+func TestTableUpdate(t *testing.T) {
+	require := require.New(t)
+	ctx := sql.NewEmptyContext()
+
+	table := NewPartitionedTable("foo", sql.Schema{
+		{Name: "a", Type: sql.Int64, Source: "foo"},
+		{Name: "b", Type: sql.Text, Source: "foo"},
+	}, 2)
+
+	require.NoError(table.Insert(ctx, sql.NewRow(int64(1), "a")))
+	require.NoError(table.Insert(ctx, sql.NewRow(int64(2), "b")))
+	require.NoError(table.Insert(ctx, sql.NewRow(int64(3), "c")))
+
+	require.NoError(table.Update(ctx, sql.NewRow(int64(2), "b"), sql.NewRow(int64(2), "x")))
+	require.ElementsMatch([]sql.Row{
+		{int64(1), "a"},
+		{int64(2), "x"},
+		{int64(3), "c"},
+	}, testFlatRows(t, table))
+
+	err := table.Update(ctx, sql.NewRow(int64(2), "b"), sql.NewRow(int64(2), "y"))
+	require.Error(err)
+	require.True(errRowNotFound.Is(err))
+
+	err = table.Update(ctx, sql.NewRow(int64(2), "x"), sql.NewRow(int64(2)))
+	require.Error(err)
+	require.True(sql.ErrUnexpectedRowLength.Is(err))
+}
+
+func TestTableKeys(t *testing.T) {
+	require := require.New(t)
+	ctx := sql.NewEmptyContext()
+
+	table := NewPartitionedTable("foo", sql.Schema{
+		{Name: "a", Type: sql.Int64, Source: "foo", PrimaryKey: true},
+		{Name: "b", Type: sql.Text, Source: "foo", Nullable: true, Unique: true},
+	}, 2)
+
+	require.Equal([]sql.Key{
+		{Name: sql.PrimaryKeyName, Columns: []int{0}},
+		{Name: "b", Columns: []int{1}},
+	}, table.Keys())
+
+	require.NoError(table.Insert(ctx, sql.NewRow(int64(1), "a")))
+	require.NoError(table.Insert(ctx, sql.NewRow(int64(2), nil)))
+	require.NoError(table.Insert(ctx, sql.NewRow(int64(3), nil)))
+
+	err := table.Insert(ctx, sql.NewRow(int64(1), "x"))
+	require.Error(err)
+	require.True(sql.ErrDuplicateEntry.Is(err))
+	require.Equal("Duplicate entry '1' for key 'PRIMARY'", err.Error())
+
+	err = table.Insert(ctx, sql.NewRow(int64(4), "a"))
+	require.Error(err)
+	require.True(sql.ErrDuplicateEntry.Is(err))
+	require.Equal("Duplicate entry 'a' for key 'b'", err.Error())
+
+	// A row can be updated keeping the values of its keys.
+	require.NoError(table.Update(ctx, sql.NewRow(int64(1), "a"), sql.NewRow(int64(1), "y")))
+
+	err = table.Update(ctx, sql.NewRow(int64(2), nil), sql.NewRow(int64(1), nil))
+	require.Error(err)
+	require.True(sql.ErrDuplicateEntry.Is(err))
+
+	// The old values of updated and deleted rows can be used again.
+	require.NoError(table.Insert(ctx, sql.NewRow(int64(4), "a")))
+	require.NoError(table.Delete(ctx, sql.NewRow(int64(4), "a")))
+	require.NoError(table.Insert(ctx, sql.NewRow(int64(5), "a")))
+
+	require.ElementsMatch([]sql.Row{
+		{int64(1), "y"},
+		{int64(2), nil},
+		{int64(3), nil},
+		{int64(5), "a"},
+	}, testFlatRows(t, table))
+
+	require.NoError(table.Truncate(ctx))
+	require.NoError(table.Insert(ctx, sql.NewRow(int64(1), "y")))
+}
+
+func TestTableCompositeKeys(t *testing.T) {
+	require := require.New(t)
+	ctx := sql.NewEmptyContext()
+
+	table := NewPartitionedTable("foo", sql.Schema{
+		{Name: "a", Type: sql.Int64, Source: "foo", UniqueKeys: []string{"ab"}},
+		{Name: "b", Type: sql.Float64, Source: "foo", Nullable: true, UniqueKeys: []string{"ab"}},
+	}, 2)
+
+	require.Equal([]sql.Key{{Name: "ab", Columns: []int{0, 1}}}, table.Keys())
+
+	require.NoError(table.Insert(ctx, sql.NewRow(int64(1), float64(1.2))))
+	require.NoError(table.Insert(ctx, sql.NewRow(int64(1), float64(1.4))))
+	require.NoError(table.Insert(ctx, sql.NewRow(int64(2), float64(1.2))))
+	require.NoError(table.Insert(ctx, sql.NewRow(int64(1), nil)))
+	require.NoError(table.Insert(ctx, sql.NewRow(int64(1), nil)))
+
+	// Values are compared using the types of their columns.
+	err := table.Insert(ctx, sql.NewRow(int32(1), float64(1.2)))
+	require.Error(err)
+	require.True(sql.ErrDuplicateEntry.Is(err))
+	require.Equal("Duplicate entry '1-1.2' for key 'ab'", err.Error())
+
+	// Both 1.2 and 1.4 are 1 as integers, so the column can't be modified.
+	err = table.ModifyColumn(ctx, "b", &sql.Column{Name: "b", Type: sql.Int64, Nullable: true})
+	require.Error(err)
+	require.True(sql.ErrDuplicateEntry.Is(err))
+	require.Equal(sql.Float64, table.Schema()[1].Type)
+
+	require.NoError(table.Delete(ctx, sql.NewRow(int64(1), float64(1.4))))
+	require.NoError(table.ModifyColumn(ctx, "b", &sql.Column{Name: "b", Type: sql.Int64, Nullable: true}))
+	require.Equal([]sql.Key{{Name: "ab", Columns: []int{0, 1}}}, table.Keys())
+
+	err = table.Insert(ctx, sql.NewRow(int64(2), int64(1)))
+	require.Error(err)
+	require.True(sql.ErrDuplicateEntry.Is(err))
+
+	// Without b, the key would only have a, which has duplicated values.
+	err = table.DropColumn(ctx, "b")
+	require.Error(err)
+	require.True(sql.ErrDuplicateEntry.Is(err))
+	require.Len(table.Schema(), 2)
+}
+
+func TestTableAutoIncrement(t *testing.T) {
+	require := require.New(t)
+	ctx := sql.NewEmptyContext()
+
+	table := NewTable("foo", sql.Schema{
+		{Name: "a", Type: sql.Int64, Source: "foo", PrimaryKey: true, AutoIncrement: true},
+		{Name: "b", Type: sql.Text, Source: "foo"},
+	})
+
+	next, err := table.NextAutoIncrementValue(ctx, 1)
+	require.NoError(err)
+	require.Equal(uint64(1), next)
+
+	// Inserting a greater value makes generated values greater than it.
+	require.NoError(table.Insert(ctx, sql.NewRow(int64(7), "a")))
+
+	next, err = table.NextAutoIncrementValue(ctx, 1)
+	require.NoError(err)
+	require.Equal(uint64(8), next)
+
+	next, err = table.NextAutoIncrementValue(ctx, 5)
+	require.NoError(err)
+	require.Equal(uint64(11), next)
+
+	require.NoError(table.Truncate(ctx))
+
+	next, err = table.NextAutoIncrementValue(ctx, 5)
+	require.NoError(err)
+	require.Equal(uint64(1), next)
+
+	_, err = NewTable("bar", sql.Schema{
+		{Name: "a", Type: sql.Int64, Source: "bar"},
+	}).NextAutoIncrementValue(ctx, 1)
+	require.Error(err)
+}
+
+func TestTableDelete(t *testing.T) {
+	require := require.New(t)
+	ctx := sql.NewEmptyContext()
+
+	table := NewPartitionedTable("foo", sql.Schema{
+		{Name: "a", Type: sql.Int64, Source: "foo"},
+		{Name: "b", Type: sql.Text, Source: "foo"},
+	}, 2)
+
+	require.NoError(table.Insert(ctx, sql.NewRow(int64(1), "a")))
+	require.NoError(table.Insert(ctx, sql.NewRow(int64(2), "b")))
+	require.NoError(table.Insert(ctx, sql.NewRow(int64(1), "a")))
+
+	require.NoError(table.Delete(ctx, sql.NewRow(int64(1), "a")))
+	require.ElementsMatch([]sql.Row{
+		{int64(2), "b"},
+		{int64(1), "a"},
+	}, testFlatRows(t, table))
+
+	require.NoError(table.Delete(ctx, sql.NewRow(int64(2), "b")))
+	require.Equal([]sql.Row{{int64(1), "a"}}, testFlatRows(t, table))
+
+	err := table.Delete(ctx, sql.NewRow(int64(2), "b"))
+	require.Error(err)
+	require.True(errRowNotFound.Is(err))
+}
+
+func TestTableTruncate(t *testing.T) {
+	require := require.New(t)
+	ctx := sql.NewEmptyContext()
+
+	table := NewPartitionedTable("foo", sql.Schema{
+		{Name: "a", Type: sql.Int64, Source: "foo"},
+	}, 2)
+
+	require.NoError(table.Insert(ctx, sql.NewRow(int64(1))))
+	require.NoError(table.Insert(ctx, sql.NewRow(int64(2))))
+	require.NoError(table.Truncate(ctx))
+	require.Len(testFlatRows(t, table), 0)
+
+	count, err := table.PartitionCount(ctx)
+	require.NoError(err)
+	require.Equal(int64(2), count)
+}
+
+func TestTableAlterColumns(t *testing.T) {
+	require := require.New(t)
+	ctx := sql.NewEmptyContext()
+
+	table := NewTable("foo", sql.Schema{
+		{Name: "a", Type: sql.Int64, Source: "foo"},
+		{Name: "b", Type: sql.Text, Source: "foo"},
+	})
+
+	require.NoError(table.Insert(ctx, sql.NewRow(int64(1), "1")))
+	require.NoError(table.Insert(ctx, sql.NewRow(int64(2), "x")))
+
+	require.NoError(table.AddColumn(ctx, &sql.Column{Name: "c", Type: sql.Int64}))
+	require.NoError(table.AddColumn(ctx, &sql.Column{Name: "d", Type: sql.Text, Nullable: true}))
+	require.Equal([]sql.Row{
+		{int64(1), "1", int64(0), nil},
+		{int64(2), "x", int64(0), nil},
+	}, testFlatRows(t, table))
+
+	err := table.AddColumn(ctx, &sql.Column{Name: "A", Type: sql.Int64})
+	require.Error(err)
+	require.True(sql.ErrColumnAlreadyExists.Is(err))
+
+	require.NoError(table.DropColumn(ctx, "c"))
+	require.Equal([]sql.Row{
+		{int64(1), "1", nil},
+		{int64(2), "x", nil},
+	}, testFlatRows(t, table))
+
+	err = table.DropColumn(ctx, "c")
+	require.Error(err)
+	require.True(sql.ErrColumnNotFound.Is(err))
+
+	// "x" can't be converted to a number, so the table must not change.
+	err = table.ModifyColumn(ctx, "b", &sql.Column{Name: "b", Type: sql.Int64})
+	require.Error(err)
+	require.Equal(sql.Text, table.Schema()[1].Type)
+
+	require.NoError(table.ModifyColumn(ctx, "a", &sql.Column{Name: "e", Type: sql.Text}))
+	require.Equal(sql.Schema{
+		{Name: "e", Type: sql.Text, Source: "foo"},
+		{Name: "b", Type: sql.Text, Source: "foo"},
+		{Name: "d", Type: sql.Text, Nullable: true, Source: "foo"},
+	}, table.Schema())
+	require.Equal([]sql.Row{
+		{"1", "1", nil},
+		{"2", "x", nil},
+	}, testFlatRows(t, table))
+}
+
 func TestFiltered(t *testing.T) {
 	for _, test := range tests {
 		t.Run(test.name, func(t *testing.T) {
@@ -0,0 +1,172 @@
+package mem
+
+import "gopkg.in/src-d/go-mysql-server.v0/sql"
+
+var _ sql.TransactionalDatabase = (*Database)(nil)
+
+// BeginTransaction implements the sql.TransactionalDatabase interface. The
+// transaction keeps track of the rows inserted, updated and deleted in the
+// session on the tables of the database, and only those changes are undone
+// if the transaction is rolled back. Changes are made directly on the
+// tables, so the rest of sessions see them right away.
+//
+// Changes of the tables themselves, such as creating, dropping or altering
+// them, are not part of the transaction, as the statements making them
+// commit the transaction implicitly.
+func (d *Database) BeginTransaction(ctx *sql.Context) (sql.Transaction, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	tx := &transaction{db: d, session: ctx.Session}
+	d.transactions[ctx.Session] = tx
+	return tx, nil
+}
+
+// transaction returns the transaction open on the database in the session
+// of the given context, if any.
+func (d *Database) transaction(ctx *sql.Context) *transaction {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.transactions[ctx.Session]
+}
+
+// endTransaction removes the given transaction from the open ones.
+func (d *Database) endTransaction(tx *transaction) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.transactions[tx.session] == tx {
+		delete(d.transactions, tx.session)
+	}
+}
+
+// transaction is a transaction on an in-memory database.
+type transaction struct {
+	db      *Database
+	session sql.Session
+	// undo are the functions undoing the changes made in the transaction,
+	// in the order they were made.
+	undo []func()
+}
+
+// Commit implements the sql.Transaction interface. Changes are made
+// directly on the tables, so there is nothing to do but forgetting them.
+func (t *transaction) Commit(ctx *sql.Context) error {
+	t.db.endTransaction(t)
+	t.undo = nil
+	return nil
+}
+
+// Rollback implements the sql.Transaction interface. Changes are undone
+// from the last to the first.
+func (t *transaction) Rollback(ctx *sql.Context) error {
+	t.db.endTransaction(t)
+	for i := len(t.undo) - 1; i >= 0; i-- {
+		t.undo[i]()
+	}
+
+	t.undo = nil
+	return nil
+}
+
+// undoScope keeps the functions undoing the changes made by a single
+// operation, so they can be undone if the operation fails.
+type undoScope struct {
+	parent *undoScope
+	undo   []func()
+}
+
+// atomically calls f and undoes the changes it made to the tables of the
+// database in the session of the given context if it fails, even if there
+// is no transaction open. Otherwise the changes are kept by the enclosing
+// scope or by the transaction open in the session, if any.
+func (d *Database) atomically(ctx *sql.Context, f func() error) error {
+	d.mu.Lock()
+	scope := &undoScope{parent: d.scopes[ctx.Session]}
+	d.scopes[ctx.Session] = scope
+	d.mu.Unlock()
+
+	err := f()
+
+	d.mu.Lock()
+	if scope.parent != nil {
+		d.scopes[ctx.Session] = scope.parent
+	} else {
+		delete(d.scopes, ctx.Session)
+	}
+	d.mu.Unlock()
+
+	if err != nil {
+		for i := len(scope.undo) - 1; i >= 0; i-- {
+			scope.undo[i]()
+		}
+		return err
+	}
+
+	for _, undo := range scope.undo {
+		d.logUndo(ctx, undo)
+	}
+	return nil
+}
+
+// logUndo adds the given function undoing a change to the innermost scope
+// open in the session of the given context or, if there is none, to the
+// transaction open in it, if any.
+func (d *Database) logUndo(ctx *sql.Context, undo func()) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if scope := d.scopes[ctx.Session]; scope != nil {
+		scope.undo = append(scope.undo, undo)
+	} else if tx := d.transactions[ctx.Session]; tx != nil {
+		tx.undo = append(tx.undo, undo)
+	}
+}
+
+// logUndo adds the given function undoing a change of the table to the
+// database of the table, if any.
+func (t *Table) logUndo(ctx *sql.Context, undo func()) {
+	if t.db != nil {
+		t.db.logUndo(ctx, undo)
+	}
+}
+
+// replaceRow replaces the first row of the given partition that is equal to
+// the old row with the new one, or removes it if the new row is nil. Rows
+// changed by other sessions since then are not found, so nothing is done.
+// The rows were in the table, so they can always be indexed.
+func (t *Table) replaceRow(key string, old, new sql.Row) {
+	for i, r := range t.partitions[key] {
+		if equal, err := r.Equals(old, t.schema); err != nil || !equal {
+			continue
+		}
+
+		_ = t.unindexRow(r)
+		if new != nil {
+			_ = t.indexRow(new)
+		}
+
+		rows := make([]sql.Row, 0, len(t.partitions[key]))
+		rows = append(rows, t.partitions[key][:i]...)
+		if new != nil {
+			rows = append(rows, new)
+		}
+		t.partitions[key] = append(rows, t.partitions[key][i+1:]...)
+		return
+	}
+}
+
+// insertRow inserts the given row in the given partition at the given
+// position, or at the end if the partition has fewer rows now.
+func (t *Table) insertRow(key string, pos int, row sql.Row) {
+	_ = t.indexRow(row)
+	rows := t.partitions[key]
+	if pos > len(rows) {
+		pos = len(rows)
+	}
+
+	result := make([]sql.Row, 0, len(rows)+1)
+	result = append(result, rows[:pos]...)
+	result = append(result, row)
+	t.partitions[key] = append(result, rows[pos:]...)
+}
@@ -12,6 +12,7 @@ import (
 	"gopkg.in/src-d/go-mysql-server.v0"
 	"gopkg.in/src-d/go-mysql-server.v0/auth"
 	"gopkg.in/src-d/go-mysql-server.v0/sql"
+	"gopkg.in/src-d/go-mysql-server.v0/sql/expression"
 
 	"github.com/sirupsen/logrus"
 	"gopkg.in/src-d/go-vitess.v1/mysql"
@@ -23,23 +24,34 @@ var regKillCmd = regexp.MustCompile(`^kill (?:(query|connection) )?(\d+)$`)
 
 var errConnectionNotFound = errors.NewKind("Connection not found: %c")
 
+var errStatementNotFound = errors.NewKind("unknown prepared statement handler (%d) given to mysqld_stmt_execute")
+
 // TODO parametrize
 const rowsBatch = 100
 
 // Handler is a connection handler for a SQLe engine.
 type Handler struct {
-	mu sync.Mutex
-	e  *sqle.Engine
-	sm *SessionManager
-	c  map[uint32]*mysql.Conn
+	mu    sync.Mutex
+	e     *sqle.Engine
+	sm    *SessionManager
+	c     map[uint32]*mysql.Conn
+	stmts map[uint32]map[uint32]*preparedStatement
+}
+
+// preparedStatement is a query prepared in a connection along with its
+// analyzed plan.
+type preparedStatement struct {
+	query string
+	node  sql.Node
 }
 
 // NewHandler creates a new Handler given a SQLe engine.
 func NewHandler(e *sqle.Engine, sm *SessionManager) *Handler {
 	return &Handler{
-		e:  e,
-		sm: sm,
-		c:  make(map[uint32]*mysql.Conn),
+		e:     e,
+		sm:    sm,
+		c:     make(map[uint32]*mysql.Conn),
+		stmts: make(map[uint32]map[uint32]*preparedStatement),
 	}
 }
 
@@ -56,12 +68,23 @@ func (h *Handler) NewConnection(c *mysql.Conn) {
 
 // ConnectionClosed reports that a connection has been closed.
 func (h *Handler) ConnectionClosed(c *mysql.Conn) {
+	ctx := h.sm.NewContext(c)
+	if err := sql.RollbackTransaction(ctx); err != nil {
+		logrus.Errorf("unable to rollback transaction on session close: %s", err)
+	}
+
 	h.sm.CloseConn(c)
 
 	h.mu.Lock()
 	delete(h.c, c.ConnectionID)
+	stmts := h.stmts[c.ConnectionID]
+	delete(h.stmts, c.ConnectionID)
 	h.mu.Unlock()
 
+	for _, stmt := range stmts {
+		h.e.ClosePrepared(stmt.node)
+	}
+
 	if err := h.e.Catalog.UnlockTables(nil, c.ConnectionID); err != nil {
 		logrus.Errorf("unable to unlock tables on session close: %s", err)
 	}
@@ -95,9 +118,177 @@ func (h *Handler) ComQuery(
 	}()
 
 	if err != nil {
+		return toSQLError(err)
+	}
+
+	if sql.IsOkResultSchema(schema) {
+		return toSQLError(writeOkResult(ctx, rows, callback))
+	}
+
+	return toSQLError(writeResult(schema, rows, callback))
+}
+
+// ComPrepare parses and analyzes the given query, which may contain `?`
+// placeholders, and caches the result so the statement can be executed
+// later. It returns the fields of the rows the statement returns, if any.
+func (h *Handler) ComPrepare(c *mysql.Conn, query string) ([]*query.Field, error) {
+	ctx := h.sm.NewContextWithQuery(c, query)
+	node, err := h.e.Prepare(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+
+	h.mu.Lock()
+	stmts, ok := h.stmts[c.ConnectionID]
+	if !ok {
+		stmts = make(map[uint32]*preparedStatement)
+		h.stmts[c.ConnectionID] = stmts
+	}
+	old := stmts[c.StatementID]
+	stmts[c.StatementID] = &preparedStatement{query: query, node: node}
+	h.mu.Unlock()
+
+	if old != nil {
+		h.e.ClosePrepared(old.node)
+	}
+
+	schema := node.Schema()
+	if len(schema) == 0 || sql.IsOkResultSchema(schema) {
+		return nil, nil
+	}
+
+	return schemaToFields(schema), nil
+}
+
+// ComStmtExecute executes a statement prepared with ComPrepare, binding the
+// given values to its placeholders.
+func (h *Handler) ComStmtExecute(
+	c *mysql.Conn,
+	prepare *mysql.PrepareData,
+	callback func(*sqltypes.Result) error,
+) (err error) {
+	h.mu.Lock()
+	stmt, ok := h.stmts[c.ConnectionID][prepare.StatementID]
+	h.mu.Unlock()
+
+	if !ok {
+		return errStatementNotFound.New(prepare.StatementID)
+	}
+
+	bindings, err := bindVariablesToExpressions(prepare.BindVars)
+	if err != nil {
+		return err
+	}
+
+	ctx := h.sm.NewContextWithQuery(c, stmt.query)
+
+	start := time.Now()
+	schema, rows, err := h.e.QueryPrepared(ctx, stmt.query, stmt.node, bindings)
+	defer func() {
+		if q, ok := h.e.Auth.(*auth.Audit); ok {
+			q.Query(ctx, time.Since(start), err)
+		}
+	}()
+
+	if err != nil {
+		return toSQLError(err)
+	}
+
+	if sql.IsOkResultSchema(schema) {
+		return toSQLError(writeOkResult(ctx, rows, callback))
+	}
+
+	return toSQLError(writeResult(schema, rows, callback))
+}
+
+// ComStmtClose removes the statement with the given id prepared in the
+// given connection, releasing its cached plan.
+func (h *Handler) ComStmtClose(c *mysql.Conn, stmtID uint32) {
+	h.mu.Lock()
+	stmt, ok := h.stmts[c.ConnectionID][stmtID]
+	delete(h.stmts[c.ConnectionID], stmtID)
+	h.mu.Unlock()
+
+	if ok {
+		h.e.ClosePrepared(stmt.node)
+	}
+}
+
+// MySQL error codes of constraint violations that are not defined in the
+// mysql package.
+const (
+	errRowIsReferenced         = 1451
+	errNoReferencedRow         = 1452
+	errCheckConstraintViolated = 3819
+)
+
+// toSQLError returns the given error as a MySQL error with its specific
+// error code and SQL state, if it has one. Other errors are returned as
+// they are.
+func toSQLError(err error) error {
+	switch {
+	case err == nil:
+		return nil
+	case sql.ErrDuplicateEntry.Is(err):
+		return mysql.NewSQLError(mysql.ERDupEntry, mysql.SSDupKey, "%s", err)
+	case sql.ErrColumnCannotBeNull.Is(err):
+		// As duplicate entries, it's an integrity constraint violation, so
+		// they share the same SQL state.
+		return mysql.NewSQLError(mysql.ERBadNullError, mysql.SSDupKey, "%s", err)
+	case sql.ErrForeignKeyParentViolation.Is(err):
+		return mysql.NewSQLError(errRowIsReferenced, mysql.SSDupKey, "%s", err)
+	case sql.ErrForeignKeyChildViolation.Is(err):
+		return mysql.NewSQLError(errNoReferencedRow, mysql.SSDupKey, "%s", err)
+	case sql.ErrCheckConstraintViolated.Is(err):
+		return mysql.NewSQLError(errCheckConstraintViolated, mysql.SSUnknownSQLState, "%s", err)
+	default:
+		return err
+	}
+}
+
+// writeOkResult sends the result of a statement that modifies rows, such as
+// an INSERT, as an OK packet with the number of affected rows and the first
+// value generated for an AUTO_INCREMENT column by the statement, if any.
+func writeOkResult(
+	ctx *sql.Context,
+	rows sql.RowIter,
+	callback func(*sqltypes.Result) error,
+) error {
+	result := new(sqltypes.Result)
+	for {
+		row, err := rows.Next()
+		if err != nil {
+			if err == io.EOF {
+				break
+			}
+
+			_ = rows.Close()
+			return err
+		}
+
+		n, err := sql.Int64.Convert(row[0])
+		if err != nil {
+			_ = rows.Close()
+			return err
+		}
+		result.RowsAffected += uint64(n.(int64))
+	}
+
+	if err := rows.Close(); err != nil {
 		return err
 	}
 
+	result.InsertID = ctx.InsertID()
+	return callback(result)
+}
+
+// writeResult sends all the rows of the given iterator to the callback in
+// batches.
+func writeResult(
+	schema sql.Schema,
+	rows sql.RowIter,
+	callback func(*sqltypes.Result) error,
+) error {
 	var r *sqltypes.Result
 	var proccesedAtLeastOneBatch bool
 	for {
@@ -210,10 +401,74 @@ func schemaToFields(s sql.Schema) []*query.Field {
 	fields := make([]*query.Field, len(s))
 	for i, c := range s {
 		fields[i] = &query.Field{
-			Name: c.Name,
-			Type: c.Type.Type(),
+			Name:         c.Name,
+			Type:         c.Type.Type(),
+			ColumnLength: sql.ColumnLength(c.Type),
+		}
+
+		// Clients need the scale to read decimals and the fractional
+		// seconds precision to read datetimes.
+		if sql.IsFixedPoint(c.Type) {
+			_, scale, _ := sql.NumericPrecision(c.Type)
+			fields[i].Decimals = uint32(scale)
+		}
+
+		if fsp := sql.DatetimePrecision(c.Type); fsp > 0 {
+			fields[i].Decimals = uint32(fsp)
 		}
 	}
 
 	return fields
 }
+
+// bindVariablesToExpressions converts the values bound to the placeholders
+// of a prepared statement to literal expressions.
+func bindVariablesToExpressions(
+	bindVars map[string]*query.BindVariable,
+) (map[string]sql.Expression, error) {
+	var result = make(map[string]sql.Expression, len(bindVars))
+	for name, bv := range bindVars {
+		v, err := sqltypes.BindVariableToValue(bv)
+		if err != nil {
+			return nil, err
+		}
+
+		expr, err := valueToExpression(v)
+		if err != nil {
+			return nil, err
+		}
+
+		result[name] = expr
+	}
+
+	return result, nil
+}
+
+func valueToExpression(v sqltypes.Value) (sql.Expression, error) {
+	switch {
+	case v.IsNull():
+		return expression.NewLiteral(nil, sql.Null), nil
+	case v.IsSigned():
+		n, err := strconv.ParseInt(v.ToString(), 10, 64)
+		if err != nil {
+			return nil, err
+		}
+		return expression.NewLiteral(n, sql.Int64), nil
+	case v.IsUnsigned():
+		n, err := strconv.ParseUint(v.ToString(), 10, 64)
+		if err != nil {
+			return nil, err
+		}
+		return expression.NewLiteral(n, sql.Uint64), nil
+	case v.IsFloat():
+		n, err := strconv.ParseFloat(v.ToString(), 64)
+		if err != nil {
+			return nil, err
+		}
+		return expression.NewLiteral(n, sql.Float64), nil
+	case v.IsBinary():
+		return expression.NewLiteral(v.ToBytes(), sql.Blob), nil
+	default:
+		return expression.NewLiteral(v.ToString(), sql.Text), nil
+	}
+}
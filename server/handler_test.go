@@ -11,6 +11,7 @@ import (
 	"gopkg.in/src-d/go-mysql-server.v0/sql"
 	"gopkg.in/src-d/go-vitess.v1/mysql"
 	"gopkg.in/src-d/go-vitess.v1/sqltypes"
+	"gopkg.in/src-d/go-vitess.v1/vt/proto/query"
 
 	opentracing "github.com/opentracing/opentracing-go"
 	"github.com/stretchr/testify/require"
@@ -217,3 +218,136 @@ func assertNoConnProcesses(t *testing.T, e *sqle.Engine, conn uint32) {
 		}
 	}
 }
+
+func TestHandlerConnectionClosedRollback(t *testing.T) {
+	require := require.New(t)
+	e := setupMemDB(require)
+
+	handler := NewHandler(
+		e,
+		NewSessionManager(
+			func(conn *mysql.Conn, addr string) sql.Session {
+				return sql.NewBaseSession()
+			},
+			opentracing.NoopTracer{},
+			"foo",
+		),
+	)
+
+	conn1 := newConn(1)
+	handler.NewConnection(conn1)
+
+	noop := func(res *sqltypes.Result) error { return nil }
+	require.NoError(handler.ComQuery(conn1, "BEGIN", noop))
+	require.NoError(handler.ComQuery(conn1, "INSERT INTO test (c1) VALUES (2000)", noop))
+
+	handler.ConnectionClosed(conn1)
+
+	conn2 := newConn(2)
+	handler.NewConnection(conn2)
+
+	var result *sqltypes.Result
+	err := handler.ComQuery(conn2, "SELECT COUNT(*) FROM test", func(res *sqltypes.Result) error {
+		result = res
+		return nil
+	})
+	require.NoError(err)
+	require.Equal("1010", result.Rows[0][0].ToString())
+}
+
+func TestHandlerPreparedStatements(t *testing.T) {
+	require := require.New(t)
+	e := setupMemDB(require)
+
+	handler := NewHandler(
+		e,
+		NewSessionManager(
+			func(conn *mysql.Conn, addr string) sql.Session {
+				return sql.NewBaseSession()
+			},
+			opentracing.NoopTracer{},
+			"foo",
+		),
+	)
+
+	conn := newConn(1)
+	conn.StatementID = 1
+	handler.NewConnection(conn)
+
+	fields, err := handler.ComPrepare(conn, "SELECT c1 FROM test WHERE c1 < ?")
+	require.NoError(err)
+	require.Len(fields, 1)
+
+	var result *sqltypes.Result
+	err = handler.ComStmtExecute(conn, &mysql.PrepareData{
+		StatementID: 1,
+		BindVars: map[string]*query.BindVariable{
+			"v1": sqltypes.Int64BindVariable(5),
+		},
+	}, func(res *sqltypes.Result) error {
+		result = res
+		return nil
+	})
+	require.NoError(err)
+	require.Len(result.Rows, 5)
+
+	handler.ComStmtClose(conn, 1)
+
+	err = handler.ComStmtExecute(conn, &mysql.PrepareData{StatementID: 1}, func(res *sqltypes.Result) error {
+		return nil
+	})
+	require.Error(err)
+	require.True(errStatementNotFound.Is(err))
+}
+
+func TestHandlerOkResult(t *testing.T) {
+	require := require.New(t)
+	e := setupMemDB(require)
+
+	handler := NewHandler(
+		e,
+		NewSessionManager(
+			func(conn *mysql.Conn, addr string) sql.Session {
+				return sql.NewBaseSession()
+			},
+			opentracing.NoopTracer{},
+			"foo",
+		),
+	)
+
+	conn := newConn(1)
+	handler.NewConnection(conn)
+
+	noop := func(res *sqltypes.Result) error { return nil }
+	require.NoError(handler.ComQuery(
+		conn,
+		"CREATE TABLE t (id INT AUTO_INCREMENT PRIMARY KEY, name TEXT)",
+		noop,
+	))
+
+	var result *sqltypes.Result
+	err := handler.ComQuery(conn, "INSERT INTO t (name) VALUES ('a'), ('b')", func(res *sqltypes.Result) error {
+		result = res
+		return nil
+	})
+	require.NoError(err)
+	require.Empty(result.Fields)
+	require.Equal(uint64(2), result.RowsAffected)
+	require.Equal(uint64(1), result.InsertID)
+
+	// Statements that don't generate a value don't report the last one.
+	err = handler.ComQuery(conn, "UPDATE t SET name = 'c' WHERE id = 2", func(res *sqltypes.Result) error {
+		result = res
+		return nil
+	})
+	require.NoError(err)
+	require.Equal(uint64(1), result.RowsAffected)
+	require.Equal(uint64(0), result.InsertID)
+
+	err = handler.ComQuery(conn, "SELECT LAST_INSERT_ID()", func(res *sqltypes.Result) error {
+		result = res
+		return nil
+	})
+	require.NoError(err)
+	require.Equal("1", result.Rows[0][0].ToString())
+}
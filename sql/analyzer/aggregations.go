@@ -1,6 +1,8 @@
 package analyzer
 
 import (
+	"strings"
+
 	"gopkg.in/src-d/go-mysql-server.v0/sql"
 	"gopkg.in/src-d/go-mysql-server.v0/sql/expression"
 	"gopkg.in/src-d/go-mysql-server.v0/sql/plan"
@@ -32,6 +34,107 @@ func reorderAggregations(ctx *sql.Context, a *Analyzer, n sql.Node) (sql.Node, e
 	})
 }
 
+// resolveHaving moves the aggregations and columns used in a having clause
+// that are not part of the group by below it to its aggregate, so they can
+// be resolved against the child of the group by. When the aggregate needs
+// to be extended, a projection is added on top of the having to keep the
+// original schema.
+func resolveHaving(ctx *sql.Context, a *Analyzer, n sql.Node) (sql.Node, error) {
+	span, _ := ctx.Span("resolve_having")
+	defer span.Finish()
+
+	a.Log("resolve having, node of type: %T", n)
+
+	return n.TransformUp(func(n sql.Node) (sql.Node, error) {
+		having, ok := n.(*plan.Having)
+		if !ok || (having.Resolved() && !containsAggregation(having.Cond)) {
+			return n, nil
+		}
+
+		g, ok := having.Child.(*plan.GroupBy)
+		if !ok || !g.Resolved() || hasUnresolvedAggregations(having.Cond) {
+			return n, nil
+		}
+
+		a.Log("pushing down aggregations of having to the group by")
+
+		return pushHavingToGroupBy(having, g)
+	})
+}
+
+func pushHavingToGroupBy(having *plan.Having, g *plan.GroupBy) (sql.Node, error) {
+	var aggregate = make([]sql.Expression, len(g.Aggregate))
+	copy(aggregate, g.Aggregate)
+
+	cond, err := having.Cond.TransformUp(func(e sql.Expression) (sql.Expression, error) {
+		agg, ok := e.(sql.Aggregation)
+		if !ok {
+			return e, nil
+		}
+
+		for _, ae := range aggregate {
+			expr := ae
+			if alias, ok := ae.(*expression.Alias); ok {
+				expr = alias.Child
+			}
+
+			if expr.String() == agg.String() {
+				name, _ := getNameAndSource(ae)
+				return expression.NewUnresolvedColumn(name), nil
+			}
+		}
+
+		// The aggregation is aliased because its name can change once the
+		// columns it uses are resolved.
+		name := agg.String()
+		aggregate = append(aggregate, expression.NewAlias(agg, name))
+		return expression.NewUnresolvedColumn(name), nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var names = make(map[string]struct{})
+	for _, e := range aggregate {
+		name, _ := getNameAndSource(e)
+		names[strings.ToLower(name)] = struct{}{}
+	}
+
+	// Columns that are not in the aggregate are also pushed down, so they
+	// are available for the having.
+	expression.Inspect(cond, func(e sql.Expression) bool {
+		col, ok := e.(column)
+		if !ok || col.Resolved() {
+			return true
+		}
+
+		name := strings.ToLower(col.Name())
+		if _, ok := names[name]; !ok {
+			names[name] = struct{}{}
+			aggregate = append(aggregate, col)
+		}
+
+		return true
+	})
+
+	if len(aggregate) == len(g.Aggregate) {
+		return plan.NewHaving(cond, g), nil
+	}
+
+	schema := g.Schema()
+	var projection = make([]sql.Expression, len(schema))
+	for i, col := range schema {
+		projection[i] = expression.NewGetFieldWithTable(
+			i, col.Type, col.Source, col.Name, col.Nullable,
+		)
+	}
+
+	return plan.NewProject(
+		projection,
+		plan.NewHaving(cond, plan.NewGroupBy(aggregate, g.Grouping, g.Child)),
+	), nil
+}
+
 func fixAggregations(projection, grouping []sql.Expression, child sql.Node) (sql.Node, error) {
 	var aggregate = make([]sql.Expression, 0, len(projection))
 	var newProjection = make([]sql.Expression, len(projection))
@@ -117,3 +220,17 @@ func containsAggregation(e sql.Expression) bool {
 	})
 	return hasAgg
 }
+
+// hasUnresolvedAggregations reports whether the given expression contains
+// an aggregate function that has not been resolved yet.
+func hasUnresolvedAggregations(e sql.Expression) bool {
+	var hasAgg bool
+	expression.Inspect(e, func(e sql.Expression) bool {
+		if f, ok := e.(*expression.UnresolvedFunction); ok && f.IsAggregate {
+			hasAgg = true
+			return false
+		}
+		return true
+	})
+	return hasAgg
+}
@@ -126,3 +126,116 @@ func TestReorderAggregationsMultiple(t *testing.T) {
 
 	require.Equal(expected, result)
 }
+
+func TestResolveHaving(t *testing.T) {
+	table := mem.NewTable("foo", sql.Schema{
+		{Name: "a", Type: sql.Int64, Source: "foo"},
+		{Name: "b", Type: sql.Int64, Source: "foo"},
+	})
+	rule := getRule("resolve_having")
+
+	testCases := []struct {
+		name     string
+		node     sql.Node
+		expected sql.Node
+	}{
+		{
+			"aggregation in group by",
+			plan.NewHaving(
+				expression.NewGreaterThan(
+					aggregation.NewCount(expression.NewStar()),
+					expression.NewLiteral(int64(1), sql.Int64),
+				),
+				plan.NewGroupBy(
+					[]sql.Expression{
+						expression.NewGetFieldWithTable(0, sql.Int64, "foo", "a", false),
+						expression.NewAlias(aggregation.NewCount(expression.NewStar()), "c"),
+					},
+					[]sql.Expression{
+						expression.NewGetFieldWithTable(0, sql.Int64, "foo", "a", false),
+					},
+					plan.NewResolvedTable(table),
+				),
+			),
+			plan.NewHaving(
+				expression.NewGreaterThan(
+					expression.NewUnresolvedColumn("c"),
+					expression.NewLiteral(int64(1), sql.Int64),
+				),
+				plan.NewGroupBy(
+					[]sql.Expression{
+						expression.NewGetFieldWithTable(0, sql.Int64, "foo", "a", false),
+						expression.NewAlias(aggregation.NewCount(expression.NewStar()), "c"),
+					},
+					[]sql.Expression{
+						expression.NewGetFieldWithTable(0, sql.Int64, "foo", "a", false),
+					},
+					plan.NewResolvedTable(table),
+				),
+			),
+		},
+		{
+			"aggregation and column not in group by",
+			plan.NewHaving(
+				expression.NewAnd(
+					expression.NewGreaterThan(
+						aggregation.NewMax(expression.NewUnresolvedQualifiedColumn("foo", "b")),
+						expression.NewLiteral(int64(1), sql.Int64),
+					),
+					expression.NewEquals(
+						expression.NewUnresolvedQualifiedColumn("foo", "b"),
+						expression.NewLiteral(int64(1), sql.Int64),
+					),
+				),
+				plan.NewGroupBy(
+					[]sql.Expression{
+						expression.NewGetFieldWithTable(0, sql.Int64, "foo", "a", false),
+					},
+					[]sql.Expression{
+						expression.NewGetFieldWithTable(0, sql.Int64, "foo", "a", false),
+					},
+					plan.NewResolvedTable(table),
+				),
+			),
+			plan.NewProject(
+				[]sql.Expression{
+					expression.NewGetFieldWithTable(0, sql.Int64, "foo", "a", false),
+				},
+				plan.NewHaving(
+					expression.NewAnd(
+						expression.NewGreaterThan(
+							expression.NewUnresolvedColumn("MAX(foo.b)"),
+							expression.NewLiteral(int64(1), sql.Int64),
+						),
+						expression.NewEquals(
+							expression.NewUnresolvedQualifiedColumn("foo", "b"),
+							expression.NewLiteral(int64(1), sql.Int64),
+						),
+					),
+					plan.NewGroupBy(
+						[]sql.Expression{
+							expression.NewGetFieldWithTable(0, sql.Int64, "foo", "a", false),
+							expression.NewAlias(
+								aggregation.NewMax(expression.NewUnresolvedQualifiedColumn("foo", "b")),
+								"MAX(foo.b)",
+							),
+							expression.NewUnresolvedQualifiedColumn("foo", "b"),
+						},
+						[]sql.Expression{
+							expression.NewGetFieldWithTable(0, sql.Int64, "foo", "a", false),
+						},
+						plan.NewResolvedTable(table),
+					),
+				),
+			),
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := rule.Apply(sql.NewEmptyContext(), NewDefault(nil), tt.node)
+			require.NoError(t, err)
+			require.Equal(t, tt.expected, result)
+		})
+	}
+}
@@ -142,6 +142,9 @@ type Analyzer struct {
 	Batches []*Batch
 	// Catalog of databases and registered functions.
 	Catalog *sql.Catalog
+	// scope of the outer query, only set when analyzing a subquery
+	// expression.
+	scope *scope
 }
 
 // NewDefault creates a default Analyzer instance with all default Rules and configuration.
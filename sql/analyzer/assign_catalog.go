@@ -26,6 +26,65 @@ func assignCatalog(ctx *sql.Context, a *Analyzer, n sql.Node) (sql.Node, error)
 			nc.Catalog = a.Catalog
 			nc.CurrentDatabase = a.Catalog.CurrentDatabase()
 			return &nc, nil
+		case *plan.DropTable:
+			nc := *node
+			nc.Catalog = a.Catalog
+			return &nc, nil
+		case *plan.Update:
+			nc := *node
+			nc.Catalog = a.Catalog
+			nc.CurrentDatabase = a.Catalog.CurrentDatabase()
+			return &nc, nil
+		case *plan.Delete:
+			nc := *node
+			nc.Catalog = a.Catalog
+			nc.CurrentDatabase = a.Catalog.CurrentDatabase()
+			return &nc, nil
+		case *plan.Truncate:
+			nc := *node
+			nc.Catalog = a.Catalog
+			nc.CurrentDatabase = a.Catalog.CurrentDatabase()
+			return &nc, nil
+		case *plan.DropColumn:
+			nc := *node
+			nc.Catalog = a.Catalog
+			nc.CurrentDatabase = a.Catalog.CurrentDatabase()
+			return &nc, nil
+		case *plan.ModifyColumn:
+			nc := *node
+			nc.Catalog = a.Catalog
+			nc.CurrentDatabase = a.Catalog.CurrentDatabase()
+			return &nc, nil
+		case *plan.RenameTable:
+			nc := *node
+			nc.Catalog = a.Catalog
+			return &nc, nil
+		case *plan.CreateView:
+			nc := *node
+			nc.Catalog = a.Catalog
+			return &nc, nil
+		case *plan.DropView:
+			nc := *node
+			nc.Catalog = a.Catalog
+			nc.CurrentDatabase = a.Catalog.CurrentDatabase()
+			return &nc, nil
+		case *plan.ShowCreateView:
+			nc := *node
+			nc.Catalog = a.Catalog
+			nc.CurrentDatabase = a.Catalog.CurrentDatabase()
+			return &nc, nil
+		case *plan.ShowTables:
+			nc := *node
+			nc.Catalog = a.Catalog
+			return &nc, nil
+		case *plan.CreateDatabase:
+			nc := *node
+			nc.Catalog = a.Catalog
+			return &nc, nil
+		case *plan.DropDatabase:
+			nc := *node
+			nc.Catalog = a.Catalog
+			return &nc, nil
 		case *plan.ShowIndexes:
 			nc := *node
 			nc.Registry = a.Catalog.IndexRegistry
@@ -60,6 +119,10 @@ func assignCatalog(ctx *sql.Context, a *Analyzer, n sql.Node) (sql.Node, error)
 			nc := *node
 			nc.Catalog = a.Catalog
 			return &nc, nil
+		case *plan.BeginTransaction:
+			nc := *node
+			nc.Catalog = a.Catalog
+			return &nc, nil
 		default:
 			return n, nil
 		}
@@ -40,6 +40,33 @@ func TestAssignCatalog(t *testing.T) {
 	require.Equal(c, di.Catalog)
 	require.Equal("foo", di.CurrentDatabase)
 
+	node, err = f.Apply(sql.NewEmptyContext(), a,
+		plan.NewDropColumn(plan.NewResolvedTable(tbl), "a"))
+	require.NoError(err)
+
+	dc, ok := node.(*plan.DropColumn)
+	require.True(ok)
+	require.Equal(c, dc.Catalog)
+	require.Equal("foo", dc.CurrentDatabase)
+
+	node, err = f.Apply(sql.NewEmptyContext(), a,
+		plan.NewTruncate(plan.NewResolvedTable(tbl)))
+	require.NoError(err)
+
+	tr, ok := node.(*plan.Truncate)
+	require.True(ok)
+	require.Equal(c, tr.Catalog)
+	require.Equal("foo", tr.CurrentDatabase)
+
+	node, err = f.Apply(sql.NewEmptyContext(), a,
+		plan.NewDelete(plan.NewResolvedTable(tbl)))
+	require.NoError(err)
+
+	del, ok := node.(*plan.Delete)
+	require.True(ok)
+	require.Equal(c, del.Catalog)
+	require.Equal("foo", del.CurrentDatabase)
+
 	node, err = f.Apply(sql.NewEmptyContext(), a, plan.NewShowIndexes(db, "table-test", nil))
 	require.NoError(err)
 
@@ -48,6 +48,17 @@ func assignIndexes(a *Analyzer, node sql.Node) (map[string]*indexLookup, error)
 			return false
 		}
 
+		// indexes can't be used for tables on the NULL-padded side of an
+		// outer join below the filter.
+		for _, s := range outerJoinNullableSources(filter.Child) {
+			if idx, ok := result[s]; ok {
+				for _, index := range idx.indexes {
+					a.Catalog.ReleaseIndex(index)
+				}
+				delete(result, s)
+			}
+		}
+
 		if indexes != nil {
 			indexes = indexesIntersection(a, indexes, result)
 		} else {
@@ -708,7 +719,7 @@ func containsColumns(e sql.Expression) bool {
 }
 
 func isEvaluable(e sql.Expression) bool {
-	return !containsColumns(e)
+	return !containsColumns(e) && !containsSubquery(e) && !containsBindVars(e)
 }
 
 func canMergeIndexes(a, b sql.IndexLookup) bool {
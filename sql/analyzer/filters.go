@@ -5,6 +5,7 @@ import (
 
 	"gopkg.in/src-d/go-mysql-server.v0/sql"
 	"gopkg.in/src-d/go-mysql-server.v0/sql/expression"
+	"gopkg.in/src-d/go-mysql-server.v0/sql/plan"
 )
 
 type filters map[string][]sql.Expression
@@ -18,6 +19,12 @@ func (f filters) merge(f2 filters) {
 func exprToTableFilters(expr sql.Expression) filters {
 	filtersByTable := make(filters)
 	for _, expr := range splitExpression(expr) {
+		// Subqueries and references to the outer query need the context of
+		// the query to be evaluated, so they can't be handled by tables.
+		if containsSubquery(expr) {
+			continue
+		}
+
 		var seenTables = make(map[string]struct{})
 		var lastTable string
 		_, _ = expr.TransformUp(func(e sql.Expression) (sql.Expression, error) {
@@ -71,3 +78,75 @@ func getUnhandledFilters(all, handled []sql.Expression) []sql.Expression {
 
 	return unhandledFilters
 }
+
+// outerJoinNullableSources returns the sources of all the tables in the given
+// node that are on the side of an outer join whose rows may be padded with
+// NULLs. Filters found above those joins cannot be evaluated against the
+// tables themselves, because they would filter out rows before the padding
+// happens.
+func outerJoinNullableSources(n sql.Node) []string {
+	var sources []string
+	plan.Inspect(n, func(n sql.Node) bool {
+		switch n := n.(type) {
+		case *plan.LeftJoin:
+			sources = append(sources, nodeSources(n.Right)...)
+		case *plan.RightJoin:
+			sources = append(sources, nodeSources(n.Left)...)
+		case *plan.FullOuterJoin:
+			sources = append(sources, nodeSources(n.Left)...)
+			sources = append(sources, nodeSources(n.Right)...)
+		}
+		return true
+	})
+	return sources
+}
+
+// removeSourcesFilters returns the given filters without the ones that
+// mention any of the given sources.
+func removeSourcesFilters(exprs []sql.Expression, sources []string) []sql.Expression {
+	var result []sql.Expression
+	for _, e := range exprs {
+		var found bool
+		for _, s := range expressionSources(e) {
+			for _, s2 := range sources {
+				if s == s2 {
+					found = true
+					break
+				}
+			}
+		}
+
+		if !found {
+			result = append(result, e)
+		}
+	}
+	return result
+}
+
+// containsSubquery returns whether the expression contains a subquery or a
+// reference to the outer query from inside a subquery.
+func containsSubquery(e sql.Expression) bool {
+	var result bool
+	expression.Inspect(e, func(e sql.Expression) bool {
+		switch e.(type) {
+		case *expression.Subquery, *expression.OuterField:
+			result = true
+			return false
+		}
+		return true
+	})
+	return result
+}
+
+// containsBindVars returns whether the expression contains a bind variable.
+func containsBindVars(e sql.Expression) bool {
+	var result bool
+	expression.Inspect(e, func(e sql.Expression) bool {
+		if _, ok := e.(*expression.BindVar); ok {
+			result = true
+			return false
+		}
+		return true
+	})
+	return result
+}
@@ -201,8 +201,27 @@ func moveJoinConditionsToFilter(ctx *sql.Context, a *Analyzer, n sql.Node) (sql.
 	a.Log("moving join conditions to filter, node of type: %T", n)
 
 	return n.TransformUp(func(n sql.Node) (sql.Node, error) {
-		join, ok := n.(*plan.InnerJoin)
-		if !ok {
+		var join *plan.InnerJoin
+		switch n := n.(type) {
+		case *plan.InnerJoin:
+			join = n
+		case *plan.LeftJoin:
+			// Only the conditions on the NULL-padded side can be moved, the
+			// rows of the other side must be kept even if they don't match.
+			right, cond, err := moveJoinConditionsToChild(n.Right, n.Cond)
+			if err != nil {
+				return nil, err
+			}
+
+			return plan.NewLeftJoin(n.Left, right, cond), nil
+		case *plan.RightJoin:
+			left, cond, err := moveJoinConditionsToChild(n.Left, n.Cond)
+			if err != nil {
+				return nil, err
+			}
+
+			return plan.NewRightJoin(left, n.Right, cond), nil
+		default:
 			return n, nil
 		}
 
@@ -258,6 +277,41 @@ func moveJoinConditionsToFilter(ctx *sql.Context, a *Analyzer, n sql.Node) (sql.
 	})
 }
 
+// moveJoinConditionsToChild moves the parts of the given join condition that
+// only reference the given child of the join to a filter on top of it. It
+// returns the new child and the remaining condition of the join.
+func moveJoinConditionsToChild(
+	child sql.Node,
+	cond sql.Expression,
+) (sql.Node, sql.Expression, error) {
+	sources := nodeSources(child)
+	var childFilters, condFilters []sql.Expression
+	for _, e := range splitExpression(cond) {
+		if containsSources(sources, expressionSources(e)) {
+			childFilters = append(childFilters, e)
+		} else {
+			condFilters = append(condFilters, e)
+		}
+	}
+
+	if len(childFilters) == 0 {
+		return child, cond, nil
+	}
+
+	filter, err := fixFieldIndexes(child.Schema(), expression.JoinAnd(childFilters...))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// outer joins can't be converted to cross joins, so the condition is
+	// always true if nothing is left.
+	if len(condFilters) == 0 {
+		condFilters = append(condFilters, expression.NewLiteral(true, sql.Boolean))
+	}
+
+	return plan.NewFilter(filter, child), expression.JoinAnd(condFilters...), nil
+}
+
 func removeUnnecessaryConverts(ctx *sql.Context, a *Analyzer, n sql.Node) (sql.Node, error) {
 	span, _ := ctx.Span("remove_unnecessary_converts")
 	defer span.Finish()
@@ -292,6 +292,55 @@ func TestMoveJoinConditionsToFilter(t *testing.T) {
 	)
 
 	require.Equal(result, expected)
+
+	node = plan.NewLeftJoin(
+		plan.NewResolvedTable(t1),
+		plan.NewResolvedTable(t2),
+		expression.JoinAnd(
+			eq(col(0, "t1", "a"), col(2, "t2", "c")),
+			eq(col(0, "t1", "a"), lit(5)),
+			eq(col(3, "t2", "d"), lit(5)),
+		),
+	)
+
+	result, err = rule.Apply(sql.NewEmptyContext(), NewDefault(nil), node)
+	require.NoError(err)
+
+	// conditions on the left side can't be moved, because the left rows
+	// are returned even if they don't match.
+	expected = plan.NewLeftJoin(
+		plan.NewResolvedTable(t1),
+		plan.NewFilter(
+			eq(col(1, "t2", "d"), lit(5)),
+			plan.NewResolvedTable(t2),
+		),
+		and(
+			eq(col(0, "t1", "a"), col(2, "t2", "c")),
+			eq(col(0, "t1", "a"), lit(5)),
+		),
+	)
+
+	require.Equal(expected, result)
+
+	node = plan.NewRightJoin(
+		plan.NewResolvedTable(t1),
+		plan.NewResolvedTable(t2),
+		eq(col(0, "t1", "a"), lit(5)),
+	)
+
+	result, err = rule.Apply(sql.NewEmptyContext(), NewDefault(nil), node)
+	require.NoError(err)
+
+	expected = plan.NewRightJoin(
+		plan.NewFilter(
+			eq(col(0, "t1", "a"), lit(5)),
+			plan.NewResolvedTable(t1),
+		),
+		plan.NewResolvedTable(t2),
+		expression.NewLiteral(true, sql.Boolean),
+	)
+
+	require.Equal(expected, result)
 }
 
 func TestEvalFilter(t *testing.T) {
@@ -6,9 +6,12 @@ import (
 )
 
 func shouldParallelize(node sql.Node) bool {
-	// Do not try to parallelize index operations.
+	// Do not try to parallelize index operations, updates, deletes or
+	// table alterations.
 	switch node.(type) {
-	case *plan.CreateIndex, *plan.DropIndex, *plan.Describe:
+	case *plan.CreateIndex, *plan.DropIndex, *plan.Describe,
+		*plan.Update, *plan.Delete, *plan.Truncate,
+		*plan.AddColumn, *plan.DropColumn, *plan.ModifyColumn:
 		return false
 	default:
 		return true
@@ -0,0 +1,156 @@
+package analyzer
+
+import (
+	"gopkg.in/src-d/go-mysql-server.v0/sql"
+	"gopkg.in/src-d/go-mysql-server.v0/sql/expression"
+	"gopkg.in/src-d/go-mysql-server.v0/sql/plan"
+)
+
+// AnalyzePrepared prepares a node that was already analyzed to be executed
+// again. The given values are bound to the bind variables of the node and
+// the process tracking of its previous analysis is replaced with the one of
+// the process in the given context. The indexes used by the node are not
+// released after the execution, but when ReleasePrepared is called.
+//
+// Rules like eval_filter and pushdown can't do anything with bind variables
+// before their values are known, so nodes that had any are analyzed again
+// with the default rules and the ones after them once they are bound. The
+// indexes used by that analysis are released after the execution.
+func (a *Analyzer) AnalyzePrepared(
+	ctx *sql.Context,
+	n sql.Node,
+	bindings map[string]sql.Expression,
+) (sql.Node, error) {
+	span, ctx := ctx.Span("analyze_prepared")
+	defer span.Finish()
+
+	a.Log("binding %d variables of prepared node", len(bindings))
+	n, err := stripPrepared(n)
+	if err != nil {
+		return nil, err
+	}
+
+	pending := hasBindVars(n)
+	n, err = bindVars(n, bindings)
+	if err != nil {
+		return nil, err
+	}
+
+	batches := []*Batch{afterAllBatch()}
+	if pending {
+		batches = preparedBatches()
+	}
+
+	for _, batch := range batches {
+		n, err = batch.Eval(ctx, a, n)
+		if ErrMaxAnalysisIters.Is(err) {
+			a.Log(err.Error())
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return n, nil
+}
+
+// preparedBatches returns the batches of rules applied again to a prepared
+// node once the values of its bind variables are known. The node is already
+// resolved and validated, so only the default rules and the ones after them
+// are needed.
+func preparedBatches() []*Batch {
+	return []*Batch{
+		&Batch{
+			Desc:       "analyzer rules",
+			Iterations: maxAnalysisIterations,
+			Rules:      DefaultRules,
+		},
+		&Batch{
+			Desc:       "once execution rules after default",
+			Iterations: 1,
+			Rules:      OnceAfterDefault,
+		},
+		afterAllBatch(),
+	}
+}
+
+// afterAllBatch returns the batch of rules tracking the process of a
+// prepared node and parallelizing it, which are applied before every
+// execution.
+func afterAllBatch() *Batch {
+	return &Batch{
+		Desc:       "after-all rules",
+		Iterations: 1,
+		Rules:      OnceAfterAll,
+	}
+}
+
+// ReleasePrepared releases the indexes used by a node analyzed to be
+// executed with AnalyzePrepared. It must be called once the node is not
+// going to be executed anymore.
+func (a *Analyzer) ReleasePrepared(n sql.Node) {
+	plan.Inspect(n, func(n sql.Node) bool {
+		if r, ok := n.(*releaser); ok {
+			r.Release()
+		}
+		return true
+	})
+}
+
+// stripPrepared removes from the given node the process node and the
+// process tables added by the track_process rule, the exchanges added by
+// the parallelize rule, and the nodes releasing the indexes of the query.
+func stripPrepared(n sql.Node) (sql.Node, error) {
+	return stripQueryProcess(n).TransformUp(func(n sql.Node) (sql.Node, error) {
+		switch n := n.(type) {
+		case *releaser:
+			return n.Child, nil
+		case *plan.Exchange:
+			return n.Child, nil
+		case *plan.ResolvedTable:
+			switch t := n.Table.(type) {
+			case *plan.ProcessTable:
+				return plan.NewResolvedTable(t.Underlying()), nil
+			case *plan.ProcessIndexableTable:
+				return plan.NewResolvedTable(t.Underlying()), nil
+			}
+		}
+		return n, nil
+	})
+}
+
+// hasBindVars returns whether the given node has any bind variable.
+func hasBindVars(n sql.Node) bool {
+	var result bool
+	plan.InspectExpressions(n, func(e sql.Expression) bool {
+		if _, ok := e.(*expression.BindVar); ok {
+			result = true
+		}
+		return !result
+	})
+	return result
+}
+
+// bindVars replaces all the bind variables in the given node, including the
+// ones in subqueries, with their values.
+func bindVars(n sql.Node, bindings map[string]sql.Expression) (sql.Node, error) {
+	return n.TransformExpressionsUp(func(e sql.Expression) (sql.Expression, error) {
+		switch e := e.(type) {
+		case *expression.BindVar:
+			value, ok := bindings[e.Name()]
+			if !ok {
+				return nil, expression.ErrUnboundBindVar.New(e.Name())
+			}
+			return value, nil
+		case *expression.Subquery:
+			query, err := bindVars(e.Query, bindings)
+			if err != nil {
+				return nil, err
+			}
+			return e.WithQuery(query), nil
+		default:
+			return e, nil
+		}
+	})
+}
@@ -0,0 +1,179 @@
+package analyzer
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"gopkg.in/src-d/go-mysql-server.v0/mem"
+	"gopkg.in/src-d/go-mysql-server.v0/sql"
+	"gopkg.in/src-d/go-mysql-server.v0/sql/expression"
+	"gopkg.in/src-d/go-mysql-server.v0/sql/plan"
+)
+
+func TestAnalyzePrepared(t *testing.T) {
+	require := require.New(t)
+
+	table := mem.NewTable("foo", sql.Schema{{Name: "a", Type: sql.Int64, Source: "foo"}})
+	for _, i := range []int64{1, 2, 3} {
+		require.NoError(table.Insert(sql.NewEmptyContext(), sql.NewRow(i)))
+	}
+
+	db := mem.NewDatabase("mydb")
+	db.AddTable("foo", table)
+
+	catalog := sql.NewCatalog()
+	catalog.AddDatabase(db)
+	a := NewDefault(catalog)
+
+	node := plan.NewProject(
+		[]sql.Expression{expression.NewUnresolvedColumn("a")},
+		plan.NewFilter(
+			expression.NewGreaterThan(
+				expression.NewUnresolvedColumn("a"),
+				expression.NewBindVar("v1"),
+			),
+			plan.NewUnresolvedTable("foo", ""),
+		),
+	)
+
+	ctx := sql.NewContext(context.Background(), sql.WithPid(1))
+	ctx, err := catalog.AddProcess(ctx, sql.QueryProcess, "SELECT a FROM foo WHERE a > ?")
+	require.NoError(err)
+
+	prepared, err := a.Analyze(ctx, node)
+	require.NoError(err)
+	catalog.Done(1)
+
+	// The filter with the bind variable must not be pushed down to the table.
+	var filters int
+	plan.Inspect(prepared, func(n sql.Node) bool {
+		if _, ok := n.(*plan.Filter); ok {
+			filters++
+		}
+		return true
+	})
+	require.Equal(1, filters)
+
+	for _, tt := range []struct {
+		value    int64
+		expected []sql.Row
+	}{
+		{0, []sql.Row{{int64(1)}, {int64(2)}, {int64(3)}}},
+		{2, []sql.Row{{int64(3)}}},
+	} {
+		ctx := sql.NewContext(context.Background(), sql.WithPid(2))
+		ctx, err := catalog.AddProcess(ctx, sql.QueryProcess, "SELECT a FROM foo WHERE a > ?")
+		require.NoError(err)
+
+		result, err := a.AnalyzePrepared(ctx, prepared, map[string]sql.Expression{
+			"v1": expression.NewLiteral(tt.value, sql.Int64),
+		})
+		require.NoError(err)
+
+		_, ok := result.(*plan.QueryProcess)
+		require.True(ok)
+
+		// Once bound, the filter is pushed down to the table.
+		plan.Inspect(result, func(n sql.Node) bool {
+			_, ok := n.(*plan.Filter)
+			require.False(ok)
+			return true
+		})
+
+		iter, err := result.RowIter(ctx)
+		require.NoError(err)
+		rows, err := sql.RowIterToRows(iter)
+		require.NoError(err)
+		require.Equal(tt.expected, rows)
+		require.Len(catalog.Processes(), 0)
+	}
+
+	_, err = a.AnalyzePrepared(ctx, prepared, nil)
+	require.Error(err)
+	require.True(expression.ErrUnboundBindVar.Is(err))
+
+	a.ReleasePrepared(prepared)
+}
+
+func TestAnalyzePreparedIndexes(t *testing.T) {
+	require := require.New(t)
+
+	table := mem.NewTable("foo", sql.Schema{{Name: "a", Type: sql.Int64, Source: "foo"}})
+
+	db := mem.NewDatabase("")
+	db.AddTable("foo", table)
+
+	catalog := sql.NewCatalog()
+	catalog.AddDatabase(db)
+
+	idx := &dummyIndex{
+		"foo",
+		[]sql.Expression{
+			expression.NewGetFieldWithTable(0, sql.Int64, "foo", "a", false),
+		},
+	}
+	done, ready, err := catalog.AddIndex(idx)
+	require.NoError(err)
+	close(done)
+	<-ready
+
+	a := NewDefault(catalog)
+
+	node := plan.NewProject(
+		[]sql.Expression{expression.NewUnresolvedColumn("a")},
+		plan.NewFilter(
+			expression.NewEquals(
+				expression.NewUnresolvedColumn("a"),
+				expression.NewBindVar("v1"),
+			),
+			plan.NewUnresolvedTable("foo", ""),
+		),
+	)
+
+	ctx := sql.NewContext(context.Background(), sql.WithPid(1))
+	ctx, err = catalog.AddProcess(ctx, sql.QueryProcess, "SELECT a FROM foo WHERE a = ?")
+	require.NoError(err)
+
+	prepared, err := a.Analyze(ctx, node)
+	require.NoError(err)
+	catalog.Done(1)
+
+	// The index can't be chosen until the value is known.
+	plan.Inspect(prepared, func(n sql.Node) bool {
+		_, ok := n.(*releaser)
+		require.False(ok)
+		return true
+	})
+
+	ctx = sql.NewContext(context.Background(), sql.WithPid(2))
+	ctx, err = catalog.AddProcess(ctx, sql.QueryProcess, "SELECT a FROM foo WHERE a = ?")
+	require.NoError(err)
+
+	result, err := a.AnalyzePrepared(ctx, prepared, map[string]sql.Expression{
+		"v1": expression.NewLiteral(int64(2), sql.Int64),
+	})
+	require.NoError(err)
+
+	// Once bound, the filter is pushed down and the index is used.
+	var lookup sql.IndexLookup
+	var release *releaser
+	plan.Inspect(result, func(n sql.Node) bool {
+		switch n := n.(type) {
+		case *plan.Filter:
+			require.Fail("unexpected filter")
+		case *releaser:
+			release = n
+		case *plan.ResolvedTable:
+			t := n.Table.(*plan.ProcessIndexableTable).Underlying()
+			lookup = t.(*mem.Table).IndexLookup()
+		}
+		return true
+	})
+	require.Equal(&mergeableIndexLookup{id: "2"}, lookup)
+
+	// The index is released after the execution, not with the prepared node.
+	require.NotNil(release)
+	release.Release()
+	a.ReleasePrepared(prepared)
+}
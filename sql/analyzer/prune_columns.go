@@ -16,6 +16,12 @@ func pruneColumns(ctx *sql.Context, a *Analyzer, n sql.Node) (sql.Node, error) {
 		return n, nil
 	}
 
+	// Each side of a set operation has already been analyzed on its own, and
+	// its columns don't need to match the ones used above it.
+	if hasSetOperations(n) {
+		return n, nil
+	}
+
 	columns := make(usedColumns)
 
 	// All the columns required for the output of the query must be mark as
@@ -128,6 +134,12 @@ func pruneSubqueries(
 			return n, nil
 		}
 
+		// Materialized subqueries are shared by several nodes, which may
+		// not use the same columns.
+		if _, ok := stripQueryProcess(subq.Child).(*plan.Materialized); ok {
+			return n, nil
+		}
+
 		return pruneSubqueryColumns(ctx, a, subq, parentColumns)
 	})
 }
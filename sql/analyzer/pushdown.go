@@ -20,7 +20,20 @@ func pushdown(ctx *sql.Context, a *Analyzer, n sql.Node) (sql.Node, error) {
 
 	// don't do pushdown on certain queries
 	switch n.(type) {
-	case *plan.InsertInto, *plan.CreateIndex:
+	case *plan.InsertInto, *plan.CreateIndex, *plan.Update, *plan.Delete,
+		*plan.Truncate, *plan.AddColumn, *plan.DropColumn, *plan.ModifyColumn,
+		*plan.CreateView:
+		return n, nil
+	}
+
+	// each side of a set operation has already been pushed down on its own
+	if hasSetOperations(n) {
+		return n, nil
+	}
+
+	// bind variables have no value until the query is executed, so the
+	// pushdown is done once they are bound, see AnalyzePrepared
+	if hasBindVars(n) {
 		return n, nil
 	}
 
@@ -65,6 +78,9 @@ func pushdown(ctx *sql.Context, a *Analyzer, n sql.Node) (sql.Node, error) {
 		switch node := node.(type) {
 		case *plan.Filter:
 			fs := exprToTableFilters(node.Expression)
+			for _, s := range outerJoinNullableSources(node.Child) {
+				delete(fs, s)
+			}
 			a.Log("found filters for %d tables %s", len(fs), node.Expression)
 			filters.merge(fs)
 		}
@@ -74,9 +90,15 @@ func pushdown(ctx *sql.Context, a *Analyzer, n sql.Node) (sql.Node, error) {
 	filterSpan.Finish()
 
 	indexSpan, _ := ctx.Span("assign_indexes")
-	indexes, err := assignIndexes(a, n)
-	if err != nil {
-		return nil, err
+	var indexes map[string]*indexLookup
+	var err error
+	// Subquery expressions may be executed once for every row of the outer
+	// query, so the indexes used by them could not be released properly.
+	if a.scope == nil {
+		indexes, err = assignIndexes(a, n)
+		if err != nil {
+			return nil, err
+		}
 	}
 	indexSpan.Finish()
 
@@ -92,14 +114,22 @@ func pushdown(ctx *sql.Context, a *Analyzer, n sql.Node) (sql.Node, error) {
 		a.Log("transforming node of type: %T", node)
 		switch node := node.(type) {
 		case *plan.Filter:
+			nullableSources := outerJoinNullableSources(node.Child)
 			if len(handledFilters) == 0 {
 				a.Log("no handled filters, leaving filter untouched")
-				return node, nil
+				return fixOuterJoinFilter(node, nullableSources)
+			}
+
+			// Filters on the NULL-padded side of an outer join may be equal
+			// to filters handled below the join, but must be kept anyway.
+			handled := handledFilters
+			if len(nullableSources) > 0 {
+				handled = removeSourcesFilters(handledFilters, nullableSources)
 			}
 
 			unhandled := getUnhandledFilters(
 				splitExpression(node.Expression),
-				handledFilters,
+				handled,
 			)
 
 			if len(unhandled) == 0 {
@@ -113,7 +143,10 @@ func pushdown(ctx *sql.Context, a *Analyzer, n sql.Node) (sql.Node, error) {
 				len(unhandled),
 			)
 
-			return plan.NewFilter(expression.JoinAnd(unhandled...), node.Child), nil
+			return fixOuterJoinFilter(
+				plan.NewFilter(expression.JoinAnd(unhandled...), node.Child),
+				nullableSources,
+			)
 		case *plan.ResolvedTable:
 			var table = node.Table
 
@@ -187,13 +220,35 @@ func pushdown(ctx *sql.Context, a *Analyzer, n sql.Node) (sql.Node, error) {
 				return nil, err
 			}
 
-			if ij, ok := n.(*plan.InnerJoin); ok {
-				cond, err := fixFieldIndexes(ij.Schema(), ij.Cond)
+			switch j := n.(type) {
+			case *plan.InnerJoin:
+				cond, err := fixFieldIndexes(j.Schema(), j.Cond)
 				if err != nil {
 					return nil, err
 				}
 
-				n = plan.NewInnerJoin(ij.Left, ij.Right, cond)
+				n = plan.NewInnerJoin(j.Left, j.Right, cond)
+			case *plan.LeftJoin:
+				cond, err := fixFieldIndexes(j.Schema(), j.Cond)
+				if err != nil {
+					return nil, err
+				}
+
+				n = plan.NewLeftJoin(j.Left, j.Right, cond)
+			case *plan.RightJoin:
+				cond, err := fixFieldIndexes(j.Schema(), j.Cond)
+				if err != nil {
+					return nil, err
+				}
+
+				n = plan.NewRightJoin(j.Left, j.Right, cond)
+			case *plan.FullOuterJoin:
+				cond, err := fixFieldIndexes(j.Schema(), j.Cond)
+				if err != nil {
+					return nil, err
+				}
+
+				n = plan.NewFullOuterJoin(j.Left, j.Right, cond)
 			}
 
 			return n, nil
@@ -218,6 +273,26 @@ func pushdown(ctx *sql.Context, a *Analyzer, n sql.Node) (sql.Node, error) {
 	return node, nil
 }
 
+// fixOuterJoinFilter fixes the field indexes of a filter on top of an outer
+// join. Filters on the NULL-padded side of the join are never pushed down, so
+// they stay above the join even though the projections of the tables below
+// may have changed.
+func fixOuterJoinFilter(filter *plan.Filter, nullableSources []string) (sql.Node, error) {
+	if len(nullableSources) == 0 {
+		return filter, nil
+	}
+
+	expr, err := fixFieldIndexes(filter.Child.Schema(), filter.Expression)
+	if err != nil {
+		if ErrFieldMissing.Is(err) {
+			return filter, nil
+		}
+		return nil, err
+	}
+
+	return plan.NewFilter(expr, filter.Child), nil
+}
+
 // fixFieldIndexesOnExpressions executes fixFieldIndexes on a list of exprs.
 func fixFieldIndexesOnExpressions(schema sql.Schema, expressions ...sql.Expression) ([]sql.Expression, error) {
 	var result = make([]sql.Expression, len(expressions))
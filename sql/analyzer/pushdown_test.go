@@ -83,6 +83,77 @@ func TestPushdownProjectionAndFilters(t *testing.T) {
 	require.Equal(expected, result)
 }
 
+func TestPushdownOuterJoin(t *testing.T) {
+	require := require.New(t)
+	f := getRule("pushdown")
+
+	table := mem.NewTable("mytable", sql.Schema{
+		{Name: "i", Type: sql.Int32, Source: "mytable"},
+		{Name: "f", Type: sql.Float64, Source: "mytable"},
+	})
+
+	table2 := mem.NewTable("mytable2", sql.Schema{
+		{Name: "i2", Type: sql.Int32, Source: "mytable2"},
+	})
+
+	db := mem.NewDatabase("mydb")
+	db.AddTable("mytable", table)
+	db.AddTable("mytable2", table2)
+
+	catalog := sql.NewCatalog()
+	catalog.AddDatabase(db)
+	a := NewDefault(catalog)
+
+	node := plan.NewFilter(
+		expression.NewAnd(
+			expression.NewIsNull(
+				expression.NewGetFieldWithTable(2, sql.Int32, "mytable2", "i2", false),
+			),
+			expression.NewEquals(
+				expression.NewGetFieldWithTable(1, sql.Float64, "mytable", "f", false),
+				expression.NewLiteral(3.14, sql.Float64),
+			),
+		),
+		plan.NewLeftJoin(
+			plan.NewResolvedTable(table),
+			plan.NewResolvedTable(table2),
+			expression.NewEquals(
+				expression.NewGetFieldWithTable(0, sql.Int32, "mytable", "i", false),
+				expression.NewGetFieldWithTable(2, sql.Int32, "mytable2", "i2", false),
+			),
+		),
+	)
+
+	// the filter on the right side must be kept above the join, because
+	// it also applies to the rows padded with NULLs.
+	expected := plan.NewFilter(
+		expression.NewIsNull(
+			expression.NewGetFieldWithTable(2, sql.Int32, "mytable2", "i2", false),
+		),
+		plan.NewLeftJoin(
+			plan.NewResolvedTable(
+				table.WithFilters([]sql.Expression{
+					expression.NewEquals(
+						expression.NewGetFieldWithTable(1, sql.Float64, "mytable", "f", false),
+						expression.NewLiteral(3.14, sql.Float64),
+					),
+				}).(*mem.Table).WithProjection([]string{"f", "i"}),
+			),
+			plan.NewResolvedTable(
+				table2.WithProjection([]string{"i2"}),
+			),
+			expression.NewEquals(
+				expression.NewGetFieldWithTable(1, sql.Int32, "mytable", "i", false),
+				expression.NewGetFieldWithTable(2, sql.Int32, "mytable2", "i2", false),
+			),
+		),
+	)
+
+	result, err := f.Apply(sql.NewEmptyContext(), a, node)
+	require.NoError(err)
+	require.Equal(expected, result)
+}
+
 func TestPushdownIndexable(t *testing.T) {
 	require := require.New(t)
 
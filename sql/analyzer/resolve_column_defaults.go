@@ -0,0 +1,79 @@
+package analyzer
+
+import (
+	"strings"
+
+	"gopkg.in/src-d/go-mysql-server.v0/sql"
+	"gopkg.in/src-d/go-mysql-server.v0/sql/expression"
+	"gopkg.in/src-d/go-mysql-server.v0/sql/plan"
+)
+
+// resolveColumnDefaults replaces the DEFAULT keyword in the values of an
+// INSERT with the default value of the column the value is inserted in, or
+// of the given column for DEFAULT(col).
+func resolveColumnDefaults(ctx *sql.Context, a *Analyzer, n sql.Node) (sql.Node, error) {
+	span, _ := ctx.Span("resolve_column_defaults")
+	defer span.Finish()
+
+	a.Log("resolve column defaults, node of type: %T", n)
+	return n.TransformUp(func(n sql.Node) (sql.Node, error) {
+		insert, ok := n.(*plan.InsertInto)
+		if !ok {
+			return n, nil
+		}
+
+		values, ok := insert.Right.(*plan.Values)
+		if !ok || !insert.Left.Resolved() {
+			return n, nil
+		}
+
+		schema := insert.Left.Schema()
+		tuples := make([][]sql.Expression, len(values.ExpressionTuples))
+		for i, tuple := range values.ExpressionTuples {
+			tuples[i] = make([]sql.Expression, len(tuple))
+			for j, e := range tuple {
+				def, ok := e.(*expression.DefaultColumn)
+				if !ok {
+					tuples[i][j] = e
+					continue
+				}
+
+				name := def.Name()
+				if name == "" && j < len(insert.Columns) {
+					name = insert.Columns[j]
+				}
+
+				col, err := schemaColumn(schema, name)
+				if err != nil {
+					return nil, err
+				}
+
+				v, err := col.DefaultValue()
+				if err != nil {
+					return nil, err
+				}
+
+				a.Log("default value of column %q resolved", col.Name)
+				tuples[i][j] = expression.NewLiteral(v, col.Type)
+			}
+		}
+
+		return plan.NewInsertInto(
+			insert.Left,
+			plan.NewValues(tuples),
+			insert.IsReplace,
+			insert.Ignore,
+			insert.Columns,
+			insert.OnDupExprs,
+		), nil
+	})
+}
+
+func schemaColumn(schema sql.Schema, name string) (*sql.Column, error) {
+	for _, col := range schema {
+		if strings.EqualFold(col.Name, name) {
+			return col, nil
+		}
+	}
+	return nil, ErrColumnNotFound.New(name)
+}
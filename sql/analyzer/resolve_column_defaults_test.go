@@ -0,0 +1,50 @@
+package analyzer
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"gopkg.in/src-d/go-mysql-server.v0/mem"
+	"gopkg.in/src-d/go-mysql-server.v0/sql"
+	"gopkg.in/src-d/go-mysql-server.v0/sql/expression"
+	"gopkg.in/src-d/go-mysql-server.v0/sql/plan"
+)
+
+func TestResolveColumnDefaults(t *testing.T) {
+	require := require.New(t)
+
+	f := getRule("resolve_column_defaults")
+
+	table := mem.NewTable("mytable", sql.Schema{
+		{Name: "i", Type: sql.Int32, Source: "mytable", Default: int32(5)},
+		{Name: "s", Type: sql.Text, Source: "mytable", Nullable: true},
+	})
+
+	node := plan.NewInsertInto(
+		plan.NewResolvedTable(table),
+		plan.NewValues([][]sql.Expression{
+			{expression.NewDefaultColumn(""), expression.NewDefaultColumn("")},
+			{expression.NewLiteral(int64(1), sql.Int64), expression.NewDefaultColumn("i")},
+		}),
+		false,
+		false,
+		[]string{"i", "s"},
+		nil,
+	)
+
+	expected := plan.NewInsertInto(
+		plan.NewResolvedTable(table),
+		plan.NewValues([][]sql.Expression{
+			{expression.NewLiteral(int32(5), sql.Int32), expression.NewLiteral(nil, sql.Text)},
+			{expression.NewLiteral(int64(1), sql.Int64), expression.NewLiteral(int32(5), sql.Int32)},
+		}),
+		false,
+		false,
+		[]string{"i", "s"},
+		nil,
+	)
+
+	result, err := f.Apply(sql.NewEmptyContext(), NewDefault(nil), node)
+	require.NoError(err)
+	require.Equal(expected, result)
+}
@@ -71,10 +71,18 @@ func qualifyColumns(ctx *sql.Context, a *Analyzer, n sql.Node) (sql.Node, error)
 				tables[strings.ToLower(n.Name())] = n.Child
 				indexCols(n.Name(), n.Schema())
 			}
-		case *plan.ResolvedTable, *plan.SubqueryAlias:
+		case *plan.ResolvedTable, *plan.SubqueryAlias, *plan.RecursiveTable:
 			name := strings.ToLower(n.(sql.Nameable).Name())
 			tables[name] = n
 			indexCols(name, n.Schema())
+		case *plan.Union, *plan.Intersect, *plan.Except:
+			// Only the columns of the result of a set operation are
+			// available above it, not the ones of the tables in each side.
+			colIndex = make(map[string][]string)
+			for _, col := range n.Schema() {
+				name := strings.ToLower(col.Name)
+				colIndex[name] = append(colIndex[name], strings.ToLower(col.Source))
+			}
 		}
 
 		result, err := n.TransformExpressionsUp(func(e sql.Expression) (sql.Expression, error) {
@@ -118,7 +126,17 @@ func qualifyColumns(ctx *sql.Context, a *Analyzer, n sql.Node) (sql.Node, error)
 					}
 
 					if _, ok := tables[col.Table()]; !ok {
-						return nil, sql.ErrTableNotFound.New(col.Table())
+						// The table may be in the outer query if this is a
+						// subquery expression.
+						real, ok := a.scope.table(col.Table())
+						if !ok {
+							return nil, sql.ErrTableNotFound.New(col.Table())
+						}
+
+						col = expression.NewUnresolvedQualifiedColumn(
+							real,
+							col.Name(),
+						)
 					}
 				}
 
@@ -209,6 +227,12 @@ func resolveColumns(ctx *sql.Context, a *Analyzer, n sql.Node) (sql.Node, error)
 			return n, nil
 		}
 
+		// Aggregations in a having will be resolved once they're moved to
+		// the group by below.
+		if h, ok := n.(*plan.Having); ok && (containsAggregation(h.Cond) || hasUnresolvedAggregations(h.Cond)) {
+			return n, nil
+		}
+
 		colMap := make(map[string][]*sql.Column)
 		for _, child := range n.Children() {
 			if !child.Resolved() {
@@ -283,6 +307,16 @@ func resolveColumns(ctx *sql.Context, a *Analyzer, n sql.Node) (sql.Node, error)
 					return &deferredColumn{uc}, nil
 
 				default:
+					outer, err := a.scope.column(table, name)
+					if err != nil {
+						return nil, err
+					}
+
+					if outer != nil {
+						a.Log("column %q resolved to the outer query", uc.Name())
+						return outer, nil
+					}
+
 					if table != "" {
 						return nil, ErrColumnTableNotFound.New(uc.Table(), uc.Name())
 					}
@@ -299,7 +333,12 @@ func resolveColumns(ctx *sql.Context, a *Analyzer, n sql.Node) (sql.Node, error)
 			var col *sql.Column
 			var found bool
 			for _, c := range columns {
-				_, ok := n.(*plan.GroupBy)
+				var ok bool
+				switch n.(type) {
+				case *plan.GroupBy, *plan.Having:
+					ok = true
+				}
+
 				if ok || (strings.ToLower(c.Source) == table) {
 					col = c
 					found = true
@@ -309,6 +348,16 @@ func resolveColumns(ctx *sql.Context, a *Analyzer, n sql.Node) (sql.Node, error)
 
 			if !found {
 				if table != "" {
+					outer, err := a.scope.column(table, name)
+					if err != nil {
+						return nil, err
+					}
+
+					if outer != nil {
+						a.Log("column %q resolved to the outer query", uc.Name())
+						return outer, nil
+					}
+
 					return nil, ErrColumnTableNotFound.New(uc.Table(), uc.Name())
 				}
 
@@ -326,6 +375,10 @@ func resolveColumns(ctx *sql.Context, a *Analyzer, n sql.Node) (sql.Node, error)
 			// in the row is going to be evaluated in this node
 			if plan.IsUnary(n) {
 				schema = n.Children()[0].Schema()
+			} else if insert, ok := n.(*plan.InsertInto); ok {
+				// ON DUPLICATE KEY UPDATE expressions are evaluated
+				// on the rows of the table.
+				schema = insert.Left.Schema()
 			} else {
 				schema = n.Schema()
 			}
@@ -357,145 +410,202 @@ func resolveGroupingColumns(ctx *sql.Context, a *Analyzer, n sql.Node) (sql.Node
 		return n, nil
 	}
 
+	// Columns used in a having on top of a group by must be available in
+	// the projection under the group by as well.
+	n, err := n.TransformUp(func(n sql.Node) (sql.Node, error) {
+		h, ok := n.(*plan.Having)
+		if !ok {
+			return n, nil
+		}
+
+		g, ok := h.Child.(*plan.GroupBy)
+		if !ok || g.Resolved() || len(g.Grouping) == 0 {
+			return n, nil
+		}
+
+		child, err := pushDownGroupingAliases(g, havingColumns(h.Cond, g))
+		if err != nil {
+			return nil, err
+		}
+
+		return plan.NewHaving(h.Cond, child), nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Pushing down the aliases of a group by that has already been pushed
+	// down does nothing, so the ones under a having will be left as they are.
 	return n.TransformUp(func(n sql.Node) (sql.Node, error) {
 		g, ok := n.(*plan.GroupBy)
 		if n.Resolved() || !ok || len(g.Grouping) == 0 {
 			return n, nil
 		}
 
-		// The reason we have two sets of columns, one for grouping and
-		// one for aggregate is because an alias can redefine a column name
-		// of the child schema. In the grouping, if that column is referenced
-		// it refers to the alias, and not the one in the child. However,
-		// in the aggregate, aliases in that same aggregate cannot be used,
-		// so it refers to the column in the child node.
-		var groupingColumns = make(map[string]struct{})
-		for _, g := range g.Grouping {
-			for _, n := range findAllColumns(g) {
-				groupingColumns[strings.ToLower(n)] = struct{}{}
-			}
+		return pushDownGroupingAliases(g, nil)
+	})
+}
+
+// pushDownGroupingAliases pushes down the aliases of the given group by that
+// are used in its grouping to a projection under it. The required columns
+// are also projected, in addition to the ones needed by the group by.
+func pushDownGroupingAliases(g *plan.GroupBy, required []string) (sql.Node, error) {
+	// The reason we have two sets of columns, one for grouping and
+	// one for aggregate is because an alias can redefine a column name
+	// of the child schema. In the grouping, if that column is referenced
+	// it refers to the alias, and not the one in the child. However,
+	// in the aggregate, aliases in that same aggregate cannot be used,
+	// so it refers to the column in the child node.
+	var groupingColumns = make(map[string]struct{})
+	for _, g := range g.Grouping {
+		for _, n := range findAllColumns(g) {
+			groupingColumns[strings.ToLower(n)] = struct{}{}
 		}
+	}
 
-		var aggregateColumns = make(map[string]struct{})
-		for _, agg := range g.Aggregate {
-			// This alias is going to be pushed down, so don't bother gathering
-			// its requirements.
-			if alias, ok := agg.(*expression.Alias); ok {
-				if _, ok := groupingColumns[strings.ToLower(alias.Name())]; ok {
-					continue
-				}
-			}
+	var aggregateColumns = make(map[string]struct{})
+	for _, col := range required {
+		aggregateColumns[strings.ToLower(col)] = struct{}{}
+	}
 
-			for _, n := range findAllColumns(agg) {
-				aggregateColumns[strings.ToLower(n)] = struct{}{}
+	for _, agg := range g.Aggregate {
+		// This alias is going to be pushed down, so don't bother gathering
+		// its requirements.
+		if alias, ok := agg.(*expression.Alias); ok {
+			if _, ok := groupingColumns[strings.ToLower(alias.Name())]; ok {
+				continue
 			}
 		}
 
-		var newAggregate []sql.Expression
-		var projection []sql.Expression
-		// Aliases will keep the aliases that have been pushed down and their
-		// index in the new aggregate.
-		var aliases = make(map[string]int)
-
-		var needsReorder bool
-		for _, a := range g.Aggregate {
-			alias, ok := a.(*expression.Alias)
-			// Note that aliases of aggregations cannot be used in the grouping
-			// because the grouping is needed before computing the aggregation.
-			if !ok || containsAggregation(alias) {
-				newAggregate = append(newAggregate, a)
-				continue
-			}
+		for _, n := range findAllColumns(agg) {
+			aggregateColumns[strings.ToLower(n)] = struct{}{}
+		}
+	}
 
-			name := strings.ToLower(alias.Name())
-			// Only if the alias is required in the grouping set needsReorder
-			// to true. If it's not required, there's no need for a reorder if
-			// no other alias is required.
-			_, ok = groupingColumns[name]
-			if ok {
-				aliases[name] = len(newAggregate)
-				needsReorder = true
-				delete(groupingColumns, name)
-
-				projection = append(projection, a)
-				newAggregate = append(newAggregate, expression.NewUnresolvedColumn(alias.Name()))
-			} else {
-				newAggregate = append(newAggregate, a)
-			}
+	var newAggregate []sql.Expression
+	var projection []sql.Expression
+	// Aliases will keep the aliases that have been pushed down and their
+	// index in the new aggregate.
+	var aliases = make(map[string]int)
+
+	var needsReorder bool
+	for _, a := range g.Aggregate {
+		alias, ok := a.(*expression.Alias)
+		// Note that aliases of aggregations cannot be used in the grouping
+		// because the grouping is needed before computing the aggregation.
+		if !ok || containsAggregation(alias) {
+			newAggregate = append(newAggregate, a)
+			continue
 		}
 
-		if !needsReorder {
-			return n, nil
+		name := strings.ToLower(alias.Name())
+		// Only if the alias is required in the grouping set needsReorder
+		// to true. If it's not required, there's no need for a reorder if
+		// no other alias is required.
+		_, ok = groupingColumns[name]
+		if ok {
+			aliases[name] = len(newAggregate)
+			needsReorder = true
+			delete(groupingColumns, name)
+
+			projection = append(projection, a)
+			newAggregate = append(newAggregate, expression.NewUnresolvedColumn(alias.Name()))
+		} else {
+			newAggregate = append(newAggregate, a)
 		}
+	}
 
-		// Instead of iterating columns directly, we want them sorted so the
-		// executions of the rule are consistent.
-		var missingCols = make([]string, 0, len(aggregateColumns)+len(groupingColumns))
-		for col := range aggregateColumns {
-			missingCols = append(missingCols, col)
+	if !needsReorder {
+		return g, nil
+	}
+
+	// Instead of iterating columns directly, we want them sorted so the
+	// executions of the rule are consistent.
+	var missingCols = make([]string, 0, len(aggregateColumns)+len(groupingColumns))
+	for col := range aggregateColumns {
+		missingCols = append(missingCols, col)
+	}
+	for col := range groupingColumns {
+		missingCols = append(missingCols, col)
+	}
+	sort.Strings(missingCols)
+
+	var renames = make(map[string]string)
+	// All columns required by expressions in both grouping and aggregation
+	// must also be projected in the new projection node or they will not
+	// be able to resolve.
+	for _, col := range missingCols {
+		name := col
+		// If an alias has been pushed down with the same name as a missing
+		// column, there will be a conflict of names. We must find an unique name
+		// for the missing column.
+		if _, ok := aliases[col]; ok {
+			for i := 1; ; i++ {
+				name = fmt.Sprintf("%s_%02d", col, i)
+				if !stringContains(missingCols, name) {
+					break
+				}
+			}
 		}
-		for col := range groupingColumns {
-			missingCols = append(missingCols, col)
+
+		if name == col {
+			projection = append(projection, expression.NewUnresolvedColumn(col))
+		} else {
+			renames[col] = name
+			projection = append(projection, expression.NewAlias(
+				expression.NewUnresolvedColumn(col),
+				name,
+			))
 		}
-		sort.Strings(missingCols)
-
-		var renames = make(map[string]string)
-		// All columns required by expressions in both grouping and aggregation
-		// must also be projected in the new projection node or they will not
-		// be able to resolve.
-		for _, col := range missingCols {
-			name := col
-			// If an alias has been pushed down with the same name as a missing
-			// column, there will be a conflict of names. We must find an unique name
-			// for the missing column.
-			if _, ok := aliases[col]; ok {
-				for i := 1; ; i++ {
-					name = fmt.Sprintf("%s_%02d", col, i)
-					if !stringContains(missingCols, name) {
-						break
+	}
+
+	// If there is any name conflict between columns we need to rename every
+	// usage inside the aggregate.
+	if len(renames) > 0 {
+		for i, expr := range newAggregate {
+			var err error
+			newAggregate[i], err = expr.TransformUp(func(e sql.Expression) (sql.Expression, error) {
+				col, ok := e.(*expression.UnresolvedColumn)
+				if ok {
+					// We need to make sure we don't rename the reference to the
+					// pushed down alias.
+					if to, ok := renames[col.Name()]; ok && aliases[col.Name()] != i {
+						return expression.NewUnresolvedColumn(to), nil
 					}
 				}
-			}
 
-			if name == col {
-				projection = append(projection, expression.NewUnresolvedColumn(col))
-			} else {
-				renames[col] = name
-				projection = append(projection, expression.NewAlias(
-					expression.NewUnresolvedColumn(col),
-					name,
-				))
+				return e, nil
+			})
+			if err != nil {
+				return nil, err
 			}
 		}
+	}
 
-		// If there is any name conflict between columns we need to rename every
-		// usage inside the aggregate.
-		if len(renames) > 0 {
-			for i, expr := range newAggregate {
-				var err error
-				newAggregate[i], err = expr.TransformUp(func(e sql.Expression) (sql.Expression, error) {
-					col, ok := e.(*expression.UnresolvedColumn)
-					if ok {
-						// We need to make sure we don't rename the reference to the
-						// pushed down alias.
-						if to, ok := renames[col.Name()]; ok && aliases[col.Name()] != i {
-							return expression.NewUnresolvedColumn(to), nil
-						}
-					}
+	return plan.NewGroupBy(
+		newAggregate, g.Grouping,
+		plan.NewProject(projection, g.Child),
+	), nil
+}
 
-					return e, nil
-				})
-				if err != nil {
-					return nil, err
-				}
-			}
+// havingColumns returns the columns used in the given having condition
+// that are not aliases defined in the aggregate of the group by.
+func havingColumns(cond sql.Expression, g *plan.GroupBy) []string {
+	var aliases = make(map[string]struct{})
+	for _, e := range g.Aggregate {
+		if alias, ok := e.(*expression.Alias); ok {
+			aliases[strings.ToLower(alias.Name())] = struct{}{}
 		}
+	}
 
-		return plan.NewGroupBy(
-			newAggregate, g.Grouping,
-			plan.NewProject(projection, g.Child),
-		), nil
-	})
+	var cols []string
+	for _, col := range findAllColumns(cond) {
+		if _, ok := aliases[strings.ToLower(col)]; !ok {
+			cols = append(cols, col)
+		}
+	}
+
+	return cols
 }
 
 func findAllColumns(e sql.Expression) []string {
@@ -0,0 +1,103 @@
+package analyzer
+
+import (
+	"strings"
+
+	"gopkg.in/src-d/go-mysql-server.v0/sql"
+	"gopkg.in/src-d/go-mysql-server.v0/sql/expression"
+	"gopkg.in/src-d/go-mysql-server.v0/sql/plan"
+)
+
+// resolveRecursiveCTEs analyzes the queries of recursive common table
+// expressions. The anchor query is analyzed first, so the references to
+// the expression in the recursive query can be resolved as tables with the
+// schema of the anchor query.
+func resolveRecursiveCTEs(ctx *sql.Context, a *Analyzer, n sql.Node) (sql.Node, error) {
+	span, ctx := ctx.Span("resolve_recursive_ctes")
+	defer span.Finish()
+
+	a.Log("resolving recursive common table expressions")
+	return n.TransformUp(func(n sql.Node) (sql.Node, error) {
+		cte, ok := n.(*plan.RecursiveCTE)
+		if !ok || cte.Resolved() {
+			return n, nil
+		}
+
+		a.Log("analyzing anchor of recursive common table expression %q", cte.Name())
+		anchor, err := analyzeSetOperand(ctx, a, cte.Left)
+		if err != nil {
+			return nil, err
+		}
+
+		schema := anchor.Schema()
+		if columns := cte.Columns(); len(columns) > 0 && len(columns) != len(schema) {
+			return nil, plan.ErrCTEColumnCount.New(cte.Name(), len(columns), len(schema))
+		}
+
+		table := plan.NewRecursiveTable(
+			cte.Name(),
+			plan.CTESchema(cte.Name(), cte.Columns(), schema),
+		)
+
+		recursive, err := cte.Right.TransformUp(func(n sql.Node) (sql.Node, error) {
+			t, ok := n.(*plan.UnresolvedTable)
+			if !ok || t.Database != "" || !strings.EqualFold(t.Name(), cte.Name()) {
+				return n, nil
+			}
+			return table, nil
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		a.Log("analyzing recursive query of common table expression %q", cte.Name())
+		recursive, err = analyzeSetOperand(ctx, a, recursive)
+		if err != nil {
+			return nil, err
+		}
+
+		if rs := recursive.Schema(); len(rs) != len(schema) {
+			return nil, ErrSetOperationColumns.New(len(schema), len(rs))
+		}
+
+		return plan.NewRecursiveCTE(
+			cte.Name(),
+			cte.Columns(),
+			anchor,
+			recursive,
+			cte.Distinct(),
+		), nil
+	})
+}
+
+// resolveCTEColumns replaces the nodes giving names to the columns of
+// common table expressions with a projection of the columns of their
+// queries with those names, once the queries are resolved.
+func resolveCTEColumns(ctx *sql.Context, a *Analyzer, n sql.Node) (sql.Node, error) {
+	span, _ := ctx.Span("resolve_cte_columns")
+	defer span.Finish()
+
+	return n.TransformUp(func(n sql.Node) (sql.Node, error) {
+		cte, ok := n.(*plan.CommonTableExpression)
+		if !ok || !cte.Child.Resolved() {
+			return n, nil
+		}
+
+		a.Log("resolving columns of common table expression %q", cte.Name())
+		columns := cte.Columns()
+		schema := cte.Child.Schema()
+		if len(columns) != len(schema) {
+			return nil, plan.ErrCTEColumnCount.New(cte.Name(), len(columns), len(schema))
+		}
+
+		var projection = make([]sql.Expression, len(schema))
+		for i, col := range schema {
+			projection[i] = expression.NewAlias(
+				expression.NewGetFieldWithTable(i, col.Type, col.Source, col.Name, col.Nullable),
+				columns[i],
+			)
+		}
+
+		return plan.NewProject(projection, cte.Child), nil
+	})
+}
@@ -36,6 +36,33 @@ func resolveDatabase(ctx *sql.Context, a *Analyzer, n sql.Node) (sql.Node, error
 			return nil, err
 		}
 
+		nc := *v
+		nc.Database = db
+		return &nc, nil
+	case *plan.CreateView:
+		db, err := a.Catalog.Database(databaseName(a, v.Database))
+		if err != nil {
+			return nil, err
+		}
+
+		nc := *v
+		nc.Database = db
+		return &nc, nil
+	case *plan.DropTable:
+		db, err := a.Catalog.Database(databaseName(a, v.Database))
+		if err != nil {
+			return nil, err
+		}
+
+		nc := *v
+		nc.Database = db
+		return &nc, nil
+	case *plan.RenameTable:
+		db, err := a.Catalog.Database(databaseName(a, v.Database))
+		if err != nil {
+			return nil, err
+		}
+
 		nc := *v
 		nc.Database = db
 		return &nc, nil
@@ -61,3 +88,13 @@ func resolveDatabase(ctx *sql.Context, a *Analyzer, n sql.Node) (sql.Node, error
 		return n, nil
 	}
 }
+
+// databaseName returns the name of the given database or the current one
+// if it has no name.
+func databaseName(a *Analyzer, db sql.Database) string {
+	if name := db.Name(); name != "" {
+		return name
+	}
+
+	return a.Catalog.CurrentDatabase()
+}
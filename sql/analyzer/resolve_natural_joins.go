@@ -34,8 +34,9 @@ func resolveNaturalJoins(ctx *sql.Context, a *Analyzer, n sql.Node) (sql.Node, e
 		a.Log("transforming node of type: %T", n)
 
 		if alias, ok := n.(*plan.TableAlias); ok {
-			table := alias.Child.(*plan.ResolvedTable).Name()
-			aliasTables[alias.Name()] = append(aliasTables[alias.Name()], table)
+			if table, ok := alias.Child.(sql.Nameable); ok {
+				aliasTables[alias.Name()] = append(aliasTables[alias.Name()], table.Name())
+			}
 			return n, nil
 		}
 
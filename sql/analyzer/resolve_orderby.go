@@ -163,6 +163,13 @@ func pushSortDown(sort *plan.Sort) (sql.Node, error) {
 			child.Grouping,
 			plan.NewSort(sort.SortFields, child.Child),
 		), nil
+	case *plan.Having:
+		node, err := pushSortDown(plan.NewSort(sort.SortFields, child.Child))
+		if err != nil {
+			return nil, err
+		}
+
+		return plan.NewHaving(child.Cond, node), nil
 	default:
 		// Can't do anything here, there should be either a project or a groupby
 		// below an order by.
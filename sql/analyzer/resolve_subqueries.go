@@ -1,7 +1,10 @@
 package analyzer
 
 import (
+	"strings"
+
 	"gopkg.in/src-d/go-mysql-server.v0/sql"
+	"gopkg.in/src-d/go-mysql-server.v0/sql/expression"
 	"gopkg.in/src-d/go-mysql-server.v0/sql/plan"
 )
 
@@ -25,3 +28,220 @@ func resolveSubqueries(ctx *sql.Context, a *Analyzer, n sql.Node) (sql.Node, err
 		}
 	})
 }
+
+// scope contains the information about the outer query that is needed to
+// resolve the columns of a subquery expression referencing it. If the outer
+// query is itself a subquery, parent is the scope of the query enclosing
+// it, so columns can be resolved to any of the enclosing queries, from the
+// innermost to the outermost.
+type scope struct {
+	schema  sql.Schema
+	aliases map[string]string
+	parent  *scope
+}
+
+func newScope(n sql.Node, parent *scope) *scope {
+	var schema sql.Schema
+	if plan.IsUnary(n) {
+		schema = n.Children()[0].Schema()
+	} else {
+		schema = n.Schema()
+	}
+
+	aliases := make(map[string]string)
+	plan.Inspect(n, func(n sql.Node) bool {
+		if alias, ok := n.(*plan.TableAlias); ok {
+			if t, ok := alias.Child.(sql.Nameable); ok {
+				aliases[strings.ToLower(alias.Name())] = strings.ToLower(t.Name())
+			}
+		}
+		return true
+	})
+
+	return &scope{schema, aliases, parent}
+}
+
+// table returns the name of the table with the given name or alias in the
+// innermost outer query that has it, if any.
+func (s *scope) table(name string) (string, bool) {
+	name = strings.ToLower(name)
+	for ; s != nil; s = s.parent {
+		if real, ok := s.aliases[name]; ok {
+			return real, true
+		}
+
+		for _, col := range s.schema {
+			if strings.ToLower(col.Source) == name {
+				return name, true
+			}
+		}
+	}
+
+	return "", false
+}
+
+// column returns an outer field for the column with the given table and
+// name in the innermost outer query that has it, or nil if there is none.
+func (s *scope) column(table, name string) (sql.Expression, error) {
+	for depth := 0; s != nil; depth, s = depth+1, s.parent {
+		outer, err := s.localColumn(table, name)
+		if err != nil {
+			return nil, err
+		}
+
+		if outer != nil {
+			return outer.WithDepth(depth), nil
+		}
+	}
+
+	return nil, nil
+}
+
+// localColumn returns an outer field for the column with the given table
+// and name in the query of this scope, or nil if there is none.
+func (s *scope) localColumn(table, name string) (*expression.OuterField, error) {
+	table = strings.ToLower(table)
+	name = strings.ToLower(name)
+
+	var found []int
+	var tables []string
+	for i, col := range s.schema {
+		if strings.ToLower(col.Name) != name {
+			continue
+		}
+
+		if table != "" && strings.ToLower(col.Source) != table {
+			continue
+		}
+
+		found = append(found, i)
+		tables = append(tables, col.Source)
+	}
+
+	switch len(found) {
+	case 0:
+		return nil, nil
+	case 1:
+		col := s.schema[found[0]]
+		return expression.NewOuterField(
+			found[0],
+			col.Type,
+			col.Source,
+			col.Name,
+			col.Nullable,
+		), nil
+	default:
+		return nil, ErrAmbiguousColumnName.New(name, strings.Join(tables, ", "))
+	}
+}
+
+// resolveSubqueryExpressions analyzes the queries of all subquery expressions
+// once the children of the node containing them are resolved, so they can
+// reference the columns of the outer query.
+func resolveSubqueryExpressions(ctx *sql.Context, a *Analyzer, n sql.Node) (sql.Node, error) {
+	span, ctx := ctx.Span("resolve_subquery_exprs")
+	defer span.Finish()
+
+	a.Log("resolving subquery expressions, node of type: %T", n)
+	return n.TransformUp(func(n sql.Node) (sql.Node, error) {
+		if n.Resolved() {
+			return n, nil
+		}
+
+		expressioner, ok := n.(sql.Expressioner)
+		if !ok {
+			return n, nil
+		}
+
+		for _, c := range n.Children() {
+			if !c.Resolved() {
+				return n, nil
+			}
+		}
+
+		var s *scope
+		return expressioner.TransformExpressions(func(e sql.Expression) (sql.Expression, error) {
+			subquery, ok := e.(*expression.Subquery)
+			if !ok || subquery.Resolved() {
+				return e, nil
+			}
+
+			if s == nil {
+				s = newScope(n, a.scope)
+			}
+
+			a.Log("resolving subquery expression %s", subquery)
+			return resolveSubqueryExpression(ctx, a, subquery, s)
+		})
+	})
+}
+
+func resolveSubqueryExpression(
+	ctx *sql.Context,
+	a *Analyzer,
+	subquery *expression.Subquery,
+	s *scope,
+) (sql.Expression, error) {
+	sa := *a
+	sa.scope = s
+
+	query, err := sa.Analyze(ctx, subquery.Query)
+	if err != nil {
+		return nil, err
+	}
+
+	// The process is the one of the outer query, so it must not be marked
+	// as done when the subquery finishes.
+	query = stripQueryProcess(query)
+
+	// Outer fields are resolved with their index in the schema of the outer
+	// query. Every referenced column is added as an outer expression of the
+	// subquery, so its index can be fixed by the rest of the rules if the
+	// outer schema changes, and the outer field is modified to point to it.
+	// Columns of the queries enclosing the outer one are added as outer
+	// fields of the outer query, which will point them to its own outer
+	// expressions when it's resolved, and so on.
+	type outerKey struct{ depth, index int }
+	var outer []sql.Expression
+	var indexes = make(map[outerKey]int)
+	query, err = query.TransformExpressionsUp(func(e sql.Expression) (sql.Expression, error) {
+		f, ok := e.(*expression.OuterField)
+		if !ok {
+			return e, nil
+		}
+
+		key := outerKey{f.Depth(), f.Index()}
+		idx, ok := indexes[key]
+		if !ok {
+			idx = len(outer)
+			indexes[key] = idx
+			if f.Depth() > 0 {
+				outer = append(outer, f.WithDepth(f.Depth()-1))
+			} else {
+				outer = append(outer, expression.NewGetFieldWithTable(
+					f.Index(),
+					f.Type(),
+					f.Table(),
+					f.Name(),
+					f.IsNullable(),
+				))
+			}
+		}
+
+		return f.WithIndex(idx).WithDepth(0), nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return expression.NewCorrelatedSubquery(query, outer), nil
+}
+
+// stripQueryProcess returns the child of the given node if it's a query
+// process, or the node itself otherwise.
+func stripQueryProcess(n sql.Node) sql.Node {
+	if qp, ok := n.(*plan.QueryProcess); ok {
+		return qp.Child
+	}
+	return n
+}
@@ -88,3 +88,101 @@ func TestResolveSubqueries(t *testing.T) {
 	require.NoError(err)
 	require.Equal(expected, result)
 }
+
+func TestResolveSubqueryExpressions(t *testing.T) {
+	require := require.New(t)
+	ctx := sql.NewEmptyContext()
+
+	table1 := mem.NewTable("foo", sql.Schema{{Name: "a", Type: sql.Int64, Source: "foo"}})
+	table2 := mem.NewTable("bar", sql.Schema{{Name: "b", Type: sql.Int64, Source: "bar"}})
+	for _, i := range []int64{1, 2, 3} {
+		require.NoError(table1.Insert(ctx, sql.NewRow(i)))
+		require.NoError(table2.Insert(ctx, sql.NewRow(i+1)))
+	}
+
+	db := mem.NewDatabase("mydb")
+	db.AddTable("foo", table1)
+	db.AddTable("bar", table2)
+
+	catalog := sql.NewCatalog()
+	catalog.AddDatabase(db)
+	a := withoutProcessTracking(NewDefault(catalog))
+
+	// SELECT a FROM foo f WHERE EXISTS (SELECT b FROM bar WHERE b = f.a)
+	node := plan.NewFilter(
+		expression.NewExists(expression.NewSubquery(
+			plan.NewProject(
+				[]sql.Expression{expression.NewUnresolvedColumn("b")},
+				plan.NewFilter(
+					expression.NewEquals(
+						expression.NewUnresolvedColumn("b"),
+						expression.NewUnresolvedQualifiedColumn("f", "a"),
+					),
+					plan.NewUnresolvedTable("bar", ""),
+				),
+			),
+		)),
+		plan.NewTableAlias("f", plan.NewResolvedTable(table1)),
+	)
+
+	result, err := resolveSubqueryExpressions(ctx, a, node)
+	require.NoError(err)
+	require.True(result.Resolved())
+
+	subquery := result.(*plan.Filter).Expression.(*expression.Exists).Child.(*expression.Subquery)
+	require.True(subquery.IsCorrelated())
+	require.Equal(
+		[]sql.Expression{expression.NewGetFieldWithTable(0, sql.Int64, "foo", "a", false)},
+		subquery.Outer,
+	)
+
+	var outerFields []sql.Expression
+	plan.InspectExpressions(subquery.Query, func(e sql.Expression) bool {
+		if f, ok := e.(*expression.OuterField); ok {
+			outerFields = append(outerFields, f)
+		}
+		return true
+	})
+	require.Equal(
+		[]sql.Expression{expression.NewOuterField(0, sql.Int64, "foo", "a", false)},
+		outerFields,
+	)
+
+	iter, err := result.RowIter(ctx)
+	require.NoError(err)
+	rows, err := sql.RowIterToRows(iter)
+	require.NoError(err)
+	require.Equal([]sql.Row{{int64(2)}, {int64(3)}}, rows)
+}
+
+func TestScopeColumn(t *testing.T) {
+	require := require.New(t)
+
+	outer := &scope{
+		schema:  sql.Schema{{Name: "a", Type: sql.Int64, Source: "foo"}},
+		aliases: map[string]string{"f": "foo"},
+	}
+	inner := &scope{
+		schema: sql.Schema{
+			{Name: "b", Type: sql.Int64, Source: "bar"},
+			{Name: "c", Type: sql.Int64, Source: "bar"},
+		},
+		parent: outer,
+	}
+
+	col, err := inner.column("bar", "c")
+	require.NoError(err)
+	require.Equal(expression.NewOuterField(1, sql.Int64, "bar", "c", false), col)
+
+	col, err = inner.column("foo", "a")
+	require.NoError(err)
+	require.Equal(expression.NewOuterField(0, sql.Int64, "foo", "a", false).WithDepth(1), col)
+
+	real, ok := inner.table("f")
+	require.True(ok)
+	require.Equal("foo", real)
+
+	col, err = inner.column("", "d")
+	require.NoError(err)
+	require.Nil(col)
+}
@@ -40,12 +40,19 @@ func resolveTables(ctx *sql.Context, a *Analyzer, n sql.Node) (sql.Node, error)
 
 		rt, err := a.Catalog.Table(db, name)
 		if err != nil {
-			if sql.ErrTableNotFound.Is(err) && name == dualTableName {
-				rt = dualTable
-				name = dualTableName
-			} else {
+			if !sql.ErrTableNotFound.Is(err) {
 				return nil, err
 			}
+
+			if view, ok := a.Catalog.View(db, name); ok {
+				return resolveView(ctx, a, name, view)
+			}
+
+			if name != dualTableName {
+				return nil, err
+			}
+
+			rt = dualTable
 		}
 
 		a.Log("table resolved: %q", t.Name())
@@ -53,3 +60,17 @@ func resolveTables(ctx *sql.Context, a *Analyzer, n sql.Node) (sql.Node, error)
 		return plan.NewResolvedTable(rt), nil
 	})
 }
+
+// resolveView returns the definition of the given view, analyzed as a
+// subquery aliased with the name used to reference the view, so the
+// optimizations are also applied to the tables of the view.
+func resolveView(ctx *sql.Context, a *Analyzer, name string, view sql.View) (sql.Node, error) {
+	a.Log("view resolved: %q", name)
+
+	child, err := a.Analyze(ctx, view.Definition)
+	if err != nil {
+		return nil, err
+	}
+
+	return plan.NewSubqueryAlias(name, child), nil
+}
@@ -0,0 +1,172 @@
+package analyzer
+
+import (
+	errors "gopkg.in/src-d/go-errors.v1"
+	"gopkg.in/src-d/go-mysql-server.v0/sql"
+	"gopkg.in/src-d/go-mysql-server.v0/sql/expression"
+	"gopkg.in/src-d/go-mysql-server.v0/sql/plan"
+)
+
+// ErrSetOperationColumns is returned when the queries of an UNION, INTERSECT
+// or EXCEPT don't have the same number of columns.
+var ErrSetOperationColumns = errors.NewKind("the used SELECT statements have a different number of columns: %d and %d")
+
+// resolveUnions analyzes each side of the set operations on their own, as
+// they are independent queries, and converts the columns of both sides to
+// the same types if they don't match.
+func resolveUnions(ctx *sql.Context, a *Analyzer, n sql.Node) (sql.Node, error) {
+	span, ctx := ctx.Span("resolve_unions")
+	defer span.Finish()
+
+	a.Log("resolving unions")
+	return n.TransformUp(func(n sql.Node) (sql.Node, error) {
+		switch n := n.(type) {
+		case *plan.Union:
+			left, right, err := resolveSetOperation(ctx, a, n.BinaryNode)
+			if err != nil {
+				return nil, err
+			}
+
+			return plan.NewUnion(left, right), nil
+		case *plan.Intersect:
+			left, right, err := resolveSetOperation(ctx, a, n.BinaryNode)
+			if err != nil {
+				return nil, err
+			}
+
+			return plan.NewIntersect(left, right), nil
+		case *plan.Except:
+			left, right, err := resolveSetOperation(ctx, a, n.BinaryNode)
+			if err != nil {
+				return nil, err
+			}
+
+			return plan.NewExcept(left, right), nil
+		default:
+			return n, nil
+		}
+	})
+}
+
+func resolveSetOperation(
+	ctx *sql.Context,
+	a *Analyzer,
+	n plan.BinaryNode,
+) (sql.Node, sql.Node, error) {
+	left, err := analyzeSetOperand(ctx, a, n.Left)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	right, err := analyzeSetOperand(ctx, a, n.Right)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	ls, rs := left.Schema(), right.Schema()
+	if len(ls) != len(rs) {
+		return nil, nil, ErrSetOperationColumns.New(len(ls), len(rs))
+	}
+
+	var leftTypes = make([]string, len(ls))
+	var rightTypes = make([]string, len(rs))
+	for i := range ls {
+		lt, rt := ls[i].Type, rs[i].Type
+		if lt == rt || lt == sql.Null || rt == sql.Null {
+			continue
+		}
+
+		castType := setOperationCastType(lt, rt)
+		typ := expression.NewConvert(nil, castType).Type()
+		if lt != typ {
+			leftTypes[i] = castType
+		}
+
+		if rt != typ {
+			rightTypes[i] = castType
+		}
+	}
+
+	return convertSetOperand(left, leftTypes), convertSetOperand(right, rightTypes), nil
+}
+
+func analyzeSetOperand(ctx *sql.Context, a *Analyzer, n sql.Node) (sql.Node, error) {
+	// Nested set operations have already been resolved.
+	if n.Resolved() {
+		return n, nil
+	}
+
+	a.Log("analyzing set operand of type %T", n)
+	n, err := a.Analyze(ctx, n)
+	if err != nil {
+		return nil, err
+	}
+
+	return stripQueryProcess(n), nil
+}
+
+// setOperationCastType returns the type both sides of a set operation must
+// be converted to if their columns have the given types.
+func setOperationCastType(left, right sql.Type) string {
+	if sql.IsNumber(left) && sql.IsNumber(right) {
+		switch {
+		case sql.IsDecimal(left) || sql.IsDecimal(right),
+			sql.IsFixedPoint(left) || sql.IsFixedPoint(right):
+			return expression.ConvertToDecimal
+		case sql.IsUnsigned(left) && sql.IsUnsigned(right):
+			return expression.ConvertToUnsigned
+		default:
+			return expression.ConvertToSigned
+		}
+	}
+
+	return expression.ConvertToChar
+}
+
+// convertSetOperand adds a projection on top of the given node converting
+// its columns to the given types. Columns without type are left as is.
+func convertSetOperand(n sql.Node, types []string) sql.Node {
+	var needsConvert bool
+	for _, t := range types {
+		if t != "" {
+			needsConvert = true
+			break
+		}
+	}
+
+	if !needsConvert {
+		return n
+	}
+
+	schema := n.Schema()
+	var projection = make([]sql.Expression, len(schema))
+	for i, col := range schema {
+		projection[i] = expression.NewGetFieldWithTable(
+			i, col.Type, col.Source, col.Name, col.Nullable,
+		)
+
+		if types[i] != "" {
+			projection[i] = expression.NewAlias(
+				expression.NewConvert(projection[i], types[i]),
+				col.Name,
+			)
+		}
+	}
+
+	return plan.NewProject(projection, n)
+}
+
+// hasSetOperations reports whether there is any set operation in the given
+// node.
+func hasSetOperations(n sql.Node) bool {
+	var found bool
+	plan.Inspect(n, func(n sql.Node) bool {
+		switch n.(type) {
+		case *plan.Union, *plan.Intersect, *plan.Except:
+			found = true
+			return false
+		}
+		return true
+	})
+	return found
+}
@@ -0,0 +1,94 @@
+package analyzer
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"gopkg.in/src-d/go-mysql-server.v0/mem"
+	"gopkg.in/src-d/go-mysql-server.v0/sql"
+	"gopkg.in/src-d/go-mysql-server.v0/sql/expression"
+	"gopkg.in/src-d/go-mysql-server.v0/sql/plan"
+)
+
+func TestResolveUnions(t *testing.T) {
+	require := require.New(t)
+
+	table1 := mem.NewTable("foo", sql.Schema{
+		{Name: "a", Type: sql.Int64, Source: "foo"},
+		{Name: "b", Type: sql.Text, Source: "foo"},
+	})
+	table2 := mem.NewTable("bar", sql.Schema{
+		{Name: "c", Type: sql.Text, Source: "bar"},
+		{Name: "d", Type: sql.Text, Source: "bar"},
+	})
+	db := mem.NewDatabase("mydb")
+	db.AddTable("foo", table1)
+	db.AddTable("bar", table2)
+
+	catalog := sql.NewCatalog()
+	catalog.AddDatabase(db)
+	a := withoutProcessTracking(NewDefault(catalog))
+
+	ctx := sql.NewEmptyContext()
+
+	// SELECT a, b FROM foo UNION ALL SELECT c, d FROM bar
+	node := plan.NewUnion(
+		plan.NewProject(
+			[]sql.Expression{
+				expression.NewUnresolvedColumn("a"),
+				expression.NewUnresolvedColumn("b"),
+			},
+			plan.NewUnresolvedTable("foo", ""),
+		),
+		plan.NewProject(
+			[]sql.Expression{
+				expression.NewUnresolvedColumn("c"),
+				expression.NewUnresolvedColumn("d"),
+			},
+			plan.NewUnresolvedTable("bar", ""),
+		),
+	)
+
+	result, err := resolveUnions(ctx, a, node)
+	require.NoError(err)
+	require.True(result.Resolved())
+
+	union, ok := result.(*plan.Union)
+	require.True(ok)
+
+	left, ok := union.Left.(*plan.Project)
+	require.True(ok)
+	require.Equal(
+		expression.NewAlias(
+			expression.NewConvert(
+				expression.NewGetFieldWithTable(0, sql.Int64, "foo", "a", false),
+				expression.ConvertToChar,
+			),
+			"a",
+		),
+		left.Projections[0],
+	)
+
+	for _, col := range union.Schema() {
+		require.Equal(sql.Text, col.Type)
+	}
+
+	// SELECT a FROM foo UNION ALL SELECT c, d FROM bar
+	node = plan.NewUnion(
+		plan.NewProject(
+			[]sql.Expression{expression.NewUnresolvedColumn("a")},
+			plan.NewUnresolvedTable("foo", ""),
+		),
+		plan.NewProject(
+			[]sql.Expression{
+				expression.NewUnresolvedColumn("c"),
+				expression.NewUnresolvedColumn("d"),
+			},
+			plan.NewUnresolvedTable("bar", ""),
+		),
+	)
+
+	_, err = resolveUnions(ctx, a, node)
+	require.Error(err)
+	require.True(ErrSetOperationColumns.Is(err))
+}
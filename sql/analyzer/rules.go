@@ -12,10 +12,14 @@ var DefaultRules = []Rule{
 	{"resolve_grouping_columns", resolveGroupingColumns},
 	{"qualify_columns", qualifyColumns},
 	{"resolve_columns", resolveColumns},
+	{"resolve_subquery_exprs", resolveSubqueryExpressions},
 	{"resolve_database", resolveDatabase},
 	{"resolve_star", resolveStar},
+	{"resolve_cte_columns", resolveCTEColumns},
 	{"resolve_functions", resolveFunctions},
+	{"resolve_having", resolveHaving},
 	{"reorder_aggregations", reorderAggregations},
+	{"resolve_windows", resolveWindows},
 	{"reorder_projection", reorderProjection},
 	{"move_join_conds_to_filter", moveJoinConditionsToFilter},
 	{"eval_filter", evalFilter},
@@ -26,7 +30,10 @@ var DefaultRules = []Rule{
 // DefaultRules.
 var OnceBeforeDefault = []Rule{
 	{"resolve_subqueries", resolveSubqueries},
+	{"resolve_unions", resolveUnions},
+	{"resolve_recursive_ctes", resolveRecursiveCTEs},
 	{"resolve_tables", resolveTables},
+	{"resolve_column_defaults", resolveColumnDefaults},
 }
 
 // OnceAfterDefault contains the rules to be applied just once after the
@@ -0,0 +1,142 @@
+package analyzer
+
+import (
+	"gopkg.in/src-d/go-mysql-server.v0/sql"
+	"gopkg.in/src-d/go-mysql-server.v0/sql/expression"
+	"gopkg.in/src-d/go-mysql-server.v0/sql/plan"
+)
+
+// resolveWindows replaces the projections with windows with Window nodes,
+// which are the ones that can evaluate them. Like aggregations, windows
+// that are not at the root of their expression are moved to the window
+// node and a projection is added on top of it to compute the expressions.
+func resolveWindows(ctx *sql.Context, a *Analyzer, n sql.Node) (sql.Node, error) {
+	span, _ := ctx.Span("resolve_windows")
+	defer span.Finish()
+
+	if !n.Resolved() {
+		return n, nil
+	}
+
+	a.Log("resolve windows, node of type: %T", n)
+
+	return n.TransformUp(func(n sql.Node) (sql.Node, error) {
+		p, ok := n.(*plan.Project)
+		if !ok || !hasWindows(p.Projections...) {
+			return n, nil
+		}
+
+		for _, e := range p.Projections {
+			if err := validateWindowFunctions(e); err != nil {
+				return nil, err
+			}
+		}
+
+		if !hasHiddenWindows(p.Projections...) {
+			a.Log("replacing projection with window")
+			return plan.NewWindow(p.Projections, p.Child), nil
+		}
+
+		a.Log("fixing windows of projection")
+
+		return fixWindows(p.Projections, p.Child)
+	})
+}
+
+func fixWindows(projection []sql.Expression, child sql.Node) (sql.Node, error) {
+	var windows = make([]sql.Expression, 0, len(projection))
+	var newProjection = make([]sql.Expression, len(projection))
+
+	for i, p := range projection {
+		var transformed bool
+		e, err := p.TransformUp(func(e sql.Expression) (sql.Expression, error) {
+			w, ok := e.(*expression.Window)
+			if !ok {
+				return e, nil
+			}
+
+			transformed = true
+			windows = append(windows, w)
+			return expression.NewGetField(
+				len(windows)-1, w.Type(), w.String(), w.IsNullable(),
+			), nil
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		if !transformed {
+			windows = append(windows, e)
+			name, source := getNameAndSource(e)
+			newProjection[i] = expression.NewGetFieldWithTable(
+				len(windows)-1, e.Type(), source, name, e.IsNullable(),
+			)
+		} else {
+			newProjection[i] = e
+		}
+	}
+
+	return plan.NewProject(
+		newProjection,
+		plan.NewWindow(windows, child),
+	), nil
+}
+
+// validateWindowFunctions returns an error if any of the windows of the
+// given expression has a function that is neither a window function nor
+// an aggregation.
+func validateWindowFunctions(e sql.Expression) error {
+	var err error
+	expression.Inspect(e, func(e sql.Expression) bool {
+		w, ok := e.(*expression.Window)
+		if !ok || err != nil {
+			return err == nil
+		}
+
+		switch w.Function.(type) {
+		case sql.WindowFunction, sql.Aggregation:
+		default:
+			err = plan.ErrNotWindowFunction.New(w.Function)
+		}
+
+		return false
+	})
+	return err
+}
+
+// hasWindows reports whether any of the given expressions has a window.
+func hasWindows(exprs ...sql.Expression) bool {
+	for _, e := range exprs {
+		if containsWindow(e) {
+			return true
+		}
+	}
+	return false
+}
+
+// hasHiddenWindows reports whether any of the given expressions has a
+// window that is not at the root of the expression or of its alias.
+func hasHiddenWindows(exprs ...sql.Expression) bool {
+	for _, e := range exprs {
+		if alias, ok := e.(*expression.Alias); ok {
+			e = alias.Child
+		}
+
+		if _, ok := e.(*expression.Window); !ok && containsWindow(e) {
+			return true
+		}
+	}
+	return false
+}
+
+func containsWindow(e sql.Expression) bool {
+	var hasWindow bool
+	expression.Inspect(e, func(e sql.Expression) bool {
+		if _, ok := e.(*expression.Window); ok {
+			hasWindow = true
+			return false
+		}
+		return true
+	})
+	return hasWindow
+}
@@ -0,0 +1,76 @@
+package analyzer
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"gopkg.in/src-d/go-mysql-server.v0/mem"
+	"gopkg.in/src-d/go-mysql-server.v0/sql"
+	"gopkg.in/src-d/go-mysql-server.v0/sql/expression"
+	"gopkg.in/src-d/go-mysql-server.v0/sql/expression/function"
+	"gopkg.in/src-d/go-mysql-server.v0/sql/plan"
+)
+
+func TestResolveWindows(t *testing.T) {
+	require := require.New(t)
+
+	table := plan.NewResolvedTable(mem.NewTable("foo", sql.Schema{
+		{Name: "a", Type: sql.Int64, Source: "foo"},
+		{Name: "b", Type: sql.Int64, Source: "foo"},
+	}))
+	rule := getRule("resolve_windows")
+
+	a := expression.NewGetFieldWithTable(0, sql.Int64, "foo", "a", false)
+	window := expression.NewWindow(
+		function.NewRowNumber(),
+		[]sql.Expression{a},
+		[]expression.WindowSortField{
+			{Column: expression.NewGetFieldWithTable(1, sql.Int64, "foo", "b", false)},
+		},
+		nil,
+	)
+
+	node := plan.NewProject(
+		[]sql.Expression{a, expression.NewAlias(window, "n")},
+		table,
+	)
+
+	result, err := rule.Apply(sql.NewEmptyContext(), NewDefault(nil), node)
+	require.NoError(err)
+	require.Equal(plan.NewWindow(node.Projections, table), result)
+
+	node = plan.NewProject(
+		[]sql.Expression{
+			a,
+			expression.NewArithmetic(window, expression.NewLiteral(int64(1), sql.Int64), "+"),
+		},
+		table,
+	)
+
+	expected := plan.NewProject(
+		[]sql.Expression{
+			expression.NewGetFieldWithTable(0, sql.Int64, "foo", "a", false),
+			expression.NewArithmetic(
+				expression.NewGetField(1, sql.Uint64, window.String(), false),
+				expression.NewLiteral(int64(1), sql.Int64),
+				"+",
+			),
+		},
+		plan.NewWindow([]sql.Expression{a, window}, table),
+	)
+
+	result, err = rule.Apply(sql.NewEmptyContext(), NewDefault(nil), node)
+	require.NoError(err)
+	require.Equal(expected, result)
+
+	node = plan.NewProject(
+		[]sql.Expression{
+			expression.NewWindow(expression.NewLiteral(int64(1), sql.Int64), nil, nil, nil),
+		},
+		table,
+	)
+
+	_, err = rule.Apply(sql.NewEmptyContext(), NewDefault(nil), node)
+	require.Error(err)
+	require.True(plan.ErrNotWindowFunction.Is(err))
+}
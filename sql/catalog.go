@@ -8,14 +8,41 @@ import (
 	"gopkg.in/src-d/go-errors.v1"
 )
 
-// ErrDatabaseNotFound is thrown when a database is not found
-var ErrDatabaseNotFound = errors.NewKind("database not found: %s")
+var (
+	// ErrDatabaseNotFound is thrown when a database is not found
+	ErrDatabaseNotFound = errors.NewKind("database not found: %s")
 
-// Catalog holds databases, tables and functions.
+	// ErrDatabaseExists is thrown when a database with the same name
+	// already exists.
+	ErrDatabaseExists = errors.NewKind("can't create database %s; database exists")
+
+	// ErrNoDatabaseProvider is thrown when databases are created or dropped
+	// and the catalog has no database provider.
+	ErrNoDatabaseProvider = errors.NewKind("there is no database provider to create or drop databases")
+
+	// ErrCannotDropDatabase is thrown when trying to drop a database that
+	// can't be dropped.
+	ErrCannotDropDatabase = errors.NewKind("can't drop database %s")
+)
+
+// DatabaseProvider creates and drops the databases of a catalog.
+type DatabaseProvider interface {
+	// CreateDatabase creates a new database with the given name.
+	CreateDatabase(ctx *Context, name string) (Database, error)
+	// DropDatabase drops the given database.
+	DropDatabase(ctx *Context, db Database) error
+}
+
+// Catalog holds databases, tables, views and functions.
 type Catalog struct {
 	FunctionRegistry
 	*IndexRegistry
 	*ProcessList
+	*ViewRegistry
+
+	// DatabaseProvider is used to create and drop databases. If it's nil,
+	// databases can't be created or dropped.
+	DatabaseProvider DatabaseProvider
 
 	mu              sync.RWMutex
 	currentDatabase string
@@ -35,6 +62,7 @@ func NewCatalog() *Catalog {
 		FunctionRegistry: NewFunctionRegistry(),
 		IndexRegistry:    NewIndexRegistry(),
 		ProcessList:      NewProcessList(),
+		ViewRegistry:     NewViewRegistry(),
 		locks:            make(sessionLocks),
 	}
 }
@@ -74,6 +102,111 @@ func (c *Catalog) AddDatabase(db Database) {
 	c.mu.Unlock()
 }
 
+// CreateDatabase creates a new database with the given name using the
+// database provider and adds it to the catalog.
+func (c *Catalog) CreateDatabase(ctx *Context, name string) (Database, error) {
+	if c.DatabaseProvider == nil {
+		return nil, ErrNoDatabaseProvider.New()
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if _, err := c.dbs.Database(name); err == nil {
+		return nil, ErrDatabaseExists.New(name)
+	}
+
+	db, err := c.DatabaseProvider.CreateDatabase(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+
+	if c.currentDatabase == "" {
+		c.currentDatabase = db.Name()
+	}
+
+	c.dbs.Add(db)
+	return db, nil
+}
+
+// DropDatabase drops the database with the given name using the database
+// provider and removes it from the catalog. All the indexes of its tables
+// and its views are deleted and all the locks held on its tables are
+// released.
+func (c *Catalog) DropDatabase(ctx *Context, name string) error {
+	if c.DatabaseProvider == nil {
+		return ErrNoDatabaseProvider.New()
+	}
+
+	if strings.ToLower(name) == InformationSchemaDatabaseName {
+		return ErrCannotDropDatabase.New(name)
+	}
+
+	db, err := c.Database(name)
+	if err != nil {
+		return err
+	}
+
+	for _, table := range db.Tables() {
+		if err := c.DeleteTableIndexes(ctx, db.Name(), table); err != nil {
+			return err
+		}
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if err := c.releaseDatabaseLocks(ctx, db); err != nil {
+		return err
+	}
+
+	if err := c.DatabaseProvider.DropDatabase(ctx, db); err != nil {
+		return err
+	}
+
+	c.dbs.Remove(db.Name())
+	c.ViewRegistry.DeleteDatabaseViews(db.Name())
+	if strings.ToLower(c.currentDatabase) == strings.ToLower(db.Name()) {
+		c.currentDatabase = ""
+	}
+
+	return nil
+}
+
+// releaseDatabaseLocks unlocks the tables of the given database locked by
+// any session client. It is assumed the caller holds the catalog lock.
+func (c *Catalog) releaseDatabaseLocks(ctx *Context, db Database) error {
+	var errors []string
+	for id, dbs := range c.locks {
+		for name, tables := range dbs {
+			if strings.ToLower(name) != strings.ToLower(db.Name()) {
+				continue
+			}
+
+			for t := range tables {
+				table, err := c.dbs.Table(name, t)
+				if err != nil {
+					continue
+				}
+
+				if lockable, ok := table.(Lockable); ok {
+					if err := lockable.Unlock(ctx, id); err != nil {
+						errors = append(errors, err.Error())
+					}
+				}
+			}
+
+			delete(dbs, name)
+		}
+	}
+
+	if len(errors) > 0 {
+		return fmt.Errorf("error unlocking tables of database %s: %s", db.Name(), strings.Join(errors, ", "))
+	}
+
+	return nil
+}
+
 // Database returns the database with the given name.
 func (c *Catalog) Database(db string) (Database, error) {
 	c.mu.RLock()
@@ -108,6 +241,17 @@ func (d *Databases) Add(db Database) {
 	*d = append(*d, db)
 }
 
+// Remove removes the database with the given name, if it exists.
+func (d *Databases) Remove(name string) {
+	name = strings.ToLower(name)
+	for i, db := range *d {
+		if strings.ToLower(db.Name()) == name {
+			*d = append((*d)[:i:i], (*d)[i+1:]...)
+			return
+		}
+	}
+}
+
 // Table returns the Table with the given name if it exists.
 func (d Databases) Table(dbName string, tableName string) (Table, error) {
 	db, err := d.Database(dbName)
@@ -122,3 +122,68 @@ func (l *lockableTable) Unlock(ctx *sql.Context, id uint32) error {
 	l.unlocks++
 	return nil
 }
+
+func TestCatalogCreateDatabase(t *testing.T) {
+	require := require.New(t)
+
+	c := sql.NewCatalog()
+	_, err := c.CreateDatabase(sql.NewEmptyContext(), "foo")
+	require.True(sql.ErrNoDatabaseProvider.Is(err))
+
+	c.DatabaseProvider = mem.NewDatabaseProvider()
+	db, err := c.CreateDatabase(sql.NewEmptyContext(), "foo")
+	require.NoError(err)
+	require.Equal("foo", db.Name())
+	require.Equal("foo", c.CurrentDatabase())
+
+	result, err := c.Database("foo")
+	require.NoError(err)
+	require.Equal(db, result)
+
+	_, err = c.CreateDatabase(sql.NewEmptyContext(), "FOO")
+	require.True(sql.ErrDatabaseExists.Is(err))
+}
+
+func TestCatalogDropDatabase(t *testing.T) {
+	require := require.New(t)
+	ctx := sql.NewEmptyContext()
+
+	db := mem.NewDatabase("foo")
+	t1 := newLockableTable(mem.NewTable("t1", nil))
+	db.AddTable("t1", t1)
+
+	other := mem.NewDatabase("bar")
+	t2 := newLockableTable(mem.NewTable("t2", nil))
+	other.AddTable("t2", t2)
+
+	c := sql.NewCatalog()
+	c.AddDatabase(db)
+	c.AddDatabase(other)
+
+	require.True(sql.ErrNoDatabaseProvider.Is(c.DropDatabase(ctx, "foo")))
+
+	c.DatabaseProvider = mem.NewDatabaseProvider()
+	c.LockTable(1, "t1")
+	c.SetCurrentDatabase("bar")
+	c.LockTable(1, "t2")
+	c.SetCurrentDatabase("foo")
+
+	require.NoError(c.DropDatabase(ctx, "foo"))
+	require.Equal(1, t1.unlocks)
+	require.Equal(0, t2.unlocks)
+	require.Equal("", c.CurrentDatabase())
+	require.Equal(sql.Databases{other}, c.AllDatabases())
+
+	_, err := c.Database("foo")
+	require.True(sql.ErrDatabaseNotFound.Is(err))
+
+	require.NoError(c.UnlockTables(ctx, 1))
+	require.Equal(1, t1.unlocks)
+	require.Equal(1, t2.unlocks)
+
+	err = c.DropDatabase(ctx, "foo")
+	require.True(sql.ErrDatabaseNotFound.Is(err))
+
+	err = c.DropDatabase(ctx, sql.InformationSchemaDatabaseName)
+	require.True(sql.ErrCannotDropDatabase.Is(err))
+}
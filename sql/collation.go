@@ -0,0 +1,224 @@
+package sql
+
+import (
+	"fmt"
+	"hash/crc64"
+	"strings"
+
+	"gopkg.in/src-d/go-errors.v1"
+)
+
+var (
+	// ErrUnknownCollation is returned when a collation does not exist.
+	ErrUnknownCollation = errors.NewKind("unknown collation: %s")
+
+	// ErrUnknownCharacterSet is returned when a character set does not
+	// exist.
+	ErrUnknownCharacterSet = errors.NewKind("unknown character set: %s")
+
+	// ErrCollationNotSupported is returned when a collation is used with a
+	// type that is not a character string.
+	ErrCollationNotSupported = errors.NewKind("collation %s is not valid for type %s")
+)
+
+// Collation is a set of rules to compare and sort strings.
+type Collation struct {
+	// Name is the name of the collation.
+	Name string
+	// CharacterSet is the name of the character set of the collation.
+	CharacterSet string
+	// ID is the identifier MySQL uses for the collation.
+	ID uint64
+	// Default reports whether it's the default collation of its character
+	// set.
+	Default bool
+	// CaseInsensitive reports whether the collation ignores the case of the
+	// strings when comparing them. Case insensitive collations also ignore
+	// trailing spaces.
+	CaseInsensitive bool
+}
+
+func (c Collation) String() string { return c.Name }
+
+// Compare compares the two given strings using the collation.
+func (c Collation) Compare(a, b string) int {
+	if !c.CaseInsensitive {
+		return strings.Compare(a, b)
+	}
+
+	return strings.Compare(c.Key(a), c.Key(b))
+}
+
+// Key returns a string that is equal for all the strings that are equal
+// using the collation, so it can be used to group and hash them.
+func (c Collation) Key(s string) string {
+	if !c.CaseInsensitive {
+		return s
+	}
+
+	return strings.ToUpper(strings.TrimRight(s, " "))
+}
+
+var (
+	// CollationBinary is the collation of binary strings.
+	CollationBinary = Collation{Name: "binary", CharacterSet: "binary", ID: 63, Default: true}
+	// CollationUtf8GeneralCI is the case insensitive collation of utf8.
+	CollationUtf8GeneralCI = Collation{Name: "utf8_general_ci", CharacterSet: "utf8", ID: 33, Default: true, CaseInsensitive: true}
+	// CollationUtf8Bin is the binary collation of utf8.
+	CollationUtf8Bin = Collation{Name: "utf8_bin", CharacterSet: "utf8", ID: 83}
+	// CollationUtf8mb4GeneralCI is the case insensitive collation of
+	// utf8mb4.
+	CollationUtf8mb4GeneralCI = Collation{Name: "utf8mb4_general_ci", CharacterSet: "utf8mb4", ID: 45, Default: true, CaseInsensitive: true}
+	// CollationUtf8mb4Bin is the binary collation of utf8mb4.
+	CollationUtf8mb4Bin = Collation{Name: "utf8mb4_bin", CharacterSet: "utf8mb4", ID: 46}
+	// CollationUtf8mb4UnicodeCI is the case insensitive collation of utf8mb4
+	// based on the Unicode Collation Algorithm. Strings are compared like
+	// with utf8mb4_general_ci.
+	CollationUtf8mb4UnicodeCI = Collation{Name: "utf8mb4_unicode_ci", CharacterSet: "utf8mb4", ID: 224, CaseInsensitive: true}
+
+	// DefaultCollation is the collation of the text types that don't have
+	// an explicit one.
+	DefaultCollation = CollationUtf8Bin
+)
+
+// Collations are all the supported collations.
+var Collations = []Collation{
+	CollationBinary,
+	CollationUtf8GeneralCI,
+	CollationUtf8Bin,
+	CollationUtf8mb4GeneralCI,
+	CollationUtf8mb4Bin,
+	CollationUtf8mb4UnicodeCI,
+}
+
+// CharacterSet is a set of characters and their encoding.
+type CharacterSet struct {
+	// Name is the name of the character set.
+	Name string
+	// Description is a human readable description of the character set.
+	Description string
+	// MaxLen is the maximum number of bytes of a character.
+	MaxLen uint32
+}
+
+// DefaultCollation returns the default collation of the character set.
+func (cs CharacterSet) DefaultCollation() Collation {
+	for _, c := range Collations {
+		if c.CharacterSet == cs.Name && c.Default {
+			return c
+		}
+	}
+	return CollationBinary
+}
+
+// CharacterSets are all the supported character sets.
+var CharacterSets = []CharacterSet{
+	{Name: "binary", Description: "Binary pseudo charset", MaxLen: 1},
+	{Name: "utf8", Description: "UTF-8 Unicode", MaxLen: 3},
+	{Name: "utf8mb4", Description: "UTF-8 Unicode", MaxLen: 4},
+}
+
+// ParseCollation returns the collation with the given name, which is case
+// insensitive.
+func ParseCollation(name string) (Collation, error) {
+	for _, c := range Collations {
+		if strings.EqualFold(c.Name, name) {
+			return c, nil
+		}
+	}
+	return Collation{}, ErrUnknownCollation.New(name)
+}
+
+// ParseCharacterSet returns the character set with the given name, which
+// is case insensitive.
+func ParseCharacterSet(name string) (CharacterSet, error) {
+	for _, cs := range CharacterSets {
+		if strings.EqualFold(cs.Name, name) {
+			return cs, nil
+		}
+	}
+	return CharacterSet{}, ErrUnknownCharacterSet.New(name)
+}
+
+// CollationOf returns the collation of the given type if it's a character
+// string type, such as TEXT or VARCHAR. The second value reports whether
+// the type has a collation.
+func CollationOf(t Type) (Collation, bool) {
+	switch t := t.(type) {
+	case textT:
+		return t.Collation(), true
+	case stringT:
+		if t.isBinary() {
+			return Collation{}, false
+		}
+		return t.Collation(), true
+	default:
+		return Collation{}, false
+	}
+}
+
+// CollationKeys returns the given values, which have the given types,
+// replacing the strings by their key in the collation of their type, so
+// strings that are equal using it have the same key.
+func CollationKeys(types []Type, vals []interface{}) []interface{} {
+	keys := make([]interface{}, len(vals))
+	for i, v := range vals {
+		if s, ok := v.(string); ok {
+			if c, ok := CollationOf(types[i]); ok {
+				v = c.Key(s)
+			}
+		}
+
+		keys[i] = v
+	}
+
+	return keys
+}
+
+var hashTable = crc64.MakeTable(crc64.ISO)
+
+// HashValues returns the hash of the given values, which have the given
+// types. Strings that are equal using the collation of their type have the
+// same hash. Values of other types must have been converted to their type,
+// as values with different Go types have different hashes.
+func HashValues(types []Type, vals []interface{}) uint64 {
+	keys := CollationKeys(types, vals)
+	strs := make([]string, len(keys))
+	for i, v := range keys {
+		strs[i] = fmt.Sprintf("%#v", v)
+	}
+
+	return crc64.Checksum([]byte(strings.Join(strs, ",")), hashTable)
+}
+
+// WithCollation returns the given character string type using the given
+// collation to compare its values. It returns an error if the type is not a
+// character string type.
+func WithCollation(t Type, c Collation) (Type, error) {
+	// The default collation is not kept, so types with it are equal to
+	// the ones without collation.
+	collation := c
+	if c == DefaultCollation {
+		collation = Collation{}
+	}
+
+	switch t := t.(type) {
+	case textT:
+		t.collation = collation
+		return t, nil
+	case stringT:
+		if !t.isBinary() {
+			t.collation = collation
+			return t, nil
+		}
+	}
+
+	return nil, ErrCollationNotSupported.New(c, t)
+}
+
+// TextWithCollation returns a TEXT type using the given collation to
+// compare its values.
+func TextWithCollation(c Collation) Type {
+	t, _ := WithCollation(Text, c)
+	return t
+}
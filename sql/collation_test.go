@@ -0,0 +1,119 @@
+package sql
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCollationCompare(t *testing.T) {
+	testCases := []struct {
+		collation Collation
+		a, b      string
+		expected  int
+	}{
+		{CollationUtf8Bin, "a", "A", 1},
+		{CollationUtf8Bin, "a", "a ", -1},
+		{CollationUtf8GeneralCI, "a", "A", 0},
+		{CollationUtf8GeneralCI, "a", "A  ", 0},
+		{CollationUtf8GeneralCI, "a", "B", -1},
+		{CollationUtf8mb4UnicodeCI, "Über", "über", 0},
+		{CollationBinary, "a", "A", 1},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.collation.Name+" "+tt.a+" "+tt.b, func(t *testing.T) {
+			require.Equal(t, tt.expected, tt.collation.Compare(tt.a, tt.b))
+		})
+	}
+}
+
+func TestCollationKey(t *testing.T) {
+	require := require.New(t)
+
+	require.Equal("abc ", CollationUtf8Bin.Key("abc "))
+	require.Equal("ABC", CollationUtf8GeneralCI.Key("aBc  "))
+	require.Equal(
+		CollationUtf8mb4GeneralCI.Key("foo"),
+		CollationUtf8mb4GeneralCI.Key("FOO"),
+	)
+}
+
+func TestHashValues(t *testing.T) {
+	require := require.New(t)
+
+	ci, err := WithCollation(Text, CollationUtf8GeneralCI)
+	require.NoError(err)
+
+	types := []Type{ci, Int64}
+	require.Equal(
+		HashValues(types, []interface{}{"foo", int64(1)}),
+		HashValues(types, []interface{}{"FOO ", int64(1)}),
+	)
+	require.NotEqual(
+		HashValues(types, []interface{}{"foo", int64(1)}),
+		HashValues(types, []interface{}{"foo", int64(2)}),
+	)
+
+	types = []Type{Text, Int64}
+	require.NotEqual(
+		HashValues(types, []interface{}{"foo", int64(1)}),
+		HashValues(types, []interface{}{"FOO", int64(1)}),
+	)
+	require.Equal(
+		[]interface{}{"foo", int64(1)},
+		CollationKeys(types, []interface{}{"foo", int64(1)}),
+	)
+}
+
+func TestParseCollation(t *testing.T) {
+	require := require.New(t)
+
+	c, err := ParseCollation("UTF8MB4_General_CI")
+	require.NoError(err)
+	require.Equal(CollationUtf8mb4GeneralCI, c)
+
+	_, err = ParseCollation("latin1_swedish_ci")
+	require.Error(err)
+	require.True(ErrUnknownCollation.Is(err))
+
+	cs, err := ParseCharacterSet("utf8mb4")
+	require.NoError(err)
+	require.Equal(CollationUtf8mb4GeneralCI, cs.DefaultCollation())
+
+	_, err = ParseCharacterSet("latin1")
+	require.Error(err)
+	require.True(ErrUnknownCharacterSet.Is(err))
+}
+
+func TestWithCollation(t *testing.T) {
+	require := require.New(t)
+
+	typ, err := WithCollation(Text, DefaultCollation)
+	require.NoError(err)
+	require.Equal(Text, typ)
+
+	typ, err = WithCollation(VarChar(10), CollationUtf8GeneralCI)
+	require.NoError(err)
+	require.NotEqual(VarChar(10), typ)
+	c, ok := CollationOf(typ)
+	require.True(ok)
+	require.Equal(CollationUtf8GeneralCI, c)
+
+	cmp, err := typ.Compare("foo", "FOO")
+	require.NoError(err)
+	require.Equal(0, cmp)
+
+	cmp, err = VarChar(10).Compare("foo", "FOO")
+	require.NoError(err)
+	require.Equal(1, cmp)
+
+	_, ok = CollationOf(Int64)
+	require.False(ok)
+	_, ok = CollationOf(VarBinary(10))
+	require.False(ok)
+
+	_, err = WithCollation(Int64, CollationUtf8GeneralCI)
+	require.Error(err)
+	require.True(ErrCollationNotSupported.Is(err))
+}
@@ -0,0 +1,143 @@
+package sql
+
+import (
+	"fmt"
+	"strings"
+
+	"gopkg.in/src-d/go-errors.v1"
+)
+
+var (
+	// ErrCheckConstraintViolated is returned when a row makes the
+	// expression of a CHECK constraint false.
+	ErrCheckConstraintViolated = errors.NewKind("Check constraint '%s' is violated.")
+
+	// ErrForeignKeyChildViolation is returned when a row references values
+	// that don't exist in the referenced table of a foreign key.
+	ErrForeignKeyChildViolation = errors.NewKind("Cannot add or update a child row: a foreign key constraint fails (`%s`, %s)")
+
+	// ErrForeignKeyParentViolation is returned when a row referenced by
+	// the rows of another table is deleted or its referenced values are
+	// updated, and the foreign key restricts it.
+	ErrForeignKeyParentViolation = errors.NewKind("Cannot delete or update a parent row: a foreign key constraint fails (`%s`, %s)")
+
+	// ErrInvalidForeignKey is returned when a foreign key can't be created
+	// with the given definition.
+	ErrInvalidForeignKey = errors.NewKind("Failed to add the foreign key constraint '%s': %s")
+
+	// ErrTableReferenced is returned when a table is dropped or truncated
+	// while it's referenced by a foreign key of another table.
+	ErrTableReferenced = errors.NewKind("Cannot drop or truncate table '%s' referenced by a foreign key constraint '%s' on table '%s'.")
+
+	// ErrConstraintsNotSupported is returned when a table with CHECK or
+	// FOREIGN KEY constraints is created on a database that can't enforce
+	// them.
+	ErrConstraintsNotSupported = errors.NewKind("tables with constraints cannot be created on database %s")
+)
+
+// CheckConstraint is a CHECK constraint of a table. Rows inserted or
+// updated in the table can't make its expression false, but they can make
+// it NULL.
+type CheckConstraint struct {
+	// Name is the name of the constraint.
+	Name string
+	// Expr is the expression checked for each row of the table.
+	Expr Expression
+}
+
+// Check returns an error if the given row violates the constraint.
+func (c CheckConstraint) Check(ctx *Context, row Row) error {
+	v, err := c.Expr.Eval(ctx, row)
+	if err != nil {
+		return err
+	}
+
+	if v == nil {
+		return nil
+	}
+
+	ok, err := Boolean.Convert(v)
+	if err != nil {
+		return err
+	}
+
+	if ok == false {
+		return ErrCheckConstraintViolated.New(c.Name)
+	}
+
+	return nil
+}
+
+// Definition returns the SQL definition of the constraint.
+func (c CheckConstraint) Definition() string {
+	return fmt.Sprintf("CONSTRAINT `%s` CHECK (%s)", c.Name, c.Expr)
+}
+
+// ForeignKeyAction is what happens to the rows referencing a row that is
+// deleted.
+type ForeignKeyAction string
+
+const (
+	// ForeignKeyRestrict forbids deleting rows that are referenced.
+	ForeignKeyRestrict ForeignKeyAction = "RESTRICT"
+	// ForeignKeyNoAction is the same as ForeignKeyRestrict.
+	ForeignKeyNoAction ForeignKeyAction = "NO ACTION"
+	// ForeignKeyCascade deletes the rows referencing the deleted row.
+	ForeignKeyCascade ForeignKeyAction = "CASCADE"
+	// ForeignKeySetNull sets to NULL the columns of the rows referencing
+	// the deleted row.
+	ForeignKeySetNull ForeignKeyAction = "SET NULL"
+)
+
+// ForeignKey is a FOREIGN KEY constraint of a table. The values of its
+// columns in the rows of the table must be NULL or exist in the referenced
+// columns of the referenced table, which is in the same database.
+type ForeignKey struct {
+	// Name is the name of the constraint.
+	Name string
+	// Columns are the names of the columns of the table.
+	Columns []string
+	// ReferencedTable is the name of the referenced table.
+	ReferencedTable string
+	// ReferencedColumns are the names of the columns of the referenced
+	// table, in the same order as Columns.
+	ReferencedColumns []string
+	// OnDelete is the action taken when a referenced row is deleted.
+	OnDelete ForeignKeyAction
+}
+
+// Restricts reports whether referenced rows can't be deleted.
+func (fk ForeignKey) Restricts() bool {
+	return fk.OnDelete != ForeignKeyCascade && fk.OnDelete != ForeignKeySetNull
+}
+
+// References reports whether the foreign key references the table with
+// the given name.
+func (fk ForeignKey) References(table string) bool {
+	return strings.EqualFold(fk.ReferencedTable, table)
+}
+
+// Definition returns the SQL definition of the constraint.
+func (fk ForeignKey) Definition() string {
+	def := fmt.Sprintf(
+		"CONSTRAINT `%s` FOREIGN KEY (%s) REFERENCES `%s` (%s)",
+		fk.Name,
+		quoteIdents(fk.Columns),
+		fk.ReferencedTable,
+		quoteIdents(fk.ReferencedColumns),
+	)
+
+	if !fk.Restricts() {
+		def += " ON DELETE " + string(fk.OnDelete)
+	}
+
+	return def
+}
+
+func quoteIdents(idents []string) string {
+	var quoted = make([]string, len(idents))
+	for i, ident := range idents {
+		quoted[i] = "`" + ident + "`"
+	}
+	return strings.Join(quoted, ", ")
+}
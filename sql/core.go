@@ -16,6 +16,13 @@ var (
 	// table with a name of an existing one
 	ErrTableAlreadyExists = errors.NewKind("table with name %s already exists")
 
+	// ErrColumnAlreadyExists is thrown when someone tries to add a column
+	// with the same name as an existing one.
+	ErrColumnAlreadyExists = errors.NewKind("column with name %s already exists in table %s")
+
+	// ErrColumnNotFound is thrown when a column of a table could not be found.
+	ErrColumnNotFound = errors.NewKind("column %s not found in table %s")
+
 	// ErrTableNotFound is returned when the table is not available from the
 	// current scope.
 	ErrTableNotFound = errors.NewKind("table not found: %s")
@@ -94,6 +101,28 @@ type Aggregation interface {
 	Merge(ctx *Context, buffer, partial Row) error
 }
 
+// WindowFunction is a function that can only be evaluated over a window,
+// such as ROW_NUMBER or LAG. Its value for each row depends on the position
+// of the row in its partition (EvalWindow), so it can't be evaluated with
+// just the row.
+type WindowFunction interface {
+	Expression
+	// EvalWindow evaluates the function for the row at the given position
+	// of the given partition.
+	EvalWindow(ctx *Context, partition WindowPartition, i int) (interface{}, error)
+}
+
+// WindowPartition is a partition of the rows of a window, sorted by the
+// ORDER BY of the window.
+type WindowPartition struct {
+	// Rows are the rows of the partition.
+	Rows []Row
+	// PeerGroups has the number of the group of peers of each row, starting
+	// at zero. Peers are consecutive rows with the same values in the ORDER
+	// BY of the window, so all rows are peers if it has no ORDER BY.
+	PeerGroups []int
+}
+
 // Node is a node in the execution plan tree.
 type Node interface {
 	Resolvable
@@ -182,6 +211,48 @@ type Inserter interface {
 	Insert(*Context, Row) error
 }
 
+// PrimaryKeyTable is a table that enforces its primary and unique keys.
+// Inserting or updating a row with the same values in the columns of a key
+// as another row must fail with ErrDuplicateEntry.
+type PrimaryKeyTable interface {
+	Table
+	// Keys returns the primary and unique keys of the table.
+	Keys() []Key
+}
+
+// AutoIncrementTable is a table with an AUTO_INCREMENT column, whose values
+// are generated by the table.
+type AutoIncrementTable interface {
+	Table
+	// NextAutoIncrementValue reserves and returns the next value of the
+	// AUTO_INCREMENT column, which is greater than all the values the
+	// column had. Values are generated in steps of the given increment.
+	NextAutoIncrementValue(ctx *Context, increment uint64) (uint64, error)
+}
+
+// ConstraintTable is a table with CHECK and FOREIGN KEY constraints. Rows
+// inserted or updated in the table that violate any of them must fail with
+// ErrCheckConstraintViolated or ErrForeignKeyChildViolation.
+type ConstraintTable interface {
+	Table
+	// Checks returns the CHECK constraints of the table.
+	Checks() []CheckConstraint
+	// ForeignKeys returns the FOREIGN KEY constraints of the table.
+	ForeignKeys() []ForeignKey
+}
+
+// Updater allow rows to be updated in them.
+type Updater interface {
+	// Update replaces the given old row with the new one.
+	Update(ctx *Context, old, new Row) error
+}
+
+// Deleter allow rows to be deleted from them.
+type Deleter interface {
+	// Delete the given row.
+	Delete(*Context, Row) error
+}
+
 // Database represents the database.
 type Database interface {
 	Nameable
@@ -194,6 +265,46 @@ type Alterable interface {
 	Create(name string, schema Schema) error
 }
 
+// ConstraintAlterable should be implemented by databases that can create
+// tables with CHECK and FOREIGN KEY constraints and enforce them.
+type ConstraintAlterable interface {
+	Alterable
+	// CreateWithConstraints creates a table with the given constraints.
+	CreateWithConstraints(name string, schema Schema, checks []CheckConstraint, foreignKeys []ForeignKey) error
+}
+
+// TableDropper should be implemented by databases that can drop tables.
+type TableDropper interface {
+	// DropTable removes the table with the given name.
+	DropTable(name string) error
+}
+
+// TableRenamer should be implemented by databases that can rename tables.
+type TableRenamer interface {
+	// RenameTable changes the name of the table with the given old name.
+	RenameTable(oldName, newName string) error
+}
+
+// Truncater should be implemented by tables that can remove all their rows
+// at once.
+type Truncater interface {
+	// Truncate removes all the rows of the table.
+	Truncate(*Context) error
+}
+
+// TableAlterer should be implemented by tables whose columns can be added,
+// dropped or modified. Existing rows must be updated to match the new
+// schema.
+type TableAlterer interface {
+	// AddColumn adds the given column at the end of the table schema.
+	AddColumn(ctx *Context, column *Column) error
+	// DropColumn removes the column with the given name.
+	DropColumn(ctx *Context, name string) error
+	// ModifyColumn replaces the column with the given name with the given
+	// column, which may have a different name or type.
+	ModifyColumn(ctx *Context, name string, column *Column) error
+}
+
 // Lockable should be implemented by tables that can be locked and unlocked.
 type Lockable interface {
 	Nameable
@@ -2,6 +2,7 @@ package expression
 
 import (
 	"fmt"
+	"math/big"
 	"reflect"
 
 	errors "gopkg.in/src-d/go-errors.v1"
@@ -92,6 +93,13 @@ func (a *Arithmetic) String() string {
 func (a *Arithmetic) Type() sql.Type {
 	switch a.op {
 	case sqlparser.PlusStr, sqlparser.MinusStr, sqlparser.MultStr, sqlparser.DivStr:
+		if sql.IsFixedPoint(a.Left.Type()) || sql.IsFixedPoint(a.Right.Type()) {
+			if typ, ok := a.decimalType(); ok {
+				return typ
+			}
+			return sql.Float64
+		}
+
 		if sql.IsInteger(a.Left.Type()) && sql.IsInteger(a.Right.Type()) {
 			if sql.IsUnsigned(a.Left.Type()) && sql.IsUnsigned(a.Right.Type()) {
 				return sql.Uint64
@@ -114,6 +122,55 @@ func (a *Arithmetic) Type() sql.Type {
 	return sql.Float64
 }
 
+// divPrecisionIncrement is the number of digits the scale of the result of
+// a division is increased, like the div_precision_increment MySQL variable.
+const divPrecisionIncrement = 4
+
+// decimalType returns the decimal type of the result of the operation if
+// both operands are exact numbers. As in MySQL, the scale of the result is
+// big enough to keep all the digits of the operands, except for divisions.
+func (a *Arithmetic) decimalType() (sql.Type, bool) {
+	lp, ls, ok := sql.NumericPrecision(a.Left.Type())
+	if !ok {
+		return nil, false
+	}
+
+	rp, rs, ok := sql.NumericPrecision(a.Right.Type())
+	if !ok {
+		return nil, false
+	}
+
+	var precision, scale int
+	switch a.op {
+	case sqlparser.PlusStr, sqlparser.MinusStr:
+		scale = maxInt(ls, rs)
+		precision = maxInt(lp-ls, rp-rs) + scale + 1
+	case sqlparser.MultStr:
+		scale = ls + rs
+		precision = lp + rp
+	case sqlparser.DivStr:
+		scale = ls + divPrecisionIncrement
+		precision = lp - ls + rs + scale
+	}
+
+	if scale > sql.DecimalMaxScale {
+		scale = sql.DecimalMaxScale
+	}
+
+	if precision > sql.DecimalMaxPrecision {
+		precision = sql.DecimalMaxPrecision
+	}
+
+	return sql.Decimal(precision, scale), true
+}
+
+func maxInt(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
+
 // TransformUp implements the Expression interface.
 func (a *Arithmetic) TransformUp(f sql.TransformExprFunc) (sql.Expression, error) {
 	l, err := a.Left.TransformUp(f)
@@ -136,6 +193,10 @@ func (a *Arithmetic) Eval(ctx *sql.Context, row sql.Row) (interface{}, error) {
 		return nil, err
 	}
 
+	if typ := a.Type(); sql.IsFixedPoint(typ) {
+		return a.evalDecimal(typ, lval, rval)
+	}
+
 	lval, rval, err = a.convertLeftRight(lval, rval)
 	if err != nil {
 		return nil, err
@@ -199,6 +260,44 @@ func (a *Arithmetic) convertLeftRight(lval interface{}, rval interface{}) (inter
 	return lval64, rval64, nil
 }
 
+// evalDecimal performs the operation with the exact values of the operands,
+// so no precision is lost, and converts the result to the given type.
+func (a *Arithmetic) evalDecimal(typ sql.Type, lval, rval interface{}) (interface{}, error) {
+	if lval == nil || rval == nil {
+		return nil, nil
+	}
+
+	l, err := sql.ParseDecimal(lval)
+	if err != nil {
+		return nil, err
+	}
+
+	r, err := sql.ParseDecimal(rval)
+	if err != nil {
+		return nil, err
+	}
+
+	var result = new(big.Rat)
+	switch a.op {
+	case sqlparser.PlusStr:
+		result.Add(l, r)
+	case sqlparser.MinusStr:
+		result.Sub(l, r)
+	case sqlparser.MultStr:
+		result.Mul(l, r)
+	case sqlparser.DivStr:
+		// Division by zero is NULL, as in MySQL.
+		if r.Sign() == 0 {
+			return nil, nil
+		}
+		result.Quo(l, r)
+	default:
+		return nil, errUnableToEval.New(lval, a.op, rval)
+	}
+
+	return typ.Convert(result)
+}
+
 func plus(lval, rval interface{}) (interface{}, error) {
 	switch l := lval.(type) {
 	case uint64:
@@ -437,6 +536,15 @@ func (e *UnaryMinus) Eval(ctx *sql.Context, row sql.Row) (interface{}, error) {
 		}
 	}
 
+	if sql.IsFixedPoint(e.Child.Type()) {
+		n, err := sql.ParseDecimal(child)
+		if err != nil {
+			return nil, err
+		}
+
+		return e.Child.Type().Convert(new(big.Rat).Neg(n))
+	}
+
 	switch n := child.(type) {
 	case float64:
 		return -n, nil
@@ -450,6 +558,14 @@ func (e *UnaryMinus) Eval(ctx *sql.Context, row sql.Row) (interface{}, error) {
 		return -n, nil
 	case uint32:
 		return -int32(n), nil
+	case int16:
+		return -n, nil
+	case uint16:
+		return -int32(n), nil
+	case int8:
+		return -n, nil
+	case uint8:
+		return -int16(n), nil
 	default:
 		return nil, sql.ErrInvalidType.New(reflect.TypeOf(n))
 	}
@@ -462,7 +578,11 @@ func (e *UnaryMinus) Type() sql.Type {
 		return sql.Float64
 	}
 
-	if typ == sql.Uint32 {
+	if typ == sql.Uint8 {
+		return sql.Int16
+	}
+
+	if typ == sql.Uint16 || typ == sql.Uint24 || typ == sql.Uint32 {
 		return sql.Int32
 	}
 
@@ -361,6 +361,79 @@ func TestAllInt64(t *testing.T) {
 	}
 }
 
+func TestDecimalArithmetic(t *testing.T) {
+	decimal := sql.Decimal(10, 2)
+	testCases := []struct {
+		name     string
+		left     sql.Expression
+		right    sql.Expression
+		op       string
+		typ      sql.Type
+		expected interface{}
+	}{
+		{
+			"decimal + decimal",
+			NewLiteral("0.10", decimal),
+			NewLiteral("0.20", decimal),
+			"+",
+			sql.Decimal(11, 2),
+			"0.30",
+		},
+		{
+			"decimal - int",
+			NewLiteral("10.25", decimal),
+			NewLiteral(int64(11), sql.Int64),
+			"-",
+			sql.Decimal(22, 2),
+			"-0.75",
+		},
+		{
+			"decimal * decimal",
+			NewLiteral("1.05", decimal),
+			NewLiteral("3.10", sql.Decimal(5, 1)),
+			"*",
+			sql.Decimal(15, 3),
+			"3.255",
+		},
+		{
+			"decimal / int",
+			NewLiteral("10.00", decimal),
+			NewLiteral(int32(3), sql.Int32),
+			"/",
+			sql.Decimal(14, 6),
+			"3.333333",
+		},
+		{
+			"decimal / 0",
+			NewLiteral("10.00", decimal),
+			NewLiteral("0.00", decimal),
+			"/",
+			sql.Decimal(14, 6),
+			nil,
+		},
+		{
+			"decimal + float",
+			NewLiteral("1.50", decimal),
+			NewLiteral(float64(1), sql.Float64),
+			"+",
+			sql.Float64,
+			float64(2.5),
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			require := require.New(t)
+			e := NewArithmetic(tt.left, tt.right, tt.op)
+			require.Equal(tt.typ, e.Type())
+
+			result, err := e.Eval(sql.NewEmptyContext(), sql.NewRow())
+			require.NoError(err)
+			require.Equal(tt.expected, result)
+		})
+	}
+}
+
 func TestUnaryMinus(t *testing.T) {
 	testCases := []struct {
 		name     string
@@ -376,6 +449,7 @@ func TestUnaryMinus(t *testing.T) {
 		{"float64", float64(1), sql.Float64, float64(-1)},
 		{"int text", "1", sql.Text, float64(-1)},
 		{"float text", "1.2", sql.Text, float64(-1.2)},
+		{"decimal", "1.20", sql.Decimal(5, 2), "-1.20"},
 		{"nil", nil, sql.Text, nil},
 	}
 
@@ -0,0 +1,56 @@
+package expression
+
+import (
+	errors "gopkg.in/src-d/go-errors.v1"
+	"gopkg.in/src-d/go-mysql-server.v0/sql"
+)
+
+// ErrUnboundBindVar is returned when a bind variable is evaluated before a
+// value has been bound to it.
+var ErrUnboundBindVar = errors.NewKind("no value bound for variable :%s")
+
+// BindVar is a placeholder for a value that is given when a prepared
+// statement is executed, such as `?` or `:name`. It's considered resolved so
+// queries can be analyzed before their values are known, but it must be
+// replaced with the bound value before evaluation.
+type BindVar struct {
+	name string
+}
+
+// NewBindVar creates a new BindVar expression with the given name.
+func NewBindVar(name string) *BindVar {
+	return &BindVar{name: name}
+}
+
+// Name returns the name of the bind variable.
+func (v *BindVar) Name() string { return v.name }
+
+// Resolved implements the Expression interface.
+func (*BindVar) Resolved() bool { return true }
+
+// IsNullable implements the Expression interface.
+func (*BindVar) IsNullable() bool { return true }
+
+// Type implements the Expression interface. The type of a bind variable is
+// unknown until a value is bound to it.
+func (*BindVar) Type() sql.Type { return sql.Null }
+
+// Eval implements the Expression interface.
+func (v *BindVar) Eval(ctx *sql.Context, row sql.Row) (interface{}, error) {
+	return nil, ErrUnboundBindVar.New(v.name)
+}
+
+func (v *BindVar) String() string {
+	return ":" + v.name
+}
+
+// TransformUp implements the Expression interface.
+func (v *BindVar) TransformUp(f sql.TransformExprFunc) (sql.Expression, error) {
+	n := *v
+	return f(&n)
+}
+
+// Children implements the Expression interface.
+func (*BindVar) Children() []sql.Expression {
+	return nil
+}
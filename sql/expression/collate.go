@@ -0,0 +1,52 @@
+package expression
+
+import (
+	"fmt"
+
+	"gopkg.in/src-d/go-mysql-server.v0/sql"
+)
+
+// Collate is an expression that sets the collation used to compare the
+// string its child evaluates to, as in `expr COLLATE collation`.
+type Collate struct {
+	UnaryExpression
+	Collation sql.Collation
+}
+
+// NewCollate creates a new Collate expression.
+func NewCollate(child sql.Expression, collation sql.Collation) *Collate {
+	return &Collate{UnaryExpression{child}, collation}
+}
+
+// Type implements the Expression interface. Character string types keep
+// their type with the new collation and the rest of types become TEXT.
+func (c *Collate) Type() sql.Type {
+	t, err := sql.WithCollation(c.Child.Type(), c.Collation)
+	if err != nil {
+		return sql.TextWithCollation(c.Collation)
+	}
+	return t
+}
+
+// Eval implements the Expression interface.
+func (c *Collate) Eval(ctx *sql.Context, row sql.Row) (interface{}, error) {
+	v, err := c.Child.Eval(ctx, row)
+	if err != nil || v == nil {
+		return nil, err
+	}
+
+	return sql.Text.Convert(v)
+}
+
+func (c *Collate) String() string {
+	return fmt.Sprintf("%s COLLATE %s", c.Child, c.Collation)
+}
+
+// TransformUp implements the Expression interface.
+func (c *Collate) TransformUp(f sql.TransformExprFunc) (sql.Expression, error) {
+	child, err := c.Child.TransformUp(f)
+	if err != nil {
+		return nil, err
+	}
+	return f(NewCollate(child, c.Collation))
+}
@@ -69,6 +69,17 @@ func (c *comparison) evalLeftAndRight(ctx *sql.Context, row sql.Row) (interface{
 }
 
 func (c *comparison) castLeftAndRight(left, right interface{}) (interface{}, interface{}, error) {
+	// Enums and sets are compared with numbers by their ordinals or
+	// bitmasks, and with anything else by their values.
+	if isEnumOrSet(c.Left().Type()) || isEnumOrSet(c.Right().Type()) {
+		if !sql.IsNumber(c.Left().Type()) && !sql.IsNumber(c.Right().Type()) {
+			c.compareType = sql.Text
+			return enumOrSetToText(c.Left().Type(), left),
+				enumOrSetToText(c.Right().Type(), right),
+				nil
+		}
+	}
+
 	if sql.IsNumber(c.Left().Type()) || sql.IsNumber(c.Right().Type()) {
 		if sql.IsDecimal(c.Left().Type()) || sql.IsDecimal(c.Right().Type()) {
 			left, right, err := convertLeftAndRight(left, right, ConvertToDecimal)
@@ -80,6 +91,18 @@ func (c *comparison) castLeftAndRight(left, right interface{}) (interface{}, int
 			return left, right, nil
 		}
 
+		// Decimals are compared using their exact values, no matter their
+		// precision and scale.
+		if sql.IsFixedPoint(c.Left().Type()) {
+			c.compareType = c.Left().Type()
+			return left, right, nil
+		}
+
+		if sql.IsFixedPoint(c.Right().Type()) {
+			c.compareType = c.Right().Type()
+			return left, right, nil
+		}
+
 		if sql.IsSigned(c.Left().Type()) || sql.IsSigned(c.Right().Type()) {
 			left, right, err := convertLeftAndRight(left, right, ConvertToSigned)
 			if err != nil {
@@ -104,10 +127,48 @@ func (c *comparison) castLeftAndRight(left, right interface{}) (interface{}, int
 		return nil, nil, err
 	}
 
-	c.compareType = sql.Text
+	c.compareType = sql.TextWithCollation(compareCollation(c.Left(), c.Right()))
 	return left, right, nil
 }
 
+// compareCollation returns the collation used to compare the strings the
+// given expressions evaluate to. As in MySQL, the collation of an explicit
+// COLLATE takes precedence over the collations of the types, and a
+// collation other than the default one takes precedence over the default.
+func compareCollation(left, right sql.Expression) sql.Collation {
+	for _, e := range []sql.Expression{left, right} {
+		if c, ok := e.(*Collate); ok {
+			return c.Collation
+		}
+	}
+
+	for _, e := range []sql.Expression{left, right} {
+		if c, ok := sql.CollationOf(e.Type()); ok && c != sql.DefaultCollation {
+			return c
+		}
+	}
+
+	return sql.DefaultCollation
+}
+
+func isEnumOrSet(t sql.Type) bool {
+	return sql.IsEnum(t) || sql.IsSet(t)
+}
+
+// enumOrSetToText returns the value of an ENUM or SET as a string, or the
+// given value converted to a string if it's of any other type.
+func enumOrSetToText(t sql.Type, v interface{}) interface{} {
+	if isEnumOrSet(t) {
+		return t.SQL(v).ToString()
+	}
+
+	s, err := sql.Text.Convert(v)
+	if err != nil {
+		return v
+	}
+	return s
+}
+
 func convertLeftAndRight(left, right interface{}, convertTo string) (interface{}, interface{}, error) {
 	l, err := convertValue(left, convertTo)
 	if err != nil {
@@ -119,6 +119,58 @@ func TestEquals(t *testing.T) {
 	}
 }
 
+func TestEnumComparison(t *testing.T) {
+	require := require.New(t)
+	typ := sql.Enum("b", "a")
+	field := NewGetField(0, typ, "col1", true)
+
+	testCases := []struct {
+		cmp      sql.Expression
+		expected interface{}
+	}{
+		// Strings are compared with the values of the enum.
+		{NewEquals(field, NewLiteral("a", sql.Text)), true},
+		{NewLessThan(field, NewLiteral("b", sql.Text)), true},
+		{NewEquals(field, NewLiteral("c", sql.Text)), false},
+		// Numbers are compared with the ordinals.
+		{NewEquals(field, NewLiteral(int64(2), sql.Int64)), true},
+		{NewGreaterThan(field, NewLiteral(int64(1), sql.Int64)), true},
+	}
+
+	for _, tt := range testCases {
+		require.Equal(tt.expected, eval(t, tt.cmp, sql.NewRow(uint16(2))), tt.cmp.String())
+	}
+}
+
+func TestCollatedComparison(t *testing.T) {
+	require := require.New(t)
+	ci := sql.TextWithCollation(sql.CollationUtf8GeneralCI)
+	field := NewGetField(0, ci, "col1", true)
+	binField := NewGetField(0, sql.Text, "col1", true)
+
+	testCases := []struct {
+		cmp      sql.Expression
+		expected interface{}
+	}{
+		{NewEquals(field, NewLiteral("FOO", sql.Text)), true},
+		{NewEquals(field, NewLiteral("foo  ", sql.Text)), true},
+		{NewLessThan(field, NewLiteral("Bar", sql.Text)), false},
+		{NewEquals(binField, NewLiteral("FOO", sql.Text)), false},
+		{NewEquals(
+			NewCollate(binField, sql.CollationUtf8mb4GeneralCI),
+			NewLiteral("FOO", sql.Text),
+		), true},
+		{NewEquals(
+			NewCollate(field, sql.CollationUtf8Bin),
+			NewLiteral("FOO", sql.Text),
+		), false},
+	}
+
+	for _, tt := range testCases {
+		require.Equal(tt.expected, eval(t, tt.cmp, sql.NewRow("foo")), tt.cmp.String())
+	}
+}
+
 func TestLessThan(t *testing.T) {
 	require := require.New(t)
 	for resultType, cmpCase := range comparisonCases {
@@ -0,0 +1,147 @@
+package aggregation
+
+import (
+	"fmt"
+	"math"
+	"math/bits"
+
+	"gopkg.in/src-d/go-mysql-server.v0/sql"
+	"gopkg.in/src-d/go-mysql-server.v0/sql/expression"
+)
+
+// hllPrecision is the number of bits of the hashes used to choose the
+// register of a HyperLogLog, which has 2^hllPrecision registers. With 14
+// bits the standard error of the estimation is about 0.8%.
+const hllPrecision = 14
+
+// hyperLogLog estimates the number of distinct values added to it using a
+// fixed amount of memory. See "HyperLogLog: the analysis of a near-optimal
+// cardinality estimation algorithm" by Flajolet et al.
+type hyperLogLog struct {
+	registers [1 << hllPrecision]uint8
+}
+
+// add adds the value with the given hash.
+func (h *hyperLogLog) add(hash uint64) {
+	idx := hash >> (64 - hllPrecision)
+	// The bit after the precision bits makes the rank at most 64-precision
+	// when the rest of the bits are zero.
+	w := hash<<hllPrecision | 1<<(hllPrecision-1)
+	rank := uint8(bits.LeadingZeros64(w) + 1)
+	if rank > h.registers[idx] {
+		h.registers[idx] = rank
+	}
+}
+
+// merge merges the given HyperLogLog into this one, which then estimates
+// the number of distinct values added to any of both.
+func (h *hyperLogLog) merge(other *hyperLogLog) {
+	for i, r := range other.registers {
+		if r > h.registers[i] {
+			h.registers[i] = r
+		}
+	}
+}
+
+// estimate returns the estimated number of distinct values.
+func (h *hyperLogLog) estimate() int64 {
+	const m = float64(1 << hllPrecision)
+	alpha := 0.7213 / (1 + 1.079/m)
+
+	var sum float64
+	var zeros int
+	for _, r := range h.registers {
+		sum += 1 / float64(uint64(1)<<r)
+		if r == 0 {
+			zeros++
+		}
+	}
+
+	e := alpha * m * m / sum
+	// The estimation is biased for small cardinalities, which are estimated
+	// with linear counting instead.
+	if e <= 2.5*m && zeros > 0 {
+		e = m * math.Log(m/float64(zeros))
+	}
+
+	return int64(math.Round(e))
+}
+
+// mixHash improves the distribution of the bits of the given hash using
+// the finalizer of MurmurHash3, since HyperLogLog needs all of them to be
+// uniformly distributed.
+func mixHash(h uint64) uint64 {
+	h ^= h >> 33
+	h *= 0xff51afd7ed558ccd
+	h ^= h >> 33
+	h *= 0xc4ceb9fe1a85ec53
+	h ^= h >> 33
+	return h
+}
+
+// ApproxCountDistinct aggregation returns an estimation of the number of
+// distinct non null values of its child, which uses a fixed amount of
+// memory regardless of the number of values.
+type ApproxCountDistinct struct {
+	expression.UnaryExpression
+}
+
+// NewApproxCountDistinct returns a new ApproxCountDistinct node.
+func NewApproxCountDistinct(e sql.Expression) *ApproxCountDistinct {
+	return &ApproxCountDistinct{expression.UnaryExpression{Child: e}}
+}
+
+// Type implements the Expression interface.
+func (a *ApproxCountDistinct) Type() sql.Type {
+	return sql.Int64
+}
+
+// IsNullable implements the Expression interface.
+func (a *ApproxCountDistinct) IsNullable() bool {
+	return false
+}
+
+func (a *ApproxCountDistinct) String() string {
+	return fmt.Sprintf("APPROX_COUNT_DISTINCT(%s)", a.Child)
+}
+
+// TransformUp implements the Expression interface.
+func (a *ApproxCountDistinct) TransformUp(f sql.TransformExprFunc) (sql.Expression, error) {
+	child, err := a.Child.TransformUp(f)
+	if err != nil {
+		return nil, err
+	}
+	return f(NewApproxCountDistinct(child))
+}
+
+// NewBuffer implements the Aggregation interface.
+func (a *ApproxCountDistinct) NewBuffer() sql.Row {
+	return sql.NewRow(new(hyperLogLog))
+}
+
+// Update implements the Aggregation interface.
+func (a *ApproxCountDistinct) Update(ctx *sql.Context, buffer, row sql.Row) error {
+	v, err := a.Child.Eval(ctx, row)
+	if err != nil {
+		return err
+	}
+
+	if v == nil {
+		return nil
+	}
+
+	hash := hashValues([]sql.Expression{a.Child}, []interface{}{v})
+	buffer[0].(*hyperLogLog).add(mixHash(hash))
+	return nil
+}
+
+// Merge implements the Aggregation interface.
+func (a *ApproxCountDistinct) Merge(ctx *sql.Context, buffer, partial sql.Row) error {
+	buffer[0].(*hyperLogLog).merge(partial[0].(*hyperLogLog))
+	return nil
+}
+
+// Eval implements the Aggregation interface.
+func (a *ApproxCountDistinct) Eval(ctx *sql.Context, buffer sql.Row) (interface{}, error) {
+	return buffer[0].(*hyperLogLog).estimate(), nil
+}
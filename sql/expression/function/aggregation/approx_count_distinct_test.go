@@ -0,0 +1,43 @@
+package aggregation
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"gopkg.in/src-d/go-mysql-server.v0/sql"
+	"gopkg.in/src-d/go-mysql-server.v0/sql/expression"
+)
+
+func TestApproxCountDistinct(t *testing.T) {
+	require := require.New(t)
+	ctx := sql.NewEmptyContext()
+
+	a := NewApproxCountDistinct(expression.NewGetField(0, sql.Int64, "a", true))
+	require.Equal("APPROX_COUNT_DISTINCT(a)", a.String())
+
+	b := a.NewBuffer()
+	require.Equal(int64(0), eval(t, a, b))
+
+	for _, v := range []interface{}{int64(1), int64(2), nil, int64(1), int64(3)} {
+		require.NoError(a.Update(ctx, b, sql.NewRow(v)))
+	}
+	require.Equal(int64(3), eval(t, a, b))
+}
+
+func TestApproxCountDistinct_Merge(t *testing.T) {
+	require := require.New(t)
+	ctx := sql.NewEmptyContext()
+
+	a := NewApproxCountDistinct(expression.NewGetField(0, sql.Int64, "a", true))
+
+	// Both buffers have 60000 distinct values, 20000 of which are in both.
+	b := a.NewBuffer()
+	partial := a.NewBuffer()
+	for i := 0; i < 60000; i++ {
+		require.NoError(a.Update(ctx, b, sql.NewRow(int64(i))))
+		require.NoError(a.Update(ctx, partial, sql.NewRow(int64(i+40000))))
+	}
+
+	require.NoError(a.Merge(ctx, b, partial))
+	require.InEpsilon(100000, eval(t, a, b), 0.03)
+}
@@ -0,0 +1,253 @@
+package aggregation
+
+import (
+	"fmt"
+	"math"
+	"sort"
+
+	errors "gopkg.in/src-d/go-errors.v1"
+	"gopkg.in/src-d/go-mysql-server.v0/sql"
+	"gopkg.in/src-d/go-mysql-server.v0/sql/expression"
+)
+
+// ErrInvalidPercentile is returned when the percentile of APPROX_PERCENTILE
+// is not a number between 0 and 1.
+var ErrInvalidPercentile = errors.NewKind("invalid percentile %v, it must be a number between 0 and 1")
+
+// tdigestCompression is the compression of the t-digests. Both the number
+// of centroids of a t-digest and the accuracy of its estimations grow with
+// it; with 200 the error is below 1% even for the percentiles close to 0
+// and 1 of the merge of several t-digests.
+const tdigestCompression = 200
+
+type centroid struct {
+	mean   float64
+	weight float64
+}
+
+// tdigest estimates the quantiles of the values added to it keeping a
+// bounded number of centroids, which are smaller near the extremes so the
+// estimations of the quantiles close to 0 and 1 are more accurate. See
+// "Computing extremely accurate quantiles using t-digests" by Dunning and
+// Ertl.
+type tdigest struct {
+	centroids []centroid
+	// unmerged are the centroids added since the last compression.
+	unmerged []centroid
+	count    float64
+	min, max float64
+}
+
+func newTDigest() *tdigest {
+	return &tdigest{min: math.Inf(1), max: math.Inf(-1)}
+}
+
+// add adds the given value.
+func (t *tdigest) add(x float64) {
+	t.addCentroid(centroid{x, 1})
+	t.min = math.Min(t.min, x)
+	t.max = math.Max(t.max, x)
+}
+
+func (t *tdigest) addCentroid(c centroid) {
+	t.unmerged = append(t.unmerged, c)
+	t.count += c.weight
+	if len(t.unmerged) >= 5*tdigestCompression {
+		t.compress()
+	}
+}
+
+// merge merges the given t-digest into this one, which then estimates the
+// quantiles of the values added to any of both.
+func (t *tdigest) merge(other *tdigest) {
+	for _, c := range other.centroids {
+		t.addCentroid(c)
+	}
+
+	for _, c := range other.unmerged {
+		t.addCentroid(c)
+	}
+
+	t.min = math.Min(t.min, other.min)
+	t.max = math.Max(t.max, other.max)
+}
+
+// scale is the scale function of the t-digest, so adjacent centroids are
+// merged as long as the difference of the scale of their quantiles is at
+// most 1.
+func scale(q float64) float64 {
+	return tdigestCompression / (2 * math.Pi) * math.Asin(2*q-1)
+}
+
+// compress merges the unmerged centroids with the rest.
+func (t *tdigest) compress() {
+	if len(t.unmerged) == 0 {
+		return
+	}
+
+	all := append(t.centroids, t.unmerged...)
+	sort.Slice(all, func(i, j int) bool {
+		return all[i].mean < all[j].mean
+	})
+
+	var result []centroid
+	var seen float64
+	cur := all[0]
+	for _, c := range all[1:] {
+		if scale((seen+cur.weight+c.weight)/t.count)-scale(seen/t.count) <= 1 {
+			cur.weight += c.weight
+			cur.mean += (c.mean - cur.mean) * c.weight / cur.weight
+			continue
+		}
+
+		result = append(result, cur)
+		seen += cur.weight
+		cur = c
+	}
+
+	t.centroids = append(result, cur)
+	t.unmerged = nil
+}
+
+// quantile returns the estimated value of the given quantile, or false if
+// no values were added.
+func (t *tdigest) quantile(q float64) (float64, bool) {
+	t.compress()
+	if len(t.centroids) == 0 {
+		return 0, false
+	}
+
+	if len(t.centroids) == 1 {
+		return t.centroids[0].mean, true
+	}
+
+	// The values of each centroid are assumed to be around its mean, so
+	// the values between the means of two centroids are interpolated.
+	target := q * t.count
+	first, last := t.centroids[0], t.centroids[len(t.centroids)-1]
+	if target < first.weight/2 {
+		return t.min + (first.mean-t.min)*target/(first.weight/2), true
+	}
+
+	if target > t.count-last.weight/2 {
+		excess := target - (t.count - last.weight/2)
+		return last.mean + (t.max-last.mean)*excess/(last.weight/2), true
+	}
+
+	seen := first.weight / 2
+	for i := 1; i < len(t.centroids); i++ {
+		prev, c := t.centroids[i-1], t.centroids[i]
+		gap := (prev.weight + c.weight) / 2
+		if target <= seen+gap {
+			return prev.mean + (c.mean-prev.mean)*(target-seen)/gap, true
+		}
+		seen += gap
+	}
+
+	return last.mean, true
+}
+
+// ApproxPercentile aggregation returns an estimation of the value at the
+// given percentile, a number between 0 and 1, of the non null values of
+// its child, which uses a bounded amount of memory regardless of the
+// number of values.
+type ApproxPercentile struct {
+	expression.BinaryExpression
+}
+
+// NewApproxPercentile returns a new ApproxPercentile node.
+func NewApproxPercentile(e, percentile sql.Expression) *ApproxPercentile {
+	return &ApproxPercentile{expression.BinaryExpression{Left: e, Right: percentile}}
+}
+
+// Type implements the Expression interface.
+func (a *ApproxPercentile) Type() sql.Type {
+	return sql.Float64
+}
+
+// IsNullable implements the Expression interface.
+func (a *ApproxPercentile) IsNullable() bool {
+	return true
+}
+
+func (a *ApproxPercentile) String() string {
+	return fmt.Sprintf("APPROX_PERCENTILE(%s, %s)", a.Left, a.Right)
+}
+
+// TransformUp implements the Expression interface.
+func (a *ApproxPercentile) TransformUp(f sql.TransformExprFunc) (sql.Expression, error) {
+	e, err := a.Left.TransformUp(f)
+	if err != nil {
+		return nil, err
+	}
+
+	percentile, err := a.Right.TransformUp(f)
+	if err != nil {
+		return nil, err
+	}
+
+	return f(NewApproxPercentile(e, percentile))
+}
+
+// NewBuffer implements the Aggregation interface. The buffer keeps the
+// t-digest of the values and the percentile.
+func (a *ApproxPercentile) NewBuffer() sql.Row {
+	return sql.NewRow(newTDigest(), nil)
+}
+
+// Update implements the Aggregation interface.
+func (a *ApproxPercentile) Update(ctx *sql.Context, buffer, row sql.Row) error {
+	if buffer[1] == nil {
+		p, err := a.Right.Eval(ctx, row)
+		if err != nil {
+			return err
+		}
+
+		percentile, err := sql.Float64.Convert(p)
+		if p == nil || err != nil || percentile.(float64) < 0 || percentile.(float64) > 1 {
+			return ErrInvalidPercentile.New(p)
+		}
+
+		buffer[1] = percentile
+	}
+
+	v, err := a.Left.Eval(ctx, row)
+	if err != nil {
+		return err
+	}
+
+	if v == nil {
+		return nil
+	}
+
+	x, err := sql.Float64.Convert(v)
+	if err != nil {
+		x = float64(0)
+	}
+
+	buffer[0].(*tdigest).add(x.(float64))
+	return nil
+}
+
+// Merge implements the Aggregation interface.
+func (a *ApproxPercentile) Merge(ctx *sql.Context, buffer, partial sql.Row) error {
+	buffer[0].(*tdigest).merge(partial[0].(*tdigest))
+	if buffer[1] == nil {
+		buffer[1] = partial[1]
+	}
+	return nil
+}
+
+// Eval implements the Aggregation interface.
+func (a *ApproxPercentile) Eval(ctx *sql.Context, buffer sql.Row) (interface{}, error) {
+	if buffer[1] == nil {
+		return nil, nil
+	}
+
+	v, ok := buffer[0].(*tdigest).quantile(buffer[1].(float64))
+	if !ok {
+		return nil, nil
+	}
+
+	return v, nil
+}
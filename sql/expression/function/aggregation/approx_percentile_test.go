@@ -0,0 +1,90 @@
+package aggregation
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"gopkg.in/src-d/go-mysql-server.v0/sql"
+	"gopkg.in/src-d/go-mysql-server.v0/sql/expression"
+)
+
+func TestApproxPercentile(t *testing.T) {
+	ctx := sql.NewEmptyContext()
+	field := expression.NewGetField(0, sql.Int64, "a", true)
+
+	testCases := []struct {
+		percentile float64
+		expected   float64
+	}{
+		{0, 1},
+		{0.5, 3},
+		{1, 5},
+	}
+
+	for _, tt := range testCases {
+		a := NewApproxPercentile(field, expression.NewLiteral(tt.percentile, sql.Float64))
+		t.Run(a.String(), func(t *testing.T) {
+			require := require.New(t)
+
+			b := a.NewBuffer()
+			require.Nil(eval(t, a, b))
+
+			for _, v := range []interface{}{int64(5), int64(1), nil, int64(4), int64(2), int64(3)} {
+				require.NoError(a.Update(ctx, b, sql.NewRow(v)))
+			}
+			require.Equal(tt.expected, eval(t, a, b))
+		})
+	}
+}
+
+func TestApproxPercentile_Merge(t *testing.T) {
+	ctx := sql.NewEmptyContext()
+	field := expression.NewGetField(0, sql.Int64, "a", true)
+
+	testCases := []struct {
+		percentile float64
+		expected   float64
+	}{
+		{0.05, 5000},
+		{0.5, 50000},
+		{0.99, 99000},
+	}
+
+	for _, tt := range testCases {
+		a := NewApproxPercentile(field, expression.NewLiteral(tt.percentile, sql.Float64))
+		t.Run(a.String(), func(t *testing.T) {
+			require := require.New(t)
+
+			// The values from 1 to 100000 are split in 4 partial buffers.
+			var partials []sql.Row
+			for i := 0; i < 4; i++ {
+				partials = append(partials, a.NewBuffer())
+			}
+
+			for i := 1; i <= 100000; i++ {
+				require.NoError(a.Update(ctx, partials[i%4], sql.NewRow(int64(i))))
+			}
+
+			b := a.NewBuffer()
+			for _, p := range partials {
+				require.NoError(a.Merge(ctx, b, p))
+			}
+
+			require.InEpsilon(tt.expected, eval(t, a, b), 0.01)
+		})
+	}
+}
+
+func TestApproxPercentile_Invalid(t *testing.T) {
+	require := require.New(t)
+	ctx := sql.NewEmptyContext()
+
+	a := NewApproxPercentile(
+		expression.NewGetField(0, sql.Int64, "a", true),
+		expression.NewLiteral(1.5, sql.Float64),
+	)
+
+	err := a.Update(ctx, a.NewBuffer(), sql.NewRow(int64(1)))
+	require.Error(err)
+	require.True(ErrInvalidPercentile.Is(err))
+}
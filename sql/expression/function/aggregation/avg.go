@@ -2,11 +2,16 @@ package aggregation // import "gopkg.in/src-d/go-mysql-server.v0/sql/expression/
 
 import (
 	"fmt"
+	"math/big"
 
 	"gopkg.in/src-d/go-mysql-server.v0/sql"
 	"gopkg.in/src-d/go-mysql-server.v0/sql/expression"
 )
 
+// avgScaleIncrement is the number of digits the scale of the average of
+// decimals is increased with respect to the scale of the decimals.
+const avgScaleIncrement = 4
+
 // Avg node to calculate the average from numeric column
 type Avg struct {
 	expression.UnaryExpression
@@ -28,6 +33,15 @@ func (a *Avg) Resolved() bool {
 
 // Type implements AggregationExpression interface. (AggregationExpression[Expression]])
 func (a *Avg) Type() sql.Type {
+	if _, scale, ok := sql.NumericPrecision(a.Child.Type()); ok && sql.IsFixedPoint(a.Child.Type()) {
+		scale += avgScaleIncrement
+		if scale > sql.DecimalMaxScale {
+			scale = sql.DecimalMaxScale
+		}
+
+		return sql.Decimal(sql.DecimalMaxPrecision, scale)
+	}
+
 	return sql.Float64
 }
 
@@ -43,8 +57,16 @@ func (a *Avg) Eval(ctx *sql.Context, buffer sql.Row) (interface{}, error) {
 		return nil, nil
 	}
 
-	sum := buffer[0].(float64)
 	rows := buffer[1].(int64)
+	if sum, ok := buffer[0].(*big.Rat); ok {
+		if rows == 0 {
+			return a.Type().Convert(0)
+		}
+
+		return a.Type().Convert(new(big.Rat).Quo(sum, big.NewRat(rows, 1)))
+	}
+
+	sum := buffer[0].(float64)
 
 	if rows == 0 {
 		return float64(0), nil
@@ -64,6 +86,11 @@ func (a *Avg) TransformUp(f sql.TransformExprFunc) (sql.Expression, error) {
 
 // NewBuffer implements AggregationExpression interface. (AggregationExpression)
 func (a *Avg) NewBuffer() sql.Row {
+	// The sum of decimals is exact.
+	if sql.IsFixedPoint(a.Child.Type()) {
+		return sql.NewRow(new(big.Rat), int64(0), false)
+	}
+
 	const (
 		sum   = float64(0)
 		rows  = int64(0)
@@ -90,6 +117,17 @@ func (a *Avg) Update(ctx *sql.Context, buffer, row sql.Row) error {
 		return nil
 	}
 
+	if sum, ok := buffer[0].(*big.Rat); ok {
+		n, err := sql.ParseDecimal(v)
+		if err != nil {
+			return err
+		}
+
+		sum.Add(sum, n)
+		buffer[1] = buffer[1].(int64) + 1
+		return nil
+	}
+
 	v, err = sql.Float64.Convert(v)
 	if err != nil {
 		v = float64(0)
@@ -103,15 +141,18 @@ func (a *Avg) Update(ctx *sql.Context, buffer, row sql.Row) error {
 
 // Merge implements AggregationExpression interface. (AggregationExpression)
 func (a *Avg) Merge(ctx *sql.Context, buffer, partial sql.Row) error {
-	bsum := buffer[0].(float64)
 	brows := buffer[1].(int64)
 	bnulls := buffer[2].(bool)
 
-	psum := partial[0].(float64)
 	prows := partial[1].(int64)
 	pnulls := buffer[2].(bool)
 
-	buffer[0] = bsum + psum
+	if bsum, ok := buffer[0].(*big.Rat); ok {
+		bsum.Add(bsum, partial[0].(*big.Rat))
+	} else {
+		buffer[0] = buffer[0].(float64) + partial[0].(float64)
+	}
+
 	buffer[1] = brows + prows
 	buffer[2] = bnulls || pnulls
 
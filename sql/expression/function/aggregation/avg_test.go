@@ -41,6 +41,22 @@ func TestAvg_Eval_INT32(t *testing.T) {
 	require.Equal(float64(1.5), eval(t, avgNode, buffer))
 }
 
+func TestAvg_Eval_Decimal(t *testing.T) {
+	require := require.New(t)
+	ctx := sql.NewEmptyContext()
+
+	avgNode := NewAvg(expression.NewGetField(0, sql.Decimal(10, 2), "col1", true))
+	require.Equal(sql.Decimal(sql.DecimalMaxPrecision, 6), avgNode.Type())
+
+	buffer := avgNode.NewBuffer()
+	require.Equal("0.000000", eval(t, avgNode, buffer))
+
+	require.NoError(avgNode.Update(ctx, buffer, sql.NewRow("0.10")))
+	require.NoError(avgNode.Update(ctx, buffer, sql.NewRow("0.20")))
+	require.NoError(avgNode.Update(ctx, buffer, sql.NewRow("0.20")))
+	require.Equal("0.166667", eval(t, avgNode, buffer))
+}
+
 func TestAvg_Eval_UINT64(t *testing.T) {
 	require := require.New(t)
 	ctx := sql.NewEmptyContext()
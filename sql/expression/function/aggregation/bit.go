@@ -0,0 +1,135 @@
+package aggregation
+
+import (
+	"fmt"
+	"math"
+
+	"gopkg.in/src-d/go-mysql-server.v0/sql"
+	"gopkg.in/src-d/go-mysql-server.v0/sql/expression"
+)
+
+// BitOp is a bitwise operation of a Bit aggregation.
+type BitOp byte
+
+const (
+	// BitAndOp is the bitwise AND.
+	BitAndOp BitOp = iota
+	// BitOrOp is the bitwise OR.
+	BitOrOp
+	// BitXorOp is the bitwise XOR.
+	BitXorOp
+)
+
+func (op BitOp) String() string {
+	switch op {
+	case BitAndOp:
+		return "BIT_AND"
+	case BitOrOp:
+		return "BIT_OR"
+	default:
+		return "BIT_XOR"
+	}
+}
+
+// Bit aggregation returns the result of the bitwise operation of all the
+// non null values of its child as 64-bit unsigned integers. If there are
+// no values, the result is the identity of the operation, that is, all the
+// bits set for BIT_AND and none set for the rest.
+type Bit struct {
+	expression.UnaryExpression
+	Op BitOp
+}
+
+// NewBitAnd returns a new BIT_AND aggregation.
+func NewBitAnd(e sql.Expression) *Bit {
+	return &Bit{expression.UnaryExpression{Child: e}, BitAndOp}
+}
+
+// NewBitOr returns a new BIT_OR aggregation.
+func NewBitOr(e sql.Expression) *Bit {
+	return &Bit{expression.UnaryExpression{Child: e}, BitOrOp}
+}
+
+// NewBitXor returns a new BIT_XOR aggregation.
+func NewBitXor(e sql.Expression) *Bit {
+	return &Bit{expression.UnaryExpression{Child: e}, BitXorOp}
+}
+
+// Type implements the Expression interface.
+func (b *Bit) Type() sql.Type {
+	return sql.Uint64
+}
+
+// IsNullable implements the Expression interface.
+func (b *Bit) IsNullable() bool {
+	return false
+}
+
+func (b *Bit) String() string {
+	return fmt.Sprintf("%s(%s)", b.Op, b.Child)
+}
+
+// TransformUp implements the Expression interface.
+func (b *Bit) TransformUp(f sql.TransformExprFunc) (sql.Expression, error) {
+	child, err := b.Child.TransformUp(f)
+	if err != nil {
+		return nil, err
+	}
+	return f(&Bit{expression.UnaryExpression{Child: child}, b.Op})
+}
+
+// NewBuffer implements the Aggregation interface.
+func (b *Bit) NewBuffer() sql.Row {
+	if b.Op == BitAndOp {
+		return sql.NewRow(uint64(math.MaxUint64))
+	}
+
+	return sql.NewRow(uint64(0))
+}
+
+// Update implements the Aggregation interface.
+func (b *Bit) Update(ctx *sql.Context, buffer, row sql.Row) error {
+	v, err := b.Child.Eval(ctx, row)
+	if err != nil {
+		return err
+	}
+
+	if v == nil {
+		return nil
+	}
+
+	// Negative values are used as their two's complement.
+	n, ok := v.(uint64)
+	if !ok {
+		i, err := sql.Int64.Convert(v)
+		if err != nil {
+			i = int64(0)
+		}
+		n = uint64(i.(int64))
+	}
+
+	buffer[0] = b.apply(buffer[0].(uint64), n)
+	return nil
+}
+
+// Merge implements the Aggregation interface.
+func (b *Bit) Merge(ctx *sql.Context, buffer, partial sql.Row) error {
+	buffer[0] = b.apply(buffer[0].(uint64), partial[0].(uint64))
+	return nil
+}
+
+// Eval implements the Aggregation interface.
+func (b *Bit) Eval(ctx *sql.Context, buffer sql.Row) (interface{}, error) {
+	return buffer[0], nil
+}
+
+func (b *Bit) apply(x, y uint64) uint64 {
+	switch b.Op {
+	case BitAndOp:
+		return x & y
+	case BitOrOp:
+		return x | y
+	default:
+		return x ^ y
+	}
+}
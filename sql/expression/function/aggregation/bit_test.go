@@ -0,0 +1,54 @@
+package aggregation
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"gopkg.in/src-d/go-mysql-server.v0/sql"
+	"gopkg.in/src-d/go-mysql-server.v0/sql/expression"
+)
+
+func TestBit(t *testing.T) {
+	ctx := sql.NewEmptyContext()
+	field := expression.NewGetField(0, sql.Int64, "a", true)
+
+	testCases := []struct {
+		agg      *Bit
+		empty    uint64
+		expected uint64
+	}{
+		{NewBitAnd(field), math.MaxUint64, 4},
+		{NewBitOr(field), 0, 15},
+		{NewBitXor(field), 0, 13},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.agg.String(), func(t *testing.T) {
+			require := require.New(t)
+
+			b := tt.agg.NewBuffer()
+			require.Equal(tt.empty, eval(t, tt.agg, b))
+
+			require.NoError(tt.agg.Update(ctx, b, sql.NewRow(int64(12))))
+			require.NoError(tt.agg.Update(ctx, b, sql.NewRow(nil)))
+
+			partial := tt.agg.NewBuffer()
+			require.NoError(tt.agg.Update(ctx, partial, sql.NewRow(int64(7))))
+			require.NoError(tt.agg.Update(ctx, partial, sql.NewRow(int64(6))))
+
+			require.NoError(tt.agg.Merge(ctx, b, partial))
+			require.Equal(tt.expected, eval(t, tt.agg, b))
+		})
+	}
+}
+
+func TestBit_Negative(t *testing.T) {
+	require := require.New(t)
+	ctx := sql.NewEmptyContext()
+
+	b := NewBitOr(expression.NewGetField(0, sql.Int64, "a", true))
+	buf := b.NewBuffer()
+	require.NoError(b.Update(ctx, buf, sql.NewRow(int64(-1))))
+	require.Equal(uint64(math.MaxUint64), eval(t, b, buf))
+}
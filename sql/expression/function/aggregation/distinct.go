@@ -0,0 +1,176 @@
+package aggregation
+
+import (
+	"fmt"
+	"reflect"
+
+	"gopkg.in/src-d/go-mysql-server.v0/sql"
+	"gopkg.in/src-d/go-mysql-server.v0/sql/expression"
+)
+
+// distinctValues is the set of distinct rows seen by an aggregation. The
+// rows are kept in the order they were added, along with the values they
+// are distinct by and their hashes. Rows with the same hash are compared
+// by their values, so hash collisions don't drop any row.
+type distinctValues struct {
+	// buckets are the positions of the rows with each hash.
+	buckets map[uint64][]int
+	hashes  []uint64
+	values  [][]interface{}
+	rows    []sql.Row
+}
+
+func newDistinctValues() *distinctValues {
+	return &distinctValues{buckets: make(map[uint64][]int)}
+}
+
+// add adds the given row to the set if there is no row with the same
+// values, which have the types of the given expressions, in it.
+func (d *distinctValues) add(exprs []sql.Expression, vals []interface{}, row sql.Row) {
+	types := expressionTypes(exprs)
+	d.insert(sql.HashValues(types, vals), sql.CollationKeys(types, vals), row)
+}
+
+func (d *distinctValues) insert(hash uint64, vals []interface{}, row sql.Row) {
+	for _, i := range d.buckets[hash] {
+		if reflect.DeepEqual(d.values[i], vals) {
+			return
+		}
+	}
+
+	d.buckets[hash] = append(d.buckets[hash], len(d.rows))
+	d.hashes = append(d.hashes, hash)
+	d.values = append(d.values, vals)
+	d.rows = append(d.rows, row)
+}
+
+// merge adds all the rows of the given set to this one.
+func (d *distinctValues) merge(other *distinctValues) {
+	for i, row := range other.rows {
+		d.insert(other.hashes[i], other.values[i], row)
+	}
+}
+
+// expressionTypes returns the types of the given expressions.
+func expressionTypes(exprs []sql.Expression) []sql.Type {
+	types := make([]sql.Type, len(exprs))
+	for i, e := range exprs {
+		types[i] = e.Type()
+	}
+	return types
+}
+
+// hashValues returns the hash of the given values, which have the types of
+// the given expressions. Strings that are equal using the collation of
+// their expression have the same hash.
+func hashValues(exprs []sql.Expression, vals []interface{}) uint64 {
+	return sql.HashValues(expressionTypes(exprs), vals)
+}
+
+// Distinct is an aggregation that only aggregates the distinct non null
+// values of its child, such as COUNT(DISTINCT x). Since the same value can
+// be in several partial buffers, the buffer keeps the distinct values and
+// the aggregation is computed with them when the buffer is evaluated.
+type Distinct struct {
+	expression.UnaryExpression
+	name string
+}
+
+// NewCountDistinct returns a new COUNT(DISTINCT ...) aggregation.
+func NewCountDistinct(e sql.Expression) *Distinct {
+	return &Distinct{expression.UnaryExpression{Child: e}, "COUNT"}
+}
+
+// NewSumDistinct returns a new SUM(DISTINCT ...) aggregation.
+func NewSumDistinct(e sql.Expression) *Distinct {
+	return &Distinct{expression.UnaryExpression{Child: e}, "SUM"}
+}
+
+// NewAvgDistinct returns a new AVG(DISTINCT ...) aggregation.
+func NewAvgDistinct(e sql.Expression) *Distinct {
+	return &Distinct{expression.UnaryExpression{Child: e}, "AVG"}
+}
+
+// aggregation returns the aggregation that is computed with the distinct
+// values, which reads them from the first column of each row.
+func (d *Distinct) aggregation() sql.Aggregation {
+	field := expression.NewGetField(
+		0, d.Child.Type(), d.Child.String(), d.Child.IsNullable(),
+	)
+
+	switch d.name {
+	case "SUM":
+		return NewSum(field)
+	case "AVG":
+		return NewAvg(field)
+	default:
+		return NewCount(field)
+	}
+}
+
+// Resolved implements the Expression interface.
+func (d *Distinct) Resolved() bool {
+	return d.Child.Resolved()
+}
+
+// Type implements the Expression interface.
+func (d *Distinct) Type() sql.Type {
+	return d.aggregation().Type()
+}
+
+// IsNullable implements the Expression interface.
+func (d *Distinct) IsNullable() bool {
+	return d.aggregation().IsNullable()
+}
+
+func (d *Distinct) String() string {
+	return fmt.Sprintf("%s(DISTINCT %s)", d.name, d.Child)
+}
+
+// TransformUp implements the Expression interface.
+func (d *Distinct) TransformUp(f sql.TransformExprFunc) (sql.Expression, error) {
+	child, err := d.Child.TransformUp(f)
+	if err != nil {
+		return nil, err
+	}
+	return f(&Distinct{expression.UnaryExpression{Child: child}, d.name})
+}
+
+// NewBuffer implements the Aggregation interface.
+func (d *Distinct) NewBuffer() sql.Row {
+	return sql.NewRow(newDistinctValues())
+}
+
+// Update implements the Aggregation interface.
+func (d *Distinct) Update(ctx *sql.Context, buffer, row sql.Row) error {
+	v, err := d.Child.Eval(ctx, row)
+	if err != nil {
+		return err
+	}
+
+	if v == nil {
+		return nil
+	}
+
+	buffer[0].(*distinctValues).add([]sql.Expression{d.Child}, []interface{}{v}, sql.NewRow(v))
+	return nil
+}
+
+// Merge implements the Aggregation interface.
+func (d *Distinct) Merge(ctx *sql.Context, buffer, partial sql.Row) error {
+	buffer[0].(*distinctValues).merge(partial[0].(*distinctValues))
+	return nil
+}
+
+// Eval implements the Aggregation interface.
+func (d *Distinct) Eval(ctx *sql.Context, buffer sql.Row) (interface{}, error) {
+	agg := d.aggregation()
+	b := agg.NewBuffer()
+	for _, row := range buffer[0].(*distinctValues).rows {
+		if err := agg.Update(ctx, b, row); err != nil {
+			return nil, err
+		}
+	}
+
+	return agg.Eval(ctx, b)
+}
@@ -0,0 +1,89 @@
+package aggregation
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"gopkg.in/src-d/go-mysql-server.v0/sql"
+	"gopkg.in/src-d/go-mysql-server.v0/sql/expression"
+)
+
+func TestDistinct_String(t *testing.T) {
+	require := require.New(t)
+
+	c := NewCountDistinct(expression.NewGetField(0, sql.Int64, "a", true))
+	require.Equal("COUNT(DISTINCT a)", c.String())
+	require.Equal(sql.Int32, c.Type())
+	require.False(c.IsNullable())
+
+	s := NewSumDistinct(expression.NewGetField(0, sql.Int64, "a", true))
+	require.Equal("SUM(DISTINCT a)", s.String())
+	require.Equal(sql.Float64, s.Type())
+}
+
+func TestDistinct_Eval(t *testing.T) {
+	ctx := sql.NewEmptyContext()
+	field := expression.NewGetField(0, sql.Int64, "a", true)
+
+	testCases := []struct {
+		agg      *Distinct
+		expected interface{}
+	}{
+		{NewCountDistinct(field), int32(3)},
+		{NewSumDistinct(field), float64(6)},
+		{NewAvgDistinct(field), float64(2)},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.agg.String(), func(t *testing.T) {
+			require := require.New(t)
+
+			b := tt.agg.NewBuffer()
+			for _, v := range []interface{}{int64(1), int64(2), nil, int64(1)} {
+				require.NoError(tt.agg.Update(ctx, b, sql.NewRow(v)))
+			}
+
+			// The values of the partial buffer that are already in the
+			// other one are only aggregated once.
+			partial := tt.agg.NewBuffer()
+			for _, v := range []interface{}{int64(3), int64(2)} {
+				require.NoError(tt.agg.Update(ctx, partial, sql.NewRow(v)))
+			}
+
+			require.NoError(tt.agg.Merge(ctx, b, partial))
+			require.Equal(tt.expected, eval(t, tt.agg, b))
+		})
+	}
+}
+
+func TestDistinct_Strings(t *testing.T) {
+	require := require.New(t)
+	ctx := sql.NewEmptyContext()
+
+	c := NewCountDistinct(expression.NewGetField(0, sql.Text, "a", true))
+	b := c.NewBuffer()
+	require.NoError(c.Update(ctx, b, sql.NewRow("foo")))
+	require.NoError(c.Update(ctx, b, sql.NewRow("bar")))
+	require.NoError(c.Update(ctx, b, sql.NewRow("foo")))
+	require.Equal(int32(2), eval(t, c, b))
+}
+
+func TestDistinctValues_HashCollisions(t *testing.T) {
+	require := require.New(t)
+
+	// All the rows have the same hash, so they are compared by value.
+	d := newDistinctValues()
+	d.insert(1, []interface{}{int64(1)}, sql.NewRow(int64(1)))
+	d.insert(1, []interface{}{int64(2)}, sql.NewRow(int64(2)))
+	d.insert(1, []interface{}{int64(1)}, sql.NewRow(int64(1)))
+
+	other := newDistinctValues()
+	other.insert(1, []interface{}{int64(3)}, sql.NewRow(int64(3)))
+	other.insert(1, []interface{}{int64(2)}, sql.NewRow(int64(2)))
+	d.merge(other)
+
+	require.Equal(
+		[]sql.Row{sql.NewRow(int64(1)), sql.NewRow(int64(2)), sql.NewRow(int64(3))},
+		d.rows,
+	)
+}
@@ -0,0 +1,269 @@
+package aggregation
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"gopkg.in/src-d/go-mysql-server.v0/sql"
+)
+
+// DefaultSeparator is the separator of the values of GROUP_CONCAT when no
+// separator is given.
+const DefaultSeparator = ","
+
+// SortField is a column the values of an aggregation are sorted by.
+type SortField struct {
+	// Column to order by.
+	Column sql.Expression
+	// Descending is whether the order is descending.
+	Descending bool
+}
+
+// GroupConcat aggregation returns the concatenation of the values of its
+// expressions in the rows where none of them is null, sorted by the given
+// fields and separated by the given separator. The result is truncated to
+// the length in the group_concat_max_len session variable.
+type GroupConcat struct {
+	Distinct  bool
+	Exprs     []sql.Expression
+	OrderBy   []SortField
+	Separator string
+}
+
+// NewGroupConcat returns a new GroupConcat node.
+func NewGroupConcat(
+	distinct bool,
+	exprs []sql.Expression,
+	orderBy []SortField,
+	separator string,
+) *GroupConcat {
+	return &GroupConcat{distinct, exprs, orderBy, separator}
+}
+
+// Resolved implements the Expression interface.
+func (g *GroupConcat) Resolved() bool {
+	for _, e := range g.Children() {
+		if !e.Resolved() {
+			return false
+		}
+	}
+	return true
+}
+
+// Type implements the Expression interface.
+func (g *GroupConcat) Type() sql.Type {
+	return sql.Text
+}
+
+// IsNullable implements the Expression interface.
+func (g *GroupConcat) IsNullable() bool {
+	return true
+}
+
+// Children implements the Expression interface.
+func (g *GroupConcat) Children() []sql.Expression {
+	children := append([]sql.Expression(nil), g.Exprs...)
+	for _, sf := range g.OrderBy {
+		children = append(children, sf.Column)
+	}
+	return children
+}
+
+func (g *GroupConcat) String() string {
+	var exprs = make([]string, len(g.Exprs))
+	for i, e := range g.Exprs {
+		exprs[i] = e.String()
+	}
+
+	var distinct string
+	if g.Distinct {
+		distinct = "DISTINCT "
+	}
+
+	var orderBy string
+	if len(g.OrderBy) > 0 {
+		var fields = make([]string, len(g.OrderBy))
+		for i, sf := range g.OrderBy {
+			fields[i] = sf.Column.String()
+			if sf.Descending {
+				fields[i] += " DESC"
+			}
+		}
+		orderBy = " ORDER BY " + strings.Join(fields, ", ")
+	}
+
+	return fmt.Sprintf(
+		"GROUP_CONCAT(%s%s%s SEPARATOR '%s')",
+		distinct, strings.Join(exprs, ", "), orderBy, g.Separator,
+	)
+}
+
+// TransformUp implements the Expression interface.
+func (g *GroupConcat) TransformUp(f sql.TransformExprFunc) (sql.Expression, error) {
+	var exprs = make([]sql.Expression, len(g.Exprs))
+	for i, e := range g.Exprs {
+		var err error
+		exprs[i], err = e.TransformUp(f)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var orderBy = make([]SortField, len(g.OrderBy))
+	for i, sf := range g.OrderBy {
+		col, err := sf.Column.TransformUp(f)
+		if err != nil {
+			return nil, err
+		}
+		orderBy[i] = SortField{col, sf.Descending}
+	}
+
+	return f(NewGroupConcat(g.Distinct, exprs, orderBy, g.Separator))
+}
+
+// NewBuffer implements the Aggregation interface. The buffer keeps the
+// concatenated values of the expressions of each row followed by the
+// values of the fields it's sorted by.
+func (g *GroupConcat) NewBuffer() sql.Row {
+	return sql.NewRow(newDistinctValues())
+}
+
+// Update implements the Aggregation interface.
+func (g *GroupConcat) Update(ctx *sql.Context, buffer, row sql.Row) error {
+	var vals = make([]interface{}, len(g.Exprs))
+	var buf strings.Builder
+	for i, e := range g.Exprs {
+		v, err := e.Eval(ctx, row)
+		if err != nil {
+			return err
+		}
+
+		if v == nil {
+			return nil
+		}
+
+		s, err := sql.Text.Convert(v)
+		if err != nil {
+			return err
+		}
+
+		vals[i] = v
+		buf.WriteString(s.(string))
+	}
+
+	var value = sql.NewRow(buf.String())
+	for _, sf := range g.OrderBy {
+		v, err := sf.Column.Eval(ctx, row)
+		if err != nil {
+			return err
+		}
+		value = append(value, v)
+	}
+
+	values := buffer[0].(*distinctValues)
+	if !g.Distinct {
+		// Every row is added, so their values and hashes are not needed.
+		values.rows = append(values.rows, value)
+		return nil
+	}
+
+	values.add(g.Exprs, vals, value)
+	return nil
+}
+
+// Merge implements the Aggregation interface.
+func (g *GroupConcat) Merge(ctx *sql.Context, buffer, partial sql.Row) error {
+	values, other := buffer[0].(*distinctValues), partial[0].(*distinctValues)
+	if !g.Distinct {
+		values.rows = append(values.rows, other.rows...)
+		return nil
+	}
+
+	values.merge(other)
+	return nil
+}
+
+// Eval implements the Aggregation interface.
+func (g *GroupConcat) Eval(ctx *sql.Context, buffer sql.Row) (interface{}, error) {
+	rows := append([]sql.Row(nil), buffer[0].(*distinctValues).rows...)
+	if len(rows) == 0 {
+		return nil, nil
+	}
+
+	if len(g.OrderBy) > 0 {
+		var err error
+		sort.SliceStable(rows, func(i, j int) bool {
+			if err != nil {
+				return false
+			}
+
+			var less bool
+			less, err = g.less(rows[i], rows[j])
+			return less
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	maxLen := sql.GroupConcatMaxLen(ctx.Session)
+	var buf strings.Builder
+	for i, row := range rows {
+		if i > 0 {
+			buf.WriteString(g.Separator)
+		}
+		buf.WriteString(row[0].(string))
+
+		if uint64(buf.Len()) > maxLen {
+			ctx.Warn(1260, "Row %d was cut by GROUP_CONCAT()", i+1)
+			return truncateString(buf.String(), maxLen), nil
+		}
+	}
+
+	return buf.String(), nil
+}
+
+// less reports whether the given buffered row goes before the other one
+// in the order of the aggregation. Nulls are the smallest values.
+func (g *GroupConcat) less(a, b sql.Row) (bool, error) {
+	for i, sf := range g.OrderBy {
+		av, bv := a[i+1], b[i+1]
+		if sf.Descending {
+			av, bv = bv, av
+		}
+
+		if av == nil || bv == nil {
+			if av == nil && bv == nil {
+				continue
+			}
+			return av == nil, nil
+		}
+
+		cmp, err := sf.Column.Type().Compare(av, bv)
+		if err != nil {
+			return false, err
+		}
+
+		if cmp != 0 {
+			return cmp < 0, nil
+		}
+	}
+
+	return false, nil
+}
+
+// truncateString returns the longest prefix of the given string with at
+// most the given number of bytes that does not split any character.
+func truncateString(s string, n uint64) string {
+	if uint64(len(s)) <= n {
+		return s
+	}
+
+	s = s[:n]
+	for len(s) > 0 && !utf8.ValidString(s) {
+		s = s[:len(s)-1]
+	}
+	return s
+}
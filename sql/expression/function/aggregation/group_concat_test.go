@@ -0,0 +1,123 @@
+package aggregation
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"gopkg.in/src-d/go-mysql-server.v0/sql"
+	"gopkg.in/src-d/go-mysql-server.v0/sql/expression"
+)
+
+func TestGroupConcat_String(t *testing.T) {
+	require := require.New(t)
+
+	g := NewGroupConcat(
+		true,
+		[]sql.Expression{
+			expression.NewGetField(0, sql.Text, "a", true),
+			expression.NewGetField(1, sql.Int64, "b", true),
+		},
+		[]SortField{{expression.NewGetField(1, sql.Int64, "b", true), true}},
+		"-",
+	)
+	require.Equal("GROUP_CONCAT(DISTINCT a, b ORDER BY b DESC SEPARATOR '-')", g.String())
+}
+
+func TestGroupConcat_Eval(t *testing.T) {
+	a := expression.NewGetField(0, sql.Text, "a", true)
+	b := expression.NewGetField(1, sql.Int64, "b", true)
+
+	rows := []sql.Row{
+		{"foo", int64(3)},
+		{"bar", int64(1)},
+		{nil, int64(4)},
+		{"foo", int64(2)},
+		{"baz", nil},
+	}
+
+	testCases := []struct {
+		name     string
+		agg      *GroupConcat
+		expected interface{}
+	}{
+		{
+			"default",
+			NewGroupConcat(false, []sql.Expression{a}, nil, DefaultSeparator),
+			"foo,bar,foo,baz",
+		},
+		{
+			"distinct",
+			NewGroupConcat(true, []sql.Expression{a}, nil, DefaultSeparator),
+			"foo,bar,baz",
+		},
+		{
+			"order by",
+			NewGroupConcat(false, []sql.Expression{a}, []SortField{{b, false}}, " "),
+			"baz bar foo foo",
+		},
+		{
+			"order by descending",
+			NewGroupConcat(false, []sql.Expression{a, b}, []SortField{{b, true}}, ""),
+			"foo3foo2bar1",
+		},
+		{
+			"no values",
+			NewGroupConcat(false, []sql.Expression{expression.NewLiteral(nil, sql.Null)}, nil, DefaultSeparator),
+			nil,
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			require := require.New(t)
+			ctx := sql.NewEmptyContext()
+
+			buf := tt.agg.NewBuffer()
+			partial := tt.agg.NewBuffer()
+			for i, row := range rows {
+				if i < 2 {
+					require.NoError(tt.agg.Update(ctx, buf, row))
+				} else {
+					require.NoError(tt.agg.Update(ctx, partial, row))
+				}
+			}
+
+			require.NoError(tt.agg.Merge(ctx, buf, partial))
+
+			v, err := tt.agg.Eval(ctx, buf)
+			require.NoError(err)
+			require.Equal(tt.expected, v)
+		})
+	}
+}
+
+func TestGroupConcat_MaxLen(t *testing.T) {
+	require := require.New(t)
+	ctx := sql.NewEmptyContext()
+	require.NoError(ctx.Session.Set("group_concat_max_len", sql.Int64, int64(5)))
+
+	g := NewGroupConcat(
+		false,
+		[]sql.Expression{expression.NewGetField(0, sql.Text, "a", true)},
+		nil,
+		DefaultSeparator,
+	)
+
+	b := g.NewBuffer()
+	for _, v := range []string{"ab", "cd"} {
+		require.NoError(g.Update(ctx, b, sql.NewRow(v)))
+	}
+
+	v, err := g.Eval(ctx, b)
+	require.NoError(err)
+	require.Equal("ab,cd", v)
+	require.Equal(uint16(0), ctx.WarningCount())
+
+	require.NoError(g.Update(ctx, b, sql.NewRow("ñ")))
+	require.NoError(ctx.Session.Set("group_concat_max_len", sql.Int64, int64(7)))
+
+	v, err = g.Eval(ctx, b)
+	require.NoError(err)
+	require.Equal("ab,cd,", v)
+	require.Equal(uint16(1), ctx.WarningCount())
+}
@@ -0,0 +1,173 @@
+package aggregation
+
+import (
+	"fmt"
+
+	errors "gopkg.in/src-d/go-errors.v1"
+	"gopkg.in/src-d/go-mysql-server.v0/sql"
+	"gopkg.in/src-d/go-mysql-server.v0/sql/expression"
+)
+
+// ErrNullJSONKey is returned when a key of JSON_OBJECTAGG is null.
+var ErrNullJSONKey = errors.NewKind("JSON documents may not contain NULL member names")
+
+// JSONArrayAgg aggregation returns a JSON array with the values of its
+// child, including nulls, or null if there are no rows.
+type JSONArrayAgg struct {
+	expression.UnaryExpression
+}
+
+// NewJSONArrayAgg returns a new JSONArrayAgg node.
+func NewJSONArrayAgg(e sql.Expression) *JSONArrayAgg {
+	return &JSONArrayAgg{expression.UnaryExpression{Child: e}}
+}
+
+// Type implements the Expression interface.
+func (j *JSONArrayAgg) Type() sql.Type {
+	return sql.JSON
+}
+
+// IsNullable implements the Expression interface.
+func (j *JSONArrayAgg) IsNullable() bool {
+	return true
+}
+
+func (j *JSONArrayAgg) String() string {
+	return fmt.Sprintf("JSON_ARRAYAGG(%s)", j.Child)
+}
+
+// TransformUp implements the Expression interface.
+func (j *JSONArrayAgg) TransformUp(f sql.TransformExprFunc) (sql.Expression, error) {
+	child, err := j.Child.TransformUp(f)
+	if err != nil {
+		return nil, err
+	}
+	return f(NewJSONArrayAgg(child))
+}
+
+// NewBuffer implements the Aggregation interface.
+func (j *JSONArrayAgg) NewBuffer() sql.Row {
+	return sql.NewRow([]interface{}(nil))
+}
+
+// Update implements the Aggregation interface.
+func (j *JSONArrayAgg) Update(ctx *sql.Context, buffer, row sql.Row) error {
+	v, err := j.Child.Eval(ctx, row)
+	if err != nil {
+		return err
+	}
+
+	buffer[0] = append(buffer[0].([]interface{}), v)
+	return nil
+}
+
+// Merge implements the Aggregation interface.
+func (j *JSONArrayAgg) Merge(ctx *sql.Context, buffer, partial sql.Row) error {
+	buffer[0] = append(buffer[0].([]interface{}), partial[0].([]interface{})...)
+	return nil
+}
+
+// Eval implements the Aggregation interface.
+func (j *JSONArrayAgg) Eval(ctx *sql.Context, buffer sql.Row) (interface{}, error) {
+	values := buffer[0].([]interface{})
+	if len(values) == 0 {
+		return nil, nil
+	}
+
+	return values, nil
+}
+
+// JSONObjectAgg aggregation returns a JSON object with the values of its
+// right child keyed by the values of its left one, or null if there are no
+// rows. If a key is repeated, the last value is kept.
+type JSONObjectAgg struct {
+	expression.BinaryExpression
+}
+
+// NewJSONObjectAgg returns a new JSONObjectAgg node.
+func NewJSONObjectAgg(key, value sql.Expression) *JSONObjectAgg {
+	return &JSONObjectAgg{expression.BinaryExpression{Left: key, Right: value}}
+}
+
+// Type implements the Expression interface.
+func (j *JSONObjectAgg) Type() sql.Type {
+	return sql.JSON
+}
+
+// IsNullable implements the Expression interface.
+func (j *JSONObjectAgg) IsNullable() bool {
+	return true
+}
+
+func (j *JSONObjectAgg) String() string {
+	return fmt.Sprintf("JSON_OBJECTAGG(%s, %s)", j.Left, j.Right)
+}
+
+// TransformUp implements the Expression interface.
+func (j *JSONObjectAgg) TransformUp(f sql.TransformExprFunc) (sql.Expression, error) {
+	key, err := j.Left.TransformUp(f)
+	if err != nil {
+		return nil, err
+	}
+
+	value, err := j.Right.TransformUp(f)
+	if err != nil {
+		return nil, err
+	}
+
+	return f(NewJSONObjectAgg(key, value))
+}
+
+// NewBuffer implements the Aggregation interface.
+func (j *JSONObjectAgg) NewBuffer() sql.Row {
+	return sql.NewRow(make(map[string]interface{}))
+}
+
+// Update implements the Aggregation interface.
+func (j *JSONObjectAgg) Update(ctx *sql.Context, buffer, row sql.Row) error {
+	key, err := j.Left.Eval(ctx, row)
+	if err != nil {
+		return err
+	}
+
+	if key == nil {
+		return ErrNullJSONKey.New()
+	}
+
+	key, err = sql.Text.Convert(key)
+	if err != nil {
+		return err
+	}
+
+	value, err := j.Right.Eval(ctx, row)
+	if err != nil {
+		return err
+	}
+
+	buffer[0].(map[string]interface{})[key.(string)] = value
+	return nil
+}
+
+// Merge implements the Aggregation interface.
+func (j *JSONObjectAgg) Merge(ctx *sql.Context, buffer, partial sql.Row) error {
+	object := buffer[0].(map[string]interface{})
+	for k, v := range partial[0].(map[string]interface{}) {
+		object[k] = v
+	}
+	return nil
+}
+
+// Eval implements the Aggregation interface. The object is copied, as the
+// buffer may still be updated afterwards, e.g. by windows.
+func (j *JSONObjectAgg) Eval(ctx *sql.Context, buffer sql.Row) (interface{}, error) {
+	object := buffer[0].(map[string]interface{})
+	if len(object) == 0 {
+		return nil, nil
+	}
+
+	result := make(map[string]interface{}, len(object))
+	for k, v := range object {
+		result[k] = v
+	}
+	return result, nil
+}
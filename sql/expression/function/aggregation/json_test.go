@@ -0,0 +1,59 @@
+package aggregation
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"gopkg.in/src-d/go-mysql-server.v0/sql"
+	"gopkg.in/src-d/go-mysql-server.v0/sql/expression"
+)
+
+func TestJSONArrayAgg(t *testing.T) {
+	require := require.New(t)
+	ctx := sql.NewEmptyContext()
+
+	j := NewJSONArrayAgg(expression.NewGetField(0, sql.Int64, "a", true))
+	require.Equal("JSON_ARRAYAGG(a)", j.String())
+
+	b := j.NewBuffer()
+	require.Nil(eval(t, j, b))
+
+	require.NoError(j.Update(ctx, b, sql.NewRow(int64(1))))
+	require.NoError(j.Update(ctx, b, sql.NewRow(nil)))
+
+	partial := j.NewBuffer()
+	require.NoError(j.Update(ctx, partial, sql.NewRow(int64(3))))
+
+	require.NoError(j.Merge(ctx, b, partial))
+	require.Equal([]interface{}{int64(1), nil, int64(3)}, eval(t, j, b))
+}
+
+func TestJSONObjectAgg(t *testing.T) {
+	require := require.New(t)
+	ctx := sql.NewEmptyContext()
+
+	j := NewJSONObjectAgg(
+		expression.NewGetField(0, sql.Text, "k", true),
+		expression.NewGetField(1, sql.Int64, "v", true),
+	)
+	require.Equal("JSON_OBJECTAGG(k, v)", j.String())
+
+	b := j.NewBuffer()
+	require.Nil(eval(t, j, b))
+
+	require.NoError(j.Update(ctx, b, sql.NewRow("a", int64(1))))
+	require.NoError(j.Update(ctx, b, sql.NewRow("b", nil)))
+
+	partial := j.NewBuffer()
+	require.NoError(j.Update(ctx, partial, sql.NewRow("a", int64(3))))
+
+	require.NoError(j.Merge(ctx, b, partial))
+	require.Equal(
+		map[string]interface{}{"a": int64(3), "b": nil},
+		eval(t, j, b),
+	)
+
+	err := j.Update(ctx, b, sql.NewRow(nil, int64(1)))
+	require.Error(err)
+	require.True(ErrNullJSONKey.Is(err))
+}
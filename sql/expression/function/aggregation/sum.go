@@ -2,6 +2,7 @@ package aggregation
 
 import (
 	"fmt"
+	"math/big"
 
 	"gopkg.in/src-d/go-mysql-server.v0/sql"
 	"gopkg.in/src-d/go-mysql-server.v0/sql/expression"
@@ -18,8 +19,13 @@ func NewSum(e sql.Expression) *Sum {
 	return &Sum{expression.UnaryExpression{Child: e}}
 }
 
-// Type returns the resultant type of the aggregation.
+// Type returns the resultant type of the aggregation, which is a decimal
+// with the same scale for decimals and DOUBLE for the rest of the types.
 func (m *Sum) Type() sql.Type {
+	if _, scale, ok := sql.NumericPrecision(m.Child.Type()); ok && sql.IsFixedPoint(m.Child.Type()) {
+		return sql.Decimal(sql.DecimalMaxPrecision, scale)
+	}
+
 	return sql.Float64
 }
 
@@ -52,6 +58,20 @@ func (m *Sum) Update(ctx *sql.Context, buffer, row sql.Row) error {
 		return nil
 	}
 
+	if sql.IsFixedPoint(m.Child.Type()) {
+		val, err := sql.ParseDecimal(v)
+		if err != nil {
+			return err
+		}
+
+		if buffer[0] == nil {
+			buffer[0] = new(big.Rat)
+		}
+
+		buffer[0].(*big.Rat).Add(buffer[0].(*big.Rat), val)
+		return nil
+	}
+
 	val, err := sql.Float64.Convert(v)
 	if err != nil {
 		val = float64(0)
@@ -74,6 +94,9 @@ func (m *Sum) Merge(ctx *sql.Context, buffer, partial sql.Row) error {
 // Eval implements the Aggregation interface.
 func (m *Sum) Eval(ctx *sql.Context, buffer sql.Row) (interface{}, error) {
 	sum := buffer[0]
+	if r, ok := sum.(*big.Rat); ok {
+		return m.Type().Convert(r)
+	}
 
 	return sum, nil
 }
@@ -73,3 +73,20 @@ func TestSum(t *testing.T) {
 		})
 	}
 }
+
+func TestSumDecimal(t *testing.T) {
+	require := require.New(t)
+	ctx := sql.NewEmptyContext()
+
+	sum := NewSum(expression.NewGetField(0, sql.Decimal(10, 2), "", true))
+	require.Equal(sql.Decimal(sql.DecimalMaxPrecision, 2), sum.Type())
+
+	buf := sum.NewBuffer()
+	for _, v := range []interface{}{"0.10", "0.20", nil, "1000000.05"} {
+		require.NoError(sum.Update(ctx, buf, sql.NewRow(v)))
+	}
+
+	result, err := sum.Eval(ctx, buf)
+	require.NoError(err)
+	require.Equal("1000000.35", result)
+}
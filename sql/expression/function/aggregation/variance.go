@@ -0,0 +1,158 @@
+package aggregation
+
+import (
+	"fmt"
+	"math"
+
+	"gopkg.in/src-d/go-mysql-server.v0/sql"
+	"gopkg.in/src-d/go-mysql-server.v0/sql/expression"
+)
+
+// Variance aggregation returns the variance or the standard deviation of
+// the non null values of its child. The buffer keeps the number of values,
+// their mean and the sum of the squares of their differences with the mean,
+// which are updated with Welford's method and merged with the method of
+// Chan et al., so they don't lose precision with large values.
+type Variance struct {
+	expression.UnaryExpression
+	// Sample is whether the sample variance is computed instead of the
+	// population one.
+	Sample bool
+	// StdDev is whether the standard deviation is returned instead of the
+	// variance.
+	StdDev bool
+}
+
+// NewVarPop returns a new VAR_POP aggregation.
+func NewVarPop(e sql.Expression) *Variance {
+	return &Variance{expression.UnaryExpression{Child: e}, false, false}
+}
+
+// NewVarSamp returns a new VAR_SAMP aggregation.
+func NewVarSamp(e sql.Expression) *Variance {
+	return &Variance{expression.UnaryExpression{Child: e}, true, false}
+}
+
+// NewStdDevPop returns a new STDDEV_POP aggregation.
+func NewStdDevPop(e sql.Expression) *Variance {
+	return &Variance{expression.UnaryExpression{Child: e}, false, true}
+}
+
+// NewStdDevSamp returns a new STDDEV_SAMP aggregation.
+func NewStdDevSamp(e sql.Expression) *Variance {
+	return &Variance{expression.UnaryExpression{Child: e}, true, true}
+}
+
+// Type implements the Expression interface.
+func (v *Variance) Type() sql.Type {
+	return sql.Float64
+}
+
+// IsNullable implements the Expression interface.
+func (v *Variance) IsNullable() bool {
+	return true
+}
+
+func (v *Variance) String() string {
+	name := "VAR"
+	if v.StdDev {
+		name = "STDDEV"
+	}
+
+	if v.Sample {
+		name += "_SAMP"
+	} else {
+		name += "_POP"
+	}
+
+	return fmt.Sprintf("%s(%s)", name, v.Child)
+}
+
+// TransformUp implements the Expression interface.
+func (v *Variance) TransformUp(f sql.TransformExprFunc) (sql.Expression, error) {
+	child, err := v.Child.TransformUp(f)
+	if err != nil {
+		return nil, err
+	}
+	return f(&Variance{expression.UnaryExpression{Child: child}, v.Sample, v.StdDev})
+}
+
+// NewBuffer implements the Aggregation interface.
+func (v *Variance) NewBuffer() sql.Row {
+	const (
+		count = int64(0)
+		mean  = float64(0)
+		m2    = float64(0)
+	)
+
+	return sql.NewRow(count, mean, m2)
+}
+
+// Update implements the Aggregation interface.
+func (v *Variance) Update(ctx *sql.Context, buffer, row sql.Row) error {
+	val, err := v.Child.Eval(ctx, row)
+	if err != nil {
+		return err
+	}
+
+	if val == nil {
+		return nil
+	}
+
+	val, err = sql.Float64.Convert(val)
+	if err != nil {
+		val = float64(0)
+	}
+
+	x := val.(float64)
+	count := buffer[0].(int64) + 1
+	mean := buffer[1].(float64)
+	delta := x - mean
+	mean += delta / float64(count)
+
+	buffer[0] = count
+	buffer[1] = mean
+	buffer[2] = buffer[2].(float64) + delta*(x-mean)
+
+	return nil
+}
+
+// Merge implements the Aggregation interface.
+func (v *Variance) Merge(ctx *sql.Context, buffer, partial sql.Row) error {
+	bcount, pcount := buffer[0].(int64), partial[0].(int64)
+	if pcount == 0 {
+		return nil
+	}
+
+	count := bcount + pcount
+	bmean, pmean := buffer[1].(float64), partial[1].(float64)
+	delta := pmean - bmean
+
+	buffer[0] = count
+	buffer[1] = bmean + delta*float64(pcount)/float64(count)
+	buffer[2] = buffer[2].(float64) + partial[2].(float64) +
+		delta*delta*float64(bcount)*float64(pcount)/float64(count)
+
+	return nil
+}
+
+// Eval implements the Aggregation interface.
+func (v *Variance) Eval(ctx *sql.Context, buffer sql.Row) (interface{}, error) {
+	count := buffer[0].(int64)
+	m2 := buffer[2].(float64)
+
+	if v.Sample {
+		count--
+	}
+
+	if count <= 0 {
+		return nil, nil
+	}
+
+	variance := m2 / float64(count)
+	if v.StdDev {
+		return math.Sqrt(variance), nil
+	}
+
+	return variance, nil
+}
@@ -0,0 +1,94 @@
+package aggregation
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"gopkg.in/src-d/go-mysql-server.v0/sql"
+	"gopkg.in/src-d/go-mysql-server.v0/sql/expression"
+)
+
+func TestVariance_String(t *testing.T) {
+	require := require.New(t)
+	field := expression.NewGetField(0, sql.Float64, "a", true)
+
+	require.Equal("VAR_POP(a)", NewVarPop(field).String())
+	require.Equal("VAR_SAMP(a)", NewVarSamp(field).String())
+	require.Equal("STDDEV_POP(a)", NewStdDevPop(field).String())
+	require.Equal("STDDEV_SAMP(a)", NewStdDevSamp(field).String())
+}
+
+func TestVariance_Eval(t *testing.T) {
+	ctx := sql.NewEmptyContext()
+	field := expression.NewGetField(0, sql.Float64, "a", true)
+	values := []interface{}{2, 4, nil, 4, 4, 5, 5, 7, 9}
+
+	testCases := []struct {
+		agg      *Variance
+		expected float64
+	}{
+		{NewVarPop(field), 4},
+		{NewVarSamp(field), 32.0 / 7},
+		{NewStdDevPop(field), 2},
+		{NewStdDevSamp(field), math.Sqrt(32.0 / 7)},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.agg.String(), func(t *testing.T) {
+			require := require.New(t)
+
+			b := tt.agg.NewBuffer()
+			require.Nil(eval(t, tt.agg, b))
+
+			// Half of the values are aggregated in a partial buffer that
+			// is merged at the end.
+			partial := tt.agg.NewBuffer()
+			for i, v := range values {
+				if i%2 == 0 {
+					require.NoError(tt.agg.Update(ctx, b, sql.NewRow(v)))
+				} else {
+					require.NoError(tt.agg.Update(ctx, partial, sql.NewRow(v)))
+				}
+			}
+
+			require.NoError(tt.agg.Merge(ctx, b, partial))
+			require.InDelta(tt.expected, eval(t, tt.agg, b), 1e-9)
+		})
+	}
+}
+
+func TestVariance_Stability(t *testing.T) {
+	require := require.New(t)
+	ctx := sql.NewEmptyContext()
+
+	v := NewVarSamp(expression.NewGetField(0, sql.Float64, "a", true))
+	b := v.NewBuffer()
+	partial := v.NewBuffer()
+	for i, x := range []float64{4, 7, 13, 16} {
+		buf := b
+		if i >= 2 {
+			buf = partial
+		}
+		require.NoError(v.Update(ctx, buf, sql.NewRow(1e9+x)))
+	}
+
+	require.NoError(v.Merge(ctx, b, partial))
+	require.InDelta(float64(30), eval(t, v, b), 1e-6)
+}
+
+func TestVariance_SingleValue(t *testing.T) {
+	require := require.New(t)
+	ctx := sql.NewEmptyContext()
+	field := expression.NewGetField(0, sql.Float64, "a", true)
+
+	pop := NewVarPop(field)
+	b := pop.NewBuffer()
+	require.NoError(pop.Update(ctx, b, sql.NewRow(3)))
+	require.Equal(float64(0), eval(t, pop, b))
+
+	samp := NewVarSamp(field)
+	b = samp.NewBuffer()
+	require.NoError(samp.Update(ctx, b, sql.NewRow(3)))
+	require.Nil(eval(t, samp, b))
+}
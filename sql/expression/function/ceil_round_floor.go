@@ -62,6 +62,15 @@ func (c *Ceil) Eval(ctx *sql.Context, row sql.Row) (interface{}, error) {
 		return int32(math.Ceil(child.(float64))), nil
 	}
 
+	if sql.IsFixedPoint(c.Child.Type()) {
+		child, err = sql.Float64.Convert(child)
+		if err != nil {
+			return nil, err
+		}
+
+		return c.Child.Type().Convert(math.Ceil(child.(float64)))
+	}
+
 	if !sql.IsDecimal(c.Child.Type()) {
 		return child, err
 	}
@@ -129,6 +138,15 @@ func (f *Floor) Eval(ctx *sql.Context, row sql.Row) (interface{}, error) {
 		return int32(math.Floor(child.(float64))), nil
 	}
 
+	if sql.IsFixedPoint(f.Child.Type()) {
+		child, err = sql.Float64.Convert(child)
+		if err != nil {
+			return nil, err
+		}
+
+		return f.Child.Type().Convert(math.Floor(child.(float64)))
+	}
+
 	if !sql.IsDecimal(f.Child.Type()) {
 		return child, err
 	}
@@ -225,6 +243,16 @@ func (r *Round) Eval(ctx *sql.Context, row sql.Row) (interface{}, error) {
 		return int32(math.Round(xNum*math.Pow(10.0, dVal)) / math.Pow(10.0, dVal)), nil
 	}
 
+	if sql.IsFixedPoint(r.Left.Type()) {
+		xVal, err = sql.Float64.Convert(xVal)
+		if err != nil {
+			return nil, err
+		}
+
+		xNum := xVal.(float64)
+		return r.Left.Type().Convert(math.Round(xNum*math.Pow(10.0, dVal)) / math.Pow(10.0, dVal))
+	}
+
 	switch xNum := xVal.(type) {
 	case float64:
 		return math.Round(xNum*math.Pow(10.0, dVal)) / math.Pow(10.0, dVal), nil
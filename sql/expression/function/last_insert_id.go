@@ -0,0 +1,37 @@
+package function
+
+import "gopkg.in/src-d/go-mysql-server.v0/sql"
+
+// LastInsertID returns the first value generated for an AUTO_INCREMENT
+// column by the last INSERT of the session.
+type LastInsertID struct{}
+
+// NewLastInsertID creates a new LastInsertID UDF node.
+func NewLastInsertID() sql.Expression {
+	return LastInsertID{}
+}
+
+// Children implements the sql.Expression interface.
+func (LastInsertID) Children() []sql.Expression { return nil }
+
+// Type implements the sql.Expression interface.
+func (LastInsertID) Type() sql.Type { return sql.Uint64 }
+
+// Resolved implements the sql.Expression interface.
+func (LastInsertID) Resolved() bool { return true }
+
+// TransformUp implements the sql.Expression interface.
+func (LastInsertID) TransformUp(f sql.TransformExprFunc) (sql.Expression, error) {
+	return f(LastInsertID{})
+}
+
+// IsNullable implements the sql.Expression interface.
+func (LastInsertID) IsNullable() bool { return false }
+
+// String implements the fmt.Stringer interface.
+func (LastInsertID) String() string { return "last_insert_id()" }
+
+// Eval implements the sql.Expression interface.
+func (LastInsertID) Eval(ctx *sql.Context, _ sql.Row) (interface{}, error) {
+	return ctx.LastInsertID(), nil
+}
@@ -0,0 +1,24 @@
+package function
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"gopkg.in/src-d/go-mysql-server.v0/sql"
+)
+
+func TestLastInsertID(t *testing.T) {
+	require := require.New(t)
+
+	ctx := sql.NewEmptyContext()
+	f := NewLastInsertID()
+
+	result, err := f.Eval(ctx, nil)
+	require.NoError(err)
+	require.Equal(uint64(0), result)
+
+	ctx.SetLastInsertID(42)
+	result, err = f.Eval(ctx, nil)
+	require.NoError(err)
+	require.Equal(uint64(42), result)
+}
@@ -24,49 +24,97 @@ var Defaults = sql.Functions{
 	"sum": sql.Function1(func(e sql.Expression) sql.Expression {
 		return aggregation.NewSum(e)
 	}),
-	"is_binary":     sql.Function1(NewIsBinary),
-	"substring":     sql.FunctionN(NewSubstring),
-	"mid":           sql.FunctionN(NewSubstring),
-	"substr":        sql.FunctionN(NewSubstring),
-	"year":          sql.Function1(NewYear),
-	"month":         sql.Function1(NewMonth),
-	"day":           sql.Function1(NewDay),
-	"weekday":       sql.Function1(NewWeekday),
-	"hour":          sql.Function1(NewHour),
-	"minute":        sql.Function1(NewMinute),
-	"second":        sql.Function1(NewSecond),
-	"dayofweek":     sql.Function1(NewDayOfWeek),
-	"dayofyear":     sql.Function1(NewDayOfYear),
-	"array_length":  sql.Function1(NewArrayLength),
-	"split":         sql.Function2(NewSplit),
-	"concat":        sql.FunctionN(NewConcat),
-	"concat_ws":     sql.FunctionN(NewConcatWithSeparator),
-	"coalesce":      sql.FunctionN(NewCoalesce),
-	"lower":         sql.Function1(NewLower),
-	"upper":         sql.Function1(NewUpper),
-	"ceiling":       sql.Function1(NewCeil),
-	"ceil":          sql.Function1(NewCeil),
-	"floor":         sql.Function1(NewFloor),
-	"round":         sql.FunctionN(NewRound),
-	"connection_id": sql.Function0(NewConnectionID),
-	"soundex":       sql.Function1(NewSoundex),
-	"json_extract":  sql.FunctionN(NewJSONExtract),
-	"ln":            sql.Function1(NewLogBaseFunc(float64(math.E))),
-	"log2":          sql.Function1(NewLogBaseFunc(float64(2))),
-	"log10":         sql.Function1(NewLogBaseFunc(float64(10))),
-	"log":           sql.FunctionN(NewLog),
-	"rpad":          sql.FunctionN(NewPadFunc(rPadType)),
-	"lpad":          sql.FunctionN(NewPadFunc(lPadType)),
-	"sqrt":          sql.Function1(NewSqrt),
-	"pow":           sql.Function2(NewPower),
-	"power":         sql.Function2(NewPower),
-	"ltrim":         sql.Function1(NewTrimFunc(lTrimType)),
-	"rtrim":         sql.Function1(NewTrimFunc(rTrimType)),
-	"trim":          sql.Function1(NewTrimFunc(bTrimType)),
-	"reverse":       sql.Function1(NewReverse),
-	"repeat":        sql.Function2(NewRepeat),
-	"replace":       sql.Function3(NewReplace),
-	"ifnull":        sql.Function2(NewIfNull),
-	"nullif":        sql.Function2(NewNullIf),
-	"now":           sql.Function0(NewNow),
+	"std": sql.Function1(func(e sql.Expression) sql.Expression {
+		return aggregation.NewStdDevPop(e)
+	}),
+	"stddev": sql.Function1(func(e sql.Expression) sql.Expression {
+		return aggregation.NewStdDevPop(e)
+	}),
+	"stddev_pop": sql.Function1(func(e sql.Expression) sql.Expression {
+		return aggregation.NewStdDevPop(e)
+	}),
+	"stddev_samp": sql.Function1(func(e sql.Expression) sql.Expression {
+		return aggregation.NewStdDevSamp(e)
+	}),
+	"variance": sql.Function1(func(e sql.Expression) sql.Expression {
+		return aggregation.NewVarPop(e)
+	}),
+	"var_pop": sql.Function1(func(e sql.Expression) sql.Expression {
+		return aggregation.NewVarPop(e)
+	}),
+	"var_samp": sql.Function1(func(e sql.Expression) sql.Expression {
+		return aggregation.NewVarSamp(e)
+	}),
+	"bit_and": sql.Function1(func(e sql.Expression) sql.Expression {
+		return aggregation.NewBitAnd(e)
+	}),
+	"bit_or": sql.Function1(func(e sql.Expression) sql.Expression {
+		return aggregation.NewBitOr(e)
+	}),
+	"bit_xor": sql.Function1(func(e sql.Expression) sql.Expression {
+		return aggregation.NewBitXor(e)
+	}),
+	"json_arrayagg": sql.Function1(func(e sql.Expression) sql.Expression {
+		return aggregation.NewJSONArrayAgg(e)
+	}),
+	"json_objectagg": sql.Function2(func(key, value sql.Expression) sql.Expression {
+		return aggregation.NewJSONObjectAgg(key, value)
+	}),
+	"approx_count_distinct": sql.Function1(func(e sql.Expression) sql.Expression {
+		return aggregation.NewApproxCountDistinct(e)
+	}),
+	"approx_percentile": sql.Function2(func(e, percentile sql.Expression) sql.Expression {
+		return aggregation.NewApproxPercentile(e, percentile)
+	}),
+	"is_binary":      sql.Function1(NewIsBinary),
+	"substring":      sql.FunctionN(NewSubstring),
+	"mid":            sql.FunctionN(NewSubstring),
+	"substr":         sql.FunctionN(NewSubstring),
+	"year":           sql.Function1(NewYear),
+	"month":          sql.Function1(NewMonth),
+	"day":            sql.Function1(NewDay),
+	"weekday":        sql.Function1(NewWeekday),
+	"hour":           sql.Function1(NewHour),
+	"minute":         sql.Function1(NewMinute),
+	"second":         sql.Function1(NewSecond),
+	"dayofweek":      sql.Function1(NewDayOfWeek),
+	"dayofyear":      sql.Function1(NewDayOfYear),
+	"array_length":   sql.Function1(NewArrayLength),
+	"split":          sql.Function2(NewSplit),
+	"concat":         sql.FunctionN(NewConcat),
+	"concat_ws":      sql.FunctionN(NewConcatWithSeparator),
+	"coalesce":       sql.FunctionN(NewCoalesce),
+	"lower":          sql.Function1(NewLower),
+	"upper":          sql.Function1(NewUpper),
+	"ceiling":        sql.Function1(NewCeil),
+	"ceil":           sql.Function1(NewCeil),
+	"floor":          sql.Function1(NewFloor),
+	"round":          sql.FunctionN(NewRound),
+	"connection_id":  sql.Function0(NewConnectionID),
+	"last_insert_id": sql.Function0(NewLastInsertID),
+	"soundex":        sql.Function1(NewSoundex),
+	"json_extract":   sql.FunctionN(NewJSONExtract),
+	"ln":             sql.Function1(NewLogBaseFunc(float64(math.E))),
+	"log2":           sql.Function1(NewLogBaseFunc(float64(2))),
+	"log10":          sql.Function1(NewLogBaseFunc(float64(10))),
+	"log":            sql.FunctionN(NewLog),
+	"rpad":           sql.FunctionN(NewPadFunc(rPadType)),
+	"lpad":           sql.FunctionN(NewPadFunc(lPadType)),
+	"sqrt":           sql.Function1(NewSqrt),
+	"pow":            sql.Function2(NewPower),
+	"power":          sql.Function2(NewPower),
+	"ltrim":          sql.Function1(NewTrimFunc(lTrimType)),
+	"rtrim":          sql.Function1(NewTrimFunc(rTrimType)),
+	"trim":           sql.Function1(NewTrimFunc(bTrimType)),
+	"reverse":        sql.Function1(NewReverse),
+	"repeat":         sql.Function2(NewRepeat),
+	"replace":        sql.Function3(NewReplace),
+	"ifnull":         sql.Function2(NewIfNull),
+	"nullif":         sql.Function2(NewNullIf),
+	"now":            sql.Function0(NewNow),
+	"row_number":     sql.Function0(NewRowNumber),
+	"rank":           sql.Function0(NewRank),
+	"dense_rank":     sql.Function0(NewDenseRank),
+	"lag":            sql.FunctionN(NewLag),
+	"lead":           sql.FunctionN(NewLead),
 }
@@ -23,15 +23,42 @@ func getDatePart(
 		return nil, nil
 	}
 
-	date, err := sql.Timestamp.Convert(val)
-	if err != nil {
-		date, err = sql.Date.Convert(val)
+	return f(dateOrTime(u.Child.Type(), val)), nil
+}
+
+// dateOrTime converts the given value of the given type to a time.Time or,
+// if it's a TIME, to a time.Duration. Nil is returned if the value can't be
+// converted to any of them.
+func dateOrTime(typ sql.Type, val interface{}) interface{} {
+	if _, ok := val.(time.Duration); ok || typ == sql.Time {
+		d, err := sql.Time.Convert(val)
+		if err != nil {
+			return nil
+		}
+		return d
+	}
+
+	if typ == sql.Year {
+		y, err := sql.Year.Convert(val)
 		if err != nil {
-			date = nil
+			return nil
 		}
+		return time.Date(int(y.(int16)), time.January, 1, 0, 0, 0, 0, time.UTC)
 	}
 
-	return f(date), nil
+	if date, err := sql.Timestamp.Convert(val); err == nil {
+		return date
+	}
+
+	if date, err := sql.Date.Convert(val); err == nil {
+		return date
+	}
+
+	if d, err := sql.Time.Convert(val); err == nil {
+		return d
+	}
+
+	return nil
 }
 
 // Year is a function that returns the year of a date.
@@ -155,7 +182,7 @@ func (d *Weekday) TransformUp(f sql.TransformExprFunc) (sql.Expression, error) {
 	return f(NewWeekday(child))
 }
 
-// Hour is a function that returns the hour of a date.
+// Hour is a function that returns the hour of a date or time.
 type Hour struct {
 	expression.UnaryExpression
 }
@@ -185,7 +212,7 @@ func (h *Hour) TransformUp(f sql.TransformExprFunc) (sql.Expression, error) {
 	return f(NewHour(child))
 }
 
-// Minute is a function that returns the minute of a date.
+// Minute is a function that returns the minute of a date or time.
 type Minute struct {
 	expression.UnaryExpression
 }
@@ -215,7 +242,7 @@ func (m *Minute) TransformUp(f sql.TransformExprFunc) (sql.Expression, error) {
 	return f(NewMinute(child))
 }
 
-// Second is a function that returns the second of a date.
+// Second is a function that returns the second of a date or time.
 type Second struct {
 	expression.UnaryExpression
 }
@@ -308,11 +335,33 @@ func (d *DayOfYear) TransformUp(f sql.TransformExprFunc) (sql.Expression, error)
 
 func datePartFunc(fn func(time.Time) int) func(interface{}) interface{} {
 	return func(v interface{}) interface{} {
-		if v == nil {
+		t, ok := v.(time.Time)
+		if !ok {
 			return nil
 		}
 
-		return int32(fn(v.(time.Time)))
+		return int32(fn(t))
+	}
+}
+
+// timePartFunc is like datePartFunc, but the part can also be taken from a
+// TIME, in which case the sign of the time is ignored, as in MySQL.
+func timePartFunc(
+	fn func(time.Time) int,
+	durationFn func(time.Duration) int,
+) func(interface{}) interface{} {
+	dateFn := datePartFunc(fn)
+	return func(v interface{}) interface{} {
+		d, ok := v.(time.Duration)
+		if !ok {
+			return dateFn(v)
+		}
+
+		if d < 0 {
+			d = -d
+		}
+
+		return int32(durationFn(d))
 	}
 }
 
@@ -321,9 +370,9 @@ var (
 	month     = datePartFunc(func(t time.Time) int { return int(t.Month()) })
 	day       = datePartFunc((time.Time).Day)
 	weekday   = datePartFunc(func(t time.Time) int { return (int(t.Weekday()) + 6) % 7 })
-	hour      = datePartFunc((time.Time).Hour)
-	minute    = datePartFunc((time.Time).Minute)
-	second    = datePartFunc((time.Time).Second)
+	hour      = timePartFunc((time.Time).Hour, func(d time.Duration) int { return int(d / time.Hour) })
+	minute    = timePartFunc((time.Time).Minute, func(d time.Duration) int { return int(d % time.Hour / time.Minute) })
+	second    = timePartFunc((time.Time).Second, func(d time.Duration) int { return int(d % time.Minute / time.Second) })
 	dayOfWeek = datePartFunc(func(t time.Time) int { return int(t.Weekday()) + 1 })
 	dayOfYear = datePartFunc((time.Time).YearDay)
 )
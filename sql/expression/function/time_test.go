@@ -153,6 +153,8 @@ func TestTime_Hour(t *testing.T) {
 		{"date as string", sql.NewRow(stringDate), int32(14), false},
 		{"date as time", sql.NewRow(time.Now()), int32(time.Now().UTC().Hour()), false},
 		{"date as unix timestamp", sql.NewRow(int64(tsDate)), int32(9), false},
+		{"time as duration", sql.NewRow(-(838*time.Hour + 12*time.Minute + 5*time.Second)), int32(838), false},
+		{"time as string", sql.NewRow("10:11:12"), int32(10), false},
 	}
 
 	for _, tt := range testCases {
@@ -184,6 +186,8 @@ func TestTime_Minute(t *testing.T) {
 		{"date as string", sql.NewRow(stringDate), int32(15), false},
 		{"date as time", sql.NewRow(time.Now()), int32(time.Now().UTC().Minute()), false},
 		{"date as unix timestamp", sql.NewRow(int64(tsDate)), int32(35), false},
+		{"time as duration", sql.NewRow(-(838*time.Hour + 12*time.Minute + 5*time.Second)), int32(12), false},
+		{"time as string", sql.NewRow("10:11:12"), int32(11), false},
 	}
 
 	for _, tt := range testCases {
@@ -214,6 +218,8 @@ func TestTime_Second(t *testing.T) {
 		{"date as string", sql.NewRow(stringDate), int32(16), false},
 		{"date as time", sql.NewRow(time.Now()), int32(time.Now().UTC().Second()), false},
 		{"date as unix timestamp", sql.NewRow(int64(tsDate)), int32(45), false},
+		{"time as duration", sql.NewRow(-(838*time.Hour + 12*time.Minute + 5*time.Second)), int32(5), false},
+		{"time as string", sql.NewRow("10:11:12"), int32(12), false},
 	}
 
 	for _, tt := range testCases {
@@ -292,6 +298,39 @@ func TestTime_DayOfYear(t *testing.T) {
 	}
 }
 
+func TestTime_DateAndTimeTypes(t *testing.T) {
+	ctx := sql.NewEmptyContext()
+	datetime := time.Date(2018, time.October, 18, 5, 22, 25, 123000000, time.UTC)
+
+	testCases := []struct {
+		name     string
+		f        func(sql.Expression) sql.Expression
+		typ      sql.Type
+		value    interface{}
+		expected interface{}
+	}{
+		{"year of datetime", NewYear, sql.Datetime(3), datetime, int32(2018)},
+		{"second of datetime", NewSecond, sql.Datetime(3), datetime, int32(25)},
+		{"hour of time", NewHour, sql.Time, 30 * time.Hour, int32(30)},
+		{"hour of time as string", NewHour, sql.Time, "-100:00:00", int32(100)},
+		{"minute of time as number", NewMinute, sql.Time, int64(101112), int32(11)},
+		{"day of time", NewDay, sql.Time, time.Hour, nil},
+		{"year of year", NewYear, sql.Year, int16(2019), int32(2019)},
+		{"year of year as string", NewYear, sql.Year, "19", int32(2019)},
+		{"invalid year", NewYear, sql.Year, "foo", nil},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			require := require.New(t)
+			f := tt.f(expression.NewGetField(0, tt.typ, "foo", false))
+			val, err := f.Eval(ctx, sql.NewRow(tt.value))
+			require.NoError(err)
+			require.Equal(tt.expected, val)
+		})
+	}
+}
+
 func TestNow(t *testing.T) {
 	require := require.New(t)
 	date := time.Date(2018, time.December, 2, 16, 25, 0, 0, time.Local)
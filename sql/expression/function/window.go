@@ -0,0 +1,277 @@
+package function
+
+import (
+	"fmt"
+
+	errors "gopkg.in/src-d/go-errors.v1"
+	"gopkg.in/src-d/go-mysql-server.v0/sql"
+)
+
+// ErrMissingOver is returned when a window function is evaluated without a
+// window.
+var ErrMissingOver = errors.NewKind("window function %s requires an OVER clause")
+
+// ErrInvalidOffset is returned when the offset of LAG or LEAD is not a
+// non-negative integer.
+var ErrInvalidOffset = errors.NewKind("invalid offset for %s: %v")
+
+// RowNumber is a window function that returns the number of the row in its
+// partition, starting at 1.
+type RowNumber struct{}
+
+// NewRowNumber creates a new RowNumber window function.
+func NewRowNumber() sql.Expression {
+	return RowNumber{}
+}
+
+// Children implements the sql.Expression interface.
+func (RowNumber) Children() []sql.Expression { return nil }
+
+// Type implements the sql.Expression interface.
+func (RowNumber) Type() sql.Type { return sql.Uint64 }
+
+// Resolved implements the sql.Expression interface.
+func (RowNumber) Resolved() bool { return true }
+
+// TransformUp implements the sql.Expression interface.
+func (RowNumber) TransformUp(f sql.TransformExprFunc) (sql.Expression, error) {
+	return f(RowNumber{})
+}
+
+// IsNullable implements the sql.Expression interface.
+func (RowNumber) IsNullable() bool { return false }
+
+// String implements the fmt.Stringer interface.
+func (RowNumber) String() string { return "ROW_NUMBER()" }
+
+// Eval implements the sql.Expression interface.
+func (r RowNumber) Eval(ctx *sql.Context, _ sql.Row) (interface{}, error) {
+	return nil, ErrMissingOver.New(r)
+}
+
+// EvalWindow implements the sql.WindowFunction interface.
+func (RowNumber) EvalWindow(ctx *sql.Context, partition sql.WindowPartition, i int) (interface{}, error) {
+	return uint64(i + 1), nil
+}
+
+// Rank is a window function that returns the rank of the row in its
+// partition, which is the number of the first of its peers, so there are
+// gaps in the ranks of rows with peers.
+type Rank struct{}
+
+// NewRank creates a new Rank window function.
+func NewRank() sql.Expression {
+	return Rank{}
+}
+
+// Children implements the sql.Expression interface.
+func (Rank) Children() []sql.Expression { return nil }
+
+// Type implements the sql.Expression interface.
+func (Rank) Type() sql.Type { return sql.Uint64 }
+
+// Resolved implements the sql.Expression interface.
+func (Rank) Resolved() bool { return true }
+
+// TransformUp implements the sql.Expression interface.
+func (Rank) TransformUp(f sql.TransformExprFunc) (sql.Expression, error) {
+	return f(Rank{})
+}
+
+// IsNullable implements the sql.Expression interface.
+func (Rank) IsNullable() bool { return false }
+
+// String implements the fmt.Stringer interface.
+func (Rank) String() string { return "RANK()" }
+
+// Eval implements the sql.Expression interface.
+func (r Rank) Eval(ctx *sql.Context, _ sql.Row) (interface{}, error) {
+	return nil, ErrMissingOver.New(r)
+}
+
+// EvalWindow implements the sql.WindowFunction interface.
+func (Rank) EvalWindow(ctx *sql.Context, partition sql.WindowPartition, i int) (interface{}, error) {
+	first := i
+	for first > 0 && partition.PeerGroups[first-1] == partition.PeerGroups[i] {
+		first--
+	}
+	return uint64(first + 1), nil
+}
+
+// DenseRank is a window function that returns the rank of the row in its
+// partition without gaps, which is the number of its group of peers.
+type DenseRank struct{}
+
+// NewDenseRank creates a new DenseRank window function.
+func NewDenseRank() sql.Expression {
+	return DenseRank{}
+}
+
+// Children implements the sql.Expression interface.
+func (DenseRank) Children() []sql.Expression { return nil }
+
+// Type implements the sql.Expression interface.
+func (DenseRank) Type() sql.Type { return sql.Uint64 }
+
+// Resolved implements the sql.Expression interface.
+func (DenseRank) Resolved() bool { return true }
+
+// TransformUp implements the sql.Expression interface.
+func (DenseRank) TransformUp(f sql.TransformExprFunc) (sql.Expression, error) {
+	return f(DenseRank{})
+}
+
+// IsNullable implements the sql.Expression interface.
+func (DenseRank) IsNullable() bool { return false }
+
+// String implements the fmt.Stringer interface.
+func (DenseRank) String() string { return "DENSE_RANK()" }
+
+// Eval implements the sql.Expression interface.
+func (r DenseRank) Eval(ctx *sql.Context, _ sql.Row) (interface{}, error) {
+	return nil, ErrMissingOver.New(r)
+}
+
+// EvalWindow implements the sql.WindowFunction interface.
+func (DenseRank) EvalWindow(ctx *sql.Context, partition sql.WindowPartition, i int) (interface{}, error) {
+	return uint64(partition.PeerGroups[i] + 1), nil
+}
+
+// Lag is a window function that returns the value of an expression for
+// the row that is a number of rows before the current one in its
+// partition, or a default value if there is no such row. Lead is the same
+// function with rows after the current one.
+type Lag struct {
+	name    string
+	expr    sql.Expression
+	offset  sql.Expression
+	def     sql.Expression
+	forward bool
+}
+
+func newOffsetFunction(name string, forward bool, args []sql.Expression) (sql.Expression, error) {
+	if len(args) == 0 || len(args) > 3 {
+		return nil, sql.ErrInvalidArgumentNumber.New("1, 2 or 3", len(args))
+	}
+
+	f := &Lag{name: name, expr: args[0], forward: forward}
+	if len(args) > 1 {
+		f.offset = args[1]
+	}
+	if len(args) > 2 {
+		f.def = args[2]
+	}
+
+	return f, nil
+}
+
+// NewLag creates a new LAG window function with the expression, and
+// optionally the offset, 1 by default, and the default value, NULL by
+// default.
+func NewLag(args ...sql.Expression) (sql.Expression, error) {
+	return newOffsetFunction("LAG", false, args)
+}
+
+// NewLead creates a new LEAD window function with the expression, and
+// optionally the offset, 1 by default, and the default value, NULL by
+// default.
+func NewLead(args ...sql.Expression) (sql.Expression, error) {
+	return newOffsetFunction("LEAD", true, args)
+}
+
+// Children implements the sql.Expression interface.
+func (f *Lag) Children() []sql.Expression {
+	var children = []sql.Expression{f.expr}
+	if f.offset != nil {
+		children = append(children, f.offset)
+	}
+	if f.def != nil {
+		children = append(children, f.def)
+	}
+	return children
+}
+
+// Type implements the sql.Expression interface.
+func (f *Lag) Type() sql.Type { return f.expr.Type() }
+
+// Resolved implements the sql.Expression interface.
+func (f *Lag) Resolved() bool {
+	for _, e := range f.Children() {
+		if !e.Resolved() {
+			return false
+		}
+	}
+	return true
+}
+
+// TransformUp implements the sql.Expression interface.
+func (f *Lag) TransformUp(fn sql.TransformExprFunc) (sql.Expression, error) {
+	var args = f.Children()
+	for i, e := range args {
+		var err error
+		if args[i], err = e.TransformUp(fn); err != nil {
+			return nil, err
+		}
+	}
+
+	nf, err := newOffsetFunction(f.name, f.forward, args)
+	if err != nil {
+		return nil, err
+	}
+
+	return fn(nf)
+}
+
+// IsNullable implements the sql.Expression interface.
+func (f *Lag) IsNullable() bool { return true }
+
+// String implements the fmt.Stringer interface.
+func (f *Lag) String() string {
+	var args = f.Children()
+	switch len(args) {
+	case 1:
+		return fmt.Sprintf("%s(%s)", f.name, args[0])
+	case 2:
+		return fmt.Sprintf("%s(%s, %s)", f.name, args[0], args[1])
+	default:
+		return fmt.Sprintf("%s(%s, %s, %s)", f.name, args[0], args[1], args[2])
+	}
+}
+
+// Eval implements the sql.Expression interface.
+func (f *Lag) Eval(ctx *sql.Context, _ sql.Row) (interface{}, error) {
+	return nil, ErrMissingOver.New(f)
+}
+
+// EvalWindow implements the sql.WindowFunction interface.
+func (f *Lag) EvalWindow(ctx *sql.Context, partition sql.WindowPartition, i int) (interface{}, error) {
+	row := partition.Rows[i]
+
+	offset := int64(1)
+	if f.offset != nil {
+		v, err := f.offset.Eval(ctx, row)
+		if err != nil {
+			return nil, err
+		}
+
+		n, err := sql.Int64.Convert(v)
+		if err != nil || v == nil || n.(int64) < 0 {
+			return nil, ErrInvalidOffset.New(f.name, v)
+		}
+		offset = n.(int64)
+	}
+
+	if !f.forward {
+		offset = -offset
+	}
+
+	j := int64(i) + offset
+	if j < 0 || j >= int64(len(partition.Rows)) {
+		if f.def == nil {
+			return nil, nil
+		}
+		return f.def.Eval(ctx, row)
+	}
+
+	return f.expr.Eval(ctx, partition.Rows[j])
+}
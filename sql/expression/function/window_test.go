@@ -0,0 +1,120 @@
+package function
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"gopkg.in/src-d/go-mysql-server.v0/sql"
+	"gopkg.in/src-d/go-mysql-server.v0/sql/expression"
+)
+
+func TestRankingFunctions(t *testing.T) {
+	ctx := sql.NewEmptyContext()
+
+	partition := sql.WindowPartition{
+		Rows:       []sql.Row{{int64(1)}, {int64(2)}, {int64(2)}, {int64(3)}},
+		PeerGroups: []int{0, 1, 1, 2},
+	}
+
+	testCases := []struct {
+		fn       sql.Expression
+		expected []interface{}
+	}{
+		{NewRowNumber(), []interface{}{uint64(1), uint64(2), uint64(3), uint64(4)}},
+		{NewRank(), []interface{}{uint64(1), uint64(2), uint64(2), uint64(4)}},
+		{NewDenseRank(), []interface{}{uint64(1), uint64(2), uint64(2), uint64(3)}},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.fn.String(), func(t *testing.T) {
+			require := require.New(t)
+
+			fn := tt.fn.(sql.WindowFunction)
+			var result []interface{}
+			for i := range partition.Rows {
+				v, err := fn.EvalWindow(ctx, partition, i)
+				require.NoError(err)
+				result = append(result, v)
+			}
+			require.Equal(tt.expected, result)
+
+			_, err := fn.Eval(ctx, partition.Rows[0])
+			require.Error(err)
+			require.True(ErrMissingOver.Is(err))
+		})
+	}
+}
+
+func TestLagLead(t *testing.T) {
+	ctx := sql.NewEmptyContext()
+	col := expression.NewGetField(0, sql.Int64, "a", false)
+
+	partition := sql.WindowPartition{
+		Rows:       []sql.Row{{int64(1)}, {int64(2)}, {int64(3)}},
+		PeerGroups: []int{0, 1, 2},
+	}
+
+	testCases := []struct {
+		name     string
+		new      func(...sql.Expression) (sql.Expression, error)
+		args     []sql.Expression
+		expected []interface{}
+		err      bool
+	}{
+		{"LAG(a)", NewLag, []sql.Expression{col}, []interface{}{nil, int64(1), int64(2)}, false},
+		{"LEAD(a)", NewLead, []sql.Expression{col}, []interface{}{int64(2), int64(3), nil}, false},
+		{
+			"LAG(a, 2, 0)",
+			NewLag,
+			[]sql.Expression{
+				col,
+				expression.NewLiteral(int64(2), sql.Int64),
+				expression.NewLiteral(int64(0), sql.Int64),
+			},
+			[]interface{}{int64(0), int64(0), int64(1)},
+			false,
+		},
+		{
+			"LEAD(a, 0)",
+			NewLead,
+			[]sql.Expression{col, expression.NewLiteral(int64(0), sql.Int64)},
+			[]interface{}{int64(1), int64(2), int64(3)},
+			false,
+		},
+		{
+			"LEAD(a, -1)",
+			NewLead,
+			[]sql.Expression{col, expression.NewLiteral(int64(-1), sql.Int64)},
+			nil,
+			true,
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			require := require.New(t)
+
+			f, err := tt.new(tt.args...)
+			require.NoError(err)
+			require.Equal(tt.name, f.String())
+
+			fn := f.(sql.WindowFunction)
+			var result []interface{}
+			for i := range partition.Rows {
+				v, err := fn.EvalWindow(ctx, partition, i)
+				if tt.err {
+					require.Error(err)
+					require.True(ErrInvalidOffset.Is(err))
+					return
+				}
+				require.NoError(err)
+				result = append(result, v)
+			}
+			require.Equal(tt.expected, result)
+		})
+	}
+
+	_, err := NewLag()
+	require.Error(t, err)
+	require.True(t, sql.ErrInvalidArgumentNumber.Is(err))
+}
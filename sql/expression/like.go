@@ -67,6 +67,9 @@ func (l *Like) Eval(ctx *sql.Context, row sql.Row) (interface{}, error) {
 			return nil, err
 		}
 		right = patternToRegex(v.(string))
+		if compareCollation(l.Left, l.Right).CaseInsensitive {
+			right = "(?i)" + right
+		}
 	}
 	// for non-cached regex every time create a new matcher
 	if !l.cached {
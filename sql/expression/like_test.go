@@ -63,3 +63,25 @@ func TestLike(t *testing.T) {
 		})
 	}
 }
+
+func TestLikeCollation(t *testing.T) {
+	require := require.New(t)
+
+	f := NewLike(
+		NewGetField(0, sql.TextWithCollation(sql.CollationUtf8GeneralCI), "", false),
+		NewLiteral("ab%", sql.Text),
+	)
+
+	value, err := f.Eval(sql.NewEmptyContext(), sql.NewRow("ABC"))
+	require.NoError(err)
+	require.Equal(true, value)
+
+	f = NewLike(
+		NewGetField(0, sql.Text, "", false),
+		NewLiteral("ab%", sql.Text),
+	)
+
+	value, err = f.Eval(sql.NewEmptyContext(), sql.NewRow("ABC"))
+	require.NoError(err)
+	require.Equal(false, value)
+}
@@ -0,0 +1,76 @@
+package expression
+
+import (
+	"fmt"
+
+	errors "gopkg.in/src-d/go-errors.v1"
+	"gopkg.in/src-d/go-mysql-server.v0/sql"
+)
+
+// ErrInvalidSetField is returned when the left side of a SetField is not a
+// column.
+var ErrInvalidSetField = errors.NewKind("cannot assign a value to %s, it is not a column")
+
+// SetField is an expression that assigns the value of the right expression
+// to the column on the left side. Its evaluation returns a copy of the given
+// row with the column updated.
+type SetField struct {
+	BinaryExpression
+}
+
+// NewSetField creates a new SetField expression.
+func NewSetField(col, value sql.Expression) *SetField {
+	return &SetField{BinaryExpression{Left: col, Right: value}}
+}
+
+// Type implements the Expression interface.
+func (s *SetField) Type() sql.Type {
+	return s.Left.Type()
+}
+
+// Eval implements the Expression interface.
+func (s *SetField) Eval(ctx *sql.Context, row sql.Row) (interface{}, error) {
+	field, ok := s.Left.(*GetField)
+	if !ok {
+		return nil, ErrInvalidSetField.New(s.Left)
+	}
+
+	if field.fieldIndex < 0 || field.fieldIndex >= len(row) {
+		return nil, ErrIndexOutOfBounds.New(field.fieldIndex, len(row))
+	}
+
+	val, err := s.Right.Eval(ctx, row)
+	if err != nil {
+		return nil, err
+	}
+
+	if val != nil {
+		val, err = field.fieldType.Convert(val)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	updated := row.Copy()
+	updated[field.fieldIndex] = val
+	return updated, nil
+}
+
+func (s *SetField) String() string {
+	return fmt.Sprintf("SET %s = %s", s.Left, s.Right)
+}
+
+// TransformUp implements the Expression interface.
+func (s *SetField) TransformUp(f sql.TransformExprFunc) (sql.Expression, error) {
+	left, err := s.Left.TransformUp(f)
+	if err != nil {
+		return nil, err
+	}
+
+	right, err := s.Right.TransformUp(f)
+	if err != nil {
+		return nil, err
+	}
+
+	return f(NewSetField(left, right))
+}
@@ -0,0 +1,30 @@
+package expression
+
+import (
+	"testing"
+
+	"gopkg.in/src-d/go-mysql-server.v0/sql"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSetField(t *testing.T) {
+	require := require.New(t)
+
+	row := sql.NewRow(int64(1), "foo")
+	e := NewSetField(
+		NewGetField(0, sql.Int64, "col1", false),
+		NewLiteral("42", sql.Text),
+	)
+	require.Equal(sql.Int64, e.Type())
+	require.Equal(sql.NewRow(int64(42), "foo"), eval(t, e, row))
+	require.Equal(sql.NewRow(int64(1), "foo"), row)
+
+	e = NewSetField(
+		NewLiteral(int64(1), sql.Int64),
+		NewLiteral("42", sql.Text),
+	)
+	_, err := e.Eval(sql.NewEmptyContext(), row)
+	require.Error(err)
+	require.True(ErrInvalidSetField.Is(err))
+}
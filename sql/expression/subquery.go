@@ -0,0 +1,426 @@
+package expression
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+
+	errors "gopkg.in/src-d/go-errors.v1"
+	"gopkg.in/src-d/go-mysql-server.v0/sql"
+)
+
+var (
+	// ErrSubqueryMultipleRows is returned when a subquery used as a scalar
+	// value returns more than one row.
+	ErrSubqueryMultipleRows = errors.NewKind("subquery returns more than 1 row")
+	// ErrSubqueryMultipleColumns is returned when a subquery used as a scalar
+	// value or in an IN expression returns more than one column.
+	ErrSubqueryMultipleColumns = errors.NewKind("operand should contain 1 column, but subquery returns %d")
+	// ErrOuterFieldNotFound is returned when an outer field is evaluated
+	// outside of the subquery it belongs to.
+	ErrOuterFieldNotFound = errors.NewKind("unable to find outer field with index %d in scope of %d values")
+	// ErrInvalidExistsOperand is returned when the operand of EXISTS is not
+	// a subquery.
+	ErrInvalidExistsOperand = errors.NewKind("operand of EXISTS must be a subquery, but is %T")
+)
+
+// Subquery is an expression that evaluates a query. It can be used as a
+// scalar value, in which case the query must return at most one row with
+// a single column, or as the operand of IN and EXISTS.
+//
+// If the subquery is correlated, Outer contains the expressions that are
+// evaluated against the row of the outer query and whose values can be
+// referenced inside the subquery using OuterField. Otherwise, the subquery
+// is only executed once and its result is cached.
+type Subquery struct {
+	Query sql.Node
+	Outer []sql.Expression
+
+	mu     sync.Mutex
+	cached bool
+	rows   []sql.Row
+}
+
+// NewSubquery returns a new subquery expression.
+func NewSubquery(query sql.Node) *Subquery {
+	return &Subquery{Query: query}
+}
+
+// NewCorrelatedSubquery returns a new subquery expression that references
+// the given expressions of the outer query.
+func NewCorrelatedSubquery(query sql.Node, outer []sql.Expression) *Subquery {
+	return &Subquery{Query: query, Outer: outer}
+}
+
+// IsCorrelated returns whether the subquery references the outer query.
+func (s *Subquery) IsCorrelated() bool {
+	return len(s.Outer) > 0
+}
+
+// Children implements the Expression interface.
+func (s *Subquery) Children() []sql.Expression {
+	return s.Outer
+}
+
+// Resolved implements the Expression interface.
+func (s *Subquery) Resolved() bool {
+	for _, e := range s.Outer {
+		if !e.Resolved() {
+			return false
+		}
+	}
+
+	return s.Query.Resolved()
+}
+
+// IsNullable implements the Expression interface.
+func (s *Subquery) IsNullable() bool {
+	return true
+}
+
+// Type implements the Expression interface.
+func (s *Subquery) Type() sql.Type {
+	schema := s.Query.Schema()
+	if len(schema) == 0 {
+		return sql.Null
+	}
+
+	return schema[0].Type
+}
+
+// Eval implements the Expression interface.
+func (s *Subquery) Eval(ctx *sql.Context, row sql.Row) (interface{}, error) {
+	rows, err := s.EvalRows(ctx, row)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(s.Query.Schema()) != 1 {
+		return nil, ErrSubqueryMultipleColumns.New(len(s.Query.Schema()))
+	}
+
+	switch len(rows) {
+	case 0:
+		return nil, nil
+	case 1:
+		return rows[0][0], nil
+	default:
+		return nil, ErrSubqueryMultipleRows.New()
+	}
+}
+
+// EvalRows executes the subquery for the given row of the outer query and
+// returns all the resulting rows.
+func (s *Subquery) EvalRows(ctx *sql.Context, row sql.Row) ([]sql.Row, error) {
+	if !s.IsCorrelated() {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+
+		if s.cached {
+			return s.rows, nil
+		}
+	}
+
+	values := make([]interface{}, len(s.Outer))
+	for i, e := range s.Outer {
+		v, err := e.Eval(ctx, row)
+		if err != nil {
+			return nil, err
+		}
+		values[i] = v
+	}
+
+	ctx = ctx.WithContext(context.WithValue(ctx.Context, outerValuesKey{}, values))
+
+	iter, err := s.Query.RowIter(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := sql.RowIterToRows(iter)
+	if err != nil {
+		return nil, err
+	}
+
+	if !s.IsCorrelated() {
+		s.rows = rows
+		s.cached = true
+	}
+
+	return rows, nil
+}
+
+func (s *Subquery) String() string {
+	return fmt.Sprintf("(%s)", strings.TrimSpace(s.Query.String()))
+}
+
+// TransformUp implements the Expression interface.
+func (s *Subquery) TransformUp(f sql.TransformExprFunc) (sql.Expression, error) {
+	var outer = make([]sql.Expression, len(s.Outer))
+	for i, e := range s.Outer {
+		var err error
+		outer[i], err = e.TransformUp(f)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if len(outer) == 0 {
+		outer = nil
+	}
+
+	return f(NewCorrelatedSubquery(s.Query, outer))
+}
+
+// WithQuery returns a copy of the subquery with the given query.
+func (s *Subquery) WithQuery(query sql.Node) *Subquery {
+	return NewCorrelatedSubquery(query, s.Outer)
+}
+
+type outerValuesKey struct{}
+
+// OuterField is a reference from inside a subquery to a value of the outer
+// query. The index is the position of the value in the Outer expressions of
+// the subquery.
+//
+// While the subquery is being analyzed, the index is the position of the
+// column in the schema of the query it references instead, which is the
+// immediate outer query when the depth is 0, the one enclosing it when it
+// is 1, and so on.
+type OuterField struct {
+	table      string
+	fieldIndex int
+	depth      int
+	name       string
+	fieldType  sql.Type
+	nullable   bool
+}
+
+// NewOuterField creates a new OuterField expression.
+func NewOuterField(index int, fieldType sql.Type, table, fieldName string, nullable bool) *OuterField {
+	return &OuterField{
+		table:      table,
+		fieldIndex: index,
+		name:       fieldName,
+		fieldType:  fieldType,
+		nullable:   nullable,
+	}
+}
+
+// Index returns the index of the value in the outer scope.
+func (f *OuterField) Index() int { return f.fieldIndex }
+
+// WithIndex returns a copy of the field with the given index.
+func (f *OuterField) WithIndex(index int) *OuterField {
+	nf := *f
+	nf.fieldIndex = index
+	return &nf
+}
+
+// Depth returns the number of queries between the subquery of the field
+// and the query it references.
+func (f *OuterField) Depth() int { return f.depth }
+
+// WithDepth returns a copy of the field with the given depth.
+func (f *OuterField) WithDepth(depth int) *OuterField {
+	nf := *f
+	nf.depth = depth
+	return &nf
+}
+
+// Table returns the name of the field table.
+func (f *OuterField) Table() string { return f.table }
+
+// Name implements the Nameable interface.
+func (f *OuterField) Name() string { return f.name }
+
+// Children implements the Expression interface.
+func (*OuterField) Children() []sql.Expression { return nil }
+
+// Resolved implements the Expression interface.
+func (*OuterField) Resolved() bool { return true }
+
+// IsNullable implements the Expression interface.
+func (f *OuterField) IsNullable() bool { return f.nullable }
+
+// Type implements the Expression interface.
+func (f *OuterField) Type() sql.Type { return f.fieldType }
+
+// Eval implements the Expression interface.
+func (f *OuterField) Eval(ctx *sql.Context, row sql.Row) (interface{}, error) {
+	values, _ := ctx.Value(outerValuesKey{}).([]interface{})
+	if f.fieldIndex < 0 || f.fieldIndex >= len(values) {
+		return nil, ErrOuterFieldNotFound.New(f.fieldIndex, len(values))
+	}
+
+	return values[f.fieldIndex], nil
+}
+
+// TransformUp implements the Expression interface.
+func (f *OuterField) TransformUp(fn sql.TransformExprFunc) (sql.Expression, error) {
+	n := *f
+	return fn(&n)
+}
+
+func (f *OuterField) String() string {
+	if f.table == "" {
+		return fmt.Sprintf("outer.%s", f.name)
+	}
+	return fmt.Sprintf("outer.%s.%s", f.table, f.name)
+}
+
+// InSubquery is an expression that checks whether an expression is inside
+// the result of a subquery.
+type InSubquery struct {
+	BinaryExpression
+}
+
+// NewInSubquery creates an InSubquery expression.
+func NewInSubquery(left sql.Expression, right *Subquery) *InSubquery {
+	return &InSubquery{BinaryExpression{left, right}}
+}
+
+// Type implements the Expression interface.
+func (in *InSubquery) Type() sql.Type {
+	return sql.Boolean
+}
+
+// Eval implements the Expression interface. Following the SQL semantics, it
+// returns NULL instead of false if the value is not found and the subquery
+// returned any NULL.
+func (in *InSubquery) Eval(ctx *sql.Context, row sql.Row) (interface{}, error) {
+	typ := in.Left.Type()
+	leftElems := sql.NumColumns(typ)
+	left, err := in.Left.Eval(ctx, row)
+	if err != nil {
+		return nil, err
+	}
+
+	if left == nil {
+		return nil, nil
+	}
+
+	left, err = typ.Convert(left)
+	if err != nil {
+		return nil, err
+	}
+
+	subquery := in.Right.(*Subquery)
+	if n := len(subquery.Query.Schema()); n != leftElems {
+		return nil, ErrInvalidOperandColumns.New(leftElems, n)
+	}
+
+	rows, err := subquery.EvalRows(ctx, row)
+	if err != nil {
+		return nil, err
+	}
+
+	var hasNull bool
+	for _, r := range rows {
+		var right interface{}
+		if leftElems == 1 {
+			right = r[0]
+		} else {
+			right = []interface{}(r)
+		}
+
+		if right == nil {
+			hasNull = true
+			continue
+		}
+
+		right, err = typ.Convert(right)
+		if err != nil {
+			return nil, err
+		}
+
+		cmp, err := typ.Compare(left, right)
+		if err != nil {
+			return nil, err
+		}
+
+		if cmp == 0 {
+			return true, nil
+		}
+	}
+
+	if hasNull {
+		return nil, nil
+	}
+
+	return false, nil
+}
+
+// TransformUp implements the Expression interface.
+func (in *InSubquery) TransformUp(f sql.TransformExprFunc) (sql.Expression, error) {
+	left, err := in.Left.TransformUp(f)
+	if err != nil {
+		return nil, err
+	}
+
+	right, err := in.Right.TransformUp(f)
+	if err != nil {
+		return nil, err
+	}
+
+	subquery, ok := right.(*Subquery)
+	if !ok {
+		return nil, ErrUnsupportedInOperand.New(right)
+	}
+
+	return f(NewInSubquery(left, subquery))
+}
+
+func (in *InSubquery) String() string {
+	return fmt.Sprintf("%s IN %s", in.Left, in.Right)
+}
+
+// Exists is an expression that checks whether a subquery returns any row.
+type Exists struct {
+	UnaryExpression
+}
+
+// NewExists creates an Exists expression.
+func NewExists(subquery *Subquery) *Exists {
+	return &Exists{UnaryExpression{subquery}}
+}
+
+// Type implements the Expression interface.
+func (e *Exists) Type() sql.Type {
+	return sql.Boolean
+}
+
+// IsNullable implements the Expression interface.
+func (e *Exists) IsNullable() bool {
+	return false
+}
+
+// Eval implements the Expression interface.
+func (e *Exists) Eval(ctx *sql.Context, row sql.Row) (interface{}, error) {
+	rows, err := e.Child.(*Subquery).EvalRows(ctx, row)
+	if err != nil {
+		return nil, err
+	}
+
+	return len(rows) > 0, nil
+}
+
+// TransformUp implements the Expression interface.
+func (e *Exists) TransformUp(f sql.TransformExprFunc) (sql.Expression, error) {
+	child, err := e.Child.TransformUp(f)
+	if err != nil {
+		return nil, err
+	}
+
+	subquery, ok := child.(*Subquery)
+	if !ok {
+		return nil, ErrInvalidExistsOperand.New(child)
+	}
+
+	return f(NewExists(subquery))
+}
+
+func (e *Exists) String() string {
+	return fmt.Sprintf("EXISTS %s", e.Child)
+}
@@ -0,0 +1,165 @@
+package expression
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"gopkg.in/src-d/go-mysql-server.v0/sql"
+)
+
+func TestSubquery(t *testing.T) {
+	require := require.New(t)
+	ctx := sql.NewEmptyContext()
+
+	query := &rowsNode{rows: []sql.Row{{int64(1)}}}
+	subquery := NewSubquery(query)
+	require.Equal(sql.Int64, subquery.Type())
+	require.False(subquery.IsCorrelated())
+
+	v, err := subquery.Eval(ctx, nil)
+	require.NoError(err)
+	require.Equal(int64(1), v)
+
+	// the result of uncorrelated subqueries is cached
+	_, err = subquery.Eval(ctx, nil)
+	require.NoError(err)
+	require.Equal(1, query.executions)
+
+	v, err = NewSubquery(&rowsNode{}).Eval(ctx, nil)
+	require.NoError(err)
+	require.Nil(v)
+
+	_, err = NewSubquery(&rowsNode{rows: []sql.Row{{int64(1)}, {int64(2)}}}).Eval(ctx, nil)
+	require.Error(err)
+	require.True(ErrSubqueryMultipleRows.Is(err))
+}
+
+func TestCorrelatedSubquery(t *testing.T) {
+	require := require.New(t)
+	ctx := sql.NewEmptyContext()
+
+	query := &rowsNode{
+		rows: []sql.Row{{int64(1), "a"}, {int64(2), "b"}},
+		filter: NewEquals(
+			NewGetField(0, sql.Int64, "i", false),
+			NewOuterField(0, sql.Int64, "t", "j", false),
+		),
+		project: []int{1},
+	}
+	subquery := NewCorrelatedSubquery(query, []sql.Expression{
+		NewGetFieldWithTable(1, sql.Int64, "t", "j", false),
+	})
+	require.True(subquery.IsCorrelated())
+
+	v, err := subquery.Eval(ctx, sql.NewRow("foo", int64(2)))
+	require.NoError(err)
+	require.Equal("b", v)
+
+	v, err = subquery.Eval(ctx, sql.NewRow("foo", int64(1)))
+	require.NoError(err)
+	require.Equal("a", v)
+
+	v, err = subquery.Eval(ctx, sql.NewRow("foo", int64(3)))
+	require.NoError(err)
+	require.Nil(v)
+
+	require.Equal(3, query.executions)
+
+	_, err = NewOuterField(0, sql.Int64, "t", "j", false).Eval(ctx, nil)
+	require.Error(err)
+	require.True(ErrOuterFieldNotFound.Is(err))
+}
+
+func TestInSubquery(t *testing.T) {
+	testCases := []struct {
+		name     string
+		left     sql.Expression
+		rows     []sql.Row
+		expected interface{}
+	}{
+		{"found", NewLiteral(int64(2), sql.Int64), []sql.Row{{int64(1)}, {int64(2)}}, true},
+		{"not found", NewLiteral(int64(3), sql.Int64), []sql.Row{{int64(1)}, {int64(2)}}, false},
+		{"not found with nulls", NewLiteral(int64(3), sql.Int64), []sql.Row{{int64(1)}, {nil}}, nil},
+		{"found with nulls", NewLiteral(int64(1), sql.Int64), []sql.Row{{int64(1)}, {nil}}, true},
+		{"left is null", NewLiteral(nil, sql.Null), []sql.Row{{int64(1)}}, nil},
+		{"empty", NewLiteral(int64(1), sql.Int64), nil, false},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			require := require.New(t)
+			in := NewInSubquery(tt.left, NewSubquery(&rowsNode{rows: tt.rows}))
+			v, err := in.Eval(sql.NewEmptyContext(), nil)
+			require.NoError(err)
+			require.Equal(tt.expected, v)
+		})
+	}
+}
+
+func TestExists(t *testing.T) {
+	require := require.New(t)
+	ctx := sql.NewEmptyContext()
+
+	v, err := NewExists(NewSubquery(&rowsNode{rows: []sql.Row{{nil}}})).Eval(ctx, nil)
+	require.NoError(err)
+	require.Equal(true, v)
+
+	v, err = NewExists(NewSubquery(&rowsNode{})).Eval(ctx, nil)
+	require.NoError(err)
+	require.Equal(false, v)
+}
+
+// rowsNode is a node returning the given rows matching the filter, only with
+// the given columns, if any.
+type rowsNode struct {
+	rows       []sql.Row
+	filter     sql.Expression
+	project    []int
+	executions int
+}
+
+func (n *rowsNode) Resolved() bool       { return true }
+func (n *rowsNode) String() string       { return "rowsNode" }
+func (n *rowsNode) Children() []sql.Node { return nil }
+func (n *rowsNode) Schema() sql.Schema {
+	if len(n.project) > 0 {
+		return sql.Schema{{Name: "s", Type: sql.Text}}
+	}
+	return sql.Schema{{Name: "i", Type: sql.Int64}}
+}
+
+func (n *rowsNode) RowIter(ctx *sql.Context) (sql.RowIter, error) {
+	n.executions++
+	var rows []sql.Row
+	for _, row := range n.rows {
+		if n.filter != nil {
+			v, err := n.filter.Eval(ctx, row)
+			if err != nil {
+				return nil, err
+			}
+
+			if v != true {
+				continue
+			}
+		}
+
+		if len(n.project) > 0 {
+			var projected sql.Row
+			for _, i := range n.project {
+				projected = append(projected, row[i])
+			}
+			row = projected
+		}
+
+		rows = append(rows, row)
+	}
+	return sql.RowsToRowIter(rows...), nil
+}
+
+func (n *rowsNode) TransformUp(f sql.TransformNodeFunc) (sql.Node, error) {
+	return f(n)
+}
+
+func (n *rowsNode) TransformExpressionsUp(f sql.TransformExprFunc) (sql.Node, error) {
+	return n, nil
+}
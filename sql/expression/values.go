@@ -0,0 +1,48 @@
+package expression
+
+import (
+	"fmt"
+
+	"gopkg.in/src-d/go-mysql-server.v0/sql"
+)
+
+// Values is the VALUES(col) function, which can be used in the ON DUPLICATE
+// KEY UPDATE clause of an INSERT to refer to the value that would have been
+// inserted in the column. The insert replaces it with the value of the
+// column in the new row, so evaluating it anywhere else returns NULL.
+type Values struct {
+	UnaryExpression
+}
+
+// NewValues creates a new Values expression for the given column.
+func NewValues(col sql.Expression) *Values {
+	return &Values{UnaryExpression{col}}
+}
+
+// Type implements the Expression interface.
+func (v *Values) Type() sql.Type {
+	return v.Child.Type()
+}
+
+// IsNullable implements the Expression interface.
+func (v *Values) IsNullable() bool {
+	return true
+}
+
+// Eval implements the Expression interface.
+func (v *Values) Eval(ctx *sql.Context, row sql.Row) (interface{}, error) {
+	return nil, nil
+}
+
+func (v *Values) String() string {
+	return fmt.Sprintf("VALUES(%s)", v.Child)
+}
+
+// TransformUp implements the Expression interface.
+func (v *Values) TransformUp(f sql.TransformExprFunc) (sql.Expression, error) {
+	child, err := v.Child.TransformUp(f)
+	if err != nil {
+		return nil, err
+	}
+	return f(NewValues(child))
+}
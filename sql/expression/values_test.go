@@ -0,0 +1,22 @@
+package expression
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"gopkg.in/src-d/go-mysql-server.v0/sql"
+)
+
+func TestValues(t *testing.T) {
+	require := require.New(t)
+
+	e := NewValues(NewGetField(0, sql.Int64, "a", false))
+	require.Equal(sql.Int64, e.Type())
+	require.True(e.IsNullable())
+	require.Equal("VALUES(a)", e.String())
+
+	// Outside of an INSERT it's always NULL.
+	v, err := e.Eval(sql.NewEmptyContext(), sql.NewRow(int64(1)))
+	require.NoError(err)
+	require.Nil(v)
+}
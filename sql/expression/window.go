@@ -0,0 +1,199 @@
+package expression
+
+import (
+	"fmt"
+	"strings"
+
+	errors "gopkg.in/src-d/go-errors.v1"
+	"gopkg.in/src-d/go-mysql-server.v0/sql"
+)
+
+// ErrWindowEval is returned when a window is evaluated outside of a window
+// node, which is the only one that can evaluate it.
+var ErrWindowEval = errors.NewKind("window function %s can only be used in the select list")
+
+// WindowFrameUnit is the unit of the bounds of a window frame.
+type WindowFrameUnit byte
+
+const (
+	// RowsFrame is a frame whose bounds are a number of rows before or
+	// after the current row.
+	RowsFrame WindowFrameUnit = iota
+	// RangeFrame is a frame whose bounds are the rows with a value of the
+	// ORDER BY of the window within a range of the value of the current row.
+	RangeFrame
+)
+
+func (u WindowFrameUnit) String() string {
+	if u == RangeFrame {
+		return "RANGE"
+	}
+	return "ROWS"
+}
+
+// WindowFrameBoundType is the type of a bound of a window frame.
+type WindowFrameBoundType byte
+
+const (
+	// UnboundedPreceding is the first row of the partition.
+	UnboundedPreceding WindowFrameBoundType = iota
+	// Preceding is a number of rows or a range before the current row.
+	Preceding
+	// CurrentRow is the current row, or its peers in RANGE frames.
+	CurrentRow
+	// Following is a number of rows or a range after the current row.
+	Following
+	// UnboundedFollowing is the last row of the partition.
+	UnboundedFollowing
+)
+
+// WindowFrameBound is the start or the end of a window frame.
+type WindowFrameBound struct {
+	Type WindowFrameBoundType
+	// Offset is the number of rows or the range of Preceding and Following
+	// bounds.
+	Offset int64
+}
+
+func (b WindowFrameBound) String() string {
+	switch b.Type {
+	case UnboundedPreceding:
+		return "UNBOUNDED PRECEDING"
+	case Preceding:
+		return fmt.Sprintf("%d PRECEDING", b.Offset)
+	case CurrentRow:
+		return "CURRENT ROW"
+	case Following:
+		return fmt.Sprintf("%d FOLLOWING", b.Offset)
+	default:
+		return "UNBOUNDED FOLLOWING"
+	}
+}
+
+// WindowFrame is the set of rows of the partition of the current row used
+// to evaluate an aggregation over a window.
+type WindowFrame struct {
+	Unit       WindowFrameUnit
+	Start, End WindowFrameBound
+}
+
+func (f *WindowFrame) String() string {
+	return fmt.Sprintf("%s BETWEEN %s AND %s", f.Unit, f.Start, f.End)
+}
+
+// WindowSortField is an expression of the ORDER BY of a window.
+type WindowSortField struct {
+	Column     sql.Expression
+	Descending bool
+}
+
+// Window is a window function or an aggregation evaluated over a window,
+// that is, `f(...) OVER (PARTITION BY ... ORDER BY ... frame)`. The rows
+// are split in partitions by the values of the PARTITION BY expressions
+// and sorted by the ORDER BY. Aggregations are evaluated with the rows of
+// the frame of each row, which is all the partition when there is no ORDER
+// BY, or the rows up to the last peer of the current row otherwise. It can
+// only be evaluated by a plan.Window node.
+type Window struct {
+	Function    sql.Expression
+	PartitionBy []sql.Expression
+	OrderBy     []WindowSortField
+	// Frame is the frame of the window, or nil to use the default one.
+	Frame *WindowFrame
+}
+
+// NewWindow returns a new Window expression.
+func NewWindow(
+	fn sql.Expression,
+	partitionBy []sql.Expression,
+	orderBy []WindowSortField,
+	frame *WindowFrame,
+) *Window {
+	return &Window{fn, partitionBy, orderBy, frame}
+}
+
+// Type implements the sql.Expression interface.
+func (w *Window) Type() sql.Type { return w.Function.Type() }
+
+// IsNullable implements the sql.Expression interface.
+func (w *Window) IsNullable() bool { return w.Function.IsNullable() }
+
+// Resolved implements the sql.Expression interface.
+func (w *Window) Resolved() bool {
+	for _, e := range w.Children() {
+		if !e.Resolved() {
+			return false
+		}
+	}
+	return true
+}
+
+// Children implements the sql.Expression interface.
+func (w *Window) Children() []sql.Expression {
+	var children = []sql.Expression{w.Function}
+	children = append(children, w.PartitionBy...)
+	for _, f := range w.OrderBy {
+		children = append(children, f.Column)
+	}
+	return children
+}
+
+// Eval implements the sql.Expression interface.
+func (w *Window) Eval(ctx *sql.Context, row sql.Row) (interface{}, error) {
+	return nil, ErrWindowEval.New(w.Function)
+}
+
+// TransformUp implements the sql.Expression interface.
+func (w *Window) TransformUp(f sql.TransformExprFunc) (sql.Expression, error) {
+	fn, err := w.Function.TransformUp(f)
+	if err != nil {
+		return nil, err
+	}
+
+	var partitionBy = make([]sql.Expression, len(w.PartitionBy))
+	for i, e := range w.PartitionBy {
+		partitionBy[i], err = e.TransformUp(f)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var orderBy = make([]WindowSortField, len(w.OrderBy))
+	for i, sf := range w.OrderBy {
+		col, err := sf.Column.TransformUp(f)
+		if err != nil {
+			return nil, err
+		}
+		orderBy[i] = WindowSortField{col, sf.Descending}
+	}
+
+	return f(NewWindow(fn, partitionBy, orderBy, w.Frame))
+}
+
+func (w *Window) String() string {
+	var parts []string
+	if len(w.PartitionBy) > 0 {
+		var exprs = make([]string, len(w.PartitionBy))
+		for i, e := range w.PartitionBy {
+			exprs[i] = e.String()
+		}
+		parts = append(parts, "PARTITION BY "+strings.Join(exprs, ", "))
+	}
+
+	if len(w.OrderBy) > 0 {
+		var fields = make([]string, len(w.OrderBy))
+		for i, sf := range w.OrderBy {
+			fields[i] = sf.Column.String()
+			if sf.Descending {
+				fields[i] += " DESC"
+			}
+		}
+		parts = append(parts, "ORDER BY "+strings.Join(fields, ", "))
+	}
+
+	if w.Frame != nil {
+		parts = append(parts, w.Frame.String())
+	}
+
+	return fmt.Sprintf("%s OVER (%s)", w.Function, strings.Join(parts, " "))
+}
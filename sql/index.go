@@ -7,6 +7,7 @@ import (
 
 	"github.com/sirupsen/logrus"
 	"gopkg.in/src-d/go-errors.v1"
+	"gopkg.in/src-d/go-vitess.v1/vt/sqlparser"
 )
 
 // IndexBatchSize is the number of rows to save at a time when creating indexes.
@@ -438,6 +439,10 @@ var (
 	// ErrIndexDeleteInvalidStatus is returned when the index trying to delete
 	// does not have a ready or outdated state.
 	ErrIndexDeleteInvalidStatus = errors.NewKind("can't delete index %q because it's not ready for removal")
+
+	// ErrIndexDriverNotFound is returned when the driver of an index is not
+	// registered.
+	ErrIndexDriverNotFound = errors.NewKind("index driver %q not found")
 )
 
 func (r *IndexRegistry) validateIndexToAdd(idx Index) error {
@@ -624,3 +629,117 @@ func (s IndexStatus) String() string {
 		return "not ready"
 	}
 }
+
+// MarkTableIndexesOutdated marks all the indexes of the given table as
+// outdated, so they are no longer used by queries, because the rows of the
+// table changed and the indexes don't match them anymore. They can still be
+// deleted.
+func (r *IndexRegistry) MarkTableIndexesOutdated(db, table string) {
+	r.mut.Lock()
+	defer r.mut.Unlock()
+
+	for _, idx := range r.indexes {
+		if idx.Database() == db && idx.Table() == table {
+			r.setStatus(idx, IndexOutdated)
+		}
+	}
+}
+
+// DeleteTableIndexes deletes all the indexes of the given table, both from
+// the registry and from disk. Indexes are deleted even if they are being
+// used or are not ready yet.
+func (r *IndexRegistry) DeleteTableIndexes(ctx *Context, db string, table Table) error {
+	return r.deleteIndexes(ctx, db, table, func(Index) bool { return true })
+}
+
+// DeleteColumnIndexes deletes all the indexes of the given table with an
+// expression using the column with the given name, the same way as
+// DeleteTableIndexes, as they are no longer valid once the column is
+// dropped or modified.
+func (r *IndexRegistry) DeleteColumnIndexes(ctx *Context, db string, table Table, column string) error {
+	return r.deleteIndexes(ctx, db, table, func(idx Index) bool {
+		for _, e := range idx.Expressions() {
+			if usesColumn(e, table.Name(), column) {
+				return true
+			}
+		}
+		return false
+	})
+}
+
+// usesColumn returns whether the given index expression has a column with
+// the given name of the given table. Expressions that can't be parsed are
+// considered to use it, so their indexes are not kept when they may be no
+// longer valid.
+func usesColumn(expr, table, column string) bool {
+	stmt, err := sqlparser.Parse("SELECT " + expr)
+	if err != nil {
+		return true
+	}
+
+	var found bool
+	_ = sqlparser.Walk(func(node sqlparser.SQLNode) (bool, error) {
+		col, ok := node.(*sqlparser.ColName)
+		if !ok {
+			return true, nil
+		}
+
+		qualifier := col.Qualifier.Name.String()
+		if col.Name.EqualString(column) &&
+			(qualifier == "" || strings.EqualFold(qualifier, table)) {
+			found = true
+		}
+		return !found, nil
+	}, stmt)
+	return found
+}
+
+// deleteIndexes deletes the indexes of the given table matching the given
+// function. All the indexes are released and their drivers checked before
+// deleting any of them. If an index can't be deleted, the rest of them are
+// deleted anyway and the first error is returned.
+func (r *IndexRegistry) deleteIndexes(ctx *Context, db string, table Table, matches func(Index) bool) error {
+	var indexes []Index
+	// IndexesByTable retains all the indexes it returns.
+	for _, idx := range r.IndexesByTable(db, table.Name()) {
+		r.ReleaseIndex(idx)
+		if matches(idx) {
+			indexes = append(indexes, idx)
+		}
+	}
+
+	drivers := make([]IndexDriver, len(indexes))
+	for i, idx := range indexes {
+		drivers[i] = r.IndexDriver(idx.Driver())
+		if drivers[i] == nil {
+			return ErrIndexDriverNotFound.New(idx.Driver())
+		}
+	}
+
+	var firstErr error
+	for i, idx := range indexes {
+		if err := r.deleteIndex(ctx, db, table, idx, drivers[i]); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+
+	return firstErr
+}
+
+// deleteIndex deletes the given index of the given table from the registry
+// and from disk using the given driver.
+func (r *IndexRegistry) deleteIndex(ctx *Context, db string, table Table, idx Index, driver IndexDriver) error {
+	done, err := r.DeleteIndex(db, idx.ID(), true)
+	if err != nil {
+		return err
+	}
+
+	<-done
+
+	partitions, err := table.Partitions(ctx)
+	if err != nil {
+		return err
+	}
+
+	return driver.Delete(idx, partitions)
+}
@@ -162,6 +162,87 @@ func TestDeleteIndex(t *testing.T) {
 	require.Len(r.indexes, 0)
 }
 
+func TestDeleteColumnIndexes(t *testing.T) {
+	require := require.New(t)
+	r := NewIndexRegistry()
+	r.RegisterIndexDriver(&loadDriver{id: "dummy"})
+
+	indexes := []*dummyIdx{
+		{id: "idx_a", database: "foo", table: "bar", expr: []Expression{fieldExpr("bar.a")}},
+		{id: "idx_ab", database: "foo", table: "bar", expr: []Expression{fieldExpr("bar.ab")}},
+		{id: "idx_b_a", database: "foo", table: "bar", expr: []Expression{fieldExpr("bar.b"), fieldExpr("bar.a")}},
+		{id: "idx_lower", database: "foo", table: "bar", expr: []Expression{fieldExpr("LOWER(bar.A)")}},
+		{id: "idx_concat", database: "foo", table: "bar", expr: []Expression{fieldExpr("CONCAT(bar.b, 'bar.a')")}},
+		{id: "idx_baz", database: "foo", table: "baz", expr: []Expression{fieldExpr("baz.a")}},
+	}
+
+	for _, idx := range indexes {
+		key := indexKey{idx.Database(), idx.ID()}
+		r.indexes[key] = idx
+		r.indexOrder = append(r.indexOrder, key)
+		r.setStatus(idx, IndexReady)
+	}
+
+	table := &partitionedTable{dummyTable{name: "bar"}}
+	require.NoError(r.DeleteColumnIndexes(NewEmptyContext(), "foo", table, "a"))
+
+	var ids []string
+	for _, key := range r.indexOrder {
+		ids = append(ids, key.id)
+	}
+	require.Equal([]string{"idx_ab", "idx_concat", "idx_baz"}, ids)
+}
+
+func TestDeleteTableIndexes_DriverNotFound(t *testing.T) {
+	require := require.New(t)
+	r := NewIndexRegistry()
+
+	indexes := []*dummyIdx{
+		{id: "idx_a", database: "foo", table: "bar", expr: []Expression{fieldExpr("bar.a")}},
+		{id: "idx_b", database: "foo", table: "bar", expr: []Expression{fieldExpr("bar.b")}},
+	}
+
+	for _, idx := range indexes {
+		key := indexKey{idx.Database(), idx.ID()}
+		r.indexes[key] = idx
+		r.indexOrder = append(r.indexOrder, key)
+		r.setStatus(idx, IndexReady)
+	}
+
+	table := &partitionedTable{dummyTable{name: "bar"}}
+	err := r.DeleteTableIndexes(NewEmptyContext(), "foo", table)
+	require.Error(err)
+	require.True(ErrIndexDriverNotFound.Is(err))
+
+	// No index is deleted and all of them are released.
+	require.Len(r.indexes, 2)
+	for _, idx := range indexes {
+		require.Equal(0, r.refCounts[indexKey{idx.Database(), idx.ID()}])
+	}
+}
+
+func TestMarkTableIndexesOutdated(t *testing.T) {
+	require := require.New(t)
+	r := NewIndexRegistry()
+
+	indexes := []*dummyIdx{
+		{id: "idx_a", database: "foo", table: "bar", expr: []Expression{fieldExpr("bar.a")}},
+		{id: "idx_b", database: "foo", table: "baz", expr: []Expression{fieldExpr("baz.b")}},
+	}
+
+	for _, idx := range indexes {
+		key := indexKey{idx.Database(), idx.ID()}
+		r.indexes[key] = idx
+		r.indexOrder = append(r.indexOrder, key)
+		r.setStatus(idx, IndexReady)
+	}
+
+	r.MarkTableIndexesOutdated("foo", "bar")
+	require.False(r.CanUseIndex(indexes[0]))
+	require.True(r.CanRemoveIndex(indexes[0]))
+	require.True(r.CanUseIndex(indexes[1]))
+}
+
 func TestDeleteIndex_InUse(t *testing.T) {
 	require := require.New(t)
 	r := NewIndexRegistry()
@@ -348,6 +429,28 @@ type dummyTable struct {
 
 func (t dummyTable) Name() string { return t.name }
 
+type partitionedTable struct {
+	dummyTable
+}
+
+func (partitionedTable) Partitions(*Context) (PartitionIter, error) { return nil, nil }
+
+// fieldExpr is an expression that is only used for its string
+// representation.
+type fieldExpr string
+
+var _ Expression = fieldExpr("")
+
+func (fieldExpr) Children() []Expression                  { return nil }
+func (fieldExpr) Eval(*Context, Row) (interface{}, error) { panic("not implemented") }
+func (e fieldExpr) TransformUp(fn TransformExprFunc) (Expression, error) {
+	return fn(e)
+}
+func (e fieldExpr) String() string { return string(e) }
+func (fieldExpr) IsNullable() bool { return false }
+func (fieldExpr) Resolved() bool   { return true }
+func (fieldExpr) Type() Type       { panic("not implemented") }
+
 type loadDriver struct {
 	indexes []Index
 	id      string
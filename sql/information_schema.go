@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"fmt"
 	"io"
+	"strings"
 )
 
 const (
@@ -19,6 +20,15 @@ const (
 	ColumnsTableName = "columns"
 	// SchemataTableName is the name of the schemata table.
 	SchemataTableName = "schemata"
+	// TableConstraintsTableName is the name of the table constraints table.
+	TableConstraintsTableName = "table_constraints"
+	// ReferentialConstraintsTableName is the name of the referential
+	// constraints table.
+	ReferentialConstraintsTableName = "referential_constraints"
+	// CheckConstraintsTableName is the name of the check constraints table.
+	CheckConstraintsTableName = "check_constraints"
+	// ViewsTableName is the name of the views table.
+	ViewsTableName = "views"
 )
 
 type informationSchemaDatabase struct {
@@ -153,6 +163,50 @@ var schemataSchema = Schema{
 	{Name: "sql_path", Type: Text, Default: nil, Nullable: true, Source: SchemataTableName},
 }
 
+var tableConstraintsSchema = Schema{
+	{Name: "constraint_catalog", Type: Text, Default: "", Nullable: false, Source: TableConstraintsTableName},
+	{Name: "constraint_schema", Type: Text, Default: "", Nullable: false, Source: TableConstraintsTableName},
+	{Name: "constraint_name", Type: Text, Default: "", Nullable: false, Source: TableConstraintsTableName},
+	{Name: "table_schema", Type: Text, Default: "", Nullable: false, Source: TableConstraintsTableName},
+	{Name: "table_name", Type: Text, Default: "", Nullable: false, Source: TableConstraintsTableName},
+	{Name: "constraint_type", Type: Text, Default: "", Nullable: false, Source: TableConstraintsTableName},
+	{Name: "enforced", Type: Text, Default: "", Nullable: false, Source: TableConstraintsTableName},
+}
+
+var referentialConstraintsSchema = Schema{
+	{Name: "constraint_catalog", Type: Text, Default: "", Nullable: false, Source: ReferentialConstraintsTableName},
+	{Name: "constraint_schema", Type: Text, Default: "", Nullable: false, Source: ReferentialConstraintsTableName},
+	{Name: "constraint_name", Type: Text, Default: "", Nullable: false, Source: ReferentialConstraintsTableName},
+	{Name: "unique_constraint_catalog", Type: Text, Default: "", Nullable: false, Source: ReferentialConstraintsTableName},
+	{Name: "unique_constraint_schema", Type: Text, Default: "", Nullable: false, Source: ReferentialConstraintsTableName},
+	{Name: "unique_constraint_name", Type: Text, Default: nil, Nullable: true, Source: ReferentialConstraintsTableName},
+	{Name: "match_option", Type: Text, Default: "", Nullable: false, Source: ReferentialConstraintsTableName},
+	{Name: "update_rule", Type: Text, Default: "", Nullable: false, Source: ReferentialConstraintsTableName},
+	{Name: "delete_rule", Type: Text, Default: "", Nullable: false, Source: ReferentialConstraintsTableName},
+	{Name: "table_name", Type: Text, Default: "", Nullable: false, Source: ReferentialConstraintsTableName},
+	{Name: "referenced_table_name", Type: Text, Default: "", Nullable: false, Source: ReferentialConstraintsTableName},
+}
+
+var checkConstraintsSchema = Schema{
+	{Name: "constraint_catalog", Type: Text, Default: "", Nullable: false, Source: CheckConstraintsTableName},
+	{Name: "constraint_schema", Type: Text, Default: "", Nullable: false, Source: CheckConstraintsTableName},
+	{Name: "constraint_name", Type: Text, Default: "", Nullable: false, Source: CheckConstraintsTableName},
+	{Name: "check_clause", Type: Text, Default: "", Nullable: false, Source: CheckConstraintsTableName},
+}
+
+var viewsSchema = Schema{
+	{Name: "table_catalog", Type: Text, Default: "", Nullable: false, Source: ViewsTableName},
+	{Name: "table_schema", Type: Text, Default: "", Nullable: false, Source: ViewsTableName},
+	{Name: "table_name", Type: Text, Default: "", Nullable: false, Source: ViewsTableName},
+	{Name: "view_definition", Type: Text, Default: "", Nullable: false, Source: ViewsTableName},
+	{Name: "check_option", Type: Text, Default: "", Nullable: false, Source: ViewsTableName},
+	{Name: "is_updatable", Type: Text, Default: "", Nullable: false, Source: ViewsTableName},
+	{Name: "definer", Type: Text, Default: "", Nullable: false, Source: ViewsTableName},
+	{Name: "security_type", Type: Text, Default: "", Nullable: false, Source: ViewsTableName},
+	{Name: "character_set_client", Type: Text, Default: "", Nullable: false, Source: ViewsTableName},
+	{Name: "collation_connection", Type: Text, Default: "", Nullable: false, Source: ViewsTableName},
+}
+
 func tablesRowIter(cat *Catalog) RowIter {
 	var rows []Row
 	for _, db := range cat.AllDatabases() {
@@ -189,6 +243,32 @@ func tablesRowIter(cat *Catalog) RowIter {
 				"",         //table_comment
 			})
 		}
+
+		for _, view := range cat.ViewsInDatabase(db.Name()) {
+			rows = append(rows, Row{
+				"def",     //table_catalog
+				db.Name(), // table_schema
+				view.Name, // table_name
+				"VIEW",    // table_type
+				nil,       // engine
+				nil,       //version
+				nil,       //row_format
+				nil,       //table_rows
+				nil,       //avg_row_length
+				nil,       //data_length
+				nil,       //max_data_length
+				nil,       //max_data_length
+				nil,       //data_free
+				nil,       //auto_increment
+				nil,       //create_time
+				nil,       //update_time
+				nil,       //check_time
+				nil,       //table_collation
+				nil,       //checksum
+				nil,       //create_options
+				"VIEW",    //table_comment
+			})
+		}
 	}
 
 	return RowsToRowIter(rows...)
@@ -209,32 +289,36 @@ func columnsRowIter(cat *Catalog) RowIter {
 				} else {
 					nullable = "NO"
 				}
-				if IsText(c.Type) {
+				if IsText(c.Type) || IsEnum(c.Type) || IsSet(c.Type) {
 					charName = "utf8mb4"
 					collName = "utf8_bin"
 				}
+				if coll, ok := CollationOf(c.Type); ok && coll != DefaultCollation {
+					charName = coll.CharacterSet
+					collName = coll.Name
+				}
 				rows = append(rows, Row{
-					"def",           // table_catalog
-					db.Name(),       // table_schema
-					t.Name(),        // table_name
-					c.Name,          // column_name
-					uint64(i),       // ordinal_position
-					c.Default,       // column_default
-					nullable,        // is_nullable
-					c.Type.String(), // data_type
-					nil,             // character_maximum_length
-					nil,             // character_octet_length
-					nil,             // numeric_precision
-					nil,             // numeric_scale
-					nil,             // datetime_precision
-					charName,        // character_set_name
-					collName,        // collation_name
-					c.Type.String(), // column_type
-					"",              // column_key
-					"",              // extra
-					"select",        // privileges
-					"",              // column_comment
-					"",              // generation_expression
+					"def",            // table_catalog
+					db.Name(),        // table_schema
+					t.Name(),         // table_name
+					c.Name,           // column_name
+					uint64(i),        // ordinal_position
+					c.Default,        // column_default
+					nullable,         // is_nullable
+					dataType(c.Type), // data_type
+					nil,              // character_maximum_length
+					nil,              // character_octet_length
+					nil,              // numeric_precision
+					nil,              // numeric_scale
+					nil,              // datetime_precision
+					charName,         // character_set_name
+					collName,         // collation_name
+					c.Type.String(),  // column_type
+					c.KeyName(),      // column_key
+					c.Extra(),        // extra
+					"select",         // privileges
+					"",               // column_comment
+					"",               // generation_expression
 				})
 			}
 		}
@@ -242,6 +326,19 @@ func columnsRowIter(cat *Catalog) RowIter {
 	return RowsToRowIter(rows...)
 }
 
+// dataType returns the name of the given type without the values of the
+// type, if it's an ENUM or SET, which are only shown in the column type.
+func dataType(t Type) string {
+	switch {
+	case IsEnum(t):
+		return "ENUM"
+	case IsSet(t):
+		return "SET"
+	default:
+		return t.String()
+	}
+}
+
 func schemataRowIter(c *Catalog) RowIter {
 	dbs := c.AllDatabases()
 
@@ -263,6 +360,164 @@ func schemataRowIter(c *Catalog) RowIter {
 	return RowsToRowIter(rows...)
 }
 
+func tableConstraintsRowIter(cat *Catalog) RowIter {
+	var rows []Row
+	for _, db := range cat.AllDatabases() {
+		for _, t := range db.Tables() {
+			var constraints [][2]string
+			if pt, ok := t.(PrimaryKeyTable); ok {
+				for _, k := range pt.Keys() {
+					if k.Name == PrimaryKeyName {
+						constraints = append(constraints, [2]string{k.Name, "PRIMARY KEY"})
+					} else {
+						constraints = append(constraints, [2]string{k.Name, "UNIQUE"})
+					}
+				}
+			}
+
+			if ct, ok := t.(ConstraintTable); ok {
+				for _, fk := range ct.ForeignKeys() {
+					constraints = append(constraints, [2]string{fk.Name, "FOREIGN KEY"})
+				}
+
+				for _, check := range ct.Checks() {
+					constraints = append(constraints, [2]string{check.Name, "CHECK"})
+				}
+			}
+
+			for _, c := range constraints {
+				rows = append(rows, Row{
+					"def",     // constraint_catalog
+					db.Name(), // constraint_schema
+					c[0],      // constraint_name
+					db.Name(), // table_schema
+					t.Name(),  // table_name
+					c[1],      // constraint_type
+					"YES",     // enforced
+				})
+			}
+		}
+	}
+
+	return RowsToRowIter(rows...)
+}
+
+func referentialConstraintsRowIter(cat *Catalog) RowIter {
+	var rows []Row
+	for _, db := range cat.AllDatabases() {
+		for _, t := range db.Tables() {
+			ct, ok := t.(ConstraintTable)
+			if !ok {
+				continue
+			}
+
+			for _, fk := range ct.ForeignKeys() {
+				rows = append(rows, Row{
+					"def",                        // constraint_catalog
+					db.Name(),                    // constraint_schema
+					fk.Name,                      // constraint_name
+					"def",                        // unique_constraint_catalog
+					db.Name(),                    // unique_constraint_schema
+					referencedKeyName(db, t, fk), // unique_constraint_name
+					"NONE",                       // match_option
+					string(ForeignKeyRestrict),   // update_rule
+					string(fk.OnDelete),          // delete_rule
+					t.Name(),                     // table_name
+					fk.ReferencedTable,           // referenced_table_name
+				})
+			}
+		}
+	}
+
+	return RowsToRowIter(rows...)
+}
+
+// referencedKeyName returns the name of the key of the table referenced by
+// the foreign key that has the referenced columns, or nil if there is none.
+func referencedKeyName(db Database, table Table, fk ForeignKey) interface{} {
+	parent := table
+	if !fk.References(table.Name()) {
+		parent = db.Tables()[fk.ReferencedTable]
+	}
+
+	pt, ok := parent.(PrimaryKeyTable)
+	if !ok {
+		return nil
+	}
+
+	schema := pt.Schema()
+	for _, k := range pt.Keys() {
+		if len(k.Columns) != len(fk.ReferencedColumns) {
+			continue
+		}
+
+		var matches = true
+		for _, idx := range k.Columns {
+			matches = matches && containsFold(fk.ReferencedColumns, schema[idx].Name)
+		}
+
+		if matches {
+			return k.Name
+		}
+	}
+
+	return nil
+}
+
+func containsFold(values []string, value string) bool {
+	for _, v := range values {
+		if strings.EqualFold(v, value) {
+			return true
+		}
+	}
+	return false
+}
+
+func checkConstraintsRowIter(cat *Catalog) RowIter {
+	var rows []Row
+	for _, db := range cat.AllDatabases() {
+		for _, t := range db.Tables() {
+			ct, ok := t.(ConstraintTable)
+			if !ok {
+				continue
+			}
+
+			for _, check := range ct.Checks() {
+				rows = append(rows, Row{
+					"def",                           // constraint_catalog
+					db.Name(),                       // constraint_schema
+					check.Name,                      // constraint_name
+					fmt.Sprintf("(%s)", check.Expr), // check_clause
+				})
+			}
+		}
+	}
+
+	return RowsToRowIter(rows...)
+}
+
+func viewsRowIter(cat *Catalog) RowIter {
+	var rows []Row
+	for _, db := range cat.AllDatabases() {
+		for _, view := range cat.ViewsInDatabase(db.Name()) {
+			rows = append(rows, Row{
+				"def",                 // table_catalog
+				db.Name(),             // table_schema
+				view.Name,             // table_name
+				view.TextDefinition,   // view_definition
+				"NONE",                // check_option
+				"NO",                  // is_updatable
+				"root@localhost",      // definer
+				"DEFINER",             // security_type
+				"utf8mb4",             // character_set_client
+				DefaultCollation.Name, // collation_connection
+			})
+		}
+	}
+
+	return RowsToRowIter(rows...)
+}
+
 // NewInformationSchemaDatabase creates a new INFORMATION_SCHEMA Database.
 func NewInformationSchemaDatabase(cat *Catalog) Database {
 	return &informationSchemaDatabase{
@@ -296,6 +551,30 @@ func NewInformationSchemaDatabase(cat *Catalog) Database {
 				catalog: cat,
 				rowIter: schemataRowIter,
 			},
+			TableConstraintsTableName: &informationSchemaTable{
+				name:    TableConstraintsTableName,
+				schema:  tableConstraintsSchema,
+				catalog: cat,
+				rowIter: tableConstraintsRowIter,
+			},
+			ReferentialConstraintsTableName: &informationSchemaTable{
+				name:    ReferentialConstraintsTableName,
+				schema:  referentialConstraintsSchema,
+				catalog: cat,
+				rowIter: referentialConstraintsRowIter,
+			},
+			CheckConstraintsTableName: &informationSchemaTable{
+				name:    CheckConstraintsTableName,
+				schema:  checkConstraintsSchema,
+				catalog: cat,
+				rowIter: checkConstraintsRowIter,
+			},
+			ViewsTableName: &informationSchemaTable{
+				name:    ViewsTableName,
+				schema:  viewsSchema,
+				catalog: cat,
+				rowIter: viewsRowIter,
+			},
 		},
 	}
 }
@@ -0,0 +1,110 @@
+package sql
+
+import (
+	"fmt"
+	"strings"
+
+	"gopkg.in/src-d/go-errors.v1"
+)
+
+var (
+	// ErrDuplicateEntry is returned when a row has the same values in the
+	// columns of a primary or unique key as another row of its table.
+	ErrDuplicateEntry = errors.NewKind("Duplicate entry '%s' for key '%s'")
+
+	// ErrColumnCannotBeNull is returned when a NULL value is given to a
+	// column that is not nullable.
+	ErrColumnCannotBeNull = errors.NewKind("Column '%s' cannot be null")
+
+	// ErrNoDefaultValue is returned in strict mode when a row is inserted
+	// without a value for a column that is not nullable and has no default
+	// value.
+	ErrNoDefaultValue = errors.NewKind("Field '%s' doesn't have a default value")
+)
+
+// PrimaryKeyName is the name of the primary key of all tables.
+const PrimaryKeyName = "PRIMARY"
+
+// Key is a primary or unique key of a table. Two rows of the table can't
+// have the same values in all the columns of a key, unless any of them is
+// NULL.
+type Key struct {
+	// Name is the name of the key.
+	Name string
+	// Columns are the positions in the table schema of the columns of the
+	// key.
+	Columns []int
+}
+
+// SchemaKeys returns the keys defined by the PrimaryKey, Unique and
+// UniqueKeys fields of the columns of the given schema. The primary key, if
+// any, is the first one, each unique column has a key named after it and
+// the columns of the rest of unique keys are in the order of the schema.
+func SchemaKeys(schema Schema) []Key {
+	var keys []Key
+	var primary []int
+	for i, col := range schema {
+		if col.PrimaryKey {
+			primary = append(primary, i)
+		}
+	}
+
+	if len(primary) > 0 {
+		keys = append(keys, Key{Name: PrimaryKeyName, Columns: primary})
+	}
+
+	for i, col := range schema {
+		if col.Unique && !(col.PrimaryKey && len(primary) == 1) {
+			keys = append(keys, Key{Name: col.Name, Columns: []int{i}})
+		}
+	}
+
+	var names []string
+	var columns = make(map[string][]int)
+	for i, col := range schema {
+		for _, name := range col.UniqueKeys {
+			if _, ok := columns[name]; !ok {
+				names = append(names, name)
+			}
+			columns[name] = append(columns[name], i)
+		}
+	}
+
+	for _, name := range names {
+		keys = append(keys, Key{Name: name, Columns: columns[name]})
+	}
+
+	return keys
+}
+
+// Duplicates reports whether the two given rows of a table with the given
+// schema have the same values in the columns of the key.
+func (k Key) Duplicates(schema Schema, a, b Row) (bool, error) {
+	for _, i := range k.Columns {
+		if a[i] == nil || b[i] == nil {
+			return false, nil
+		}
+
+		cmp, err := schema[i].Type.Compare(a[i], b[i])
+		if err != nil {
+			return false, err
+		}
+
+		if cmp != 0 {
+			return false, nil
+		}
+	}
+
+	return true, nil
+}
+
+// DuplicateError returns the error for a row with the same values in the
+// columns of the key as another row.
+func (k Key) DuplicateError(row Row) error {
+	var values = make([]string, len(k.Columns))
+	for i, idx := range k.Columns {
+		values[i] = fmt.Sprint(row[idx])
+	}
+
+	return ErrDuplicateEntry.New(strings.Join(values, "-"), k.Name)
+}
@@ -0,0 +1,22 @@
+package sql
+
+// OkResultColumnName is the name of the only column of the rows returned by
+// statements that modify rows instead of returning them.
+const OkResultColumnName = "__ok_result__"
+
+// OkResultSchema is the schema of statements that modify rows instead of
+// returning them, such as INSERT, UPDATE or DELETE. They return a single
+// row with the number of affected rows, which is sent to clients as an OK
+// packet instead of as a result set.
+var OkResultSchema = Schema{{
+	Name:     OkResultColumnName,
+	Type:     Int64,
+	Default:  int64(0),
+	Nullable: false,
+}}
+
+// IsOkResultSchema reports whether the given schema is the schema of a
+// statement that modifies rows instead of returning them.
+func IsOkResultSchema(schema Schema) bool {
+	return len(schema) == 1 && schema[0].Name == OkResultColumnName
+}
@@ -0,0 +1,123 @@
+package parse
+
+import (
+	"regexp"
+	"strings"
+
+	errors "gopkg.in/src-d/go-errors.v1"
+	"gopkg.in/src-d/go-mysql-server.v0/sql"
+	"gopkg.in/src-d/go-mysql-server.v0/sql/plan"
+	"gopkg.in/src-d/go-vitess.v1/vt/sqlparser"
+)
+
+var errInvalidColumnDefinition = errors.NewKind("invalid column definition: %s")
+
+var alterTableColumnRegex = regexp.MustCompile(
+	`(?is)^alter\s+table\s+(\S+)\s+(add|drop|modify|change)\s+(column\s+)?(.+)$`,
+)
+
+var (
+	identRegex        = regexp.MustCompile("^(`[^`]+`|[^\\s`]+)$")
+	changeColumnRegex = regexp.MustCompile("(?s)^(`[^`]+`|[^\\s`]+)\\s+(.+)$")
+)
+
+// parseAlterTable parses an ALTER TABLE statement that adds, drops or
+// modifies a single column, as the SQL parser ignores everything after the
+// table name in those statements.
+func parseAlterTable(query string) (sql.Node, error) {
+	m := alterTableColumnRegex.FindStringSubmatch(query)
+	if m == nil {
+		return nil, ErrUnsupportedSyntax.New(query)
+	}
+
+	db, table := splitTableName(m[1])
+	action := strings.ToLower(m[2])
+	hasColumnKeyword := m[3] != ""
+	rest := strings.TrimSpace(m[4])
+	if rest == "" {
+		return nil, ErrUnsupportedSyntax.New(query)
+	}
+
+	if !hasColumnKeyword {
+		switch word := strings.ToLower(strings.Fields(rest)[0]); word {
+		case "index", "key", "primary", "unique", "foreign",
+			"constraint", "fulltext", "spatial", "partition":
+			return nil, ErrUnsupportedFeature.New("ALTER TABLE " + strings.ToUpper(action+" "+word))
+		}
+	}
+
+	node := plan.NewUnresolvedTable(table, db)
+	switch action {
+	case "add":
+		col, err := parseColumnDefinition(table, rest)
+		if err != nil {
+			return nil, err
+		}
+
+		return plan.NewAddColumn(node, col), nil
+	case "drop":
+		if !identRegex.MatchString(rest) {
+			return nil, ErrUnsupportedSyntax.New(query)
+		}
+
+		return plan.NewDropColumn(node, unquoteIdent(rest)), nil
+	case "modify":
+		col, err := parseColumnDefinition(table, rest)
+		if err != nil {
+			return nil, err
+		}
+
+		return plan.NewModifyColumn(node, col.Name, col), nil
+	case "change":
+		parts := changeColumnRegex.FindStringSubmatch(rest)
+		if parts == nil {
+			return nil, ErrUnsupportedSyntax.New(query)
+		}
+
+		col, err := parseColumnDefinition(table, parts[2])
+		if err != nil {
+			return nil, err
+		}
+
+		return plan.NewModifyColumn(node, unquoteIdent(parts[1]), col), nil
+	default:
+		return nil, ErrUnsupportedSyntax.New(query)
+	}
+}
+
+// parseColumnDefinition parses the definition of a single column by
+// parsing a CREATE TABLE statement with only that column.
+func parseColumnDefinition(table, def string) (*sql.Column, error) {
+	stmt, err := sqlparser.Parse("CREATE TABLE t (" + def + ")")
+	if err != nil {
+		return nil, err
+	}
+
+	ddl, ok := stmt.(*sqlparser.DDL)
+	if !ok || ddl.TableSpec == nil ||
+		len(ddl.TableSpec.Columns) != 1 || len(ddl.TableSpec.Indexes) != 0 {
+		return nil, errInvalidColumnDefinition.New(def)
+	}
+
+	schema, err := columnDefinitionToSchema(ddl.TableSpec.Columns)
+	if err != nil {
+		return nil, err
+	}
+
+	col := schema[0]
+	col.Source = table
+	return col, nil
+}
+
+func splitTableName(name string) (db, table string) {
+	parts := strings.SplitN(name, ".", 2)
+	if len(parts) == 2 {
+		return unquoteIdent(parts[0]), unquoteIdent(parts[1])
+	}
+
+	return "", unquoteIdent(name)
+}
+
+func unquoteIdent(ident string) string {
+	return strings.Trim(ident, "`")
+}
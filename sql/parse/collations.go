@@ -0,0 +1,82 @@
+package parse
+
+import (
+	"bufio"
+	"strings"
+
+	"gopkg.in/src-d/go-mysql-server.v0/sql"
+	"gopkg.in/src-d/go-mysql-server.v0/sql/plan"
+)
+
+func parseShowCollation(s string) (sql.Node, error) {
+	var pattern string
+
+	r := bufio.NewReader(strings.NewReader(s))
+	for _, fn := range []parseFunc{
+		expect("show"),
+		skipSpaces,
+		expect("collation"),
+		skipSpaces,
+		readLikePattern(&pattern),
+		skipSpaces,
+		checkEOF,
+	} {
+		if err := fn(r); err != nil {
+			return nil, err
+		}
+	}
+
+	return plan.NewShowCollation(pattern), nil
+}
+
+func parseShowCharacterSet(s string) (sql.Node, error) {
+	var pattern string
+
+	r := bufio.NewReader(strings.NewReader(s))
+	for _, fn := range []parseFunc{
+		expect("show"),
+		skipSpaces,
+		func(in *bufio.Reader) error {
+			var s string
+			if err := readIdent(&s)(in); err != nil {
+				return err
+			}
+
+			switch s {
+			case "character":
+				if err := skipSpaces(in); err != nil {
+					return err
+				}
+
+				return expect("set")(in)
+			case "charset":
+				return nil
+			}
+			return errUnexpectedSyntax.New("show {character set | charset}", s)
+		},
+		skipSpaces,
+		readLikePattern(&pattern),
+		skipSpaces,
+		checkEOF,
+	} {
+		if err := fn(r); err != nil {
+			return nil, err
+		}
+	}
+
+	return plan.NewShowCharacterSet(pattern), nil
+}
+
+// readLikePattern reads the pattern of an optional `LIKE 'pattern'` clause.
+func readLikePattern(pattern *string) parseFunc {
+	return func(in *bufio.Reader) error {
+		if expect("like")(in) == nil {
+			if err := skipSpaces(in); err != nil {
+				return err
+			}
+
+			return readValue(pattern)(in)
+		}
+		return nil
+	}
+}
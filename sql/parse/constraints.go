@@ -0,0 +1,232 @@
+package parse
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"gopkg.in/src-d/go-mysql-server.v0/sql"
+	"gopkg.in/src-d/go-mysql-server.v0/sql/expression"
+	"gopkg.in/src-d/go-mysql-server.v0/sql/plan"
+	"gopkg.in/src-d/go-vitess.v1/vt/sqlparser"
+)
+
+const constraintIdent = "(`[^`]+`|\\w+)"
+
+var (
+	checkConstraintRegex = regexp.MustCompile(
+		`(?is)^(?:constraint(?:\s+` + constraintIdent + `)?\s+)?check\s*\((.+)\)$`,
+	)
+	columnCheckRegex = regexp.MustCompile(
+		`(?is)^(.+?)\s+(?:constraint(?:\s+` + constraintIdent + `)?\s+)?check\s*\((.+)\)$`,
+	)
+	foreignKeyRegex = regexp.MustCompile(
+		`(?is)^(?:constraint(?:\s+` + constraintIdent + `)?\s+)?foreign\s+key(?:\s+` + constraintIdent + `)?\s*` +
+			`\(([^)]+)\)\s*references\s+` + constraintIdent + `\s*\(([^)]+)\)(.*)$`,
+	)
+	referentialActionRegex = regexp.MustCompile(
+		`(?is)^\s*on\s+(delete|update)\s+(restrict|cascade|set\s+null|no\s+action|set\s+default)`,
+	)
+)
+
+type checkDefinition struct {
+	name string
+	expr string
+}
+
+// parseCreateTable parses a CREATE TABLE statement with CHECK or FOREIGN
+// KEY constraints, which are not supported by the SQL parser. The
+// constraints are removed from the statement, which is then parsed as
+// usual.
+func parseCreateTable(query string) (sql.Node, error) {
+	open, close, items := splitTableDefinitions(query)
+
+	var (
+		defs   []string
+		checks []checkDefinition
+		fks    []sql.ForeignKey
+	)
+	for _, item := range items {
+		if m := checkConstraintRegex.FindStringSubmatch(item); m != nil {
+			checks = append(checks, checkDefinition{unquoteIdent(m[1]), m[2]})
+		} else if m := foreignKeyRegex.FindStringSubmatch(item); m != nil {
+			fk, err := foreignKeyDefinition(m)
+			if err != nil {
+				return nil, err
+			}
+			fks = append(fks, fk)
+		} else if m := columnCheckRegex.FindStringSubmatch(item); m != nil {
+			defs = append(defs, m[1])
+			checks = append(checks, checkDefinition{unquoteIdent(m[2]), m[3]})
+		} else {
+			defs = append(defs, item)
+		}
+	}
+
+	if open >= 0 {
+		query = query[:open+1] + strings.Join(defs, ", ") + query[close:]
+	}
+
+	stmt, err := sqlparser.Parse(query)
+	if err != nil {
+		return nil, err
+	}
+
+	ddl, ok := stmt.(*sqlparser.DDL)
+	if !ok || ddl.Action != sqlparser.CreateStr || ddl.TableSpec == nil {
+		return nil, ErrUnsupportedSyntax.New(query)
+	}
+
+	schema, err := tableSpecToSchema(ddl.TableSpec)
+	if err != nil {
+		return nil, err
+	}
+
+	name := ddl.Table.Name.String()
+	node := plan.NewCreateTable(sql.UnresolvedDatabase(""), name, schema)
+
+	var checkConstraints []sql.CheckConstraint
+	for i, c := range checks {
+		e, err := parseExpr(c.expr)
+		if err != nil {
+			return nil, err
+		}
+
+		e, err = resolveCheckColumns(name, schema, e)
+		if err != nil {
+			return nil, err
+		}
+
+		if c.name == "" {
+			c.name = fmt.Sprintf("%s_chk_%d", name, i+1)
+		}
+
+		checkConstraints = append(checkConstraints, sql.CheckConstraint{Name: c.name, Expr: e})
+	}
+
+	for i := range fks {
+		if fks[i].Name == "" {
+			fks[i].Name = fmt.Sprintf("%s_ibfk_%d", name, i+1)
+		}
+	}
+
+	return node.WithConstraints(checkConstraints, fks), nil
+}
+
+// foreignKeyDefinition returns the foreign key defined by the submatches of
+// foreignKeyRegex.
+func foreignKeyDefinition(m []string) (sql.ForeignKey, error) {
+	fk := sql.ForeignKey{
+		Name:              unquoteIdent(m[1]),
+		Columns:           splitIdents(m[3]),
+		ReferencedTable:   unquoteIdent(m[4]),
+		ReferencedColumns: splitIdents(m[5]),
+		OnDelete:          sql.ForeignKeyNoAction,
+	}
+
+	rest := m[6]
+	for strings.TrimSpace(rest) != "" {
+		action := referentialActionRegex.FindStringSubmatch(rest)
+		if action == nil {
+			return sql.ForeignKey{}, ErrUnsupportedSyntax.New(m[0])
+		}
+		rest = rest[len(action[0]):]
+
+		event := strings.ToUpper(action[1])
+		value := sql.ForeignKeyAction(strings.ToUpper(strings.Join(strings.Fields(action[2]), " ")))
+		switch {
+		case event == "UPDATE" && (value == sql.ForeignKeyRestrict || value == sql.ForeignKeyNoAction):
+		case event == "DELETE" && value != "SET DEFAULT":
+			fk.OnDelete = value
+		default:
+			return sql.ForeignKey{}, ErrUnsupportedFeature.New(fmt.Sprintf("ON %s %s", event, value))
+		}
+	}
+
+	return fk, nil
+}
+
+// resolveCheckColumns replaces the columns of the given expression with
+// the fields of the table with the given name and schema.
+func resolveCheckColumns(table string, schema sql.Schema, e sql.Expression) (sql.Expression, error) {
+	return e.TransformUp(func(e sql.Expression) (sql.Expression, error) {
+		switch e := e.(type) {
+		case *expression.UnresolvedColumn:
+			if t := e.Table(); t != "" && !strings.EqualFold(t, table) {
+				return nil, sql.ErrColumnNotFound.New(e.Name(), t)
+			}
+
+			idx := schema.IndexOf(e.Name(), table)
+			if idx < 0 {
+				return nil, sql.ErrColumnNotFound.New(e.Name(), table)
+			}
+
+			col := schema[idx]
+			return expression.NewGetField(idx, col.Type, col.Name, col.Nullable), nil
+		case *expression.Subquery, *expression.InSubquery:
+			return nil, ErrUnsupportedFeature.New("subqueries in CHECK constraints")
+		default:
+			return e, nil
+		}
+	})
+}
+
+// splitTableDefinitions returns the positions of the parentheses enclosing
+// the definitions of a CREATE TABLE statement and the definitions, which
+// are separated by commas that are not inside parentheses or quotes. The
+// positions are -1 if the parentheses are not found.
+func splitTableDefinitions(query string) (open, close int, defs []string) {
+	open, close = -1, -1
+	var (
+		depth int
+		quote byte
+		start int
+	)
+
+	for i := 0; i < len(query) && close < 0; i++ {
+		c := query[i]
+		if quote != 0 {
+			if c == '\\' && quote != '`' {
+				i++
+			} else if c == quote {
+				quote = 0
+			}
+			continue
+		}
+
+		switch c {
+		case '\'', '"', '`':
+			quote = c
+		case '(':
+			depth++
+			if depth == 1 && open < 0 {
+				open, start = i, i+1
+			}
+		case ')':
+			depth--
+			if depth == 0 && open >= 0 {
+				close = i
+				defs = append(defs, strings.TrimSpace(query[start:i]))
+			}
+		case ',':
+			if depth == 1 {
+				defs = append(defs, strings.TrimSpace(query[start:i]))
+				start = i + 1
+			}
+		}
+	}
+
+	if close < 0 {
+		return -1, -1, nil
+	}
+
+	return open, close, defs
+}
+
+func splitIdents(s string) []string {
+	var idents []string
+	for _, ident := range strings.Split(s, ",") {
+		idents = append(idents, unquoteIdent(strings.TrimSpace(ident)))
+	}
+	return idents
+}
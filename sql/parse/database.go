@@ -0,0 +1,39 @@
+package parse
+
+import (
+	"regexp"
+
+	"gopkg.in/src-d/go-mysql-server.v0/sql"
+	"gopkg.in/src-d/go-mysql-server.v0/sql/plan"
+)
+
+var (
+	createDatabaseStmtRegex = regexp.MustCompile(
+		"(?is)^create\\s+(?:database|schema)\\s+(if\\s+not\\s+exists\\s+)?(`[^`]+`|[^\\s`]+)" +
+			"((\\s+default)?\\s+(character\\s+set|charset|collate)(\\s*=\\s*|\\s+)\\S+)*$",
+	)
+	dropDatabaseStmtRegex = regexp.MustCompile(
+		"(?is)^drop\\s+(?:database|schema)\\s+(if\\s+exists\\s+)?(`[^`]+`|[^\\s`]+)$",
+	)
+)
+
+// parseCreateDatabase parses a CREATE DATABASE statement. Character set
+// and collation options are accepted but ignored.
+func parseCreateDatabase(query string) (sql.Node, error) {
+	m := createDatabaseStmtRegex.FindStringSubmatch(query)
+	if m == nil {
+		return nil, ErrUnsupportedSyntax.New(query)
+	}
+
+	return plan.NewCreateDatabase(unquoteIdent(m[2]), m[1] != ""), nil
+}
+
+// parseDropDatabase parses a DROP DATABASE statement.
+func parseDropDatabase(query string) (sql.Node, error) {
+	m := dropDatabaseStmtRegex.FindStringSubmatch(query)
+	if m == nil {
+		return nil, ErrUnsupportedSyntax.New(query)
+	}
+
+	return plan.NewDropDatabase(unquoteIdent(m[2]), m[1] != ""), nil
+}
@@ -14,7 +14,9 @@ import (
 	"gopkg.in/src-d/go-mysql-server.v0/sql"
 	"gopkg.in/src-d/go-mysql-server.v0/sql/expression"
 	"gopkg.in/src-d/go-mysql-server.v0/sql/expression/function"
+	"gopkg.in/src-d/go-mysql-server.v0/sql/expression/function/aggregation"
 	"gopkg.in/src-d/go-mysql-server.v0/sql/plan"
+	"gopkg.in/src-d/go-vitess.v1/sqltypes"
 	"gopkg.in/src-d/go-vitess.v1/vt/sqlparser"
 )
 
@@ -26,6 +28,9 @@ var (
 	ErrUnsupportedFeature = errors.NewKind("unsupported feature: %s")
 
 	// ErrUnsupportedSubqueryExpression is thrown because subqueries are not supported, yet.
+	//
+	// Deprecated: subquery expressions are supported now, so it's never
+	// returned.
 	ErrUnsupportedSubqueryExpression = errors.NewKind("unsupported subquery expression")
 
 	// ErrInvalidSQLValType is returned when a SQLVal type is not valid.
@@ -33,6 +38,46 @@ var (
 
 	// ErrInvalidSortOrder is returned when a sort order is not valid.
 	ErrInvalidSortOrder = errors.NewKind("invalod sort order: %s")
+
+	// ErrInvalidDecimalType is returned when the precision or scale of a
+	// DECIMAL column is not valid.
+	ErrInvalidDecimalType = errors.NewKind("invalid precision and scale for DECIMAL(%d,%d)")
+
+	// ErrColumnLengthTooBig is returned when the length of a column is
+	// greater than the maximum length of its type.
+	ErrColumnLengthTooBig = errors.NewKind("column length %d too big for %s (max = %d)")
+
+	// ErrMissingColumnLength is returned when a column of a type that needs
+	// a length has none.
+	ErrMissingColumnLength = errors.NewKind("missing length for column of type %s")
+
+	// ErrDatetimePrecisionTooBig is returned when the fractional seconds
+	// precision of a DATETIME column is greater than the maximum.
+	ErrDatetimePrecisionTooBig = errors.NewKind("too big precision %d for DATETIME (max = %d)")
+
+	// ErrDuplicateEnumValue is returned when a value appears more than once
+	// in the definition of an ENUM or SET column.
+	ErrDuplicateEnumValue = errors.NewKind("duplicated value '%s' in %s")
+
+	// ErrTooManySetValues is returned when a SET column has more values
+	// than the maximum.
+	ErrTooManySetValues = errors.NewKind("too many values for SET: %d (max = %d)")
+
+	// ErrKeyColumnNotFound is returned when a column of a key in a CREATE
+	// TABLE is not one of the columns of the table.
+	ErrKeyColumnNotFound = errors.NewKind("key column '%s' doesn't exist in table")
+
+	// ErrMultipleAutoIncrement is returned when a CREATE TABLE has more than
+	// one AUTO_INCREMENT column.
+	ErrMultipleAutoIncrement = errors.NewKind("there can be only one AUTO_INCREMENT column")
+
+	// ErrInvalidDefaultValue is returned when the default value of a column
+	// in a CREATE TABLE is not valid for the type of the column.
+	ErrInvalidDefaultValue = errors.NewKind("invalid default value for '%s'")
+
+	// ErrDuplicateKeyName is returned when a CREATE TABLE has more than one
+	// unique key with the same name.
+	ErrDuplicateKeyName = errors.NewKind("Duplicate key name '%s'")
 )
 
 var (
@@ -43,11 +88,26 @@ var (
 	showCreateRegex      = regexp.MustCompile(`^show create\s+\S+\s*`)
 	showVariablesRegex   = regexp.MustCompile(`^show\s+(.*)?variables\s*`)
 	showWarningsRegex    = regexp.MustCompile(`^show\s+warnings\s*`)
+	showCollationRegex   = regexp.MustCompile(`^show\s+collation\b`)
+	showCharsetRegex     = regexp.MustCompile(`^show\s+(character\s+set|charset)\b`)
 	describeRegex        = regexp.MustCompile(`^(describe|desc|explain)\s+(.*)\s+`)
 	fullProcessListRegex = regexp.MustCompile(`^show\s+(full\s+)?processlist$`)
 	unlockTablesRegex    = regexp.MustCompile(`^unlock\s+tables$`)
 	lockTablesRegex      = regexp.MustCompile(`^lock\s+tables\s`)
 	setRegex             = regexp.MustCompile(`^set\s+`)
+	alterTableRegex      = regexp.MustCompile(`^alter\s+table\s+\S+\s+(add|drop|modify|change)\s+`)
+	createDatabaseRegex  = regexp.MustCompile(`^create\s+(database|schema)\s+`)
+	dropDatabaseRegex    = regexp.MustCompile(`^drop\s+(database|schema)\s+`)
+	beginRegex           = regexp.MustCompile(`^(begin(\s+work)?|start\s+transaction)$`)
+	commitRegex          = regexp.MustCompile(`^commit(\s+work)?$`)
+	rollbackRegex        = regexp.MustCompile(`^rollback(\s+work)?$`)
+	setOperationRegex    = regexp.MustCompile(`\b(intersect|except)\b`)
+	createTableRegex     = regexp.MustCompile(`^create\s+table\s+`)
+	tableConstraintRegex = regexp.MustCompile(`\b(check|foreign\s+key)\b`)
+	createViewRegex      = regexp.MustCompile(`^create\s+(or\s+replace\s+)?view\s+`)
+	dropViewRegex        = regexp.MustCompile(`^drop\s+view\s+`)
+	withRegex            = regexp.MustCompile(`^with\s+`)
+	windowRegex          = regexp.MustCompile(`\bover\b`)
 )
 
 // Parse parses the given SQL sentence and returns the corresponding node.
@@ -66,6 +126,11 @@ func Parse(ctx *sql.Context, query string) (sql.Node, error) {
 	}
 
 	lowerQuery := strings.ToLower(s)
+	if strings.Contains(lowerQuery, windowFunctionName) {
+		if err := checkWindowFunction(s); err != nil {
+			return nil, err
+		}
+	}
 
 	switch true {
 	case describeTablesRegex.MatchString(lowerQuery):
@@ -82,6 +147,10 @@ func Parse(ctx *sql.Context, query string) (sql.Node, error) {
 		return parseShowVariables(ctx, s)
 	case showWarningsRegex.MatchString(lowerQuery):
 		return parseShowWarnings(ctx, s)
+	case showCollationRegex.MatchString(lowerQuery):
+		return parseShowCollation(s)
+	case showCharsetRegex.MatchString(lowerQuery):
+		return parseShowCharacterSet(s)
 	case describeRegex.MatchString(lowerQuery):
 		return parseDescribeQuery(ctx, s)
 	case fullProcessListRegex.MatchString(lowerQuery):
@@ -90,10 +159,43 @@ func Parse(ctx *sql.Context, query string) (sql.Node, error) {
 		return plan.NewUnlockTables(), nil
 	case lockTablesRegex.MatchString(lowerQuery):
 		return parseLockTables(ctx, s)
+	case alterTableRegex.MatchString(lowerQuery):
+		return parseAlterTable(s)
+	case createTableRegex.MatchString(lowerQuery) && tableConstraintRegex.MatchString(lowerQuery):
+		return parseCreateTable(s)
+	case createViewRegex.MatchString(lowerQuery):
+		return parseCreateView(ctx, s)
+	case dropViewRegex.MatchString(lowerQuery):
+		return parseDropView(s)
+	case withRegex.MatchString(lowerQuery):
+		return parseWith(ctx, s)
+	case createDatabaseRegex.MatchString(lowerQuery):
+		return parseCreateDatabase(s)
+	case dropDatabaseRegex.MatchString(lowerQuery):
+		return parseDropDatabase(s)
+	case beginRegex.MatchString(lowerQuery):
+		return plan.NewBeginTransaction(), nil
+	case commitRegex.MatchString(lowerQuery):
+		return plan.NewCommit(), nil
+	case rollbackRegex.MatchString(lowerQuery):
+		return plan.NewRollback(), nil
 	case setRegex.MatchString(lowerQuery):
 		s = fixSetQuery(s)
 	}
 
+	if windowRegex.MatchString(lowerQuery) {
+		var err error
+		if s, err = rewriteWindows(s); err != nil {
+			return nil, err
+		}
+	}
+
+	if setOperationRegex.MatchString(lowerQuery) {
+		if ops := findSetOperations(s); len(ops) > 0 {
+			return parseSetOperations(ctx, s, ops)
+		}
+	}
+
 	stmt, err := sqlparser.Parse(s)
 	if err != nil {
 		return nil, err
@@ -134,8 +236,14 @@ func convert(ctx *sql.Context, stmt sqlparser.Statement, query string) (sql.Node
 		return convertShow(n, query)
 	case *sqlparser.Select:
 		return convertSelect(ctx, n)
+	case *sqlparser.Union:
+		return convertUnion(ctx, n)
 	case *sqlparser.Insert:
 		return convertInsert(ctx, n)
+	case *sqlparser.Update:
+		return convertUpdate(ctx, n)
+	case *sqlparser.Delete:
+		return convertDelete(ctx, n)
 	case *sqlparser.DDL:
 		return convertDDL(n)
 	case *sqlparser.Set:
@@ -210,7 +318,11 @@ func convertSet(ctx *sql.Context, n *sqlparser.Set) (sql.Node, error) {
 func convertShow(s *sqlparser.Show, query string) (sql.Node, error) {
 	switch s.Type {
 	case sqlparser.KeywordString(sqlparser.TABLES):
-		return plan.NewShowTables(sql.UnresolvedDatabase("")), nil
+		var full bool
+		if s.ShowTablesOpt != nil {
+			full = s.ShowTablesOpt.Full != ""
+		}
+		return plan.NewShowTables(sql.UnresolvedDatabase(""), full), nil
 	case sqlparser.KeywordString(sqlparser.DATABASES):
 		return plan.NewShowDatabases(), nil
 	case sqlparser.KeywordString(sqlparser.FIELDS), sqlparser.KeywordString(sqlparser.COLUMNS):
@@ -258,10 +370,6 @@ func convertSelect(ctx *sql.Context, s *sqlparser.Select) (sql.Node, error) {
 		return nil, err
 	}
 
-	if s.Having != nil {
-		return nil, ErrUnsupportedFeature.New("HAVING")
-	}
-
 	if s.Where != nil {
 		node, err = whereToFilter(s.Where, node)
 		if err != nil {
@@ -274,19 +382,71 @@ func convertSelect(ctx *sql.Context, s *sqlparser.Select) (sql.Node, error) {
 		return nil, err
 	}
 
+	if s.Having != nil {
+		node, err = havingToHaving(s.Having, node)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	if s.Distinct != "" {
 		node = plan.NewDistinct(node)
 	}
 
-	if len(s.OrderBy) != 0 {
-		node, err = orderByToSort(s.OrderBy, node)
+	return orderByAndLimitToNode(ctx, s.OrderBy, s.Limit, node)
+}
+
+func convertSelectStatement(ctx *sql.Context, ss sqlparser.SelectStatement) (sql.Node, error) {
+	switch s := ss.(type) {
+	case *sqlparser.Select:
+		return convertSelect(ctx, s)
+	case *sqlparser.Union:
+		return convertUnion(ctx, s)
+	case *sqlparser.ParenSelect:
+		return convertSelectStatement(ctx, s.Select)
+	default:
+		return nil, ErrUnsupportedSyntax.New(ss)
+	}
+}
+
+// convertUnion converts a UNION of queries. The sql_select_limit variable
+// only applies to the result of the union, so the queries are converted
+// using an empty context.
+func convertUnion(ctx *sql.Context, u *sqlparser.Union) (sql.Node, error) {
+	left, err := convertSelectStatement(sql.NewEmptyContext(), u.Left)
+	if err != nil {
+		return nil, err
+	}
+
+	right, err := convertSelectStatement(sql.NewEmptyContext(), u.Right)
+	if err != nil {
+		return nil, err
+	}
+
+	node, err := setOperationToNode(u.Type, left, right)
+	if err != nil {
+		return nil, err
+	}
+
+	return orderByAndLimitToNode(ctx, u.OrderBy, u.Limit, node)
+}
+
+func orderByAndLimitToNode(
+	ctx *sql.Context,
+	ob sqlparser.OrderBy,
+	l *sqlparser.Limit,
+	node sql.Node,
+) (sql.Node, error) {
+	var err error
+	if len(ob) != 0 {
+		node, err = orderByToSort(ob, node)
 		if err != nil {
 			return nil, err
 		}
 	}
 
-	if s.Limit != nil {
-		node, err = limitToLimit(ctx, s.Limit.Rowcount, node)
+	if l != nil {
+		node, err = limitToLimit(ctx, l.Rowcount, node)
 		if err != nil {
 			return nil, err
 		}
@@ -295,8 +455,8 @@ func convertSelect(ctx *sql.Context, s *sqlparser.Select) (sql.Node, error) {
 		node = plan.NewLimit(int64(limit), node)
 	}
 
-	if s.Limit != nil && s.Limit.Offset != nil {
-		node, err = offsetToOffset(ctx, s.Limit.Offset, node)
+	if l != nil && l.Offset != nil {
+		node, err = offsetToOffset(ctx, l.Offset, node)
 		if err != nil {
 			return nil, err
 		}
@@ -309,13 +469,29 @@ func convertDDL(c *sqlparser.DDL) (sql.Node, error) {
 	switch c.Action {
 	case sqlparser.CreateStr:
 		return convertCreateTable(c)
+	case sqlparser.DropStr:
+		return plan.NewDropTable(
+			sql.UnresolvedDatabase(c.Table.Qualifier.String()),
+			c.Table.Name.String(),
+			c.IfExists,
+		), nil
+	case sqlparser.RenameStr:
+		return plan.NewRenameTable(
+			sql.UnresolvedDatabase(c.Table.Qualifier.String()),
+			c.Table.Name.String(),
+			c.NewName.Name.String(),
+		), nil
+	case sqlparser.TruncateStr:
+		return plan.NewTruncate(
+			plan.NewUnresolvedTable(c.Table.Name.String(), c.Table.Qualifier.String()),
+		), nil
 	default:
 		return nil, ErrUnsupportedSyntax.New(c)
 	}
 }
 
 func convertCreateTable(c *sqlparser.DDL) (sql.Node, error) {
-	schema, err := columnDefinitionToSchema(c.TableSpec.Columns)
+	schema, err := tableSpecToSchema(c.TableSpec)
 	if err != nil {
 		return nil, err
 	}
@@ -324,48 +500,443 @@ func convertCreateTable(c *sqlparser.DDL) (sql.Node, error) {
 		sql.UnresolvedDatabase(""), c.Table.Name.String(), schema), nil
 }
 
-func convertInsert(ctx *sql.Context, i *sqlparser.Insert) (sql.Node, error) {
-	if len(i.OnDup) > 0 {
-		return nil, ErrUnsupportedFeature.New("ON DUPLICATE KEY")
+func tableSpecToSchema(spec *sqlparser.TableSpec) (sql.Schema, error) {
+	schema, err := columnDefinitionToSchema(spec.Columns)
+	if err != nil {
+		return nil, err
 	}
 
-	if len(i.Ignore) > 0 {
-		return nil, ErrUnsupportedSyntax.New(i)
+	if err := indexDefinitionsToSchema(schema, spec.Indexes); err != nil {
+		return nil, err
 	}
 
+	return schema, nil
+}
+
+func convertInsert(ctx *sql.Context, i *sqlparser.Insert) (sql.Node, error) {
 	src, err := insertRowsToNode(ctx, i.Rows)
 	if err != nil {
 		return nil, err
 	}
 
+	var onDupExprs []sql.Expression
+	if len(i.OnDup) > 0 {
+		onDupExprs, err = updateExprsToExpressions(sqlparser.UpdateExprs(i.OnDup))
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	return plan.NewInsertInto(
 		plan.NewUnresolvedTable(i.Table.Name.String(), i.Table.Qualifier.String()),
 		src,
+		i.Action == sqlparser.ReplaceStr,
+		len(i.Ignore) > 0,
 		columnsToStrings(i.Columns),
+		onDupExprs,
 	), nil
 }
 
+func convertUpdate(ctx *sql.Context, u *sqlparser.Update) (sql.Node, error) {
+	node, err := writeTargetToNode(ctx, u.TableExprs, u.Where, u.OrderBy, u.Limit)
+	if err != nil {
+		return nil, err
+	}
+
+	updateExprs, err := updateExprsToExpressions(u.Exprs)
+	if err != nil {
+		return nil, err
+	}
+
+	return plan.NewUpdate(node, updateExprs), nil
+}
+
+func convertDelete(ctx *sql.Context, d *sqlparser.Delete) (sql.Node, error) {
+	if len(d.Targets) > 0 {
+		return nil, ErrUnsupportedFeature.New("multiple-table DELETE")
+	}
+
+	if len(d.Partitions) > 0 {
+		return nil, ErrUnsupportedFeature.New("DELETE with PARTITION")
+	}
+
+	node, err := writeTargetToNode(ctx, d.TableExprs, d.Where, d.OrderBy, d.Limit)
+	if err != nil {
+		return nil, err
+	}
+
+	return plan.NewDelete(node), nil
+}
+
+// writeTargetToNode returns the node with the rows an UPDATE or DELETE
+// statement will modify, that is, the table filtered by the WHERE clause,
+// then sorted and limited.
+func writeTargetToNode(
+	ctx *sql.Context,
+	te sqlparser.TableExprs,
+	where *sqlparser.Where,
+	ob sqlparser.OrderBy,
+	limit *sqlparser.Limit,
+) (sql.Node, error) {
+	if len(te) != 1 {
+		return nil, ErrUnsupportedFeature.New("multiple tables in UPDATE or DELETE")
+	}
+
+	if _, ok := te[0].(*sqlparser.AliasedTableExpr); !ok {
+		return nil, ErrUnsupportedSyntax.New(te[0])
+	}
+
+	node, err := tableExprsToTable(ctx, te)
+	if err != nil {
+		return nil, err
+	}
+
+	if where != nil {
+		node, err = whereToFilter(where, node)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if len(ob) != 0 {
+		node, err = orderByToSort(ob, node)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if limit != nil {
+		if limit.Offset != nil {
+			return nil, ErrUnsupportedFeature.New("OFFSET in UPDATE or DELETE")
+		}
+
+		node, err = limitToLimit(ctx, limit.Rowcount, node)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return node, nil
+}
+
+func updateExprsToExpressions(exprs sqlparser.UpdateExprs) ([]sql.Expression, error) {
+	var result = make([]sql.Expression, len(exprs))
+	for i, e := range exprs {
+		col, err := exprToExpression(e.Name)
+		if err != nil {
+			return nil, err
+		}
+
+		val, err := exprToExpression(e.Expr)
+		if err != nil {
+			return nil, err
+		}
+
+		result[i] = expression.NewSetField(col, val)
+	}
+
+	return result, nil
+}
+
 func columnDefinitionToSchema(colDef []*sqlparser.ColumnDefinition) (sql.Schema, error) {
 	var schema sql.Schema
+	var autoIncrement bool
 	for _, cd := range colDef {
 		typ := cd.Type
-		internalTyp, err := sql.MysqlTypeToType(typ.SQLType())
+		if typ.Autoincrement {
+			if autoIncrement {
+				return nil, ErrMultipleAutoIncrement.New()
+			}
+			autoIncrement = true
+		}
+
+		internalTyp, err := columnTypeToType(&typ)
+		if err != nil {
+			return nil, err
+		}
+
+		internalTyp, err = columnCollation(internalTyp, &typ)
+		if err != nil {
+			return nil, err
+		}
+
+		primaryKey := typ.KeyOpt == columnKeyPrimary
+		nullable := !bool(typ.NotNull) && !primaryKey
+		def, err := columnDefault(cd.Name.String(), internalTyp, nullable, typ.Default)
 		if err != nil {
 			return nil, err
 		}
 
 		schema = append(schema, &sql.Column{
-			Nullable: !bool(typ.NotNull),
-			Type:     internalTyp,
-			Name:     cd.Name.String(),
-			// TODO
-			Default: nil,
+			Nullable:      nullable,
+			Type:          internalTyp,
+			Name:          cd.Name.String(),
+			PrimaryKey:    primaryKey,
+			Unique:        typ.KeyOpt == columnKeyUnique || typ.KeyOpt == columnKeyUniqueKey,
+			AutoIncrement: bool(typ.Autoincrement),
+			Default:       def,
 		})
 	}
 
 	return schema, nil
 }
 
+// columnDefault returns the default value of a column, converted to the
+// type of the column, or sql.CurrentTimestamp for DEFAULT CURRENT_TIMESTAMP.
+// It returns an error if the value is not valid for the column.
+func columnDefault(name string, t sql.Type, nullable bool, def *sqlparser.SQLVal) (interface{}, error) {
+	if def == nil {
+		return nil, nil
+	}
+
+	// NULL and CURRENT_TIMESTAMP are keywords, which the parser keeps in
+	// lower case.
+	if def.Type == sqlparser.ValArg {
+		switch string(def.Val) {
+		case "null":
+			if !nullable {
+				return nil, ErrInvalidDefaultValue.New(name)
+			}
+			return nil, nil
+		case "current_timestamp":
+			if t.Type() != sqltypes.Timestamp && t.Type() != sqltypes.Datetime {
+				return nil, ErrInvalidDefaultValue.New(name)
+			}
+			return sql.CurrentTimestamp, nil
+		}
+	}
+
+	e, err := convertVal(def)
+	if err != nil {
+		return nil, err
+	}
+
+	v, err := e.Eval(sql.NewEmptyContext(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	v, err = t.Convert(v)
+	if err != nil {
+		return nil, ErrInvalidDefaultValue.New(name)
+	}
+
+	return v, nil
+}
+
+// Key options of column definitions. The parser doesn't export them, so
+// they must be kept in the same order as in the parser.
+const (
+	columnKeyPrimary sqlparser.ColumnKeyOption = iota + 1
+	columnKeySpatialKey
+	columnKeyUnique
+	columnKeyUniqueKey
+)
+
+// indexDefinitionsToSchema sets the primary and unique keys defined in the
+// given index definitions of a CREATE TABLE in the columns of the schema.
+// Other kinds of indexes are ignored.
+func indexDefinitionsToSchema(schema sql.Schema, indexes []*sqlparser.IndexDefinition) error {
+	var names = make(map[string]bool)
+	for _, idx := range indexes {
+		if !idx.Info.Primary && !idx.Info.Unique {
+			continue
+		}
+
+		name := idx.Info.Name.String()
+		if !idx.Info.Primary && len(idx.Columns) > 1 {
+			if names[strings.ToLower(name)] {
+				return ErrDuplicateKeyName.New(name)
+			}
+			names[strings.ToLower(name)] = true
+		}
+
+		for _, ic := range idx.Columns {
+			var col *sql.Column
+			for _, c := range schema {
+				if ic.Column.EqualString(c.Name) {
+					col = c
+					break
+				}
+			}
+
+			if col == nil {
+				return ErrKeyColumnNotFound.New(ic.Column.String())
+			}
+
+			switch {
+			case idx.Info.Primary:
+				col.PrimaryKey = true
+				col.Nullable = false
+			case len(idx.Columns) > 1:
+				col.UniqueKeys = append(col.UniqueKeys, name)
+			default:
+				col.Unique = true
+			}
+		}
+	}
+
+	return nil
+}
+
+func columnTypeToType(ct *sqlparser.ColumnType) (sql.Type, error) {
+	switch strings.ToLower(ct.Type) {
+	case "decimal", "numeric":
+		return decimalColumnType(ct)
+	case "char", "binary":
+		return stringColumnType(ct, 1, sql.CharMaxLength)
+	case "varchar", "varbinary":
+		if ct.Length == nil {
+			return nil, ErrMissingColumnLength.New(ct.Type)
+		}
+		return stringColumnType(ct, 0, sql.VarCharMaxLength)
+	case "enum", "set":
+		return enumColumnType(ct)
+	case "datetime":
+		return datetimeColumnType(ct)
+	case "time":
+		if ct.Length != nil && string(ct.Length.Val) != "0" {
+			return nil, ErrUnsupportedFeature.New("fractional seconds in TIME")
+		}
+		return sql.Time, nil
+	default:
+		return sql.MysqlTypeToType(ct.SQLType())
+	}
+}
+
+// columnCollation returns the given type using the collation or the
+// character set of the column definition, if it has any. Types without
+// collation, such as ENUM or SET, are returned as they are.
+func columnCollation(t sql.Type, ct *sqlparser.ColumnType) (sql.Type, error) {
+	if ct.Collate == "" && ct.Charset == "" {
+		return t, nil
+	}
+
+	if _, ok := sql.CollationOf(t); !ok {
+		return t, nil
+	}
+
+	if ct.Collate != "" {
+		c, err := sql.ParseCollation(ct.Collate)
+		if err != nil {
+			return nil, err
+		}
+		return sql.WithCollation(t, c)
+	}
+
+	cs, err := sql.ParseCharacterSet(ct.Charset)
+	if err != nil {
+		return nil, err
+	}
+	return sql.WithCollation(t, cs.DefaultCollation())
+}
+
+// stringColumnType returns the type of a CHAR, VARCHAR, BINARY or VARBINARY
+// column with the length of its definition, or the given default length if
+// it has none.
+func stringColumnType(ct *sqlparser.ColumnType, length, maxLength int) (sql.Type, error) {
+	if ct.Length != nil {
+		n, err := strconv.Atoi(string(ct.Length.Val))
+		if err != nil {
+			return nil, err
+		}
+		length = n
+	}
+
+	if length > maxLength {
+		return nil, ErrColumnLengthTooBig.New(length, ct.Type, maxLength)
+	}
+
+	switch strings.ToLower(ct.Type) {
+	case "char":
+		return sql.Char(length), nil
+	case "binary":
+		return sql.Binary(length), nil
+	case "varchar":
+		return sql.VarChar(length), nil
+	default:
+		return sql.VarBinary(length), nil
+	}
+}
+
+// enumColumnType returns the type of an ENUM or SET column with the values
+// of its definition.
+func enumColumnType(ct *sqlparser.ColumnType) (sql.Type, error) {
+	values := make([]string, len(ct.EnumValues))
+	seen := make(map[string]struct{}, len(ct.EnumValues))
+	for i, v := range ct.EnumValues {
+		// The parser keeps the quotes of the values.
+		if len(v) >= 2 && v[0] == '\'' && v[len(v)-1] == '\'' {
+			v = v[1 : len(v)-1]
+		}
+
+		key := strings.ToLower(strings.TrimRight(v, " "))
+		if _, ok := seen[key]; ok {
+			return nil, ErrDuplicateEnumValue.New(v, strings.ToUpper(ct.Type))
+		}
+		seen[key] = struct{}{}
+
+		values[i] = v
+	}
+
+	if strings.ToLower(ct.Type) == "enum" {
+		return sql.Enum(values...), nil
+	}
+
+	if len(values) > sql.SetMaxValues {
+		return nil, ErrTooManySetValues.New(len(values), sql.SetMaxValues)
+	}
+
+	return sql.Set(values...), nil
+}
+
+// datetimeColumnType returns the type of a DATETIME column with the
+// fractional seconds precision of its definition, which defaults to 0.
+func datetimeColumnType(ct *sqlparser.ColumnType) (sql.Type, error) {
+	var fsp int
+	if ct.Length != nil {
+		n, err := strconv.Atoi(string(ct.Length.Val))
+		if err != nil {
+			return nil, err
+		}
+		fsp = n
+	}
+
+	if fsp > sql.DatetimeMaxPrecision {
+		return nil, ErrDatetimePrecisionTooBig.New(fsp, sql.DatetimeMaxPrecision)
+	}
+
+	return sql.Datetime(fsp), nil
+}
+
+// decimalColumnType returns the type of a DECIMAL(p,s) or NUMERIC(p,s)
+// column. As in MySQL, the precision defaults to 10 and the scale to 0.
+func decimalColumnType(ct *sqlparser.ColumnType) (sql.Type, error) {
+	precision, scale := 10, 0
+	if ct.Length != nil {
+		n, err := strconv.Atoi(string(ct.Length.Val))
+		if err != nil {
+			return nil, err
+		}
+		precision = n
+	}
+
+	if ct.Scale != nil {
+		n, err := strconv.Atoi(string(ct.Scale.Val))
+		if err != nil {
+			return nil, err
+		}
+		scale = n
+	}
+
+	if precision < 1 || precision > sql.DecimalMaxPrecision ||
+		scale > sql.DecimalMaxScale || scale > precision {
+		return nil, ErrInvalidDecimalType.New(precision, scale)
+	}
+
+	return sql.Decimal(precision, scale), nil
+}
+
 func columnsToStrings(cols sqlparser.Columns) []string {
 	res := make([]string, len(cols))
 	for i, c := range cols {
@@ -380,7 +951,7 @@ func insertRowsToNode(ctx *sql.Context, ir sqlparser.InsertRows) (sql.Node, erro
 	case *sqlparser.Select:
 		return convertSelect(ctx, v)
 	case *sqlparser.Union:
-		return nil, ErrUnsupportedFeature.New("UNION")
+		return convertUnion(ctx, v)
 	case sqlparser.Values:
 		return valuesToValues(v)
 	default:
@@ -469,7 +1040,10 @@ func tableExprToTable(
 		}
 	case *sqlparser.JoinTableExpr:
 		// TODO: add support for the rest of joins
-		if t.Join != sqlparser.JoinStr && t.Join != sqlparser.NaturalJoinStr {
+		switch t.Join {
+		case sqlparser.JoinStr, sqlparser.NaturalJoinStr,
+			sqlparser.LeftJoinStr, sqlparser.RightJoinStr:
+		default:
 			return nil, ErrUnsupportedFeature.New(t.Join)
 		}
 
@@ -501,7 +1075,15 @@ func tableExprToTable(
 		if err != nil {
 			return nil, err
 		}
-		return plan.NewInnerJoin(left, right, cond), nil
+
+		switch t.Join {
+		case sqlparser.LeftJoinStr:
+			return plan.NewLeftJoin(left, right, cond), nil
+		case sqlparser.RightJoinStr:
+			return plan.NewRightJoin(left, right, cond), nil
+		default:
+			return plan.NewInnerJoin(left, right, cond), nil
+		}
 	}
 }
 
@@ -514,6 +1096,21 @@ func whereToFilter(w *sqlparser.Where, child sql.Node) (*plan.Filter, error) {
 	return plan.NewFilter(c, child), nil
 }
 
+func havingToHaving(having *sqlparser.Where, node sql.Node) (*plan.Having, error) {
+	cond, err := exprToExpression(having.Expr)
+	if err != nil {
+		return nil, err
+	}
+
+	// An aggregation in the having clause turns the whole query into an
+	// aggregation, even if there is no aggregation in the projection.
+	if p, ok := node.(*plan.Project); ok && isAggregate(cond) {
+		node = plan.NewGroupBy(p.Projections, nil, p.Child)
+	}
+
+	return plan.NewHaving(cond, node), nil
+}
+
 func orderByToSort(ob sqlparser.OrderBy, child sql.Node) (*plan.Sort, error) {
 	var sortFields []plan.SortField
 	for _, o := range ob {
@@ -539,6 +1136,81 @@ func orderByToSort(ob sqlparser.OrderBy, child sql.Node) (*plan.Sort, error) {
 	return plan.NewSort(sortFields, child), nil
 }
 
+// distinctAggregation returns the aggregation with the given name and
+// arguments that only aggregates distinct values, such as
+// COUNT(DISTINCT x).
+func distinctAggregation(name string, args []sql.Expression) (sql.Expression, error) {
+	switch name {
+	case "count", "sum", "avg":
+		if len(args) != 1 {
+			return nil, ErrUnsupportedFeature.New("DISTINCT with more than one argument in " + name)
+		}
+
+		if _, ok := args[0].(*expression.Star); ok {
+			return nil, ErrUnsupportedSyntax.New(name + "(DISTINCT *)")
+		}
+	}
+
+	switch name {
+	case "count":
+		return aggregation.NewCountDistinct(args[0]), nil
+	case "sum":
+		return aggregation.NewSumDistinct(args[0]), nil
+	case "avg":
+		return aggregation.NewAvgDistinct(args[0]), nil
+	case "min", "max":
+		// The minimum and maximum of the distinct values are the same.
+		return expression.NewUnresolvedFunction(name, true, args...), nil
+	default:
+		return nil, ErrUnsupportedFeature.New("DISTINCT in " + name)
+	}
+}
+
+func groupConcatToExpression(g *sqlparser.GroupConcatExpr) (sql.Expression, error) {
+	exprs, err := selectExprsToExpressions(g.Exprs)
+	if err != nil {
+		return nil, err
+	}
+
+	var orderBy []aggregation.SortField
+	for _, o := range g.OrderBy {
+		e, err := exprToExpression(o.Expr)
+		if err != nil {
+			return nil, err
+		}
+
+		orderBy = append(orderBy, aggregation.SortField{
+			Column:     e,
+			Descending: o.Direction == sqlparser.DescScr,
+		})
+	}
+
+	return aggregation.NewGroupConcat(
+		g.Distinct != "",
+		exprs,
+		orderBy,
+		groupConcatSeparator(g.Separator),
+	), nil
+}
+
+// groupConcatSeparator returns the separator of GROUP_CONCAT as it is kept
+// by the SQL parser, that is, ` separator 'sep'` with the separator quoted
+// as a string literal, which is read with the tokenizer so it is unescaped
+// exactly as any other string.
+func groupConcatSeparator(s string) string {
+	tkn := sqlparser.NewStringTokenizer(s)
+	if typ, _ := tkn.Scan(); typ != sqlparser.SEPARATOR {
+		return aggregation.DefaultSeparator
+	}
+
+	typ, val := tkn.Scan()
+	if typ != sqlparser.STRING {
+		return aggregation.DefaultSeparator
+	}
+
+	return string(val)
+}
+
 func limitToLimit(
 	ctx *sql.Context,
 	limit sqlparser.Expr,
@@ -583,12 +1255,29 @@ func offsetToOffset(
 	return plan.NewOffset(n.(int64), child), nil
 }
 
+// aggregateFunctions are the aggregation functions that are not known as
+// such by the SQL parser.
+var aggregateFunctions = map[string]bool{
+	"approx_count_distinct": true,
+	"approx_percentile":     true,
+	"json_arrayagg":         true,
+	"json_objectagg":        true,
+}
+
+// isAggregate reports whether the given expression has an aggregation that
+// is not evaluated over a window.
 func isAggregate(e sql.Expression) bool {
 	var isAgg bool
 	expression.Inspect(e, func(e sql.Expression) bool {
-		fn, ok := e.(*expression.UnresolvedFunction)
-		if ok {
-			isAgg = isAgg || fn.IsAggregate
+		if _, ok := e.(*expression.Window); ok {
+			return false
+		}
+
+		switch e := e.(type) {
+		case *expression.UnresolvedFunction:
+			isAgg = isAgg || e.IsAggregate
+		case sql.Aggregation:
+			isAgg = true
 		}
 
 		return true
@@ -613,6 +1302,12 @@ func selectToProjectOrGroupBy(se sqlparser.SelectExprs, g sqlparser.GroupBy, chi
 	}
 
 	if isAgg {
+		for _, e := range selectExprs {
+			if hasWindow(e) {
+				return nil, ErrUnsupportedFeature.New("window functions in queries with GROUP BY or aggregations")
+			}
+		}
+
 		groupingExprs, err := groupByToExpressions(g)
 		if err != nil {
 			return nil, err
@@ -638,6 +1333,18 @@ func selectToProjectOrGroupBy(se sqlparser.SelectExprs, g sqlparser.GroupBy, chi
 	return plan.NewProject(selectExprs, child), nil
 }
 
+// hasWindow reports whether the given expression has a window.
+func hasWindow(e sql.Expression) bool {
+	var found bool
+	expression.Inspect(e, func(e sql.Expression) bool {
+		if _, ok := e.(*expression.Window); ok {
+			found = true
+		}
+		return !found
+	})
+	return found
+}
+
 func selectExprsToExpressions(se sqlparser.SelectExprs) ([]sql.Expression, error) {
 	var exprs []sql.Expression
 	for _, e := range se {
@@ -710,13 +1417,23 @@ func exprToExpression(e sqlparser.Expr) (sql.Expression, error) {
 		}
 		return expression.NewUnresolvedColumn(v.Name.String()), nil
 	case *sqlparser.FuncExpr:
+		if v.Name.Lowered() == windowFunctionName {
+			return windowToExpression(v)
+		}
+
 		exprs, err := selectExprsToExpressions(v.Exprs)
 		if err != nil {
 			return nil, err
 		}
 
+		if v.Distinct {
+			return distinctAggregation(v.Name.Lowered(), exprs)
+		}
+
 		return expression.NewUnresolvedFunction(v.Name.Lowered(),
-			v.IsAggregate(), exprs...), nil
+			v.IsAggregate() || aggregateFunctions[v.Name.Lowered()], exprs...), nil
+	case *sqlparser.GroupConcatExpr:
+		return groupConcatToExpression(v)
 	case *sqlparser.ParenExpr:
 		return exprToExpression(v.Expr)
 	case *sqlparser.AndExpr:
@@ -750,6 +1467,25 @@ func exprToExpression(e sqlparser.Expr) (sql.Expression, error) {
 		}
 
 		return expression.NewConvert(expr, v.Type.Type), nil
+	case *sqlparser.ValuesFuncExpr:
+		col, err := exprToExpression(v.Name)
+		if err != nil {
+			return nil, err
+		}
+
+		return expression.NewValues(col), nil
+	case *sqlparser.CollateExpr:
+		expr, err := exprToExpression(v.Expr)
+		if err != nil {
+			return nil, err
+		}
+
+		c, err := sql.ParseCollation(v.Charset)
+		if err != nil {
+			return nil, err
+		}
+
+		return expression.NewCollate(expr, c), nil
 	case *sqlparser.RangeCond:
 		val, err := exprToExpression(v.Left)
 		if err != nil {
@@ -790,12 +1526,30 @@ func exprToExpression(e sqlparser.Expr) (sql.Expression, error) {
 	case *sqlparser.UnaryExpr:
 		return unaryExprToExpression(v)
 	case *sqlparser.Subquery:
-		return nil, ErrUnsupportedSubqueryExpression.New()
+		return subqueryToExpression(v)
+	case *sqlparser.ExistsExpr:
+		subquery, err := subqueryToExpression(v.Subquery)
+		if err != nil {
+			return nil, err
+		}
+		return expression.NewExists(subquery), nil
 	case *sqlparser.CaseExpr:
 		return caseExprToExpression(v)
 	}
 }
 
+// subqueryToExpression converts a subquery used as an expression. The
+// sql_select_limit variable only applies to the top level query, so the
+// subquery is converted using an empty context.
+func subqueryToExpression(s *sqlparser.Subquery) (*expression.Subquery, error) {
+	node, err := convert(sql.NewEmptyContext(), s.Select, "")
+	if err != nil {
+		return nil, err
+	}
+
+	return expression.NewSubquery(node), nil
+}
+
 func convertVal(v *sqlparser.SQLVal) (sql.Expression, error) {
 	switch v.Type {
 	case sqlparser.StrVal:
@@ -833,7 +1587,7 @@ func convertVal(v *sqlparser.SQLVal) (sql.Expression, error) {
 		}
 		return expression.NewLiteral(val, sql.Blob), nil
 	case sqlparser.ValArg:
-		return expression.NewLiteral(string(v.Val), sql.Text), nil
+		return expression.NewBindVar(strings.TrimPrefix(string(v.Val), ":")), nil
 	case sqlparser.BitVal:
 		return expression.NewLiteral(v.Val[0] == '1', sql.Boolean), nil
 	}
@@ -890,8 +1644,14 @@ func comparisonExprToExpression(c *sqlparser.ComparisonExpr) (sql.Expression, er
 			expression.NewEquals(left, right),
 		), nil
 	case sqlparser.InStr:
+		if subquery, ok := right.(*expression.Subquery); ok {
+			return expression.NewInSubquery(left, subquery), nil
+		}
 		return expression.NewIn(left, right), nil
 	case sqlparser.NotInStr:
+		if subquery, ok := right.(*expression.Subquery); ok {
+			return expression.NewNot(expression.NewInSubquery(left, subquery)), nil
+		}
 		return expression.NewNotIn(left, right), nil
 	case sqlparser.LikeStr:
 		return expression.NewLike(left, right), nil
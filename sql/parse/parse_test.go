@@ -5,6 +5,7 @@ import (
 
 	errors "gopkg.in/src-d/go-errors.v1"
 	"gopkg.in/src-d/go-mysql-server.v0/sql/expression"
+	"gopkg.in/src-d/go-mysql-server.v0/sql/expression/function/aggregation"
 	"gopkg.in/src-d/go-mysql-server.v0/sql/plan"
 
 	"github.com/stretchr/testify/require"
@@ -33,7 +34,7 @@ var fixtures = map[string]sql.Node{
 			Nullable: true,
 		}, {
 			Name:     "e",
-			Type:     sql.Text,
+			Type:     sql.VarChar(20),
 			Nullable: true,
 		}, {
 			Name:     "f",
@@ -41,6 +42,154 @@ var fixtures = map[string]sql.Node{
 			Nullable: false,
 		}},
 	),
+	`CREATE TABLE t1(a DECIMAL(10,2) NOT NULL, b NUMERIC(5), c DECIMAL)`: plan.NewCreateTable(
+		sql.UnresolvedDatabase(""),
+		"t1",
+		sql.Schema{{
+			Name:     "a",
+			Type:     sql.Decimal(10, 2),
+			Nullable: false,
+		}, {
+			Name:     "b",
+			Type:     sql.Decimal(5, 0),
+			Nullable: true,
+		}, {
+			Name:     "c",
+			Type:     sql.Decimal(10, 0),
+			Nullable: true,
+		}},
+	),
+	`CREATE TABLE t1(a TINYINT, b SMALLINT UNSIGNED, c MEDIUMINT, d CHAR(3), e CHAR, f BINARY(4), g VARBINARY(10))`: plan.NewCreateTable(
+		sql.UnresolvedDatabase(""),
+		"t1",
+		sql.Schema{{
+			Name:     "a",
+			Type:     sql.Int8,
+			Nullable: true,
+		}, {
+			Name:     "b",
+			Type:     sql.Uint16,
+			Nullable: true,
+		}, {
+			Name:     "c",
+			Type:     sql.Int24,
+			Nullable: true,
+		}, {
+			Name:     "d",
+			Type:     sql.Char(3),
+			Nullable: true,
+		}, {
+			Name:     "e",
+			Type:     sql.Char(1),
+			Nullable: true,
+		}, {
+			Name:     "f",
+			Type:     sql.Binary(4),
+			Nullable: true,
+		}, {
+			Name:     "g",
+			Type:     sql.VarBinary(10),
+			Nullable: true,
+		}},
+	),
+	`CREATE TABLE t1(a DATETIME, b DATETIME(3), c TIME, d YEAR)`: plan.NewCreateTable(
+		sql.UnresolvedDatabase(""),
+		"t1",
+		sql.Schema{{
+			Name:     "a",
+			Type:     sql.Datetime(0),
+			Nullable: true,
+		}, {
+			Name:     "b",
+			Type:     sql.Datetime(3),
+			Nullable: true,
+		}, {
+			Name:     "c",
+			Type:     sql.Time,
+			Nullable: true,
+		}, {
+			Name:     "d",
+			Type:     sql.Year,
+			Nullable: true,
+		}},
+	),
+	`CREATE TABLE t1(a ENUM('x', 'y z'), b SET('a', 'b'))`: plan.NewCreateTable(
+		sql.UnresolvedDatabase(""),
+		"t1",
+		sql.Schema{{
+			Name:     "a",
+			Type:     sql.Enum("x", "y z"),
+			Nullable: true,
+		}, {
+			Name:     "b",
+			Type:     sql.Set("a", "b"),
+			Nullable: true,
+		}},
+	),
+	`CREATE TABLE t1(a VARCHAR(10) COLLATE utf8mb4_general_ci, b TEXT CHARACTER SET utf8, c TEXT COLLATE utf8_bin)`: plan.NewCreateTable(
+		sql.UnresolvedDatabase(""),
+		"t1",
+		sql.Schema{{
+			Name:     "a",
+			Type:     mustWithCollation(sql.VarChar(10), sql.CollationUtf8mb4GeneralCI),
+			Nullable: true,
+		}, {
+			Name:     "b",
+			Type:     sql.TextWithCollation(sql.CollationUtf8GeneralCI),
+			Nullable: true,
+		}, {
+			Name:     "c",
+			Type:     sql.Text,
+			Nullable: true,
+		}},
+	),
+	`DROP TABLE foo`: plan.NewDropTable(sql.UnresolvedDatabase(""), "foo", false),
+	`DROP TABLE IF EXISTS mydb.foo`: plan.NewDropTable(
+		sql.UnresolvedDatabase("mydb"),
+		"foo",
+		true,
+	),
+	`RENAME TABLE foo TO bar`:       plan.NewRenameTable(sql.UnresolvedDatabase(""), "foo", "bar"),
+	`ALTER TABLE foo RENAME TO bar`: plan.NewRenameTable(sql.UnresolvedDatabase(""), "foo", "bar"),
+	`TRUNCATE TABLE foo`:            plan.NewTruncate(plan.NewUnresolvedTable("foo", "")),
+	`TRUNCATE mydb.foo`:             plan.NewTruncate(plan.NewUnresolvedTable("foo", "mydb")),
+	`ALTER TABLE foo ADD COLUMN bar INTEGER NOT NULL`: plan.NewAddColumn(
+		plan.NewUnresolvedTable("foo", ""),
+		&sql.Column{Name: "bar", Type: sql.Int32, Source: "foo"},
+	),
+	`ALTER TABLE mydb.foo ADD bar TEXT`: plan.NewAddColumn(
+		plan.NewUnresolvedTable("foo", "mydb"),
+		&sql.Column{Name: "bar", Type: sql.Text, Nullable: true, Source: "foo"},
+	),
+	`ALTER TABLE foo DROP COLUMN bar`: plan.NewDropColumn(
+		plan.NewUnresolvedTable("foo", ""),
+		"bar",
+	),
+	"ALTER TABLE `foo` DROP `bar`": plan.NewDropColumn(
+		plan.NewUnresolvedTable("foo", ""),
+		"bar",
+	),
+	`ALTER TABLE foo MODIFY COLUMN bar TEXT`: plan.NewModifyColumn(
+		plan.NewUnresolvedTable("foo", ""),
+		"bar",
+		&sql.Column{Name: "bar", Type: sql.Text, Nullable: true, Source: "foo"},
+	),
+	`ALTER TABLE foo CHANGE bar baz BIGINT NOT NULL`: plan.NewModifyColumn(
+		plan.NewUnresolvedTable("foo", ""),
+		"bar",
+		&sql.Column{Name: "baz", Type: sql.Int64, Source: "foo"},
+	),
+	`CREATE DATABASE foo`:                      plan.NewCreateDatabase("foo", false),
+	"CREATE SCHEMA IF NOT EXISTS `foo`":        plan.NewCreateDatabase("foo", true),
+	`CREATE DATABASE foo DEFAULT CHARSET utf8`: plan.NewCreateDatabase("foo", false),
+	`DROP DATABASE foo`:                        plan.NewDropDatabase("foo", false),
+	`DROP SCHEMA IF EXISTS foo`:                plan.NewDropDatabase("foo", true),
+	`BEGIN`:                                    plan.NewBeginTransaction(),
+	`BEGIN WORK`:                               plan.NewBeginTransaction(),
+	`START TRANSACTION`:                        plan.NewBeginTransaction(),
+	`COMMIT`:                                   plan.NewCommit(),
+	`COMMIT WORK;`:                             plan.NewCommit(),
+	`ROLLBACK`:                                 plan.NewRollback(),
 	`DESCRIBE TABLE foo;`: plan.NewDescribe(
 		plan.NewUnresolvedTable("foo", ""),
 	),
@@ -212,6 +361,96 @@ var fixtures = map[string]sql.Node{
 		[]sql.Expression{},
 		plan.NewUnresolvedTable("t1", ""),
 	),
+	`SELECT COUNT(DISTINCT foo), GROUP_CONCAT(DISTINCT bar ORDER BY foo DESC SEPARATOR ';') FROM t1 GROUP BY baz`: plan.NewGroupBy(
+		[]sql.Expression{
+			aggregation.NewCountDistinct(expression.NewUnresolvedColumn("foo")),
+			aggregation.NewGroupConcat(
+				true,
+				[]sql.Expression{expression.NewUnresolvedColumn("bar")},
+				[]aggregation.SortField{
+					{Column: expression.NewUnresolvedColumn("foo"), Descending: true},
+				},
+				";",
+			),
+		},
+		[]sql.Expression{expression.NewUnresolvedColumn("baz")},
+		plan.NewUnresolvedTable("t1", ""),
+	),
+	`SELECT GROUP_CONCAT(foo SEPARATOR 'it\'s \\ ''') FROM t1`: plan.NewGroupBy(
+		[]sql.Expression{
+			aggregation.NewGroupConcat(
+				false,
+				[]sql.Expression{expression.NewUnresolvedColumn("foo")},
+				nil,
+				`it's \ '`,
+			),
+		},
+		[]sql.Expression{},
+		plan.NewUnresolvedTable("t1", ""),
+	),
+	`SELECT GROUP_CONCAT(foo, bar) FROM t1`: plan.NewGroupBy(
+		[]sql.Expression{
+			aggregation.NewGroupConcat(
+				false,
+				[]sql.Expression{
+					expression.NewUnresolvedColumn("foo"),
+					expression.NewUnresolvedColumn("bar"),
+				},
+				nil,
+				",",
+			),
+		},
+		[]sql.Expression{},
+		plan.NewUnresolvedTable("t1", ""),
+	),
+	`SELECT JSON_ARRAYAGG(foo) FROM t1`: plan.NewGroupBy(
+		[]sql.Expression{
+			expression.NewUnresolvedFunction("json_arrayagg", true,
+				expression.NewUnresolvedColumn("foo")),
+		},
+		[]sql.Expression{},
+		plan.NewUnresolvedTable("t1", ""),
+	),
+	`SELECT MAX(DISTINCT foo) FROM t1`: plan.NewGroupBy(
+		[]sql.Expression{
+			expression.NewUnresolvedFunction("max", true,
+				expression.NewUnresolvedColumn("foo")),
+		},
+		[]sql.Expression{},
+		plan.NewUnresolvedTable("t1", ""),
+	),
+	`SELECT foo FROM t1 GROUP BY foo HAVING COUNT(*) > 5`: plan.NewHaving(
+		expression.NewGreaterThan(
+			expression.NewUnresolvedFunction("count", true, expression.NewStar()),
+			expression.NewLiteral(int64(5), sql.Int64),
+		),
+		plan.NewGroupBy(
+			[]sql.Expression{expression.NewUnresolvedColumn("foo")},
+			[]sql.Expression{expression.NewUnresolvedColumn("foo")},
+			plan.NewUnresolvedTable("t1", ""),
+		),
+	),
+	`SELECT foo FROM t1 HAVING COUNT(*) > 5`: plan.NewHaving(
+		expression.NewGreaterThan(
+			expression.NewUnresolvedFunction("count", true, expression.NewStar()),
+			expression.NewLiteral(int64(5), sql.Int64),
+		),
+		plan.NewGroupBy(
+			[]sql.Expression{expression.NewUnresolvedColumn("foo")},
+			nil,
+			plan.NewUnresolvedTable("t1", ""),
+		),
+	),
+	`SELECT foo FROM t1 HAVING foo > 5`: plan.NewHaving(
+		expression.NewGreaterThan(
+			expression.NewUnresolvedColumn("foo"),
+			expression.NewLiteral(int64(5), sql.Int64),
+		),
+		plan.NewProject(
+			[]sql.Expression{expression.NewUnresolvedColumn("foo")},
+			plan.NewUnresolvedTable("t1", ""),
+		),
+	),
 	`SELECT a FROM t1 where a regexp '.*test.*';`: plan.NewProject(
 		[]sql.Expression{
 			expression.NewUnresolvedColumn("a"),
@@ -244,9 +483,461 @@ var fixtures = map[string]sql.Node{
 			expression.NewLiteral("a", sql.Text),
 			expression.NewLiteral(int64(1), sql.Int64),
 		}}),
+		false,
+		false,
 		[]string{"col1", "col2"},
+		nil,
 	),
-	`SHOW TABLES`: plan.NewShowTables(sql.UnresolvedDatabase("")),
+	`INSERT INTO t1 (col1, col2) VALUES (?, ?)`: plan.NewInsertInto(
+		plan.NewUnresolvedTable("t1", ""),
+		plan.NewValues([][]sql.Expression{{
+			expression.NewBindVar("v1"),
+			expression.NewBindVar("v2"),
+		}}),
+		false,
+		false,
+		[]string{"col1", "col2"},
+		nil,
+	),
+	`INSERT IGNORE INTO t1 (col1) VALUES (1)`: plan.NewInsertInto(
+		plan.NewUnresolvedTable("t1", ""),
+		plan.NewValues([][]sql.Expression{{
+			expression.NewLiteral(int64(1), sql.Int64),
+		}}),
+		false,
+		true,
+		[]string{"col1"},
+		nil,
+	),
+	`REPLACE INTO t1 (col1) VALUES (1)`: plan.NewInsertInto(
+		plan.NewUnresolvedTable("t1", ""),
+		plan.NewValues([][]sql.Expression{{
+			expression.NewLiteral(int64(1), sql.Int64),
+		}}),
+		true,
+		false,
+		[]string{"col1"},
+		nil,
+	),
+	`INSERT INTO t1 (a, b) VALUES (1, 2) ON DUPLICATE KEY UPDATE b = VALUES(b) + b`: plan.NewInsertInto(
+		plan.NewUnresolvedTable("t1", ""),
+		plan.NewValues([][]sql.Expression{{
+			expression.NewLiteral(int64(1), sql.Int64),
+			expression.NewLiteral(int64(2), sql.Int64),
+		}}),
+		false,
+		false,
+		[]string{"a", "b"},
+		[]sql.Expression{
+			expression.NewSetField(
+				expression.NewUnresolvedColumn("b"),
+				expression.NewPlus(
+					expression.NewValues(expression.NewUnresolvedColumn("b")),
+					expression.NewUnresolvedColumn("b"),
+				),
+			),
+		},
+	),
+	`CREATE TABLE t1(a INT PRIMARY KEY, b TEXT UNIQUE)`: plan.NewCreateTable(
+		sql.UnresolvedDatabase(""),
+		"t1",
+		sql.Schema{{
+			Name:       "a",
+			Type:       sql.Int32,
+			PrimaryKey: true,
+		}, {
+			Name:     "b",
+			Type:     sql.Text,
+			Nullable: true,
+			Unique:   true,
+		}},
+	),
+	`CREATE TABLE t1(a INT, b INT NOT NULL, c TEXT, PRIMARY KEY (a, b), UNIQUE KEY c_key (c))`: plan.NewCreateTable(
+		sql.UnresolvedDatabase(""),
+		"t1",
+		sql.Schema{{
+			Name:       "a",
+			Type:       sql.Int32,
+			PrimaryKey: true,
+		}, {
+			Name:       "b",
+			Type:       sql.Int32,
+			PrimaryKey: true,
+		}, {
+			Name:     "c",
+			Type:     sql.Text,
+			Nullable: true,
+			Unique:   true,
+		}},
+	),
+	`CREATE TABLE t1(a INT, b INT, c INT, UNIQUE KEY bc (c, b), UNIQUE KEY ab (a, b))`: plan.NewCreateTable(
+		sql.UnresolvedDatabase(""),
+		"t1",
+		sql.Schema{{
+			Name:       "a",
+			Type:       sql.Int32,
+			Nullable:   true,
+			UniqueKeys: []string{"ab"},
+		}, {
+			Name:       "b",
+			Type:       sql.Int32,
+			Nullable:   true,
+			UniqueKeys: []string{"bc", "ab"},
+		}, {
+			Name:       "c",
+			Type:       sql.Int32,
+			Nullable:   true,
+			UniqueKeys: []string{"bc"},
+		}},
+	),
+	`CREATE TABLE t1(a INT NOT NULL AUTO_INCREMENT PRIMARY KEY, b TEXT)`: plan.NewCreateTable(
+		sql.UnresolvedDatabase(""),
+		"t1",
+		sql.Schema{{
+			Name:          "a",
+			Type:          sql.Int32,
+			PrimaryKey:    true,
+			AutoIncrement: true,
+		}, {
+			Name:     "b",
+			Type:     sql.Text,
+			Nullable: true,
+		}},
+	),
+	`CREATE TABLE t1(a INT NOT NULL DEFAULT 5, b VARCHAR(10) DEFAULT 'x', c DATETIME DEFAULT CURRENT_TIMESTAMP, d TEXT DEFAULT NULL)`: plan.NewCreateTable(
+		sql.UnresolvedDatabase(""),
+		"t1",
+		sql.Schema{{
+			Name:    "a",
+			Type:    sql.Int32,
+			Default: int32(5),
+		}, {
+			Name:     "b",
+			Type:     sql.VarChar(10),
+			Nullable: true,
+			Default:  "x",
+		}, {
+			Name:     "c",
+			Type:     sql.Datetime(0),
+			Nullable: true,
+			Default:  sql.CurrentTimestamp,
+		}, {
+			Name:     "d",
+			Type:     sql.Text,
+			Nullable: true,
+		}},
+	),
+	`CREATE TABLE t1(a INT CHECK (a > 0), b INT, CONSTRAINT b_positive CHECK (b >= 0))`: plan.NewCreateTable(
+		sql.UnresolvedDatabase(""),
+		"t1",
+		sql.Schema{{
+			Name:     "a",
+			Type:     sql.Int32,
+			Nullable: true,
+		}, {
+			Name:     "b",
+			Type:     sql.Int32,
+			Nullable: true,
+		}},
+	).WithConstraints([]sql.CheckConstraint{{
+		Name: "t1_chk_1",
+		Expr: expression.NewGreaterThan(
+			expression.NewGetField(0, sql.Int32, "a", true),
+			expression.NewLiteral(int64(0), sql.Int64),
+		),
+	}, {
+		Name: "b_positive",
+		Expr: expression.NewGreaterThanOrEqual(
+			expression.NewGetField(1, sql.Int32, "b", true),
+			expression.NewLiteral(int64(0), sql.Int64),
+		),
+	}}, nil),
+	"CREATE TABLE t2(a INT, b INT, FOREIGN KEY (a) REFERENCES t1 (a), CONSTRAINT `fk` FOREIGN KEY (b) REFERENCES t1 (b) ON DELETE SET NULL ON UPDATE RESTRICT)": plan.NewCreateTable(
+		sql.UnresolvedDatabase(""),
+		"t2",
+		sql.Schema{{
+			Name:     "a",
+			Type:     sql.Int32,
+			Nullable: true,
+		}, {
+			Name:     "b",
+			Type:     sql.Int32,
+			Nullable: true,
+		}},
+	).WithConstraints(nil, []sql.ForeignKey{{
+		Name:              "t2_ibfk_1",
+		Columns:           []string{"a"},
+		ReferencedTable:   "t1",
+		ReferencedColumns: []string{"a"},
+		OnDelete:          sql.ForeignKeyNoAction,
+	}, {
+		Name:              "fk",
+		Columns:           []string{"b"},
+		ReferencedTable:   "t1",
+		ReferencedColumns: []string{"b"},
+		OnDelete:          sql.ForeignKeySetNull,
+	}}),
+	`CREATE VIEW v AS SELECT a FROM t1`: plan.NewCreateView(
+		sql.UnresolvedDatabase(""),
+		"v",
+		plan.NewProject(
+			[]sql.Expression{expression.NewUnresolvedColumn("a")},
+			plan.NewUnresolvedTable("t1", ""),
+		),
+		"SELECT a FROM t1",
+		false,
+	),
+	"CREATE OR REPLACE VIEW mydb.`v` AS SELECT a FROM t1": plan.NewCreateView(
+		sql.UnresolvedDatabase("mydb"),
+		"v",
+		plan.NewProject(
+			[]sql.Expression{expression.NewUnresolvedColumn("a")},
+			plan.NewUnresolvedTable("t1", ""),
+		),
+		"SELECT a FROM t1",
+		true,
+	),
+	`DROP VIEW IF EXISTS v, mydb.w`: plan.NewDropView(
+		[]sql.ViewKey{{Database: "", Name: "v"}, {Database: "mydb", Name: "w"}},
+		true,
+	),
+	`SHOW CREATE VIEW mydb.v`: plan.NewShowCreateView("mydb", "v"),
+	`SHOW FULL TABLES`:        plan.NewShowTables(sql.UnresolvedDatabase(""), true),
+	`SELECT a, ROW_NUMBER() OVER (PARTITION BY b ORDER BY c DESC) FROM t1`: plan.NewProject(
+		[]sql.Expression{
+			expression.NewUnresolvedColumn("a"),
+			expression.NewWindow(
+				expression.NewUnresolvedFunction("row_number", false),
+				[]sql.Expression{expression.NewUnresolvedColumn("b")},
+				[]expression.WindowSortField{
+					{Column: expression.NewUnresolvedColumn("c"), Descending: true},
+				},
+				nil,
+			),
+		},
+		plan.NewUnresolvedTable("t1", ""),
+	),
+	`SELECT SUM(a) OVER (ORDER BY b ROWS BETWEEN 1 PRECEDING AND CURRENT ROW) AS s FROM t1`: plan.NewProject(
+		[]sql.Expression{
+			expression.NewAlias(
+				expression.NewWindow(
+					expression.NewUnresolvedFunction("sum", true, expression.NewUnresolvedColumn("a")),
+					nil,
+					[]expression.WindowSortField{
+						{Column: expression.NewUnresolvedColumn("b")},
+					},
+					&expression.WindowFrame{
+						Unit:  expression.RowsFrame,
+						Start: expression.WindowFrameBound{Type: expression.Preceding, Offset: 1},
+						End:   expression.WindowFrameBound{Type: expression.CurrentRow},
+					},
+				),
+				"s",
+			),
+		},
+		plan.NewUnresolvedTable("t1", ""),
+	),
+	`SELECT LAG(a, 2, 'x') OVER (RANGE UNBOUNDED PRECEDING) FROM t1`: plan.NewProject(
+		[]sql.Expression{
+			expression.NewWindow(
+				expression.NewUnresolvedFunction(
+					"lag",
+					false,
+					expression.NewUnresolvedColumn("a"),
+					expression.NewLiteral(int64(2), sql.Int64),
+					expression.NewLiteral("x", sql.Text),
+				),
+				nil,
+				nil,
+				&expression.WindowFrame{
+					Unit:  expression.RangeFrame,
+					Start: expression.WindowFrameBound{Type: expression.UnboundedPreceding},
+					End:   expression.WindowFrameBound{Type: expression.CurrentRow},
+				},
+			),
+		},
+		plan.NewUnresolvedTable("t1", ""),
+	),
+	`SELECT ROW_NUMBER() OVER (PARTITION BY CONCAT(a, 'it\'s', "\\")) FROM t1`: plan.NewProject(
+		[]sql.Expression{
+			expression.NewWindow(
+				expression.NewUnresolvedFunction("row_number", false),
+				[]sql.Expression{
+					expression.NewUnresolvedFunction(
+						"concat",
+						false,
+						expression.NewUnresolvedColumn("a"),
+						expression.NewLiteral("it's", sql.Text),
+						expression.NewLiteral(`\`, sql.Text),
+					),
+				},
+				nil,
+				nil,
+			),
+		},
+		plan.NewUnresolvedTable("t1", ""),
+	),
+	`SELECT __window FROM t1`: plan.NewProject(
+		[]sql.Expression{expression.NewUnresolvedColumn("__window")},
+		plan.NewUnresolvedTable("t1", ""),
+	),
+	"SELECT ROW_NUMBER() OVER (ORDER BY a) # and not a) OVER (\nFROM t1": plan.NewProject(
+		[]sql.Expression{
+			expression.NewWindow(
+				expression.NewUnresolvedFunction("row_number", false),
+				nil,
+				[]expression.WindowSortField{
+					{Column: expression.NewUnresolvedColumn("a")},
+				},
+				nil,
+			),
+		},
+		plan.NewUnresolvedTable("t1", ""),
+	),
+	`WITH t AS (SELECT a FROM t1) SELECT a FROM t`: plan.NewProject(
+		[]sql.Expression{expression.NewUnresolvedColumn("a")},
+		plan.NewSubqueryAlias("t", plan.NewProject(
+			[]sql.Expression{expression.NewUnresolvedColumn("a")},
+			plan.NewUnresolvedTable("t1", ""),
+		)),
+	),
+	`WITH t (b) AS (SELECT a FROM t1), u AS (SELECT b FROM t) SELECT b FROM u`: plan.NewProject(
+		[]sql.Expression{expression.NewUnresolvedColumn("b")},
+		plan.NewSubqueryAlias("u", plan.NewProject(
+			[]sql.Expression{expression.NewUnresolvedColumn("b")},
+			plan.NewSubqueryAlias("t", plan.NewCommonTableExpression(
+				"t",
+				[]string{"b"},
+				plan.NewProject(
+					[]sql.Expression{expression.NewUnresolvedColumn("a")},
+					plan.NewUnresolvedTable("t1", ""),
+				),
+			)),
+		)),
+	),
+	`WITH t AS (SELECT a FROM t1) SELECT a FROM t UNION ALL SELECT a FROM t`: plan.NewUnion(
+		plan.NewProject(
+			[]sql.Expression{expression.NewUnresolvedColumn("a")},
+			plan.NewSubqueryAlias("t", plan.NewMaterialized(plan.NewProject(
+				[]sql.Expression{expression.NewUnresolvedColumn("a")},
+				plan.NewUnresolvedTable("t1", ""),
+			))),
+		),
+		plan.NewProject(
+			[]sql.Expression{expression.NewUnresolvedColumn("a")},
+			plan.NewSubqueryAlias("t", plan.NewMaterialized(plan.NewProject(
+				[]sql.Expression{expression.NewUnresolvedColumn("a")},
+				plan.NewUnresolvedTable("t1", ""),
+			))),
+		),
+	),
+	`WITH RECURSIVE t (n) AS (SELECT a FROM t1 UNION ALL SELECT n + 1 FROM t WHERE n < 5) SELECT n FROM t`: plan.NewProject(
+		[]sql.Expression{expression.NewUnresolvedColumn("n")},
+		plan.NewSubqueryAlias("t", plan.NewRecursiveCTE(
+			"t",
+			[]string{"n"},
+			plan.NewProject(
+				[]sql.Expression{expression.NewUnresolvedColumn("a")},
+				plan.NewUnresolvedTable("t1", ""),
+			),
+			plan.NewProject(
+				[]sql.Expression{expression.NewArithmetic(
+					expression.NewUnresolvedColumn("n"),
+					expression.NewLiteral(int64(1), sql.Int64),
+					"+",
+				)},
+				plan.NewFilter(
+					expression.NewLessThan(
+						expression.NewUnresolvedColumn("n"),
+						expression.NewLiteral(int64(5), sql.Int64),
+					),
+					plan.NewUnresolvedTable("t", ""),
+				),
+			),
+			false,
+		)),
+	),
+	`INSERT INTO t1 (col1, col2) VALUES (DEFAULT, 1)`: plan.NewInsertInto(
+		plan.NewUnresolvedTable("t1", ""),
+		plan.NewValues([][]sql.Expression{{
+			expression.NewDefaultColumn(""),
+			expression.NewLiteral(int64(1), sql.Int64),
+		}}),
+		false,
+		false,
+		[]string{"col1", "col2"},
+		nil,
+	),
+	`SELECT a FROM t1 WHERE b = :name`: plan.NewProject(
+		[]sql.Expression{expression.NewUnresolvedColumn("a")},
+		plan.NewFilter(
+			expression.NewEquals(
+				expression.NewUnresolvedColumn("b"),
+				expression.NewBindVar("name"),
+			),
+			plan.NewUnresolvedTable("t1", ""),
+		),
+	),
+	`UPDATE t1 SET a = a + 1, b = 'x' WHERE c > 2`: plan.NewUpdate(
+		plan.NewFilter(
+			expression.NewGreaterThan(
+				expression.NewUnresolvedColumn("c"),
+				expression.NewLiteral(int64(2), sql.Int64),
+			),
+			plan.NewUnresolvedTable("t1", ""),
+		),
+		[]sql.Expression{
+			expression.NewSetField(
+				expression.NewUnresolvedColumn("a"),
+				expression.NewPlus(
+					expression.NewUnresolvedColumn("a"),
+					expression.NewLiteral(int64(1), sql.Int64),
+				),
+			),
+			expression.NewSetField(
+				expression.NewUnresolvedColumn("b"),
+				expression.NewLiteral("x", sql.Text),
+			),
+		},
+	),
+	`UPDATE t1 SET a = 1 ORDER BY b DESC LIMIT 2`: plan.NewUpdate(
+		plan.NewLimit(2,
+			plan.NewSort(
+				[]plan.SortField{{
+					Column: expression.NewUnresolvedColumn("b"),
+					Order:  plan.Descending,
+				}},
+				plan.NewUnresolvedTable("t1", ""),
+			),
+		),
+		[]sql.Expression{
+			expression.NewSetField(
+				expression.NewUnresolvedColumn("a"),
+				expression.NewLiteral(int64(1), sql.Int64),
+			),
+		},
+	),
+	`DELETE FROM t1 WHERE a = 1`: plan.NewDelete(
+		plan.NewFilter(
+			expression.NewEquals(
+				expression.NewUnresolvedColumn("a"),
+				expression.NewLiteral(int64(1), sql.Int64),
+			),
+			plan.NewUnresolvedTable("t1", ""),
+		),
+	),
+	`DELETE FROM t1 ORDER BY a LIMIT 1`: plan.NewDelete(
+		plan.NewLimit(1,
+			plan.NewSort(
+				[]plan.SortField{{
+					Column: expression.NewUnresolvedColumn("a"),
+					Order:  plan.Ascending,
+				}},
+				plan.NewUnresolvedTable("t1", ""),
+			),
+		),
+	),
+	`SHOW TABLES`: plan.NewShowTables(sql.UnresolvedDatabase(""), false),
 	`SELECT DISTINCT foo, bar FROM foo;`: plan.NewDistinct(
 		plan.NewProject(
 			[]sql.Expression{
@@ -420,6 +1111,75 @@ var fixtures = map[string]sql.Node{
 			),
 		),
 	),
+	`SELECT * FROM foo LEFT JOIN bar ON a = b`: plan.NewProject(
+		[]sql.Expression{expression.NewStar()},
+		plan.NewLeftJoin(
+			plan.NewUnresolvedTable("foo", ""),
+			plan.NewUnresolvedTable("bar", ""),
+			expression.NewEquals(
+				expression.NewUnresolvedColumn("a"),
+				expression.NewUnresolvedColumn("b"),
+			),
+		),
+	),
+	`SELECT * FROM foo RIGHT OUTER JOIN bar ON a = b`: plan.NewProject(
+		[]sql.Expression{expression.NewStar()},
+		plan.NewRightJoin(
+			plan.NewUnresolvedTable("foo", ""),
+			plan.NewUnresolvedTable("bar", ""),
+			expression.NewEquals(
+				expression.NewUnresolvedColumn("a"),
+				expression.NewUnresolvedColumn("b"),
+			),
+		),
+	),
+	`SELECT * FROM mytable WHERE i IN (SELECT i FROM foo)`: plan.NewProject(
+		[]sql.Expression{expression.NewStar()},
+		plan.NewFilter(
+			expression.NewInSubquery(
+				expression.NewUnresolvedColumn("i"),
+				expression.NewSubquery(plan.NewProject(
+					[]sql.Expression{expression.NewUnresolvedColumn("i")},
+					plan.NewUnresolvedTable("foo", ""),
+				)),
+			),
+			plan.NewUnresolvedTable("mytable", ""),
+		),
+	),
+	`SELECT * FROM mytable WHERE i NOT IN (SELECT i FROM foo)`: plan.NewProject(
+		[]sql.Expression{expression.NewStar()},
+		plan.NewFilter(
+			expression.NewNot(expression.NewInSubquery(
+				expression.NewUnresolvedColumn("i"),
+				expression.NewSubquery(plan.NewProject(
+					[]sql.Expression{expression.NewUnresolvedColumn("i")},
+					plan.NewUnresolvedTable("foo", ""),
+				)),
+			)),
+			plan.NewUnresolvedTable("mytable", ""),
+		),
+	),
+	`SELECT * FROM mytable WHERE EXISTS (SELECT i FROM foo)`: plan.NewProject(
+		[]sql.Expression{expression.NewStar()},
+		plan.NewFilter(
+			expression.NewExists(
+				expression.NewSubquery(plan.NewProject(
+					[]sql.Expression{expression.NewUnresolvedColumn("i")},
+					plan.NewUnresolvedTable("foo", ""),
+				)),
+			),
+			plan.NewUnresolvedTable("mytable", ""),
+		),
+	),
+	`SELECT (SELECT i FROM foo) FROM mytable`: plan.NewProject(
+		[]sql.Expression{
+			expression.NewSubquery(plan.NewProject(
+				[]sql.Expression{expression.NewUnresolvedColumn("i")},
+				plan.NewUnresolvedTable("foo", ""),
+			)),
+		},
+		plan.NewUnresolvedTable("mytable", ""),
+	),
 	`SELECT foo.a FROM foo`: plan.NewProject(
 		[]sql.Expression{
 			expression.NewUnresolvedQualifiedColumn("foo", "a"),
@@ -432,6 +1192,15 @@ var fixtures = map[string]sql.Node{
 		},
 		plan.NewUnresolvedTable("foo", ""),
 	),
+	`SELECT a COLLATE utf8mb4_general_ci = 'A' FROM foo`: plan.NewProject(
+		[]sql.Expression{
+			expression.NewEquals(
+				expression.NewCollate(expression.NewUnresolvedColumn("a"), sql.CollationUtf8mb4GeneralCI),
+				expression.NewLiteral("A", sql.Text),
+			),
+		},
+		plan.NewUnresolvedTable("foo", ""),
+	),
 	`SELECT 2 = 2 FROM foo`: plan.NewProject(
 		[]sql.Expression{
 			expression.NewEquals(expression.NewLiteral(int64(2), sql.Int64), expression.NewLiteral(int64(2), sql.Int64)),
@@ -835,6 +1604,10 @@ var fixtures = map[string]sql.Node{
 	`SHOW VARIABLES LIKE 'gtid_mode'`:          plan.NewShowVariables(sql.NewEmptyContext().GetAll(), "gtid_mode"),
 	`SHOW SESSION VARIABLES LIKE 'autocommit'`: plan.NewShowVariables(sql.NewEmptyContext().GetAll(), "autocommit"),
 	`UNLOCK TABLES`:                            plan.NewUnlockTables(),
+	`SHOW COLLATION`:                           plan.NewShowCollation(""),
+	`SHOW COLLATION LIKE 'utf8%'`:              plan.NewShowCollation("utf8%"),
+	`SHOW CHARACTER SET`:                       plan.NewShowCharacterSet(""),
+	`SHOW CHARSET LIKE 'utf8mb4'`:              plan.NewShowCharacterSet("utf8mb4"),
 	`LOCK TABLES foo READ`: plan.NewLockTables([]*plan.TableLock{
 		{Table: plan.NewUnresolvedTable("foo", "")},
 	}),
@@ -945,6 +1718,94 @@ var fixtures = map[string]sql.Node{
 		)},
 		plan.NewUnresolvedTable("dual", ""),
 	),
+	`SELECT a FROM t1 UNION SELECT b FROM t2`: plan.NewDistinct(
+		plan.NewUnion(
+			plan.NewProject(
+				[]sql.Expression{expression.NewUnresolvedColumn("a")},
+				plan.NewUnresolvedTable("t1", ""),
+			),
+			plan.NewProject(
+				[]sql.Expression{expression.NewUnresolvedColumn("b")},
+				plan.NewUnresolvedTable("t2", ""),
+			),
+		),
+	),
+	`SELECT a FROM t1 UNION ALL SELECT b FROM t2 ORDER BY a LIMIT 5`: plan.NewLimit(5,
+		plan.NewSort(
+			[]plan.SortField{
+				{
+					Column:       expression.NewUnresolvedColumn("a"),
+					Order:        plan.Ascending,
+					NullOrdering: plan.NullsFirst,
+				},
+			},
+			plan.NewUnion(
+				plan.NewProject(
+					[]sql.Expression{expression.NewUnresolvedColumn("a")},
+					plan.NewUnresolvedTable("t1", ""),
+				),
+				plan.NewProject(
+					[]sql.Expression{expression.NewUnresolvedColumn("b")},
+					plan.NewUnresolvedTable("t2", ""),
+				),
+			),
+		),
+	),
+	`SELECT a FROM t1 INTERSECT SELECT b FROM t2`: plan.NewIntersect(
+		plan.NewDistinct(
+			plan.NewProject(
+				[]sql.Expression{expression.NewUnresolvedColumn("a")},
+				plan.NewUnresolvedTable("t1", ""),
+			),
+		),
+		plan.NewProject(
+			[]sql.Expression{expression.NewUnresolvedColumn("b")},
+			plan.NewUnresolvedTable("t2", ""),
+		),
+	),
+	`SELECT a FROM t1 UNION SELECT b FROM t2 INTERSECT SELECT c FROM t3`: plan.NewDistinct(
+		plan.NewUnion(
+			plan.NewProject(
+				[]sql.Expression{expression.NewUnresolvedColumn("a")},
+				plan.NewUnresolvedTable("t1", ""),
+			),
+			plan.NewIntersect(
+				plan.NewDistinct(
+					plan.NewProject(
+						[]sql.Expression{expression.NewUnresolvedColumn("b")},
+						plan.NewUnresolvedTable("t2", ""),
+					),
+				),
+				plan.NewProject(
+					[]sql.Expression{expression.NewUnresolvedColumn("c")},
+					plan.NewUnresolvedTable("t3", ""),
+				),
+			),
+		),
+	),
+	`SELECT a FROM t1 EXCEPT ALL SELECT b FROM t2 ORDER BY a`: plan.NewSort(
+		[]plan.SortField{
+			{
+				Column:       expression.NewUnresolvedColumn("a"),
+				Order:        plan.Ascending,
+				NullOrdering: plan.NullsFirst,
+			},
+		},
+		plan.NewExcept(
+			plan.NewProject(
+				[]sql.Expression{expression.NewUnresolvedColumn("a")},
+				plan.NewUnresolvedTable("t1", ""),
+			),
+			plan.NewProject(
+				[]sql.Expression{expression.NewUnresolvedColumn("b")},
+				plan.NewUnresolvedTable("t2", ""),
+			),
+		),
+	),
+	`SELECT 'except' FROM t1`: plan.NewProject(
+		[]sql.Expression{expression.NewLiteral("except", sql.Text)},
+		plan.NewUnresolvedTable("t1", ""),
+	),
 }
 
 func TestParse(t *testing.T) {
@@ -962,10 +1823,46 @@ func TestParse(t *testing.T) {
 }
 
 var fixturesErrors = map[string]*errors.Kind{
-	`SHOW METHEMONEY`:                                      ErrUnsupportedFeature,
-	`LOCK TABLES foo AS READ`:                              errUnexpectedSyntax,
-	`LOCK TABLES foo LOW_PRIORITY READ`:                    errUnexpectedSyntax,
-	`SELECT * FROM mytable WHERE i IN (SELECT i FROM foo)`: ErrUnsupportedSubqueryExpression,
+	`SHOW METHEMONEY`:                                                             ErrUnsupportedFeature,
+	`DELETE t1, t2 FROM t1 JOIN t2`:                                               ErrUnsupportedFeature,
+	`UPDATE t1 SET a = 1 LIMIT 1, 2`:                                              ErrUnsupportedFeature,
+	`ALTER TABLE foo ADD INDEX (bar)`:                                             ErrUnsupportedFeature,
+	`ALTER TABLE foo DROP bar, DROP baz`:                                          ErrUnsupportedSyntax,
+	`ALTER TABLE foo ADD  ;`:                                                      ErrUnsupportedSyntax,
+	`LOCK TABLES foo AS READ`:                                                     errUnexpectedSyntax,
+	`CREATE DATABASE foo bar`:                                                     ErrUnsupportedSyntax,
+	`DROP DATABASE foo, bar`:                                                      ErrUnsupportedSyntax,
+	`LOCK TABLES foo LOW_PRIORITY READ`:                                           errUnexpectedSyntax,
+	`CREATE TABLE t1(a DECIMAL(66,2))`:                                            ErrInvalidDecimalType,
+	`CREATE TABLE t1(a DECIMAL(5,6))`:                                             ErrInvalidDecimalType,
+	`CREATE TABLE t1(a CHAR(256))`:                                                ErrColumnLengthTooBig,
+	`CREATE TABLE t1(a DATETIME(7))`:                                              ErrDatetimePrecisionTooBig,
+	`CREATE TABLE t1(a TIME(3))`:                                                  ErrUnsupportedFeature,
+	`CREATE TABLE t1(a ENUM('a', 'A'))`:                                           ErrDuplicateEnumValue,
+	`CREATE TABLE t1(a SET('a', 'a'))`:                                            ErrDuplicateEnumValue,
+	`CREATE TABLE t1(a INT, PRIMARY KEY (b))`:                                     ErrKeyColumnNotFound,
+	`CREATE TABLE t1(a INT, b INT, UNIQUE KEY k (a, b), UNIQUE KEY K (b, a))`:     ErrDuplicateKeyName,
+	`CREATE TABLE t1(a INT AUTO_INCREMENT, b INT AUTO_INCREMENT)`:                 ErrMultipleAutoIncrement,
+	`CREATE TABLE t1(a INT DEFAULT 'x')`:                                          ErrInvalidDefaultValue,
+	`CREATE TABLE t1(a VARCHAR(2) DEFAULT 'abc')`:                                 ErrInvalidDefaultValue,
+	`CREATE TABLE t1(a INT NOT NULL DEFAULT NULL)`:                                ErrInvalidDefaultValue,
+	`CREATE TABLE t1(a INT DEFAULT CURRENT_TIMESTAMP)`:                            ErrInvalidDefaultValue,
+	`CREATE TABLE t1(a INT, CHECK (b > 0))`:                                       sql.ErrColumnNotFound,
+	`CREATE TABLE t2(a INT, FOREIGN KEY (a) REFERENCES t1 (a) ON UPDATE CASCADE)`: ErrUnsupportedFeature,
+	`CREATE VIEW v AS SELECT a FROM t1 WITH CHECK OPTION`:                         ErrUnsupportedFeature,
+	`CREATE VIEW v (a) AS SELECT a FROM t1`:                                       ErrUnsupportedSyntax,
+	`DROP VIEW v w`:                                                               ErrUnsupportedSyntax,
+	`SELECT ROW_NUMBER() OVER w FROM t1 WINDOW w AS (ORDER BY a)`:                 ErrUnsupportedFeature,
+	`SELECT SUM(a) OVER (ROWS BETWEEN CURRENT ROW AND 1 PRECEDING) FROM t1`:       ErrInvalidWindowFrame,
+	`SELECT a, COUNT(*), RANK() OVER (ORDER BY a) FROM t1 GROUP BY a`:             ErrUnsupportedFeature,
+	`SELECT __window(SUM(a), X'') FROM t1`:                                        sql.ErrFunctionNotFound,
+	"SELECT SUM(a) OVER (ORDER BY `__window`(b, 'c')) FROM t1":                    sql.ErrFunctionNotFound,
+	`SELECT CONCAT(DISTINCT a) FROM t1`:                                           ErrUnsupportedFeature,
+	`WITH t AS (SELECT 1), t AS (SELECT 2) SELECT * FROM t`:                       ErrDuplicateCTE,
+	`WITH RECURSIVE t AS (SELECT a FROM t) SELECT * FROM t`:                       ErrRecursiveCTEWithoutUnion,
+	`WITH RECURSIVE t AS (SELECT a FROM t UNION ALL SELECT 1) SELECT * FROM t`:    ErrRecursiveCTEOrder,
+	`CREATE TABLE t1(a TEXT COLLATE foo)`:                                         sql.ErrUnknownCollation,
+	`SELECT 'a' COLLATE foo`:                                                      sql.ErrUnknownCollation,
 	`SELECT * FROM files
 		JOIN commit_files
 		JOIN refs
@@ -1048,3 +1945,11 @@ func TestFixSetQuery(t *testing.T) {
 		})
 	}
 }
+
+func mustWithCollation(t sql.Type, c sql.Collation) sql.Type {
+	typ, err := sql.WithCollation(t, c)
+	if err != nil {
+		panic(err)
+	}
+	return typ
+}
@@ -0,0 +1,195 @@
+package parse
+
+import (
+	"strings"
+
+	"gopkg.in/src-d/go-mysql-server.v0/sql"
+	"gopkg.in/src-d/go-mysql-server.v0/sql/plan"
+	"gopkg.in/src-d/go-vitess.v1/vt/sqlparser"
+)
+
+const (
+	intersectStr         = "intersect"
+	intersectAllStr      = "intersect all"
+	intersectDistinctStr = "intersect distinct"
+	exceptStr            = "except"
+	exceptAllStr         = "except all"
+	exceptDistinctStr    = "except distinct"
+)
+
+// setOperationToNode returns the node performing the given set operation
+// between the left and right nodes.
+func setOperationToNode(op string, left, right sql.Node) (sql.Node, error) {
+	switch strings.ToLower(op) {
+	case sqlparser.UnionAllStr:
+		return plan.NewUnion(left, right), nil
+	case sqlparser.UnionStr, sqlparser.UnionDistinctStr:
+		return plan.NewDistinct(plan.NewUnion(left, right)), nil
+	case intersectAllStr:
+		return plan.NewIntersect(left, right), nil
+	case intersectStr, intersectDistinctStr:
+		return plan.NewIntersect(plan.NewDistinct(left), right), nil
+	case exceptAllStr:
+		return plan.NewExcept(left, right), nil
+	case exceptStr, exceptDistinctStr:
+		return plan.NewExcept(plan.NewDistinct(left), right), nil
+	default:
+		return nil, ErrUnsupportedFeature.New(op)
+	}
+}
+
+// setOperation is a set operation found at the top level of a query, with
+// the offsets where it starts and ends in the query.
+type setOperation struct {
+	op         string
+	start, end int
+}
+
+// isIntersect reports whether the set operation is an INTERSECT.
+func (op setOperation) isIntersect() bool {
+	return strings.HasPrefix(op.op, intersectStr)
+}
+
+// findSetOperations returns all the set operations that are at the top
+// level of the given query, that is, outside parenthesis, if any of them
+// is an INTERSECT or EXCEPT. Otherwise, it returns nothing, because the
+// SQL parser already supports UNION.
+func findSetOperations(query string) []setOperation {
+	var ops []setOperation
+	var unsupported bool
+	var depth int
+	tkn := sqlparser.NewStringTokenizer(query)
+	for {
+		typ, val := tkn.Scan()
+		switch typ {
+		case 0, sqlparser.LEX_ERROR:
+			if !unsupported {
+				return nil
+			}
+			return ops
+		case '(':
+			depth++
+			continue
+		case ')':
+			depth--
+			continue
+		}
+
+		word := strings.ToLower(string(val))
+		if depth > 0 || (word != sqlparser.UnionStr && word != intersectStr && word != exceptStr) {
+			continue
+		}
+
+		// The position of the tokenizer is right after the next character
+		// after the token. Quoted identifiers are skipped by checking the
+		// token is exactly the keyword.
+		end := tkn.Position - 1
+		start := end - len(word)
+		if start < 0 || !strings.EqualFold(query[start:end], word) {
+			continue
+		}
+
+		op := setOperation{op: word, start: start, end: end}
+		unsupported = unsupported || word != sqlparser.UnionStr
+
+		// The operation may be followed by ALL or DISTINCT.
+		next := *tkn
+		_, val = next.Scan()
+		modifier := strings.ToLower(string(val))
+		if modifier == "all" || modifier == "distinct" {
+			*tkn = next
+			op.op += " " + modifier
+			op.end = tkn.Position - 1
+		}
+
+		ops = append(ops, op)
+	}
+}
+
+// parseSetOperations parses a query with INTERSECT or EXCEPT operations,
+// which are not supported by the SQL parser. Each query between operations
+// is parsed on its own. As in MySQL, INTERSECT has a higher precedence than
+// UNION and EXCEPT, so "a UNION b INTERSECT c" is "a UNION (b INTERSECT c)",
+// and operations with the same precedence are applied from left to right.
+// The ORDER BY and LIMIT clauses of the last query, as well as the
+// sql_select_limit variable, are applied to the result of all the
+// operations, so the queries are converted using an empty context.
+func parseSetOperations(ctx *sql.Context, query string, ops []setOperation) (sql.Node, error) {
+	var stmts []sqlparser.SelectStatement
+	var pos int
+	for _, op := range ops {
+		stmt, err := parseSelectStatement(query[pos:op.start])
+		if err != nil {
+			return nil, err
+		}
+
+		stmts = append(stmts, stmt)
+		pos = op.end
+	}
+
+	last, err := parseSelectStatement(query[pos:])
+	if err != nil {
+		return nil, err
+	}
+	stmts = append(stmts, last)
+
+	var orderBy sqlparser.OrderBy
+	var limit *sqlparser.Limit
+	if s, ok := last.(*sqlparser.Select); ok {
+		orderBy, limit = s.OrderBy, s.Limit
+		s.OrderBy, s.Limit = nil, nil
+	}
+
+	// The operands of UNION and EXCEPT are the results of the consecutive
+	// INTERSECT operations, which are applied first.
+	node, err := convertSelectStatement(sql.NewEmptyContext(), stmts[0])
+	if err != nil {
+		return nil, err
+	}
+
+	var operands []sql.Node
+	var operations []setOperation
+	for i, op := range ops {
+		right, err := convertSelectStatement(sql.NewEmptyContext(), stmts[i+1])
+		if err != nil {
+			return nil, err
+		}
+
+		if !op.isIntersect() {
+			operands = append(operands, node)
+			operations = append(operations, op)
+			node = right
+			continue
+		}
+
+		node, err = setOperationToNode(op.op, node, right)
+		if err != nil {
+			return nil, err
+		}
+	}
+	operands = append(operands, node)
+
+	node = operands[0]
+	for i, op := range operations {
+		node, err = setOperationToNode(op.op, node, operands[i+1])
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return orderByAndLimitToNode(ctx, orderBy, limit, node)
+}
+
+func parseSelectStatement(query string) (sqlparser.SelectStatement, error) {
+	stmt, err := sqlparser.Parse(query)
+	if err != nil {
+		return nil, err
+	}
+
+	s, ok := stmt.(sqlparser.SelectStatement)
+	if !ok {
+		return nil, ErrUnsupportedSyntax.New(stmt)
+	}
+
+	return s, nil
+}
@@ -46,6 +46,23 @@ func parseShowCreate(s string) (sql.Node, error) {
 			sql.UnresolvedDatabase("").Name(),
 			nil,
 			name), nil
+	case "view":
+		var name string
+
+		err := parseFuncs{
+			readRemaining(&name),
+		}.exec(r)
+		if err != nil {
+			return nil, err
+		}
+
+		name = strings.TrimSpace(name)
+		if !viewNameRegex.MatchString(name) {
+			return nil, ErrUnsupportedSyntax.New(s)
+		}
+
+		db, view := splitTableName(name)
+		return plan.NewShowCreateView(db, view), nil
 	case "database", "schema":
 		var ifNotExists bool
 		var next string
@@ -0,0 +1,75 @@
+package parse
+
+import (
+	"regexp"
+	"strings"
+
+	"gopkg.in/src-d/go-mysql-server.v0/sql"
+	"gopkg.in/src-d/go-mysql-server.v0/sql/plan"
+)
+
+var (
+	createViewDefinitionRegex = regexp.MustCompile(
+		`(?is)^create\s+(or\s+replace\s+)?view\s+(\S+)\s+as\s+(.+)$`,
+	)
+	dropViewDefinitionRegex = regexp.MustCompile(
+		`(?is)^drop\s+view\s+(if\s+exists\s+)?(.+?)(?:\s+(?:restrict|cascade))?$`,
+	)
+	viewNameRegex    = regexp.MustCompile("^(`[^`]+`|[^\\s`.,()]+)(\\.(`[^`]+`|[^\\s`.,()]+))?$")
+	selectQueryRegex = regexp.MustCompile(`(?is)^(select\b|with\b|\()`)
+	checkOptionRegex = regexp.MustCompile(`(?is)\bwith\s+((cascaded|local)\s+)?check\s+option$`)
+)
+
+// parseCreateView parses a CREATE VIEW statement, as the SQL parser
+// ignores everything after the name of the view.
+func parseCreateView(ctx *sql.Context, query string) (sql.Node, error) {
+	m := createViewDefinitionRegex.FindStringSubmatch(query)
+	if m == nil || !viewNameRegex.MatchString(m[2]) {
+		return nil, ErrUnsupportedSyntax.New(query)
+	}
+
+	text := strings.TrimSpace(m[3])
+	if !selectQueryRegex.MatchString(text) {
+		return nil, ErrUnsupportedSyntax.New(query)
+	}
+
+	if checkOptionRegex.MatchString(text) {
+		return nil, ErrUnsupportedFeature.New("WITH CHECK OPTION")
+	}
+
+	definition, err := Parse(ctx, text)
+	if err != nil {
+		return nil, err
+	}
+
+	db, name := splitTableName(m[2])
+	return plan.NewCreateView(
+		sql.UnresolvedDatabase(db),
+		name,
+		definition,
+		text,
+		m[1] != "",
+	), nil
+}
+
+// parseDropView parses a DROP VIEW statement, which the SQL parser
+// confuses with DROP TABLE.
+func parseDropView(query string) (sql.Node, error) {
+	m := dropViewDefinitionRegex.FindStringSubmatch(query)
+	if m == nil {
+		return nil, ErrUnsupportedSyntax.New(query)
+	}
+
+	var views []sql.ViewKey
+	for _, name := range strings.Split(m[2], ",") {
+		name = strings.TrimSpace(name)
+		if !viewNameRegex.MatchString(name) {
+			return nil, ErrUnsupportedSyntax.New(query)
+		}
+
+		db, view := splitTableName(name)
+		views = append(views, sql.ViewKey{Database: db, Name: view})
+	}
+
+	return plan.NewDropView(views, m[1] != ""), nil
+}
@@ -0,0 +1,361 @@
+package parse
+
+import (
+	"encoding/hex"
+	"regexp"
+	"strconv"
+	"strings"
+
+	errors "gopkg.in/src-d/go-errors.v1"
+	"gopkg.in/src-d/go-mysql-server.v0/sql"
+	"gopkg.in/src-d/go-mysql-server.v0/sql/expression"
+	"gopkg.in/src-d/go-vitess.v1/vt/sqlparser"
+)
+
+// ErrInvalidWindowFrame is returned when the frame of a window is not valid.
+var ErrInvalidWindowFrame = errors.NewKind("invalid window frame: %s")
+
+// windowFunctionName is the name of the function the windows of a query
+// are rewritten to, so the SQL parser, which does not support OVER, can
+// parse them. Its arguments are the function of the window and the window
+// specification as a hexadecimal string, so it does not need any escaping.
+// Queries can't call it themselves, see checkWindowFunction.
+const windowFunctionName = "__window"
+
+var (
+	overClauseRegex  = regexp.MustCompile(`(?i)^over\s*\(`)
+	namedWindowRegex = regexp.MustCompile(`(?i)^over\s+[a-z_]`)
+	partitionByRegex = regexp.MustCompile(`(?is)^partition\s+by\s+`)
+	windowFrameRegex = regexp.MustCompile(`(?is)^(rows|range)\s+(?:between\s+(.+?)\s+and\s+(.+?)|(.+?))$`)
+	frameBoundRegex  = regexp.MustCompile(`(?is)^(?:unbounded\s+(preceding|following)|current\s+row|(\d+)\s+(preceding|following))$`)
+)
+
+// rewriteWindows rewrites the windows of the given query, that is,
+// `f(...) OVER (...)`, to calls to the window function with the function
+// and the window specification as a hexadecimal string, which are parsed by
+// windowToExpression. Quoted strings and comments are kept as they are.
+func rewriteWindows(query string) (string, error) {
+	var (
+		buf                 []byte
+		opens               []int
+		lastOpen, lastClose = -1, -1
+		quote               byte
+	)
+
+	for i := 0; i < len(query); i++ {
+		c := query[i]
+		if quote != 0 {
+			buf = append(buf, c)
+			if c == '\\' && quote != '`' && i+1 < len(query) {
+				i++
+				buf = append(buf, query[i])
+			} else if c == quote {
+				quote = 0
+			}
+			continue
+		}
+
+		if end := commentEnd(query, i); end >= 0 {
+			buf = append(buf, query[i:end]...)
+			i = end - 1
+			continue
+		}
+
+		switch {
+		case c == '\'' || c == '"' || c == '`':
+			quote = c
+		case c == '(':
+			opens = append(opens, len(buf))
+		case c == ')':
+			if len(opens) > 0 {
+				lastOpen, lastClose = opens[len(opens)-1], len(buf)
+				opens = opens[:len(opens)-1]
+			}
+		case (i == 0 || !isIdentChar(query[i-1])) && overClauseRegex.MatchString(query[i:]):
+			start := functionCallStart(buf, lastOpen, lastClose)
+			if start < 0 {
+				return "", ErrUnsupportedSyntax.New(query)
+			}
+
+			open := i + len(overClauseRegex.FindString(query[i:])) - 1
+			close := closingParen(query, open)
+			if close < 0 {
+				return "", ErrUnsupportedSyntax.New(query)
+			}
+
+			call := string(buf[start : lastClose+1])
+			spec := hex.EncodeToString([]byte(query[open+1 : close]))
+			buf = append(buf[:start], windowFunctionName+"("+call+", X'"+spec+"')"...)
+			lastOpen, lastClose = -1, -1
+			i = close
+			continue
+		case (i == 0 || !isIdentChar(query[i-1])) && namedWindowRegex.MatchString(query[i:]):
+			if functionCallStart(buf, lastOpen, lastClose) >= 0 {
+				return "", ErrUnsupportedFeature.New("named windows")
+			}
+		}
+
+		buf = append(buf, c)
+	}
+
+	return string(buf), nil
+}
+
+// commentEnd returns the position right after the end of the comment
+// starting at the given position of the query, or -1 if no comment starts
+// there. As in MySQL, a comment starting with `--` must be followed by a
+// whitespace or control character.
+func commentEnd(query string, i int) int {
+	switch {
+	case query[i] == '#',
+		strings.HasPrefix(query[i:], "--") && (i+2 == len(query) || query[i+2] <= ' '):
+		if end := strings.IndexByte(query[i:], '\n'); end >= 0 {
+			return i + end + 1
+		}
+		return len(query)
+	case strings.HasPrefix(query[i:], "/*"):
+		if end := strings.Index(query[i+2:], "*/"); end >= 0 {
+			return i + 2 + end + 2
+		}
+		return len(query)
+	default:
+		return -1
+	}
+}
+
+// functionCallStart returns the position in the given buffer where the
+// function call that ends at its end starts, or -1 if it does not end with
+// a function call. The positions of the parenthesis of the last call are
+// given.
+func functionCallStart(buf []byte, open, close int) int {
+	if close < 0 || len(strings.TrimRight(string(buf), " \t\r\n")) != close+1 {
+		return -1
+	}
+
+	start := open
+	for start > 0 && isIdentChar(buf[start-1]) {
+		start--
+	}
+
+	if start == open {
+		return -1
+	}
+
+	return start
+}
+
+// checkWindowFunction returns an error if the given query calls the window
+// function, which only rewriteWindows can write. Identifiers with its name
+// that are not followed by a parenthesis, such as columns, are allowed.
+func checkWindowFunction(query string) error {
+	var name []byte
+	tkn := sqlparser.NewStringTokenizer(query)
+	for {
+		typ, val := tkn.Scan()
+		switch typ {
+		case 0, sqlparser.LEX_ERROR:
+			return nil
+		case sqlparser.COMMENT:
+			continue
+		case '(':
+			if name != nil {
+				return sql.ErrFunctionNotFound.New(string(name))
+			}
+		}
+
+		name = nil
+		if typ == sqlparser.ID && strings.EqualFold(string(val), windowFunctionName) {
+			name = val
+		}
+	}
+}
+
+func isIdentChar(c byte) bool {
+	return c == '_' || c == '$' ||
+		(c >= 'a' && c <= 'z') ||
+		(c >= 'A' && c <= 'Z') ||
+		(c >= '0' && c <= '9')
+}
+
+// windowToExpression converts a call to the window function written by
+// rewriteWindows to a window expression.
+func windowToExpression(f *sqlparser.FuncExpr) (sql.Expression, error) {
+	exprs, err := selectExprsToExpressions(f.Exprs)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(exprs) != 2 {
+		return nil, ErrUnsupportedSyntax.New(f)
+	}
+
+	spec, ok := exprs[1].(*expression.Literal)
+	if !ok || spec.Type() != sql.Blob {
+		return nil, ErrUnsupportedSyntax.New(f)
+	}
+
+	return parseWindowSpec(exprs[0], string(spec.Value().([]byte)))
+}
+
+// parseWindowSpec returns the window of the given function with the given
+// specification, that is, `[PARTITION BY ...] [ORDER BY ...] [frame]`.
+func parseWindowSpec(fn sql.Expression, spec string) (*expression.Window, error) {
+	spec = strings.TrimSpace(spec)
+
+	var frame *expression.WindowFrame
+	if pos := findWindowFrame(spec); pos >= 0 {
+		var err error
+		frame, err = parseWindowFrame(spec[pos:])
+		if err != nil {
+			return nil, err
+		}
+
+		spec = strings.TrimSpace(spec[:pos])
+	}
+
+	var partitionBy []sql.Expression
+	var orderBy []expression.WindowSortField
+	if spec != "" {
+		// The PARTITION BY and ORDER BY clauses are parsed as the GROUP BY
+		// and ORDER BY of a query.
+		query := "SELECT 1 FROM dual "
+		if loc := partitionByRegex.FindStringIndex(spec); loc != nil {
+			query += "GROUP BY " + spec[loc[1]:]
+		} else {
+			query += spec
+		}
+
+		stmt, err := sqlparser.Parse(query)
+		if err != nil {
+			return nil, err
+		}
+
+		s, ok := stmt.(*sqlparser.Select)
+		if !ok || s.Where != nil || s.Having != nil || s.Limit != nil {
+			return nil, ErrUnsupportedSyntax.New(spec)
+		}
+
+		if len(s.GroupBy) > 0 {
+			partitionBy, err = groupByToExpressions(s.GroupBy)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		for _, o := range s.OrderBy {
+			e, err := exprToExpression(o.Expr)
+			if err != nil {
+				return nil, err
+			}
+
+			orderBy = append(orderBy, expression.WindowSortField{
+				Column:     e,
+				Descending: o.Direction == sqlparser.DescScr,
+			})
+		}
+	}
+
+	return expression.NewWindow(fn, partitionBy, orderBy, frame), nil
+}
+
+// findWindowFrame returns the position of the frame of the given window
+// specification, or -1 if it has none.
+func findWindowFrame(spec string) int {
+	var depth int
+	tkn := sqlparser.NewStringTokenizer(spec)
+	for {
+		typ, val := tkn.Scan()
+		switch typ {
+		case 0, sqlparser.LEX_ERROR:
+			return -1
+		case '(':
+			depth++
+			continue
+		case ')':
+			depth--
+			continue
+		}
+
+		word := strings.ToLower(string(val))
+		if depth > 0 || (word != "rows" && word != "range") {
+			continue
+		}
+
+		// The position of the tokenizer is right after the next character
+		// after the token.
+		end := tkn.Position - 1
+		start := end - len(word)
+		if start >= 0 && strings.EqualFold(spec[start:end], word) {
+			return start
+		}
+	}
+}
+
+// parseWindowFrame parses the frame of a window, that is,
+// `{ROWS | RANGE} {start | BETWEEN start AND end}`.
+func parseWindowFrame(s string) (*expression.WindowFrame, error) {
+	m := windowFrameRegex.FindStringSubmatch(strings.TrimSpace(s))
+	if m == nil {
+		return nil, ErrInvalidWindowFrame.New(s)
+	}
+
+	frame := &expression.WindowFrame{
+		Unit: expression.RowsFrame,
+		End:  expression.WindowFrameBound{Type: expression.CurrentRow},
+	}
+	if strings.ToLower(m[1]) == "range" {
+		frame.Unit = expression.RangeFrame
+	}
+
+	var err error
+	if m[4] != "" {
+		frame.Start, err = parseFrameBound(m[4])
+	} else {
+		frame.Start, err = parseFrameBound(m[2])
+		if err == nil {
+			frame.End, err = parseFrameBound(m[3])
+		}
+	}
+	if err != nil {
+		return nil, ErrInvalidWindowFrame.New(s)
+	}
+
+	// The frame can't start after it ends, regardless of the offsets.
+	if frame.Start.Type == expression.UnboundedFollowing ||
+		frame.End.Type == expression.UnboundedPreceding ||
+		frame.Start.Type > frame.End.Type {
+		return nil, ErrInvalidWindowFrame.New(s)
+	}
+
+	return frame, nil
+}
+
+func parseFrameBound(s string) (expression.WindowFrameBound, error) {
+	var bound expression.WindowFrameBound
+	m := frameBoundRegex.FindStringSubmatch(strings.TrimSpace(s))
+	if m == nil {
+		return bound, ErrInvalidWindowFrame.New(s)
+	}
+
+	switch {
+	case strings.EqualFold(m[1], "preceding"):
+		bound.Type = expression.UnboundedPreceding
+	case strings.EqualFold(m[1], "following"):
+		bound.Type = expression.UnboundedFollowing
+	case m[2] == "":
+		bound.Type = expression.CurrentRow
+	default:
+		offset, err := strconv.ParseInt(m[2], 10, 64)
+		if err != nil {
+			return bound, err
+		}
+
+		bound.Offset = offset
+		bound.Type = expression.Preceding
+		if strings.EqualFold(m[3], "following") {
+			bound.Type = expression.Following
+		}
+	}
+
+	return bound, nil
+}
@@ -0,0 +1,265 @@
+package parse
+
+import (
+	"regexp"
+	"strings"
+
+	errors "gopkg.in/src-d/go-errors.v1"
+	"gopkg.in/src-d/go-mysql-server.v0/sql"
+	"gopkg.in/src-d/go-mysql-server.v0/sql/expression"
+	"gopkg.in/src-d/go-mysql-server.v0/sql/plan"
+)
+
+var (
+	// ErrDuplicateCTE is returned when a WITH clause has more than one
+	// common table expression with the same name.
+	ErrDuplicateCTE = errors.NewKind("Not unique table/alias: '%s'")
+
+	// ErrRecursiveCTEWithoutUnion is returned when a recursive common table
+	// expression is not the UNION of an anchor and a recursive query.
+	ErrRecursiveCTEWithoutUnion = errors.NewKind("Recursive Common Table Expression '%s' should contain a UNION")
+
+	// ErrRecursiveCTEOrder is returned when the anchor query of a recursive
+	// common table expression references the expression.
+	ErrRecursiveCTEOrder = errors.NewKind("Recursive Common Table Expression '%s' should have one or more non-recursive query blocks followed by one or more recursive ones")
+)
+
+var (
+	withClauseRegex = regexp.MustCompile(`(?is)^with\s+(recursive\s+)?`)
+	cteNameRegex    = regexp.MustCompile("(?is)^(`[^`]+`|\\w+)\\s*(?:\\(([^)]*)\\)\\s*)?as\\s*\\(")
+)
+
+// commonTableExpression is a common table expression of a WITH clause.
+type commonTableExpression struct {
+	name    string
+	columns []string
+	node    sql.Node
+}
+
+// parseWith parses a query with a WITH clause, which is not supported by
+// the SQL parser. The query of each common table expression and the main
+// query are parsed on their own, and the tables named after an expression
+// are replaced with subqueries with its query. The queries of expressions
+// referenced more than once are materialized, so they are only evaluated
+// once.
+func parseWith(ctx *sql.Context, query string) (sql.Node, error) {
+	m := withClauseRegex.FindStringSubmatch(query)
+	if m == nil {
+		return nil, ErrUnsupportedSyntax.New(query)
+	}
+
+	recursive := m[1] != ""
+	rest := query[len(m[0]):]
+
+	var ctes []commonTableExpression
+	var names = make(map[string]struct{})
+	for {
+		m := cteNameRegex.FindStringSubmatch(rest)
+		if m == nil {
+			return nil, ErrUnsupportedSyntax.New(query)
+		}
+
+		open := len(m[0]) - 1
+		close := closingParen(rest, open)
+		if close < 0 {
+			return nil, ErrUnsupportedSyntax.New(query)
+		}
+
+		text := strings.TrimSpace(rest[open+1 : close])
+		if !selectQueryRegex.MatchString(text) {
+			return nil, ErrUnsupportedSyntax.New(query)
+		}
+
+		node, err := Parse(ctx, text)
+		if err != nil {
+			return nil, err
+		}
+
+		cte := commonTableExpression{name: unquoteIdent(m[1]), node: node}
+		if strings.TrimSpace(m[2]) != "" {
+			cte.columns = splitIdents(m[2])
+		}
+
+		key := strings.ToLower(cte.name)
+		if _, ok := names[key]; ok {
+			return nil, ErrDuplicateCTE.New(cte.name)
+		}
+		names[key] = struct{}{}
+		ctes = append(ctes, cte)
+
+		rest = strings.TrimSpace(rest[close+1:])
+		if !strings.HasPrefix(rest, ",") {
+			break
+		}
+		rest = strings.TrimSpace(rest[1:])
+	}
+
+	if rest == "" {
+		return nil, ErrUnsupportedSyntax.New(query)
+	}
+
+	node, err := Parse(ctx, rest)
+	if err != nil {
+		return nil, err
+	}
+
+	var queries = make(map[string]sql.Node)
+	for i, cte := range ctes {
+		n, err := replaceCTEs(cte.node, queries)
+		if err != nil {
+			return nil, err
+		}
+
+		if recursive && tableReferences(cte.node, cte.name) > 0 {
+			n, err = recursiveCTE(cte, n)
+			if err != nil {
+				return nil, err
+			}
+		} else if len(cte.columns) > 0 {
+			n = plan.NewCommonTableExpression(cte.name, cte.columns, n)
+		}
+
+		references := tableReferences(node, cte.name)
+		for _, next := range ctes[i+1:] {
+			references += tableReferences(next.node, cte.name)
+		}
+
+		if references > 1 {
+			n = plan.NewMaterialized(n)
+		}
+
+		queries[strings.ToLower(cte.name)] = n
+	}
+
+	return replaceCTEs(node, queries)
+}
+
+// recursiveCTE returns the node of the given recursive common table
+// expression, whose query must be the UNION of an anchor query that
+// doesn't reference the expression and a recursive one.
+func recursiveCTE(cte commonTableExpression, n sql.Node) (sql.Node, error) {
+	var distinct bool
+	if d, ok := n.(*plan.Distinct); ok {
+		distinct = true
+		n = d.Child
+	}
+
+	union, ok := n.(*plan.Union)
+	if !ok {
+		return nil, ErrRecursiveCTEWithoutUnion.New(cte.name)
+	}
+
+	if tableReferences(union.Left, cte.name) > 0 {
+		return nil, ErrRecursiveCTEOrder.New(cte.name)
+	}
+
+	return plan.NewRecursiveCTE(cte.name, cte.columns, union.Left, union.Right, distinct), nil
+}
+
+// replaceCTEs replaces the tables without database in the given node that
+// are named after any of the given common table expressions with a
+// subquery with the node of the expression, including the ones in
+// subqueries.
+func replaceCTEs(n sql.Node, ctes map[string]sql.Node) (sql.Node, error) {
+	if len(ctes) == 0 {
+		return n, nil
+	}
+
+	n, err := n.TransformUp(func(n sql.Node) (sql.Node, error) {
+		switch n := n.(type) {
+		case *plan.UnresolvedTable:
+			if n.Database != "" {
+				return n, nil
+			}
+
+			if cte, ok := ctes[strings.ToLower(n.Name())]; ok {
+				return plan.NewSubqueryAlias(n.Name(), cte), nil
+			}
+
+			return n, nil
+		case *plan.SubqueryAlias:
+			child, err := replaceCTEs(n.Child, ctes)
+			if err != nil {
+				return nil, err
+			}
+
+			return plan.NewSubqueryAlias(n.Name(), child), nil
+		default:
+			return n, nil
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return n.TransformExpressionsUp(func(e sql.Expression) (sql.Expression, error) {
+		subquery, ok := e.(*expression.Subquery)
+		if !ok {
+			return e, nil
+		}
+
+		query, err := replaceCTEs(subquery.Query, ctes)
+		if err != nil {
+			return nil, err
+		}
+
+		return subquery.WithQuery(query), nil
+	})
+}
+
+// tableReferences returns the number of tables without database with the
+// given name in the given node, including the ones in subqueries.
+func tableReferences(n sql.Node, name string) int {
+	var count int
+	plan.Inspect(n, func(n sql.Node) bool {
+		if t, ok := n.(*plan.UnresolvedTable); ok && t.Database == "" && strings.EqualFold(t.Name(), name) {
+			count++
+		}
+		return true
+	})
+
+	plan.InspectExpressions(n, func(e sql.Expression) bool {
+		if subquery, ok := e.(*expression.Subquery); ok {
+			count += tableReferences(subquery.Query, name)
+		}
+		return true
+	})
+
+	return count
+}
+
+// closingParen returns the position of the parenthesis closing the one at
+// the given position of the query, ignoring the ones inside quotes, or -1
+// if it's not closed.
+func closingParen(query string, open int) int {
+	var (
+		depth int
+		quote byte
+	)
+
+	for i := open; i < len(query); i++ {
+		c := query[i]
+		if quote != 0 {
+			if c == '\\' && quote != '`' {
+				i++
+			} else if c == quote {
+				quote = 0
+			}
+			continue
+		}
+
+		switch c {
+		case '\'', '"', '`':
+			quote = c
+		case '(':
+			depth++
+		case ')':
+			depth--
+			if depth == 0 {
+				return i
+			}
+		}
+	}
+
+	return -1
+}
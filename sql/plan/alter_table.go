@@ -0,0 +1,329 @@
+package plan
+
+import (
+	"gopkg.in/src-d/go-errors.v1"
+	"gopkg.in/src-d/go-mysql-server.v0/sql"
+)
+
+var (
+	// ErrTruncateNotSupported is thrown when a table doesn't support being
+	// truncated.
+	ErrTruncateNotSupported = errors.NewKind("table %s doesn't support TRUNCATE")
+	// ErrAlterTableNotSupported is thrown when a table doesn't support
+	// altering its columns.
+	ErrAlterTableNotSupported = errors.NewKind("table %s doesn't support ALTER TABLE")
+)
+
+// Truncate is a node describing the removal of all the rows of a table.
+// The indexes of the table are deleted, as they are no longer valid.
+type Truncate struct {
+	UnaryNode
+	Catalog         *sql.Catalog
+	CurrentDatabase string
+}
+
+// NewTruncate creates a new Truncate node.
+func NewTruncate(table sql.Node) *Truncate {
+	return &Truncate{UnaryNode: UnaryNode{table}}
+}
+
+// Schema implements the Node interface.
+func (t *Truncate) Schema() sql.Schema { return nil }
+
+// RowIter implements the Node interface.
+func (t *Truncate) RowIter(ctx *sql.Context) (sql.RowIter, error) {
+	table, err := getResolvedTable(t.Child)
+	if err != nil {
+		return nil, err
+	}
+
+	truncater, ok := getTruncater(table)
+	if !ok {
+		return nil, ErrTruncateNotSupported.New(table.Name())
+	}
+
+	if err := truncater.Truncate(ctx); err != nil {
+		return nil, err
+	}
+
+	err = deleteTableIndexes(ctx, t.Catalog, t.CurrentDatabase, table)
+	return sql.RowsToRowIter(), err
+}
+
+func getTruncater(t sql.Table) (sql.Truncater, bool) {
+	switch t := t.(type) {
+	case sql.Truncater:
+		return t, true
+	case sql.TableWrapper:
+		return getTruncater(t.Underlying())
+	default:
+		return nil, false
+	}
+}
+
+// TransformUp implements the Transformable interface.
+func (t *Truncate) TransformUp(f sql.TransformNodeFunc) (sql.Node, error) {
+	child, err := t.Child.TransformUp(f)
+	if err != nil {
+		return nil, err
+	}
+
+	nt := *t
+	nt.Child = child
+	return f(&nt)
+}
+
+// TransformExpressionsUp implements the Transformable interface.
+func (t *Truncate) TransformExpressionsUp(f sql.TransformExprFunc) (sql.Node, error) {
+	child, err := t.Child.TransformExpressionsUp(f)
+	if err != nil {
+		return nil, err
+	}
+
+	nt := *t
+	nt.Child = child
+	return &nt, nil
+}
+
+func (t *Truncate) String() string {
+	pr := sql.NewTreePrinter()
+	_ = pr.WriteNode("Truncate")
+	_ = pr.WriteChildren(t.Child.String())
+	return pr.String()
+}
+
+// AddColumn is a node describing the addition of a column to a table.
+type AddColumn struct {
+	UnaryNode
+	Column *sql.Column
+}
+
+// NewAddColumn creates a new AddColumn node.
+func NewAddColumn(table sql.Node, column *sql.Column) *AddColumn {
+	return &AddColumn{UnaryNode{table}, column}
+}
+
+// Schema implements the Node interface.
+func (a *AddColumn) Schema() sql.Schema { return nil }
+
+// RowIter implements the Node interface.
+func (a *AddColumn) RowIter(ctx *sql.Context) (sql.RowIter, error) {
+	alterer, err := getTableAlterer(a.Child)
+	if err != nil {
+		return nil, err
+	}
+
+	return sql.RowsToRowIter(), alterer.AddColumn(ctx, a.Column)
+}
+
+// TransformUp implements the Transformable interface.
+func (a *AddColumn) TransformUp(f sql.TransformNodeFunc) (sql.Node, error) {
+	child, err := a.Child.TransformUp(f)
+	if err != nil {
+		return nil, err
+	}
+
+	return f(NewAddColumn(child, a.Column))
+}
+
+// TransformExpressionsUp implements the Transformable interface.
+func (a *AddColumn) TransformExpressionsUp(f sql.TransformExprFunc) (sql.Node, error) {
+	child, err := a.Child.TransformExpressionsUp(f)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewAddColumn(child, a.Column), nil
+}
+
+func (a *AddColumn) String() string {
+	pr := sql.NewTreePrinter()
+	_ = pr.WriteNode("AddColumn(%s %s)", a.Column.Name, a.Column.Type.Type())
+	_ = pr.WriteChildren(a.Child.String())
+	return pr.String()
+}
+
+// DropColumn is a node describing the removal of a column from a table.
+type DropColumn struct {
+	UnaryNode
+	Name            string
+	Catalog         *sql.Catalog
+	CurrentDatabase string
+}
+
+// NewDropColumn creates a new DropColumn node.
+func NewDropColumn(table sql.Node, name string) *DropColumn {
+	return &DropColumn{UnaryNode: UnaryNode{table}, Name: name}
+}
+
+// Schema implements the Node interface.
+func (d *DropColumn) Schema() sql.Schema { return nil }
+
+// RowIter implements the Node interface.
+func (d *DropColumn) RowIter(ctx *sql.Context) (sql.RowIter, error) {
+	alterer, err := getTableAlterer(d.Child)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := alterer.DropColumn(ctx, d.Name); err != nil {
+		return nil, err
+	}
+
+	err = deleteColumnIndexes(ctx, d.Catalog, d.CurrentDatabase, d.Child, d.Name)
+	return sql.RowsToRowIter(), err
+}
+
+// TransformUp implements the Transformable interface.
+func (d *DropColumn) TransformUp(f sql.TransformNodeFunc) (sql.Node, error) {
+	child, err := d.Child.TransformUp(f)
+	if err != nil {
+		return nil, err
+	}
+
+	nd := *d
+	nd.Child = child
+	return f(&nd)
+}
+
+// TransformExpressionsUp implements the Transformable interface.
+func (d *DropColumn) TransformExpressionsUp(f sql.TransformExprFunc) (sql.Node, error) {
+	child, err := d.Child.TransformExpressionsUp(f)
+	if err != nil {
+		return nil, err
+	}
+
+	nd := *d
+	nd.Child = child
+	return &nd, nil
+}
+
+func (d *DropColumn) String() string {
+	pr := sql.NewTreePrinter()
+	_ = pr.WriteNode("DropColumn(%s)", d.Name)
+	_ = pr.WriteChildren(d.Child.String())
+	return pr.String()
+}
+
+// ModifyColumn is a node describing the change of the definition of a
+// column of a table.
+type ModifyColumn struct {
+	UnaryNode
+	Name            string
+	Column          *sql.Column
+	Catalog         *sql.Catalog
+	CurrentDatabase string
+}
+
+// NewModifyColumn creates a new ModifyColumn node.
+func NewModifyColumn(table sql.Node, name string, column *sql.Column) *ModifyColumn {
+	return &ModifyColumn{UnaryNode: UnaryNode{table}, Name: name, Column: column}
+}
+
+// Schema implements the Node interface.
+func (m *ModifyColumn) Schema() sql.Schema { return nil }
+
+// RowIter implements the Node interface.
+func (m *ModifyColumn) RowIter(ctx *sql.Context) (sql.RowIter, error) {
+	alterer, err := getTableAlterer(m.Child)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := alterer.ModifyColumn(ctx, m.Name, m.Column); err != nil {
+		return nil, err
+	}
+
+	err = deleteColumnIndexes(ctx, m.Catalog, m.CurrentDatabase, m.Child, m.Name)
+	return sql.RowsToRowIter(), err
+}
+
+// TransformUp implements the Transformable interface.
+func (m *ModifyColumn) TransformUp(f sql.TransformNodeFunc) (sql.Node, error) {
+	child, err := m.Child.TransformUp(f)
+	if err != nil {
+		return nil, err
+	}
+
+	nm := *m
+	nm.Child = child
+	return f(&nm)
+}
+
+// TransformExpressionsUp implements the Transformable interface.
+func (m *ModifyColumn) TransformExpressionsUp(f sql.TransformExprFunc) (sql.Node, error) {
+	child, err := m.Child.TransformExpressionsUp(f)
+	if err != nil {
+		return nil, err
+	}
+
+	nm := *m
+	nm.Child = child
+	return &nm, nil
+}
+
+func (m *ModifyColumn) String() string {
+	pr := sql.NewTreePrinter()
+	_ = pr.WriteNode(
+		"ModifyColumn(%s, %s %s)",
+		m.Name, m.Column.Name, m.Column.Type.Type(),
+	)
+	_ = pr.WriteChildren(m.Child.String())
+	return pr.String()
+}
+
+// deleteColumnIndexes deletes all the indexes using the column with the
+// given name of the table, which are no longer valid once the column is
+// dropped or modified, if there is a catalog.
+func deleteColumnIndexes(
+	ctx *sql.Context,
+	catalog *sql.Catalog,
+	db string,
+	node sql.Node,
+	column string,
+) error {
+	if catalog == nil {
+		return nil
+	}
+
+	table, err := getResolvedTable(node)
+	if err != nil {
+		return err
+	}
+
+	return catalog.DeleteColumnIndexes(ctx, db, table, column)
+}
+
+func getResolvedTable(node sql.Node) (sql.Table, error) {
+	switch node := node.(type) {
+	case *ResolvedTable:
+		return node.Table, nil
+	default:
+		return nil, ErrTableNotValid.New()
+	}
+}
+
+func getTableAlterer(node sql.Node) (sql.TableAlterer, error) {
+	table, err := getResolvedTable(node)
+	if err != nil {
+		return nil, err
+	}
+
+	alterer, ok := getAlterableTable(table)
+	if !ok {
+		return nil, ErrAlterTableNotSupported.New(table.Name())
+	}
+
+	return alterer, nil
+}
+
+func getAlterableTable(t sql.Table) (sql.TableAlterer, bool) {
+	switch t := t.(type) {
+	case sql.TableAlterer:
+		return t, true
+	case sql.TableWrapper:
+		return getAlterableTable(t.Underlying())
+	default:
+		return nil, false
+	}
+}
@@ -0,0 +1,107 @@
+package plan
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"gopkg.in/src-d/go-mysql-server.v0/sql"
+	"gopkg.in/src-d/go-mysql-server.v0/sql/expression"
+)
+
+func TestTruncate(t *testing.T) {
+	require := require.New(t)
+	table := writeTestTable(t)
+
+	_, err := NewTruncate(NewResolvedTable(table)).RowIter(sql.NewEmptyContext())
+	require.NoError(err)
+	require.Nil(collectRows(t, NewResolvedTable(table)))
+}
+
+func TestAlterTableColumns(t *testing.T) {
+	require := require.New(t)
+	ctx := sql.NewEmptyContext()
+	table := writeTestTable(t)
+
+	_, err := NewAddColumn(
+		NewResolvedTable(table),
+		&sql.Column{Name: "c", Type: sql.Int32, Nullable: true},
+	).RowIter(ctx)
+	require.NoError(err)
+
+	_, err = NewDropColumn(NewResolvedTable(table), "b").RowIter(ctx)
+	require.NoError(err)
+
+	_, err = NewModifyColumn(
+		NewResolvedTable(table),
+		"a",
+		&sql.Column{Name: "a", Type: sql.Text},
+	).RowIter(ctx)
+	require.NoError(err)
+
+	require.Equal(sql.Schema{
+		{Name: "a", Type: sql.Text, Source: "foo"},
+		{Name: "c", Type: sql.Int32, Nullable: true, Source: "foo"},
+	}, table.Schema())
+
+	require.Equal([]sql.Row{
+		{"1", nil},
+		{"2", nil},
+		{"3", nil},
+	}, collectRows(t, NewResolvedTable(table)))
+}
+
+func TestAlterTableColumnIndexes(t *testing.T) {
+	require := require.New(t)
+	ctx := sql.NewEmptyContext()
+	table := writeTestTable(t)
+
+	driver := new(mockDriver)
+	catalog := sql.NewCatalog()
+	catalog.RegisterIndexDriver(driver)
+
+	for _, col := range []string{"a", "b"} {
+		done, ready, err := catalog.AddIndex(&mockIndex{
+			id:    "idx_" + col,
+			db:    "foo",
+			table: "foo",
+			exprs: []sql.Expression{expression.NewGetFieldWithTable(0, sql.Int64, "foo", col, false)},
+		})
+		require.NoError(err)
+		close(done)
+		<-ready
+	}
+
+	dc := NewDropColumn(NewResolvedTable(table), "b")
+	dc.Catalog = catalog
+	dc.CurrentDatabase = "foo"
+
+	_, err := dc.RowIter(ctx)
+	require.NoError(err)
+	require.Equal([]string{"idx_b"}, driver.deleted)
+	require.Nil(catalog.Index("foo", "idx_b"))
+
+	mc := NewModifyColumn(NewResolvedTable(table), "a", &sql.Column{Name: "a", Type: sql.Text})
+	mc.Catalog = catalog
+	mc.CurrentDatabase = "foo"
+
+	_, err = mc.RowIter(ctx)
+	require.NoError(err)
+	require.Equal([]string{"idx_b", "idx_a"}, driver.deleted)
+	require.Nil(catalog.Index("foo", "idx_a"))
+}
+
+func TestAlterTableNotSupported(t *testing.T) {
+	require := require.New(t)
+
+	_, err := NewDropColumn(
+		NewResolvedTable(&nonAlterableTable{writeTestTable(t)}),
+		"a",
+	).RowIter(sql.NewEmptyContext())
+	require.Error(err)
+	require.True(ErrAlterTableNotSupported.Is(err))
+}
+
+// nonAlterableTable only exposes the methods of sql.Table.
+type nonAlterableTable struct {
+	sql.Table
+}
@@ -0,0 +1,384 @@
+package plan
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/mitchellh/hashstructure"
+	errors "gopkg.in/src-d/go-errors.v1"
+	"gopkg.in/src-d/go-mysql-server.v0/sql"
+)
+
+var (
+	// ErrCTEColumnCount is returned when the column list of a common table
+	// expression doesn't have as many columns as its query.
+	ErrCTEColumnCount = errors.NewKind("common table expression %s has %d columns but its query returns %d")
+
+	// ErrUnresolvedCTE is returned when a common table expression is
+	// executed without being resolved.
+	ErrUnresolvedCTE = errors.NewKind("unresolved common table expression %s")
+)
+
+// maxRecursionDepthWarning is the code of the warning given when a
+// recursive common table expression is stopped after reaching the maximum
+// recursion depth.
+const maxRecursionDepthWarning = 3636
+
+// Materialized is a node that returns the rows of its child, which are
+// computed only once per query. All the copies of the node share the rows,
+// so a common table expression referenced more than once in a query is
+// only evaluated once.
+type Materialized struct {
+	UnaryNode
+	cache *materializedRows
+}
+
+type materializedRows struct {
+	mu   sync.Mutex
+	pid  uint64
+	done bool
+	rows []sql.Row
+}
+
+// NewMaterialized creates a new Materialized node with the given child.
+func NewMaterialized(child sql.Node) *Materialized {
+	return &Materialized{UnaryNode{Child: child}, new(materializedRows)}
+}
+
+// RowIter implements the Node interface. The rows are computed again if the
+// node is executed by another query, such as a prepared statement.
+func (m *Materialized) RowIter(ctx *sql.Context) (sql.RowIter, error) {
+	span, ctx := ctx.Span("plan.Materialized")
+	defer span.Finish()
+
+	m.cache.mu.Lock()
+	defer m.cache.mu.Unlock()
+
+	if !m.cache.done || m.cache.pid != ctx.Pid() {
+		rows, err := sql.NodeToRows(ctx, m.Child)
+		if err != nil {
+			return nil, err
+		}
+
+		m.cache.rows = rows
+		m.cache.pid = ctx.Pid()
+		m.cache.done = true
+	}
+
+	return sql.RowsToRowIter(m.cache.rows...), nil
+}
+
+// TransformUp implements the Transformable interface.
+func (m *Materialized) TransformUp(f sql.TransformNodeFunc) (sql.Node, error) {
+	child, err := m.Child.TransformUp(f)
+	if err != nil {
+		return nil, err
+	}
+
+	return f(&Materialized{UnaryNode{Child: child}, m.cache})
+}
+
+// TransformExpressionsUp implements the Transformable interface.
+func (m *Materialized) TransformExpressionsUp(f sql.TransformExprFunc) (sql.Node, error) {
+	child, err := m.Child.TransformExpressionsUp(f)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Materialized{UnaryNode{Child: child}, m.cache}, nil
+}
+
+func (m *Materialized) String() string {
+	pr := sql.NewTreePrinter()
+	_ = pr.WriteNode("Materialized")
+	_ = pr.WriteChildren(m.Child.String())
+	return pr.String()
+}
+
+// CommonTableExpression is a node that gives the given names to the columns
+// of the query of a common table expression. It's replaced by a projection
+// with the names once the query is resolved.
+type CommonTableExpression struct {
+	UnaryNode
+	name    string
+	columns []string
+}
+
+// NewCommonTableExpression creates a new CommonTableExpression node for the
+// expression with the given name, column names and query.
+func NewCommonTableExpression(name string, columns []string, query sql.Node) *CommonTableExpression {
+	return &CommonTableExpression{UnaryNode{Child: query}, name, columns}
+}
+
+// Name implements the Nameable interface.
+func (c *CommonTableExpression) Name() string { return c.name }
+
+// Columns returns the names of the columns of the expression.
+func (c *CommonTableExpression) Columns() []string { return c.columns }
+
+// Resolved implements the Resolvable interface. The node is never resolved,
+// as it must be replaced by a projection.
+func (c *CommonTableExpression) Resolved() bool { return false }
+
+// RowIter implements the Node interface.
+func (c *CommonTableExpression) RowIter(ctx *sql.Context) (sql.RowIter, error) {
+	return nil, ErrUnresolvedCTE.New(c.name)
+}
+
+// TransformUp implements the Transformable interface.
+func (c *CommonTableExpression) TransformUp(f sql.TransformNodeFunc) (sql.Node, error) {
+	child, err := c.Child.TransformUp(f)
+	if err != nil {
+		return nil, err
+	}
+
+	return f(NewCommonTableExpression(c.name, c.columns, child))
+}
+
+// TransformExpressionsUp implements the Transformable interface.
+func (c *CommonTableExpression) TransformExpressionsUp(f sql.TransformExprFunc) (sql.Node, error) {
+	child, err := c.Child.TransformExpressionsUp(f)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewCommonTableExpression(c.name, c.columns, child), nil
+}
+
+func (c *CommonTableExpression) String() string {
+	pr := sql.NewTreePrinter()
+	_ = pr.WriteNode("CommonTableExpression(%s (%s))", c.name, strings.Join(c.columns, ", "))
+	_ = pr.WriteChildren(c.Child.String())
+	return pr.String()
+}
+
+// RecursiveCTE is a node that returns the rows of a recursive common table
+// expression. Its left child is the anchor query, whose rows are the ones
+// of the first iteration, and its right child is the recursive query, which
+// is evaluated with the rows of the previous iteration as the rows of the
+// RecursiveTable nodes with the name of the expression until it returns no
+// rows or the maximum recursion depth is reached. Like SubqueryAlias, the
+// children are analyzed on their own, so it's not transformed by the
+// transformations of its parents.
+type RecursiveCTE struct {
+	BinaryNode
+	name     string
+	columns  []string
+	distinct bool
+}
+
+// NewRecursiveCTE creates a new RecursiveCTE node for the expression with
+// the given name and column names, which may be empty to use the names of
+// the anchor query. If distinct is true, the rows are returned only once.
+func NewRecursiveCTE(
+	name string,
+	columns []string,
+	anchor, recursive sql.Node,
+	distinct bool,
+) *RecursiveCTE {
+	return &RecursiveCTE{
+		BinaryNode: BinaryNode{Left: anchor, Right: recursive},
+		name:       name,
+		columns:    columns,
+		distinct:   distinct,
+	}
+}
+
+// Name implements the Nameable interface.
+func (c *RecursiveCTE) Name() string { return c.name }
+
+// Columns returns the names of the columns of the expression, if they were
+// given.
+func (c *RecursiveCTE) Columns() []string { return c.columns }
+
+// Distinct reports whether the rows are returned only once.
+func (c *RecursiveCTE) Distinct() bool { return c.distinct }
+
+// Resolved implements the Resolvable interface.
+func (c *RecursiveCTE) Resolved() bool {
+	return c.Left.Resolved() && c.Right.Resolved()
+}
+
+// Schema implements the Node interface.
+func (c *RecursiveCTE) Schema() sql.Schema {
+	return CTESchema(c.name, c.columns, setOperationSchema(c.Left, c.Right))
+}
+
+// CTESchema returns the given schema of the query of the common table
+// expression with the given name and column names.
+func CTESchema(name string, columns []string, schema sql.Schema) sql.Schema {
+	var result = make(sql.Schema, len(schema))
+	for i, col := range schema {
+		c := *col
+		c.Source = name
+		if i < len(columns) {
+			c.Name = columns[i]
+		}
+		result[i] = &c
+	}
+	return result
+}
+
+// RowIter implements the Node interface.
+func (c *RecursiveCTE) RowIter(ctx *sql.Context) (sql.RowIter, error) {
+	span, ctx := ctx.Span("plan.RecursiveCTE")
+	defer span.Finish()
+
+	schema := c.Schema()
+	seen := make(map[uint64]struct{})
+
+	// addRows converts the given rows to the types of the schema and returns
+	// the ones that must be added to the result.
+	addRows := func(rows []sql.Row) ([]sql.Row, error) {
+		var result []sql.Row
+		for _, row := range rows {
+			var converted = make(sql.Row, len(row))
+			for i, v := range row {
+				var err error
+				if converted[i], err = schema[i].Type.Convert(v); err != nil {
+					return nil, err
+				}
+			}
+
+			if c.distinct {
+				hash, err := hashstructure.Hash(converted, nil)
+				if err != nil {
+					return nil, fmt.Errorf("unable to hash row: %s", err)
+				}
+
+				if _, ok := seen[hash]; ok {
+					continue
+				}
+				seen[hash] = struct{}{}
+			}
+
+			result = append(result, converted)
+		}
+		return result, nil
+	}
+
+	rows, err := sql.NodeToRows(ctx, c.Left)
+	if err != nil {
+		return nil, err
+	}
+
+	working, err := addRows(rows)
+	if err != nil {
+		return nil, err
+	}
+
+	result := working
+	maxDepth := sql.CTEMaxRecursionDepth(ctx.Session)
+	for depth := uint64(1); len(working) > 0; depth++ {
+		if depth > maxDepth {
+			ctx.Warn(
+				maxRecursionDepthWarning,
+				"Recursive query aborted after %d iterations. Try increasing @@cte_max_recursion_depth to a larger value.",
+				maxDepth,
+			)
+			break
+		}
+
+		recursive, err := c.withWorkingRows(working)
+		if err != nil {
+			return nil, err
+		}
+
+		rows, err := sql.NodeToRows(ctx, recursive)
+		if err != nil {
+			return nil, err
+		}
+
+		working, err = addRows(rows)
+		if err != nil {
+			return nil, err
+		}
+
+		result = append(result, working...)
+	}
+
+	return sql.RowsToRowIter(result...), nil
+}
+
+// withWorkingRows returns the recursive query reading the given rows from
+// the tables of the expression.
+func (c *RecursiveCTE) withWorkingRows(rows []sql.Row) (sql.Node, error) {
+	return c.Right.TransformUp(func(n sql.Node) (sql.Node, error) {
+		t, ok := n.(*RecursiveTable)
+		if !ok || !strings.EqualFold(t.Name(), c.name) {
+			return n, nil
+		}
+
+		nt := *t
+		nt.rows = rows
+		return &nt, nil
+	})
+}
+
+// TransformUp implements the Transformable interface.
+func (c *RecursiveCTE) TransformUp(f sql.TransformNodeFunc) (sql.Node, error) {
+	return f(c)
+}
+
+// TransformExpressionsUp implements the Transformable interface.
+func (c *RecursiveCTE) TransformExpressionsUp(f sql.TransformExprFunc) (sql.Node, error) {
+	return c, nil
+}
+
+func (c *RecursiveCTE) String() string {
+	pr := sql.NewTreePrinter()
+	if len(c.columns) > 0 {
+		_ = pr.WriteNode("RecursiveCTE(%s (%s))", c.name, strings.Join(c.columns, ", "))
+	} else {
+		_ = pr.WriteNode("RecursiveCTE(%s)", c.name)
+	}
+	_ = pr.WriteChildren(c.Left.String(), c.Right.String())
+	return pr.String()
+}
+
+// RecursiveTable is the table of a recursive common table expression in
+// its recursive query, which returns the rows of the previous iteration.
+type RecursiveTable struct {
+	name   string
+	schema sql.Schema
+	rows   []sql.Row
+}
+
+// NewRecursiveTable creates a new RecursiveTable for the common table
+// expression with the given name and schema.
+func NewRecursiveTable(name string, schema sql.Schema) *RecursiveTable {
+	return &RecursiveTable{name: name, schema: schema}
+}
+
+// Name implements the Nameable interface.
+func (t *RecursiveTable) Name() string { return t.name }
+
+// Resolved implements the Resolvable interface.
+func (t *RecursiveTable) Resolved() bool { return true }
+
+// Schema implements the Node interface.
+func (t *RecursiveTable) Schema() sql.Schema { return t.schema }
+
+// Children implements the Node interface.
+func (t *RecursiveTable) Children() []sql.Node { return nil }
+
+// RowIter implements the Node interface.
+func (t *RecursiveTable) RowIter(ctx *sql.Context) (sql.RowIter, error) {
+	return sql.RowsToRowIter(t.rows...), nil
+}
+
+// TransformUp implements the Transformable interface.
+func (t *RecursiveTable) TransformUp(f sql.TransformNodeFunc) (sql.Node, error) {
+	nt := *t
+	return f(&nt)
+}
+
+// TransformExpressionsUp implements the Transformable interface.
+func (t *RecursiveTable) TransformExpressionsUp(f sql.TransformExprFunc) (sql.Node, error) {
+	return t, nil
+}
+
+func (t *RecursiveTable) String() string {
+	return fmt.Sprintf("RecursiveTable(%s)", t.name)
+}
@@ -0,0 +1,86 @@
+package plan
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"gopkg.in/src-d/go-mysql-server.v0/mem"
+	"gopkg.in/src-d/go-mysql-server.v0/sql"
+	"gopkg.in/src-d/go-mysql-server.v0/sql/expression"
+)
+
+func recursiveCTE(t *testing.T) *RecursiveCTE {
+	t.Helper()
+
+	table := mem.NewTable("anchor", sql.Schema{
+		{Name: "a", Source: "anchor", Type: sql.Int64},
+	})
+	require.NoError(t, table.Insert(sql.NewEmptyContext(), sql.NewRow(int64(1))))
+
+	anchor := NewResolvedTable(table)
+	schema := CTESchema("cte", []string{"n"}, anchor.Schema())
+
+	n := expression.NewGetFieldWithTable(0, sql.Int64, "cte", "n", false)
+	recursive := NewProject(
+		[]sql.Expression{
+			expression.NewArithmetic(n, expression.NewLiteral(int64(1), sql.Int64), "+"),
+		},
+		NewFilter(
+			expression.NewLessThan(n, expression.NewLiteral(int64(5), sql.Int64)),
+			NewRecursiveTable("cte", schema),
+		),
+	)
+
+	return NewRecursiveCTE("cte", []string{"n"}, anchor, recursive, false)
+}
+
+func TestRecursiveCTE(t *testing.T) {
+	require := require.New(t)
+	cte := recursiveCTE(t)
+
+	require.Equal(sql.Schema{
+		{Name: "n", Source: "cte", Type: sql.Int64},
+	}, cte.Schema())
+
+	require.Equal([]sql.Row{
+		{int64(1)}, {int64(2)}, {int64(3)}, {int64(4)}, {int64(5)},
+	}, collectRows(t, cte))
+}
+
+func TestRecursiveCTEMaxDepth(t *testing.T) {
+	require := require.New(t)
+	cte := recursiveCTE(t)
+
+	ctx := sql.NewEmptyContext()
+	require.NoError(ctx.Session.Set("cte_max_recursion_depth", sql.Int64, int64(2)))
+
+	rows, err := sql.NodeToRows(ctx, cte)
+	require.NoError(err)
+	require.Equal([]sql.Row{{int64(1)}, {int64(2)}, {int64(3)}}, rows)
+
+	warnings := ctx.Session.Warnings()
+	require.Len(warnings, 1)
+	require.Equal(maxRecursionDepthWarning, warnings[0].Code)
+}
+
+func TestMaterialized(t *testing.T) {
+	require := require.New(t)
+	left, _ := setOperationTables(t)
+
+	m := NewMaterialized(left)
+	copied, err := m.TransformUp(func(n sql.Node) (sql.Node, error) { return n, nil })
+	require.NoError(err)
+
+	ctx := sql.NewEmptyContext()
+	expected := []sql.Row{{int64(1)}, {int64(2)}, {int64(2)}, {int64(3)}, {int64(3)}}
+
+	rows, err := sql.NodeToRows(ctx, m)
+	require.NoError(err)
+	require.Equal(expected, rows)
+
+	// The copy returns the rows computed by the original node.
+	require.NoError(left.(*ResolvedTable).Table.(*mem.Table).Insert(ctx, sql.NewRow(int64(4))))
+	rows, err = sql.NodeToRows(ctx, copied)
+	require.NoError(err)
+	require.Equal(expected, rows)
+}
@@ -1,6 +1,9 @@
 package plan
 
 import (
+	"fmt"
+	"strings"
+
 	"gopkg.in/src-d/go-errors.v1"
 	"gopkg.in/src-d/go-mysql-server.v0/sql"
 )
@@ -10,11 +13,15 @@ var ErrCreateTable = errors.NewKind("tables cannot be created on database %s")
 
 // CreateTable is a node describing the creation of some table.
 type CreateTable struct {
-	Database sql.Database
-	name     string
-	schema   sql.Schema
+	Database    sql.Database
+	name        string
+	schema      sql.Schema
+	checks      []sql.CheckConstraint
+	foreignKeys []sql.ForeignKey
 }
 
+var _ sql.Expressioner = (*CreateTable)(nil)
+
 // NewCreateTable creates a new CreateTable node
 func NewCreateTable(db sql.Database, name string, schema sql.Schema) *CreateTable {
 	for _, s := range schema {
@@ -28,10 +35,28 @@ func NewCreateTable(db sql.Database, name string, schema sql.Schema) *CreateTabl
 	}
 }
 
+// WithConstraints returns a copy of the node creating the table with the
+// given CHECK and FOREIGN KEY constraints.
+func (c *CreateTable) WithConstraints(checks []sql.CheckConstraint, foreignKeys []sql.ForeignKey) *CreateTable {
+	nc := *c
+	nc.checks = checks
+	nc.foreignKeys = foreignKeys
+	return &nc
+}
+
 // Resolved implements the Resolvable interface.
 func (c *CreateTable) Resolved() bool {
-	_, ok := c.Database.(sql.UnresolvedDatabase)
-	return !ok
+	if _, ok := c.Database.(sql.UnresolvedDatabase); ok {
+		return false
+	}
+
+	for _, check := range c.checks {
+		if !check.Expr.Resolved() {
+			return false
+		}
+	}
+
+	return true
 }
 
 // RowIter implements the Node interface.
@@ -41,7 +66,87 @@ func (c *CreateTable) RowIter(s *sql.Context) (sql.RowIter, error) {
 		return nil, ErrCreateTable.New(c.Database.Name())
 	}
 
-	return sql.RowsToRowIter(), d.Create(c.name, c.schema)
+	if len(c.checks) == 0 && len(c.foreignKeys) == 0 {
+		return sql.RowsToRowIter(), d.Create(c.name, c.schema)
+	}
+
+	cd, ok := d.(sql.ConstraintAlterable)
+	if !ok {
+		return nil, sql.ErrConstraintsNotSupported.New(c.Database.Name())
+	}
+
+	if _, ok := c.Database.Tables()[c.name]; ok {
+		return nil, sql.ErrTableAlreadyExists.New(c.name)
+	}
+
+	for _, fk := range c.foreignKeys {
+		if err := c.validateForeignKey(fk); err != nil {
+			return nil, err
+		}
+	}
+
+	return sql.RowsToRowIter(), cd.CreateWithConstraints(c.name, c.schema, c.checks, c.foreignKeys)
+}
+
+// validateForeignKey returns an error if the referenced table of the
+// foreign key does not exist in the database, or the referenced columns
+// are not a primary or unique key of it.
+func (c *CreateTable) validateForeignKey(fk sql.ForeignKey) error {
+	schema := c.schema
+	if !fk.References(c.name) {
+		table, ok := c.Database.Tables()[fk.ReferencedTable]
+		if !ok {
+			return sql.ErrInvalidForeignKey.New(fk.Name,
+				fmt.Sprintf("referenced table %s does not exist", fk.ReferencedTable))
+		}
+		schema = table.Schema()
+	}
+
+	if len(fk.Columns) != len(fk.ReferencedColumns) {
+		return sql.ErrInvalidForeignKey.New(fk.Name,
+			"the number of columns and referenced columns is different")
+	}
+
+	for _, name := range fk.Columns {
+		idx := c.schema.IndexOf(name, c.name)
+		if idx < 0 {
+			return sql.ErrColumnNotFound.New(name, c.name)
+		}
+
+		if fk.OnDelete == sql.ForeignKeySetNull && !c.schema[idx].Nullable {
+			return sql.ErrInvalidForeignKey.New(fk.Name,
+				fmt.Sprintf("column %s must be nullable to use SET NULL", name))
+		}
+	}
+
+	var referenced = make(map[int]bool)
+	for _, name := range fk.ReferencedColumns {
+		idx := schema.IndexOf(name, fk.ReferencedTable)
+		if idx < 0 {
+			return sql.ErrColumnNotFound.New(name, fk.ReferencedTable)
+		}
+		referenced[idx] = true
+	}
+
+	for _, key := range sql.SchemaKeys(schema) {
+		if len(key.Columns) != len(referenced) {
+			continue
+		}
+
+		var matches = true
+		for _, idx := range key.Columns {
+			matches = matches && referenced[idx]
+		}
+
+		if matches {
+			return nil
+		}
+	}
+
+	return sql.ErrInvalidForeignKey.New(fk.Name, fmt.Sprintf(
+		"the referenced columns are not a primary or unique key of table %s",
+		fk.ReferencedTable,
+	))
 }
 
 // Schema implements the Node interface.
@@ -50,16 +155,315 @@ func (c *CreateTable) Schema() sql.Schema { return nil }
 // Children implements the Node interface.
 func (c *CreateTable) Children() []sql.Node { return nil }
 
+// Expressions implements the Expressioner interface.
+func (c *CreateTable) Expressions() []sql.Expression {
+	var exprs = make([]sql.Expression, len(c.checks))
+	for i, check := range c.checks {
+		exprs[i] = check.Expr
+	}
+	return exprs
+}
+
+// TransformExpressions implements the Expressioner interface.
+func (c *CreateTable) TransformExpressions(f sql.TransformExprFunc) (sql.Node, error) {
+	var checks = make([]sql.CheckConstraint, len(c.checks))
+	for i, check := range c.checks {
+		e, err := check.Expr.TransformUp(f)
+		if err != nil {
+			return nil, err
+		}
+
+		checks[i] = sql.CheckConstraint{Name: check.Name, Expr: e}
+	}
+
+	return c.WithConstraints(checks, c.foreignKeys), nil
+}
+
 // TransformUp implements the Transformable interface.
 func (c *CreateTable) TransformUp(f sql.TransformNodeFunc) (sql.Node, error) {
-	return f(NewCreateTable(c.Database, c.name, c.schema))
+	nc := *c
+	return f(&nc)
 }
 
 // TransformExpressionsUp implements the Transformable interface.
 func (c *CreateTable) TransformExpressionsUp(f sql.TransformExprFunc) (sql.Node, error) {
-	return c, nil
+	return c.TransformExpressions(f)
 }
 
 func (c *CreateTable) String() string {
 	return "CreateTable"
 }
+
+// ErrDropTable is thrown when the database doesn't support dropping tables.
+var ErrDropTable = errors.NewKind("tables cannot be dropped on database %s")
+
+// DropTable is a node describing the removal of some table.
+type DropTable struct {
+	Database sql.Database
+	Catalog  *sql.Catalog
+	name     string
+	ifExists bool
+}
+
+// NewDropTable creates a new DropTable node.
+func NewDropTable(db sql.Database, name string, ifExists bool) *DropTable {
+	return &DropTable{
+		Database: db,
+		name:     name,
+		ifExists: ifExists,
+	}
+}
+
+// Resolved implements the Resolvable interface.
+func (d *DropTable) Resolved() bool {
+	_, ok := d.Database.(sql.UnresolvedDatabase)
+	return !ok
+}
+
+// RowIter implements the Node interface.
+func (d *DropTable) RowIter(ctx *sql.Context) (sql.RowIter, error) {
+	dropper, ok := d.Database.(sql.TableDropper)
+	if !ok {
+		return nil, ErrDropTable.New(d.Database.Name())
+	}
+
+	table, ok := d.Database.Tables()[d.name]
+	if !ok {
+		if d.ifExists {
+			return sql.RowsToRowIter(), nil
+		}
+
+		return nil, sql.ErrTableNotFound.New(d.name)
+	}
+
+	if err := checkNotReferenced(d.Database, d.name); err != nil {
+		return nil, err
+	}
+
+	if err := deleteTableIndexes(ctx, d.Catalog, d.Database.Name(), table); err != nil {
+		return nil, err
+	}
+
+	return sql.RowsToRowIter(), dropper.DropTable(d.name)
+}
+
+// checkNotReferenced returns an error if any other table of the database
+// has a foreign key referencing the table with the given name.
+func checkNotReferenced(db sql.Database, name string) error {
+	for _, t := range db.Tables() {
+		ct, ok := t.(sql.ConstraintTable)
+		if !ok || strings.EqualFold(t.Name(), name) {
+			continue
+		}
+
+		for _, fk := range ct.ForeignKeys() {
+			if fk.References(name) {
+				return sql.ErrTableReferenced.New(name, fk.Name, t.Name())
+			}
+		}
+	}
+
+	return nil
+}
+
+// Schema implements the Node interface.
+func (d *DropTable) Schema() sql.Schema { return nil }
+
+// Children implements the Node interface.
+func (d *DropTable) Children() []sql.Node { return nil }
+
+// TransformUp implements the Transformable interface.
+func (d *DropTable) TransformUp(f sql.TransformNodeFunc) (sql.Node, error) {
+	nc := *d
+	return f(&nc)
+}
+
+// TransformExpressionsUp implements the Transformable interface.
+func (d *DropTable) TransformExpressionsUp(f sql.TransformExprFunc) (sql.Node, error) {
+	return d, nil
+}
+
+func (d *DropTable) String() string {
+	return fmt.Sprintf("DropTable(%s)", d.name)
+}
+
+// ErrRenameTable is thrown when the database doesn't support renaming
+// tables.
+var ErrRenameTable = errors.NewKind("tables cannot be renamed on database %s")
+
+// RenameTable is a node describing the renaming of some table.
+type RenameTable struct {
+	Database sql.Database
+	Catalog  *sql.Catalog
+	oldName  string
+	newName  string
+}
+
+// NewRenameTable creates a new RenameTable node.
+func NewRenameTable(db sql.Database, oldName, newName string) *RenameTable {
+	return &RenameTable{
+		Database: db,
+		oldName:  oldName,
+		newName:  newName,
+	}
+}
+
+// Resolved implements the Resolvable interface.
+func (r *RenameTable) Resolved() bool {
+	_, ok := r.Database.(sql.UnresolvedDatabase)
+	return !ok
+}
+
+// RowIter implements the Node interface. The indexes of the table are
+// deleted, as they are bound to the name of the table they were created on.
+func (r *RenameTable) RowIter(ctx *sql.Context) (sql.RowIter, error) {
+	renamer, ok := r.Database.(sql.TableRenamer)
+	if !ok {
+		return nil, ErrRenameTable.New(r.Database.Name())
+	}
+
+	tables := r.Database.Tables()
+	table, ok := tables[r.oldName]
+	if !ok {
+		return nil, sql.ErrTableNotFound.New(r.oldName)
+	}
+
+	if _, ok := tables[r.newName]; ok {
+		return nil, sql.ErrTableAlreadyExists.New(r.newName)
+	}
+
+	if err := deleteTableIndexes(ctx, r.Catalog, r.Database.Name(), table); err != nil {
+		return nil, err
+	}
+
+	return sql.RowsToRowIter(), renamer.RenameTable(r.oldName, r.newName)
+}
+
+// Schema implements the Node interface.
+func (r *RenameTable) Schema() sql.Schema { return nil }
+
+// Children implements the Node interface.
+func (r *RenameTable) Children() []sql.Node { return nil }
+
+// TransformUp implements the Transformable interface.
+func (r *RenameTable) TransformUp(f sql.TransformNodeFunc) (sql.Node, error) {
+	nc := *r
+	return f(&nc)
+}
+
+// TransformExpressionsUp implements the Transformable interface.
+func (r *RenameTable) TransformExpressionsUp(f sql.TransformExprFunc) (sql.Node, error) {
+	return r, nil
+}
+
+func (r *RenameTable) String() string {
+	return fmt.Sprintf("RenameTable(%s, %s)", r.oldName, r.newName)
+}
+
+// deleteTableIndexes deletes all the indexes of the given table, if there
+// is a catalog.
+func deleteTableIndexes(ctx *sql.Context, catalog *sql.Catalog, db string, table sql.Table) error {
+	if catalog == nil {
+		return nil
+	}
+
+	return catalog.DeleteTableIndexes(ctx, db, table)
+}
+
+// CreateDatabase is a node describing the creation of some database.
+type CreateDatabase struct {
+	Catalog     *sql.Catalog
+	name        string
+	ifNotExists bool
+}
+
+// NewCreateDatabase creates a new CreateDatabase node.
+func NewCreateDatabase(name string, ifNotExists bool) *CreateDatabase {
+	return &CreateDatabase{
+		name:        name,
+		ifNotExists: ifNotExists,
+	}
+}
+
+// Resolved implements the Resolvable interface.
+func (c *CreateDatabase) Resolved() bool { return true }
+
+// RowIter implements the Node interface.
+func (c *CreateDatabase) RowIter(ctx *sql.Context) (sql.RowIter, error) {
+	_, err := c.Catalog.CreateDatabase(ctx, c.name)
+	if err != nil && !(c.ifNotExists && sql.ErrDatabaseExists.Is(err)) {
+		return nil, err
+	}
+
+	return sql.RowsToRowIter(), nil
+}
+
+// Schema implements the Node interface.
+func (c *CreateDatabase) Schema() sql.Schema { return nil }
+
+// Children implements the Node interface.
+func (c *CreateDatabase) Children() []sql.Node { return nil }
+
+// TransformUp implements the Transformable interface.
+func (c *CreateDatabase) TransformUp(f sql.TransformNodeFunc) (sql.Node, error) {
+	nc := *c
+	return f(&nc)
+}
+
+// TransformExpressionsUp implements the Transformable interface.
+func (c *CreateDatabase) TransformExpressionsUp(f sql.TransformExprFunc) (sql.Node, error) {
+	return c, nil
+}
+
+func (c *CreateDatabase) String() string {
+	return fmt.Sprintf("CreateDatabase(%s)", c.name)
+}
+
+// DropDatabase is a node describing the removal of some database.
+type DropDatabase struct {
+	Catalog  *sql.Catalog
+	name     string
+	ifExists bool
+}
+
+// NewDropDatabase creates a new DropDatabase node.
+func NewDropDatabase(name string, ifExists bool) *DropDatabase {
+	return &DropDatabase{
+		name:     name,
+		ifExists: ifExists,
+	}
+}
+
+// Resolved implements the Resolvable interface.
+func (d *DropDatabase) Resolved() bool { return true }
+
+// RowIter implements the Node interface.
+func (d *DropDatabase) RowIter(ctx *sql.Context) (sql.RowIter, error) {
+	err := d.Catalog.DropDatabase(ctx, d.name)
+	if err != nil && !(d.ifExists && sql.ErrDatabaseNotFound.Is(err)) {
+		return nil, err
+	}
+
+	return sql.RowsToRowIter(), nil
+}
+
+// Schema implements the Node interface.
+func (d *DropDatabase) Schema() sql.Schema { return nil }
+
+// Children implements the Node interface.
+func (d *DropDatabase) Children() []sql.Node { return nil }
+
+// TransformUp implements the Transformable interface.
+func (d *DropDatabase) TransformUp(f sql.TransformNodeFunc) (sql.Node, error) {
+	nc := *d
+	return f(&nc)
+}
+
+// TransformExpressionsUp implements the Transformable interface.
+func (d *DropDatabase) TransformExpressionsUp(f sql.TransformExprFunc) (sql.Node, error) {
+	return d, nil
+}
+
+func (d *DropDatabase) String() string {
+	return fmt.Sprintf("DropDatabase(%s)", d.name)
+}
@@ -7,6 +7,7 @@ import (
 	"github.com/stretchr/testify/require"
 	"gopkg.in/src-d/go-mysql-server.v0/mem"
 	"gopkg.in/src-d/go-mysql-server.v0/sql"
+	"gopkg.in/src-d/go-mysql-server.v0/sql/expression"
 )
 
 func TestCreateTable(t *testing.T) {
@@ -43,3 +44,183 @@ func TestCreateTable(t *testing.T) {
 		require.Equal("testTable", s.Source)
 	}
 }
+
+func TestCreateTableForeignKeys(t *testing.T) {
+	require := require.New(t)
+	ctx := sql.NewEmptyContext()
+
+	db := mem.NewDatabase("test")
+	require.NoError(db.Create("parent", sql.Schema{
+		{Name: "id", Type: sql.Int64, Source: "parent", PrimaryKey: true},
+		{Name: "name", Type: sql.Text, Source: "parent"},
+	}))
+
+	create := func(name string, fk sql.ForeignKey) error {
+		_, err := NewCreateTable(db, name, sql.Schema{
+			{Name: "parent_id", Type: sql.Int64},
+		}).WithConstraints(nil, []sql.ForeignKey{fk}).RowIter(ctx)
+		return err
+	}
+
+	fk := sql.ForeignKey{
+		Name:              "fk",
+		Columns:           []string{"parent_id"},
+		ReferencedTable:   "parent",
+		ReferencedColumns: []string{"id"},
+	}
+
+	// The referenced columns must be a key.
+	invalid := fk
+	invalid.ReferencedColumns = []string{"name"}
+	err := create("child", invalid)
+	require.Error(err)
+	require.True(sql.ErrInvalidForeignKey.Is(err))
+
+	invalid = fk
+	invalid.ReferencedTable = "missing"
+	err = create("child", invalid)
+	require.Error(err)
+	require.True(sql.ErrInvalidForeignKey.Is(err))
+
+	invalid = fk
+	invalid.OnDelete = sql.ForeignKeySetNull
+	err = create("child", invalid)
+	require.Error(err)
+	require.True(sql.ErrInvalidForeignKey.Is(err))
+
+	require.NoError(create("child", fk))
+
+	child, ok := db.Tables()["child"].(sql.ConstraintTable)
+	require.True(ok)
+	require.Equal([]sql.ForeignKey{fk}, child.ForeignKeys())
+
+	_, err = NewDropTable(db, "parent", false).RowIter(ctx)
+	require.Error(err)
+	require.True(sql.ErrTableReferenced.Is(err))
+}
+
+func ddlTestCatalog(t *testing.T) (*sql.Catalog, *mem.Database, *mockDriver) {
+	t.Helper()
+	require := require.New(t)
+
+	table := mem.NewTable("foo", sql.Schema{
+		{Name: "a", Type: sql.Int64, Source: "foo"},
+	})
+
+	driver := new(mockDriver)
+	catalog := sql.NewCatalog()
+	catalog.RegisterIndexDriver(driver)
+	db := mem.NewDatabase("mydb")
+	db.AddTable("foo", table)
+	catalog.AddDatabase(db)
+
+	done, ready, err := catalog.AddIndex(&mockIndex{
+		id:    "idx",
+		db:    "mydb",
+		table: "foo",
+		exprs: []sql.Expression{
+			expression.NewGetFieldWithTable(0, sql.Int64, "foo", "a", false),
+		},
+	})
+	require.NoError(err)
+	close(done)
+	<-ready
+
+	return catalog, db, driver
+}
+
+func TestDropTable(t *testing.T) {
+	require := require.New(t)
+	catalog, db, driver := ddlTestCatalog(t)
+
+	d := NewDropTable(db, "foo", false)
+	d.Catalog = catalog
+	_, err := d.RowIter(sql.NewEmptyContext())
+	require.NoError(err)
+
+	_, ok := db.Tables()["foo"]
+	require.False(ok)
+	require.Equal([]string{"idx"}, driver.deleted)
+	require.Nil(catalog.Index("mydb", "idx"))
+
+	_, err = d.RowIter(sql.NewEmptyContext())
+	require.Error(err)
+	require.True(sql.ErrTableNotFound.Is(err))
+
+	_, err = NewDropTable(db, "foo", true).RowIter(sql.NewEmptyContext())
+	require.NoError(err)
+}
+
+func TestRenameTable(t *testing.T) {
+	require := require.New(t)
+	catalog, db, driver := ddlTestCatalog(t)
+
+	r := NewRenameTable(db, "foo", "bar")
+	r.Catalog = catalog
+	_, err := r.RowIter(sql.NewEmptyContext())
+	require.NoError(err)
+
+	_, ok := db.Tables()["foo"]
+	require.False(ok)
+
+	table, ok := db.Tables()["bar"]
+	require.True(ok)
+	require.Equal("bar", table.Name())
+	require.Equal("bar", table.Schema()[0].Source)
+
+	require.Equal([]string{"idx"}, driver.deleted)
+	require.Nil(catalog.Index("mydb", "idx"))
+
+	_, err = r.RowIter(sql.NewEmptyContext())
+	require.Error(err)
+	require.True(sql.ErrTableNotFound.Is(err))
+}
+
+func TestCreateDatabase(t *testing.T) {
+	require := require.New(t)
+	catalog := sql.NewCatalog()
+	catalog.DatabaseProvider = mem.NewDatabaseProvider()
+
+	c := NewCreateDatabase("foo", false)
+	c.Catalog = catalog
+	_, err := c.RowIter(sql.NewEmptyContext())
+	require.NoError(err)
+
+	db, err := catalog.Database("foo")
+	require.NoError(err)
+	require.Equal("foo", db.Name())
+
+	_, err = c.RowIter(sql.NewEmptyContext())
+	require.Error(err)
+	require.True(sql.ErrDatabaseExists.Is(err))
+
+	c = NewCreateDatabase("foo", true)
+	c.Catalog = catalog
+	_, err = c.RowIter(sql.NewEmptyContext())
+	require.NoError(err)
+}
+
+func TestDropDatabase(t *testing.T) {
+	require := require.New(t)
+	catalog, _, driver := ddlTestCatalog(t)
+	catalog.DatabaseProvider = mem.NewDatabaseProvider()
+
+	d := NewDropDatabase("mydb", false)
+	d.Catalog = catalog
+	_, err := d.RowIter(sql.NewEmptyContext())
+	require.NoError(err)
+
+	_, err = catalog.Database("mydb")
+	require.True(sql.ErrDatabaseNotFound.Is(err))
+	require.Equal([]string{"idx"}, driver.deleted)
+	require.Nil(catalog.Index("mydb", "idx"))
+
+	_, err = d.RowIter(sql.NewEmptyContext())
+	require.Error(err)
+	require.True(sql.ErrDatabaseNotFound.Is(err))
+
+	d = NewDropDatabase("mydb", true)
+	d.Catalog = catalog
+	_, err = d.RowIter(sql.NewEmptyContext())
+	require.NoError(err)
+}
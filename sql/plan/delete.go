@@ -0,0 +1,193 @@
+package plan
+
+import (
+	"io"
+	"strings"
+
+	"gopkg.in/src-d/go-errors.v1"
+	"gopkg.in/src-d/go-mysql-server.v0/sql"
+)
+
+// ErrDeleteNotSupported is thrown when a table doesn't support deletes.
+var ErrDeleteNotSupported = errors.NewKind("table doesn't support DELETE")
+
+// Delete is a node that deletes the rows returned by its child, which is
+// the table rows are deleted from, optionally filtered, sorted and limited.
+// The indexes of the table are marked as outdated once rows are deleted.
+type Delete struct {
+	UnaryNode
+	Catalog         *sql.Catalog
+	CurrentDatabase string
+}
+
+// NewDelete creates a Delete node.
+func NewDelete(child sql.Node) *Delete {
+	return &Delete{UnaryNode: UnaryNode{child}}
+}
+
+// Schema implements the Node interface.
+func (p *Delete) Schema() sql.Schema {
+	return sql.OkResultSchema
+}
+
+func getDeletable(node sql.Node) (sql.Deleter, error) {
+	switch node := node.(type) {
+	case sql.Deleter:
+		return node, nil
+	case *ResolvedTable:
+		return getDeletableTable(node.Table)
+	case *Filter:
+		return getDeletable(node.Child)
+	case *Sort:
+		return getDeletable(node.Child)
+	case *Limit:
+		return getDeletable(node.Child)
+	case *TableAlias:
+		return getDeletable(node.Child)
+	default:
+		return nil, ErrDeleteNotSupported.New()
+	}
+}
+
+func getDeletableTable(t sql.Table) (sql.Deleter, error) {
+	switch t := t.(type) {
+	case sql.Deleter:
+		return t, nil
+	case sql.TableWrapper:
+		return getDeletableTable(t.Underlying())
+	default:
+		return nil, ErrDeleteNotSupported.New()
+	}
+}
+
+// Execute deletes the rows in the database and returns the number of rows
+// that were deleted.
+func (p *Delete) Execute(ctx *sql.Context) (int, error) {
+	deletable, err := getDeletable(p.Child)
+	if err != nil {
+		return 0, err
+	}
+
+	iter, err := p.Child.RowIter(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	i := 0
+	for {
+		row, err := iter.Next()
+		if err == io.EOF {
+			break
+		}
+
+		if err != nil {
+			_ = iter.Close()
+			return i, err
+		}
+
+		if err := deletable.Delete(ctx, row); err != nil {
+			_ = iter.Close()
+			return i, err
+		}
+
+		i++
+	}
+
+	return i, iter.Close()
+}
+
+// RowIter implements the Node interface.
+func (p *Delete) RowIter(ctx *sql.Context) (sql.RowIter, error) {
+	n, err := p.Execute(ctx)
+	if n > 0 {
+		outdateTableIndexes(p.Catalog, p.CurrentDatabase, p.Child, true)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return sql.RowsToRowIter(sql.NewRow(int64(n))), nil
+}
+
+// TransformUp implements the Transformable interface.
+func (p *Delete) TransformUp(f sql.TransformNodeFunc) (sql.Node, error) {
+	child, err := p.Child.TransformUp(f)
+	if err != nil {
+		return nil, err
+	}
+
+	np := *p
+	np.Child = child
+	return f(&np)
+}
+
+// TransformExpressionsUp implements the Transformable interface.
+func (p *Delete) TransformExpressionsUp(f sql.TransformExprFunc) (sql.Node, error) {
+	child, err := p.Child.TransformExpressionsUp(f)
+	if err != nil {
+		return nil, err
+	}
+
+	np := *p
+	np.Child = child
+	return &np, nil
+}
+
+func (p *Delete) String() string {
+	pr := sql.NewTreePrinter()
+	_ = pr.WriteNode("Delete")
+	_ = pr.WriteChildren(p.Child.String())
+	return pr.String()
+}
+
+// outdateTableIndexes marks as outdated the indexes of the table rows were
+// deleted from or updated in by the given node, if there is a catalog. If
+// cascade is true, the same is done for the tables whose rows are deleted
+// or updated in cascade by their foreign keys.
+func outdateTableIndexes(catalog *sql.Catalog, db string, node sql.Node, cascade bool) {
+	if catalog == nil {
+		return
+	}
+
+	var table string
+	Inspect(node, func(n sql.Node) bool {
+		if t, ok := n.(*ResolvedTable); ok && table == "" {
+			table = t.Name()
+		}
+		return table == ""
+	})
+
+	catalog.MarkTableIndexesOutdated(db, table)
+	if !cascade {
+		return
+	}
+
+	database, err := catalog.Database(db)
+	if err != nil {
+		return
+	}
+
+	seen := map[string]struct{}{strings.ToLower(table): {}}
+	for pending := []string{table}; len(pending) > 0; pending = pending[1:] {
+		for _, t := range database.Tables() {
+			if _, ok := seen[strings.ToLower(t.Name())]; ok {
+				continue
+			}
+
+			ct, ok := t.(sql.ConstraintTable)
+			if !ok {
+				continue
+			}
+
+			for _, fk := range ct.ForeignKeys() {
+				if fk.References(pending[0]) && !fk.Restricts() {
+					seen[strings.ToLower(t.Name())] = struct{}{}
+					catalog.MarkTableIndexesOutdated(db, t.Name())
+					pending = append(pending, t.Name())
+					break
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,38 @@
+package plan
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"gopkg.in/src-d/go-mysql-server.v0/sql"
+	"gopkg.in/src-d/go-mysql-server.v0/sql/expression"
+)
+
+func TestDelete(t *testing.T) {
+	require := require.New(t)
+	table := writeTestTable(t)
+
+	del := NewDelete(
+		NewLimit(1,
+			NewSort(
+				[]SortField{{
+					Column: expression.NewGetFieldWithTable(0, sql.Int64, "foo", "a", false),
+					Order:  Descending,
+				}},
+				NewResolvedTable(table),
+			),
+		),
+	)
+
+	require.Equal([]sql.Row{{int64(1)}}, collectRows(t, del))
+	require.Equal([]sql.Row{
+		{int64(1), "a"},
+		{int64(2), "b"},
+	}, collectRows(t, NewResolvedTable(table)))
+
+	require.Equal(
+		[]sql.Row{{int64(2)}},
+		collectRows(t, NewDelete(NewResolvedTable(table))),
+	)
+	require.Nil(collectRows(t, NewResolvedTable(table)))
+}
@@ -34,7 +34,7 @@ func (d *Distinct) RowIter(ctx *sql.Context) (sql.RowIter, error) {
 		return nil, err
 	}
 
-	return sql.NewSpanIter(span, newDistinctIter(it)), nil
+	return sql.NewSpanIter(span, newDistinctIter(it, d.Child.Schema())), nil
 }
 
 // TransformUp implements the Transformable interface.
@@ -69,12 +69,14 @@ func (d Distinct) String() string {
 // result sets.
 type distinctIter struct {
 	childIter sql.RowIter
+	schema    sql.Schema
 	seen      map[uint64]struct{}
 }
 
-func newDistinctIter(child sql.RowIter) *distinctIter {
+func newDistinctIter(child sql.RowIter, schema sql.Schema) *distinctIter {
 	return &distinctIter{
 		childIter: child,
+		schema:    schema,
 		seen:      make(map[uint64]struct{}),
 	}
 }
@@ -86,7 +88,7 @@ func (di *distinctIter) Next() (sql.Row, error) {
 			return nil, err
 		}
 
-		hash, err := hashstructure.Hash(row, nil)
+		hash, err := hashstructure.Hash(collationKeys(di.schema, row), nil)
 		if err != nil {
 			return nil, fmt.Errorf("unable to hash row: %s", err)
 		}
@@ -104,6 +106,35 @@ func (di *distinctIter) Close() error {
 	return di.childIter.Close()
 }
 
+// collationKeys returns the given row with the strings in columns with a
+// case insensitive collation replaced by their collation keys, so rows that
+// are equal using the collations of their columns are hashed the same.
+func collationKeys(schema sql.Schema, row sql.Row) sql.Row {
+	var result sql.Row
+	for i, col := range schema {
+		c, ok := sql.CollationOf(col.Type)
+		if !ok || !c.CaseInsensitive || i >= len(row) {
+			continue
+		}
+
+		s, ok := row[i].(string)
+		if !ok {
+			continue
+		}
+
+		if result == nil {
+			result = row.Copy()
+		}
+		result[i] = c.Key(s)
+	}
+
+	if result == nil {
+		return row
+	}
+
+	return result
+}
+
 // OrderedDistinct is a Distinct node optimized for sorted row sets.
 // It's 2 orders of magnitude faster and uses 2 orders of magnitude less mem.
 type OrderedDistinct struct {
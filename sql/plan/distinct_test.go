@@ -57,6 +57,36 @@ func TestDistinct(t *testing.T) {
 	require.Equal([]string{"john", "jane", "martha"}, results)
 }
 
+func TestDistinctCollation(t *testing.T) {
+	require := require.New(t)
+	ctx := sql.NewEmptyContext()
+
+	ci := sql.TextWithCollation(sql.CollationUtf8GeneralCI)
+	child := mem.NewTable("test", sql.Schema{
+		{Name: "name", Type: ci, Nullable: true},
+	})
+
+	for _, r := range []sql.Row{
+		sql.NewRow("john"),
+		sql.NewRow("JOHN"),
+		sql.NewRow("jane"),
+		sql.NewRow("John "),
+	} {
+		require.NoError(child.Insert(sql.NewEmptyContext(), r))
+	}
+
+	d := NewDistinct(NewProject([]sql.Expression{
+		expression.NewGetField(0, ci, "name", true),
+	}, NewResolvedTable(child)))
+
+	iter, err := d.RowIter(ctx)
+	require.NoError(err)
+
+	rows, err := sql.RowIterToRows(iter)
+	require.NoError(err)
+	require.Equal([]sql.Row{{"john"}, {"jane"}}, rows)
+}
+
 func TestOrderedDistinct(t *testing.T) {
 	require := require.New(t)
 	ctx := sql.NewEmptyContext()
@@ -309,6 +309,15 @@ func groupingKey(
 		if err != nil {
 			return 0, err
 		}
+
+		// Strings that are equal using the collation of the expression
+		// must be in the same group.
+		if s, ok := v.(string); ok {
+			if c, ok := sql.CollationOf(expr.Type()); ok {
+				v = c.Key(s)
+			}
+		}
+
 		vals = append(vals, fmt.Sprintf("%#v", v))
 	}
 
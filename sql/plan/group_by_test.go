@@ -96,6 +96,43 @@ func TestGroupBy_RowIter(t *testing.T) {
 	require.Equal(sql.NewRow("col1_2", int64(4444)), rows[1])
 }
 
+func TestGroupBy_Collation(t *testing.T) {
+	require := require.New(t)
+	ctx := sql.NewEmptyContext()
+
+	ci := sql.TextWithCollation(sql.CollationUtf8GeneralCI)
+	child := mem.NewTable("test", sql.Schema{
+		{Name: "col1", Type: ci},
+	})
+
+	for _, r := range []sql.Row{
+		sql.NewRow("A"),
+		sql.NewRow("a "),
+		sql.NewRow("b"),
+		sql.NewRow("a"),
+	} {
+		require.NoError(child.Insert(sql.NewEmptyContext(), r))
+	}
+
+	p := NewGroupBy(
+		[]sql.Expression{
+			expression.NewGetField(0, ci, "col1", true),
+			aggregation.NewCount(expression.NewStar()),
+		},
+		[]sql.Expression{
+			expression.NewGetField(0, ci, "col1", true),
+		},
+		NewResolvedTable(child),
+	)
+
+	rows, err := sql.NodeToRows(ctx, p)
+	require.NoError(err)
+	require.ElementsMatch([]sql.Row{
+		{"a", int64(3)},
+		{"b", int64(1)},
+	}, rows)
+}
+
 func TestGroupBy_EvalEmptyBuffer(t *testing.T) {
 	require := require.New(t)
 	ctx := sql.NewEmptyContext()
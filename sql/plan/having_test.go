@@ -0,0 +1,52 @@
+package plan
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"gopkg.in/src-d/go-mysql-server.v0/mem"
+	"gopkg.in/src-d/go-mysql-server.v0/sql"
+	"gopkg.in/src-d/go-mysql-server.v0/sql/expression"
+	"gopkg.in/src-d/go-mysql-server.v0/sql/expression/function/aggregation"
+)
+
+func TestHaving(t *testing.T) {
+	require := require.New(t)
+
+	child := mem.NewTable("test", sql.Schema{
+		{Name: "col1", Source: "test", Type: sql.Text},
+		{Name: "col2", Source: "test", Type: sql.Int64},
+	})
+
+	rows := []sql.Row{
+		sql.NewRow("col1_1", int64(1111)),
+		sql.NewRow("col1_1", int64(1111)),
+		sql.NewRow("col1_2", int64(4444)),
+		sql.NewRow("col1_1", int64(1111)),
+	}
+
+	for _, r := range rows {
+		require.NoError(child.Insert(sql.NewEmptyContext(), r))
+	}
+
+	h := NewHaving(
+		expression.NewGreaterThan(
+			expression.NewGetField(1, sql.Int32, "COUNT(test.col1)", false),
+			expression.NewLiteral(int32(1), sql.Int32),
+		),
+		NewGroupBy(
+			[]sql.Expression{
+				expression.NewGetFieldWithTable(0, sql.Text, "test", "col1", false),
+				aggregation.NewCount(expression.NewGetFieldWithTable(0, sql.Text, "test", "col1", false)),
+			},
+			[]sql.Expression{
+				expression.NewGetFieldWithTable(0, sql.Text, "test", "col1", false),
+			},
+			NewResolvedTable(child),
+		),
+	)
+
+	require.True(h.Resolved())
+	require.Equal(h.Child.Schema(), h.Schema())
+	require.Equal([]sql.Row{{"col1_1", int32(3)}}, collectRows(t, h))
+}
@@ -1,39 +1,80 @@
 package plan
 
 import (
+	"fmt"
 	"io"
 	"strings"
 
 	"gopkg.in/src-d/go-errors.v1"
 	"gopkg.in/src-d/go-mysql-server.v0/sql"
 	"gopkg.in/src-d/go-mysql-server.v0/sql/expression"
+	"gopkg.in/src-d/go-vitess.v1/sqltypes"
 )
 
 // ErrInsertIntoNotSupported is thrown when a table doesn't support inserts
 var ErrInsertIntoNotSupported = errors.NewKind("table doesn't support INSERT INTO")
 
+// ErrDuplicateRowNotFound is thrown when a table reports a duplicate entry
+// but the row with the duplicated values can't be found.
+var ErrDuplicateRowNotFound = errors.NewKind("could not find the row with the duplicate entry")
+
 // InsertInto is a node describing the insertion into some table.
 type InsertInto struct {
 	BinaryNode
 	Columns []string
+	// IsReplace is true for REPLACE statements, which delete the rows with
+	// the same values in a primary or unique key before inserting the new
+	// ones.
+	IsReplace bool
+	// Ignore is true for INSERT IGNORE statements, which skip the rows that
+	// would be duplicated and turn errors converting the values into
+	// warnings.
+	Ignore bool
+	// OnDupExprs are the expressions of the ON DUPLICATE KEY UPDATE clause,
+	// which update the existing row instead of inserting a row that would
+	// be duplicated.
+	OnDupExprs []sql.Expression
 }
 
 // NewInsertInto creates an InsertInto node.
-func NewInsertInto(dst, src sql.Node, cols []string) *InsertInto {
+func NewInsertInto(
+	dst, src sql.Node,
+	isReplace, ignore bool,
+	cols []string,
+	onDupExprs []sql.Expression,
+) *InsertInto {
 	return &InsertInto{
 		BinaryNode: BinaryNode{Left: dst, Right: src},
 		Columns:    cols,
+		IsReplace:  isReplace,
+		Ignore:     ignore,
+		OnDupExprs: onDupExprs,
 	}
 }
 
+// Resolved implements the Resolvable interface.
+func (p *InsertInto) Resolved() bool {
+	return p.BinaryNode.Resolved() && expressionsResolved(p.OnDupExprs...)
+}
+
+// Expressions implements the Expressioner interface.
+func (p *InsertInto) Expressions() []sql.Expression {
+	return p.OnDupExprs
+}
+
+// TransformExpressions implements the Expressioner interface.
+func (p *InsertInto) TransformExpressions(f sql.TransformExprFunc) (sql.Node, error) {
+	exprs, err := transformExpressionsUp(f, p.OnDupExprs)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewInsertInto(p.Left, p.Right, p.IsReplace, p.Ignore, p.Columns, exprs), nil
+}
+
 // Schema implements the Node interface.
 func (p *InsertInto) Schema() sql.Schema {
-	return sql.Schema{{
-		Name:     "updated",
-		Type:     sql.Int64,
-		Default:  int64(0),
-		Nullable: false,
-	}}
+	return sql.OkResultSchema
 }
 
 func getInsertable(node sql.Node) (sql.Inserter, error) {
@@ -58,6 +99,57 @@ func getInsertableTable(t sql.Table) (sql.Inserter, error) {
 	}
 }
 
+// getTableKeys returns the keys of the given table node, if its table
+// enforces them.
+func getTableKeys(node sql.Node) []sql.Key {
+	if t, ok := node.(*ResolvedTable); ok {
+		return getKeys(t.Table)
+	}
+	return nil
+}
+
+func getKeys(t sql.Table) []sql.Key {
+	switch t := t.(type) {
+	case sql.PrimaryKeyTable:
+		return t.Keys()
+	case sql.TableWrapper:
+		return getKeys(t.Underlying())
+	default:
+		return nil
+	}
+}
+
+// getAutoIncrementTable returns the table of the given table node if it
+// generates values for an AUTO_INCREMENT column.
+func getAutoIncrementTable(node sql.Node) sql.AutoIncrementTable {
+	if t, ok := node.(*ResolvedTable); ok {
+		return getAutoIncrement(t.Table)
+	}
+	return nil
+}
+
+func getAutoIncrement(t sql.Table) sql.AutoIncrementTable {
+	switch t := t.(type) {
+	case sql.AutoIncrementTable:
+		return t
+	case sql.TableWrapper:
+		return getAutoIncrement(t.Underlying())
+	default:
+		return nil
+	}
+}
+
+// autoIncrementColumn returns the position of the AUTO_INCREMENT column in
+// the schema, or -1 if there is none.
+func autoIncrementColumn(schema sql.Schema) int {
+	for i, col := range schema {
+		if col.AutoIncrement {
+			return i
+		}
+	}
+	return -1
+}
+
 // Execute inserts the rows in the database.
 func (p *InsertInto) Execute(ctx *sql.Context) (int, error) {
 	insertable, err := getInsertable(p.Left)
@@ -67,6 +159,9 @@ func (p *InsertInto) Execute(ctx *sql.Context) (int, error) {
 
 	dstSchema := p.Left.Schema()
 	projExprs := make([]sql.Expression, len(dstSchema))
+	// Columns that are not given a value, can't be NULL and have no
+	// default value, so the rows can't be inserted in strict mode.
+	var noDefault []int
 	for i, f := range dstSchema {
 		found := false
 		for j, col := range p.Columns {
@@ -78,11 +173,52 @@ func (p *InsertInto) Execute(ctx *sql.Context) (int, error) {
 		}
 
 		if !found {
-			def, _ := f.Type.Convert(nil)
+			def, err := f.DefaultValue()
+			if err != nil {
+				return 0, err
+			}
+
+			if def == nil && !f.Nullable && !f.AutoIncrement {
+				noDefault = append(noDefault, i)
+			}
+
 			projExprs[i] = expression.NewLiteral(def, f.Type)
 		}
 	}
 
+	var deleter sql.Deleter
+	if p.IsReplace {
+		deleter, err = getDeletable(p.Left)
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	var updater sql.Updater
+	var onDupExprs []sql.Expression
+	if len(p.OnDupExprs) > 0 {
+		updater, err = getUpdatable(p.Left)
+		if err != nil {
+			return 0, err
+		}
+
+		onDupExprs, err = valuesToFields(p.OnDupExprs, len(dstSchema))
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	var keys []sql.Key
+	if p.IsReplace || len(p.OnDupExprs) > 0 {
+		keys = getTableKeys(p.Left)
+	}
+
+	var autoIncrement sql.AutoIncrementTable
+	autoIdx := autoIncrementColumn(dstSchema)
+	if autoIdx >= 0 {
+		autoIncrement = getAutoIncrementTable(p.Left)
+	}
+
 	proj := NewProject(projExprs, p.Right)
 
 	iter, err := proj.RowIter(ctx)
@@ -90,7 +226,8 @@ func (p *InsertInto) Execute(ctx *sql.Context) (int, error) {
 		return 0, err
 	}
 
-	i := 0
+	var affected, rowNum int
+	var insertID uint64
 	for {
 		row, err := iter.Next()
 		if err == io.EOF {
@@ -99,18 +236,276 @@ func (p *InsertInto) Execute(ctx *sql.Context) (int, error) {
 
 		if err != nil {
 			_ = iter.Close()
-			return i, err
+			return affected, err
 		}
 
-		if err := insertable.Insert(ctx, row); err != nil {
+		rowNum++
+		row, err = fillNoDefaultColumns(ctx, dstSchema, noDefault, row, p.Ignore)
+		if err != nil {
 			_ = iter.Close()
-			return i, err
+			return affected, err
+		}
+
+		var id uint64
+		if autoIncrement != nil {
+			row, id, err = generateAutoIncrement(ctx, autoIncrement, dstSchema[autoIdx], autoIdx, row)
+			if err != nil {
+				_ = iter.Close()
+				return affected, err
+			}
+		}
+
+		row, err = convertRow(ctx, dstSchema, row, rowNum, p.Ignore)
+		if err != nil {
+			_ = iter.Close()
+			return affected, err
+		}
+
+		if p.IsReplace {
+			n, err := deleteDuplicates(ctx, p.Left, deleter, keys, row)
+			if err != nil {
+				_ = iter.Close()
+				return affected, err
+			}
+			affected += n
 		}
 
-		i++
+		err = insertable.Insert(ctx, row)
+		if err != nil && len(onDupExprs) > 0 && sql.ErrDuplicateEntry.Is(err) {
+			var n int
+			n, err = p.updateDuplicate(ctx, updater, keys, onDupExprs, row, rowNum)
+			if err == nil {
+				affected += n
+				continue
+			}
+		}
+
+		if err != nil {
+			if p.Ignore && sql.ErrDuplicateEntry.Is(err) {
+				ctx.Warn(1062, "%s", err)
+				continue
+			}
+
+			_ = iter.Close()
+			return affected, err
+		}
+
+		if insertID == 0 {
+			insertID = id
+		}
+
+		affected++
 	}
 
-	return i, nil
+	if insertID > 0 {
+		ctx.SetInsertID(insertID)
+		ctx.Session.SetLastInsertID(insertID)
+	}
+
+	return affected, nil
+}
+
+// fillNoDefaultColumns sets the zero value of their type in the columns at
+// the given positions, which were not given a value and have no default
+// value, adding a warning for each of them. In strict mode, it returns an
+// error instead, unless ignore is true.
+func fillNoDefaultColumns(
+	ctx *sql.Context,
+	schema sql.Schema,
+	positions []int,
+	row sql.Row,
+	ignore bool,
+) (sql.Row, error) {
+	if len(positions) == 0 {
+		return row, nil
+	}
+
+	if sql.StrictMode(ctx.Session) && !ignore {
+		return nil, sql.ErrNoDefaultValue.New(schema[positions[0]].Name)
+	}
+
+	row = row.Copy()
+	for _, i := range positions {
+		// Types without a zero value, such as DATETIME, can't be filled.
+		v, err := schema[i].Type.Convert(nil)
+		if err != nil {
+			return nil, sql.ErrNoDefaultValue.New(schema[i].Name)
+		}
+
+		ctx.Warn(1364, "Field '%s' doesn't have a default value", schema[i].Name)
+		row[i] = v
+	}
+
+	return row, nil
+}
+
+// generateAutoIncrement returns the row with the next value of the
+// AUTO_INCREMENT column if the row has no value for it, that is, it's NULL
+// or 0, and the generated value. The generated value is 0 if the row
+// already had a value.
+func generateAutoIncrement(
+	ctx *sql.Context,
+	table sql.AutoIncrementTable,
+	col *sql.Column,
+	idx int,
+	row sql.Row,
+) (sql.Row, uint64, error) {
+	if row[idx] != nil {
+		v, err := sql.Int64.Convert(row[idx])
+		if err != nil || v.(int64) != 0 {
+			return row, 0, nil
+		}
+	}
+
+	id, err := table.NextAutoIncrementValue(ctx, sql.AutoIncrementIncrement(ctx.Session))
+	if err != nil {
+		return nil, 0, err
+	}
+
+	v, err := col.Type.Convert(id)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	row = row.Copy()
+	row[idx] = v
+	return row, id, nil
+}
+
+// updateDuplicate applies the ON DUPLICATE KEY UPDATE expressions to the
+// row of the table that has the same values as the given row in the columns
+// of a key. As in MySQL, it returns 2 affected rows if the row was updated
+// and 0 if its values didn't change.
+func (p *InsertInto) updateDuplicate(
+	ctx *sql.Context,
+	updater sql.Updater,
+	keys []sql.Key,
+	exprs []sql.Expression,
+	row sql.Row,
+	rowNum int,
+) (int, error) {
+	schema := p.Left.Schema()
+	old, err := findDuplicate(ctx, p.Left, keys, row)
+	if err != nil {
+		return 0, err
+	}
+
+	if old == nil {
+		return 0, ErrDuplicateRowNotFound.New()
+	}
+
+	// The expressions are evaluated on the existing row followed by the new
+	// one, so VALUES(col) can get the values of the new row.
+	newRow, err := applyUpdateExpressions(ctx, exprs, append(old.Copy(), row...))
+	if err != nil {
+		return 0, err
+	}
+
+	newRow, err = convertRow(ctx, schema, newRow[:len(schema)], rowNum, p.Ignore)
+	if err != nil {
+		return 0, err
+	}
+
+	equal, err := old.Equals(newRow, schema)
+	if err != nil {
+		return 0, err
+	}
+
+	if equal {
+		return 0, nil
+	}
+
+	if err := updater.Update(ctx, old, newRow); err != nil {
+		return 0, err
+	}
+
+	return 2, nil
+}
+
+// valuesToFields replaces the VALUES(col) expressions with fields that get
+// the value of the column in the new row, which is placed after the given
+// number of columns of the existing row.
+func valuesToFields(exprs []sql.Expression, offset int) ([]sql.Expression, error) {
+	return transformExpressionsUp(func(e sql.Expression) (sql.Expression, error) {
+		v, ok := e.(*expression.Values)
+		if !ok {
+			return e, nil
+		}
+
+		gf, ok := v.Child.(*expression.GetField)
+		if !ok {
+			return e, nil
+		}
+
+		return gf.WithIndex(gf.Index() + offset), nil
+	}, exprs)
+}
+
+// findDuplicate returns the row of the given table node that has the same
+// values as the given row in the columns of any of the keys, or nil if
+// there is none.
+func findDuplicate(ctx *sql.Context, table sql.Node, keys []sql.Key, row sql.Row) (sql.Row, error) {
+	rows, err := sql.NodeToRows(ctx, table)
+	if err != nil {
+		return nil, err
+	}
+
+	schema := table.Schema()
+	for _, r := range rows {
+		for _, k := range keys {
+			dup, err := k.Duplicates(schema, r, row)
+			if err != nil {
+				return nil, err
+			}
+
+			if dup {
+				return r, nil
+			}
+		}
+	}
+
+	return nil, nil
+}
+
+// deleteDuplicates deletes the rows of the given table node that have the
+// same values as the given row in the columns of any of the keys, and
+// returns how many rows were deleted.
+func deleteDuplicates(
+	ctx *sql.Context,
+	table sql.Node,
+	deleter sql.Deleter,
+	keys []sql.Key,
+	row sql.Row,
+) (int, error) {
+	if len(keys) == 0 {
+		return 0, nil
+	}
+
+	rows, err := sql.NodeToRows(ctx, table)
+	if err != nil {
+		return 0, err
+	}
+
+	schema := table.Schema()
+	var deleted int
+	for _, r := range rows {
+		for _, k := range keys {
+			dup, err := k.Duplicates(schema, r, row)
+			if err != nil {
+				return deleted, err
+			}
+
+			if dup {
+				if err := deleter.Delete(ctx, r); err != nil {
+					return deleted, err
+				}
+				deleted++
+				break
+			}
+		}
+	}
+
+	return deleted, nil
 }
 
 // RowIter implements the Node interface.
@@ -135,7 +530,7 @@ func (p *InsertInto) TransformUp(f sql.TransformNodeFunc) (sql.Node, error) {
 		return nil, err
 	}
 
-	return f(NewInsertInto(left, right, p.Columns))
+	return f(NewInsertInto(left, right, p.IsReplace, p.Ignore, p.Columns, p.OnDupExprs))
 }
 
 // TransformExpressionsUp implements the Transformable interface.
@@ -150,12 +545,119 @@ func (p *InsertInto) TransformExpressionsUp(f sql.TransformExprFunc) (sql.Node,
 		return nil, err
 	}
 
-	return NewInsertInto(left, right, p.Columns), nil
+	exprs, err := transformExpressionsUp(f, p.OnDupExprs)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewInsertInto(left, right, p.IsReplace, p.Ignore, p.Columns, exprs), nil
 }
 
 func (p InsertInto) String() string {
 	pr := sql.NewTreePrinter()
-	_ = pr.WriteNode("Insert(%s)", strings.Join(p.Columns, ", "))
-	_ = pr.WriteChildren(p.Left.String(), p.Right.String())
+	var name = "Insert"
+	switch {
+	case p.IsReplace:
+		name = "Replace"
+	case p.Ignore:
+		name = "InsertIgnore"
+	}
+	_ = pr.WriteNode("%s(%s)", name, strings.Join(p.Columns, ", "))
+	children := []string{p.Left.String(), p.Right.String()}
+	if len(p.OnDupExprs) > 0 {
+		var exprs = make([]string, len(p.OnDupExprs))
+		for i, e := range p.OnDupExprs {
+			exprs[i] = e.String()
+		}
+		children = append(children, fmt.Sprintf("OnDuplicateKeyUpdate(%s)", strings.Join(exprs, ", ")))
+	}
+	_ = pr.WriteChildren(children...)
 	return pr.String()
 }
+
+// convertRow converts the values of the row whose column types limit their
+// length, range or precision, such as VARCHAR, TINYINT or DATETIME(3), and
+// checks that columns that are not nullable don't get a NULL value. In
+// strict mode, values that don't fit in their column are an error.
+// Otherwise, they're truncated and a warning is added to the session. If
+// ignore is true, as in INSERT IGNORE, errors are always warnings.
+func convertRow(ctx *sql.Context, schema sql.Schema, row sql.Row, rowNum int, ignore bool) (sql.Row, error) {
+	strict := sql.StrictMode(ctx.Session) && !ignore
+
+	var result sql.Row
+	for i, col := range schema {
+		if row[i] == nil {
+			if col.Nullable {
+				continue
+			}
+
+			if !ignore {
+				return nil, sql.ErrColumnCannotBeNull.New(col.Name)
+			}
+
+			if result == nil {
+				result = row.Copy()
+			}
+
+			// The column gets the zero value of its type instead.
+			ctx.Warn(1048, "Column '%s' cannot be null", col.Name)
+			v, err := col.Type.Convert(nil)
+			if err != nil {
+				return nil, err
+			}
+			result[i] = v
+			continue
+		}
+
+		if !isBoundedType(col.Type) {
+			continue
+		}
+
+		if result == nil {
+			result = row.Copy()
+		}
+
+		if strict {
+			v, err := col.Type.Convert(row[i])
+			if err != nil {
+				return nil, err
+			}
+
+			result[i] = v
+			continue
+		}
+
+		v, truncated, err := sql.Truncate(col.Type, row[i])
+		if err != nil {
+			return nil, err
+		}
+
+		if truncated {
+			if sql.IsText(col.Type) || sql.IsEnum(col.Type) || sql.IsSet(col.Type) {
+				ctx.Warn(1265, "Data truncated for column '%s' at row %d", col.Name, rowNum)
+			} else {
+				ctx.Warn(1264, "Out of range value for column '%s' at row %d", col.Name, rowNum)
+			}
+		}
+
+		result[i] = v
+	}
+
+	if result == nil {
+		return row, nil
+	}
+
+	return result, nil
+}
+
+func isBoundedType(t sql.Type) bool {
+	switch t.Type() {
+	case sqltypes.Int8, sqltypes.Uint8, sqltypes.Int16, sqltypes.Uint16,
+		sqltypes.Int24, sqltypes.Uint24, sqltypes.Char, sqltypes.VarChar,
+		sqltypes.Binary, sqltypes.VarBinary, sqltypes.Datetime, sqltypes.Time,
+		sqltypes.Year, sqltypes.Enum, sqltypes.Set:
+		return true
+	default:
+		return false
+	}
+}
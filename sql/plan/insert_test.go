@@ -0,0 +1,254 @@
+package plan
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"gopkg.in/src-d/go-mysql-server.v0/mem"
+	"gopkg.in/src-d/go-mysql-server.v0/sql"
+	"gopkg.in/src-d/go-mysql-server.v0/sql/expression"
+)
+
+func TestInsertIntoTruncate(t *testing.T) {
+	require := require.New(t)
+
+	table := mem.NewTable("foo", sql.Schema{
+		{Name: "a", Type: sql.VarChar(3), Source: "foo"},
+		{Name: "b", Type: sql.Int8, Source: "foo"},
+	})
+
+	insert := NewInsertInto(
+		NewResolvedTable(table),
+		NewValues([][]sql.Expression{{
+			expression.NewLiteral("abcd", sql.Text),
+			expression.NewLiteral(int64(300), sql.Int64),
+		}}),
+		false,
+		false,
+		[]string{"a", "b"},
+		nil,
+	)
+
+	ctx := sql.NewEmptyContext()
+	n, err := insert.Execute(ctx)
+	require.NoError(err)
+	require.Equal(1, n)
+	require.Equal(uint16(2), ctx.WarningCount())
+
+	require.Equal([]sql.Row{
+		{"abc", int8(127)},
+	}, collectRows(t, NewResolvedTable(table)))
+
+	ctx = sql.NewEmptyContext()
+	ctx.Set("sql_mode", sql.Text, "STRICT_TRANS_TABLES")
+	_, err = insert.Execute(ctx)
+	require.Error(err)
+	require.True(sql.ErrLengthTooLong.Is(err))
+}
+
+func TestInsertIntoKeys(t *testing.T) {
+	require := require.New(t)
+
+	table := mem.NewTable("foo", sql.Schema{
+		{Name: "a", Type: sql.Int64, Source: "foo", PrimaryKey: true},
+		{Name: "b", Type: sql.Text, Source: "foo", Nullable: true, Unique: true},
+	})
+
+	values := func(rows ...sql.Row) sql.Node {
+		var exprs = make([][]sql.Expression, len(rows))
+		for i, row := range rows {
+			exprs[i] = []sql.Expression{
+				expression.NewLiteral(row[0], sql.Int64),
+				expression.NewLiteral(row[1], sql.Text),
+			}
+		}
+		return NewValues(exprs)
+	}
+
+	insert := func(isReplace, ignore bool, rows ...sql.Row) (*sql.Context, int, error) {
+		ctx := sql.NewEmptyContext()
+		n, err := NewInsertInto(
+			NewResolvedTable(table),
+			values(rows...),
+			isReplace,
+			ignore,
+			[]string{"a", "b"},
+			nil,
+		).Execute(ctx)
+		return ctx, n, err
+	}
+
+	_, n, err := insert(false, false, sql.Row{int64(1), "a"}, sql.Row{int64(2), "b"})
+	require.NoError(err)
+	require.Equal(2, n)
+
+	_, _, err = insert(false, false, sql.Row{int64(1), "c"})
+	require.Error(err)
+	require.True(sql.ErrDuplicateEntry.Is(err))
+
+	ctx, n, err := insert(false, true, sql.Row{int64(1), "c"}, sql.Row{int64(3), "c"})
+	require.NoError(err)
+	require.Equal(1, n)
+	require.Equal(uint16(1), ctx.WarningCount())
+
+	// The new row conflicts with two rows, which are both replaced.
+	_, n, err = insert(true, false, sql.Row{int64(1), "b"})
+	require.NoError(err)
+	require.Equal(3, n)
+
+	require.ElementsMatch([]sql.Row{
+		{int64(1), "b"},
+		{int64(3), "c"},
+	}, collectRows(t, NewResolvedTable(table)))
+
+	_, _, err = insert(false, false, sql.Row{nil, "d"})
+	require.Error(err)
+	require.True(sql.ErrColumnCannotBeNull.Is(err))
+
+	ctx, n, err = insert(false, true, sql.Row{nil, "d"})
+	require.NoError(err)
+	require.Equal(1, n)
+	require.Equal(uint16(1), ctx.WarningCount())
+
+	require.ElementsMatch([]sql.Row{
+		{int64(0), "d"},
+		{int64(1), "b"},
+		{int64(3), "c"},
+	}, collectRows(t, NewResolvedTable(table)))
+}
+
+func TestInsertIntoAutoIncrement(t *testing.T) {
+	require := require.New(t)
+
+	table := mem.NewTable("foo", sql.Schema{
+		{Name: "a", Type: sql.Int64, Source: "foo", PrimaryKey: true, AutoIncrement: true},
+		{Name: "b", Type: sql.Text, Source: "foo"},
+	})
+
+	insert := func(ctx *sql.Context, rows ...sql.Row) {
+		var exprs = make([][]sql.Expression, len(rows))
+		for i, row := range rows {
+			exprs[i] = []sql.Expression{
+				expression.NewLiteral(row[0], sql.Int64),
+				expression.NewLiteral(row[1], sql.Text),
+			}
+		}
+
+		_, err := NewInsertInto(
+			NewResolvedTable(table),
+			NewValues(exprs),
+			false,
+			false,
+			[]string{"a", "b"},
+			nil,
+		).Execute(ctx)
+		require.NoError(err)
+	}
+
+	ctx := sql.NewEmptyContext()
+	insert(ctx, sql.Row{nil, "a"}, sql.Row{int64(0), "b"})
+	require.Equal(uint64(1), ctx.LastInsertID())
+
+	// Explicit values are kept and don't change the last insert id.
+	insert(ctx, sql.Row{int64(10), "c"})
+	require.Equal(uint64(1), ctx.LastInsertID())
+
+	ctx.Set("auto_increment_increment", sql.Int64, int64(5))
+	insert(ctx, sql.Row{nil, "d"})
+	require.Equal(uint64(11), ctx.LastInsertID())
+
+	require.ElementsMatch([]sql.Row{
+		{int64(1), "a"},
+		{int64(2), "b"},
+		{int64(10), "c"},
+		{int64(11), "d"},
+	}, collectRows(t, NewResolvedTable(table)))
+}
+
+func TestInsertIntoOnDuplicateKeyUpdate(t *testing.T) {
+	require := require.New(t)
+
+	table := mem.NewTable("foo", sql.Schema{
+		{Name: "a", Type: sql.Int64, Source: "foo", PrimaryKey: true},
+		{Name: "b", Type: sql.Int64, Source: "foo"},
+	})
+
+	b := expression.NewGetFieldWithTable(1, sql.Int64, "foo", "b", false)
+	onDup := []sql.Expression{
+		expression.NewSetField(b, expression.NewPlus(b, expression.NewValues(b))),
+	}
+
+	insert := func(rows ...sql.Row) int {
+		var exprs = make([][]sql.Expression, len(rows))
+		for i, row := range rows {
+			exprs[i] = []sql.Expression{
+				expression.NewLiteral(row[0], sql.Int64),
+				expression.NewLiteral(row[1], sql.Int64),
+			}
+		}
+
+		n, err := NewInsertInto(
+			NewResolvedTable(table),
+			NewValues(exprs),
+			false,
+			false,
+			[]string{"a", "b"},
+			onDup,
+		).Execute(sql.NewEmptyContext())
+		require.NoError(err)
+		return n
+	}
+
+	require.Equal(2, insert(sql.Row{int64(1), int64(1)}, sql.Row{int64(2), int64(2)}))
+
+	// Inserted rows count as 1 and updated rows as 2.
+	require.Equal(3, insert(sql.Row{int64(1), int64(5)}, sql.Row{int64(3), int64(3)}))
+
+	// Rows whose values don't change are not affected.
+	require.Equal(0, insert(sql.Row{int64(2), int64(0)}))
+
+	require.ElementsMatch([]sql.Row{
+		{int64(1), int64(6)},
+		{int64(2), int64(2)},
+		{int64(3), int64(3)},
+	}, collectRows(t, NewResolvedTable(table)))
+}
+
+func TestInsertIntoDefaults(t *testing.T) {
+	require := require.New(t)
+
+	table := mem.NewTable("foo", sql.Schema{
+		{Name: "a", Type: sql.Int64, Source: "foo", Default: int64(7)},
+		{Name: "b", Type: sql.Text, Source: "foo", Nullable: true},
+		{Name: "c", Type: sql.Int64, Source: "foo"},
+		{Name: "d", Type: sql.Int64, Source: "foo", Nullable: true},
+	})
+
+	insert := NewInsertInto(
+		NewResolvedTable(table),
+		NewValues([][]sql.Expression{{
+			expression.NewLiteral("x", sql.Text),
+		}}),
+		false,
+		false,
+		[]string{"b"},
+		nil,
+	)
+
+	ctx := sql.NewEmptyContext()
+	n, err := insert.Execute(ctx)
+	require.NoError(err)
+	require.Equal(1, n)
+	// Column c is not nullable and has no default value.
+	require.Equal(uint16(1), ctx.WarningCount())
+
+	require.Equal([]sql.Row{
+		{int64(7), "x", int64(0), nil},
+	}, collectRows(t, NewResolvedTable(table)))
+
+	ctx = sql.NewEmptyContext()
+	ctx.Set("sql_mode", sql.Text, "STRICT_TRANS_TABLES")
+	_, err = insert.Execute(ctx)
+	require.Error(err)
+	require.True(sql.ErrNoDefaultValue.Is(err))
+}
@@ -0,0 +1,563 @@
+package plan
+
+import (
+	"io"
+	"reflect"
+
+	opentracing "github.com/opentracing/opentracing-go"
+	"gopkg.in/src-d/go-mysql-server.v0/sql"
+)
+
+// LeftJoin is a left outer join between two tables. All rows from the left
+// side are returned, padded with NULLs on the right side when there is no
+// matching row.
+type LeftJoin struct {
+	BinaryNode
+	Cond sql.Expression
+}
+
+// NewLeftJoin creates a new left join node from two tables.
+func NewLeftJoin(left, right sql.Node, cond sql.Expression) *LeftJoin {
+	return &LeftJoin{
+		BinaryNode: BinaryNode{
+			Left:  left,
+			Right: right,
+		},
+		Cond: cond,
+	}
+}
+
+// Schema implements the Node interface.
+func (j *LeftJoin) Schema() sql.Schema {
+	return append(j.Left.Schema(), nullableSchema(j.Right.Schema())...)
+}
+
+// Resolved implements the Resolvable interface.
+func (j *LeftJoin) Resolved() bool {
+	return j.Left.Resolved() && j.Right.Resolved() && j.Cond.Resolved()
+}
+
+// RowIter implements the Node interface.
+func (j *LeftJoin) RowIter(ctx *sql.Context) (sql.RowIter, error) {
+	span, ctx := ctx.Span("plan.LeftJoin", joinSpanTags(j.Left, j.Right))
+
+	l, err := j.Left.RowIter(ctx)
+	if err != nil {
+		span.Finish()
+		return nil, err
+	}
+
+	return sql.NewSpanIter(span, &outerJoinIter{
+		typ:           leftJoinType,
+		primary:       l,
+		sp:            j.Right,
+		ctx:           ctx,
+		cond:          j.Cond,
+		secondarySize: len(j.Right.Schema()),
+	}), nil
+}
+
+// TransformUp implements the Transformable interface.
+func (j *LeftJoin) TransformUp(f sql.TransformNodeFunc) (sql.Node, error) {
+	left, err := j.Left.TransformUp(f)
+	if err != nil {
+		return nil, err
+	}
+
+	right, err := j.Right.TransformUp(f)
+	if err != nil {
+		return nil, err
+	}
+
+	return f(NewLeftJoin(left, right, j.Cond))
+}
+
+// TransformExpressionsUp implements the Transformable interface.
+func (j *LeftJoin) TransformExpressionsUp(f sql.TransformExprFunc) (sql.Node, error) {
+	left, err := j.Left.TransformExpressionsUp(f)
+	if err != nil {
+		return nil, err
+	}
+
+	right, err := j.Right.TransformExpressionsUp(f)
+	if err != nil {
+		return nil, err
+	}
+
+	cond, err := j.Cond.TransformUp(f)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewLeftJoin(left, right, cond), nil
+}
+
+func (j *LeftJoin) String() string {
+	pr := sql.NewTreePrinter()
+	_ = pr.WriteNode("LeftJoin(%s)", j.Cond)
+	_ = pr.WriteChildren(j.Left.String(), j.Right.String())
+	return pr.String()
+}
+
+// Expressions implements the Expressioner interface.
+func (j *LeftJoin) Expressions() []sql.Expression {
+	return []sql.Expression{j.Cond}
+}
+
+// TransformExpressions implements the Expressioner interface.
+func (j *LeftJoin) TransformExpressions(f sql.TransformExprFunc) (sql.Node, error) {
+	cond, err := j.Cond.TransformUp(f)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewLeftJoin(j.Left, j.Right, cond), nil
+}
+
+// RightJoin is a right outer join between two tables. All rows from the right
+// side are returned, padded with NULLs on the left side when there is no
+// matching row.
+type RightJoin struct {
+	BinaryNode
+	Cond sql.Expression
+}
+
+// NewRightJoin creates a new right join node from two tables.
+func NewRightJoin(left, right sql.Node, cond sql.Expression) *RightJoin {
+	return &RightJoin{
+		BinaryNode: BinaryNode{
+			Left:  left,
+			Right: right,
+		},
+		Cond: cond,
+	}
+}
+
+// Schema implements the Node interface.
+func (j *RightJoin) Schema() sql.Schema {
+	return append(nullableSchema(j.Left.Schema()), j.Right.Schema()...)
+}
+
+// Resolved implements the Resolvable interface.
+func (j *RightJoin) Resolved() bool {
+	return j.Left.Resolved() && j.Right.Resolved() && j.Cond.Resolved()
+}
+
+// RowIter implements the Node interface.
+func (j *RightJoin) RowIter(ctx *sql.Context) (sql.RowIter, error) {
+	span, ctx := ctx.Span("plan.RightJoin", joinSpanTags(j.Left, j.Right))
+
+	r, err := j.Right.RowIter(ctx)
+	if err != nil {
+		span.Finish()
+		return nil, err
+	}
+
+	return sql.NewSpanIter(span, &outerJoinIter{
+		typ:           rightJoinType,
+		primary:       r,
+		sp:            j.Left,
+		ctx:           ctx,
+		cond:          j.Cond,
+		secondarySize: len(j.Left.Schema()),
+	}), nil
+}
+
+// TransformUp implements the Transformable interface.
+func (j *RightJoin) TransformUp(f sql.TransformNodeFunc) (sql.Node, error) {
+	left, err := j.Left.TransformUp(f)
+	if err != nil {
+		return nil, err
+	}
+
+	right, err := j.Right.TransformUp(f)
+	if err != nil {
+		return nil, err
+	}
+
+	return f(NewRightJoin(left, right, j.Cond))
+}
+
+// TransformExpressionsUp implements the Transformable interface.
+func (j *RightJoin) TransformExpressionsUp(f sql.TransformExprFunc) (sql.Node, error) {
+	left, err := j.Left.TransformExpressionsUp(f)
+	if err != nil {
+		return nil, err
+	}
+
+	right, err := j.Right.TransformExpressionsUp(f)
+	if err != nil {
+		return nil, err
+	}
+
+	cond, err := j.Cond.TransformUp(f)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewRightJoin(left, right, cond), nil
+}
+
+func (j *RightJoin) String() string {
+	pr := sql.NewTreePrinter()
+	_ = pr.WriteNode("RightJoin(%s)", j.Cond)
+	_ = pr.WriteChildren(j.Left.String(), j.Right.String())
+	return pr.String()
+}
+
+// Expressions implements the Expressioner interface.
+func (j *RightJoin) Expressions() []sql.Expression {
+	return []sql.Expression{j.Cond}
+}
+
+// TransformExpressions implements the Expressioner interface.
+func (j *RightJoin) TransformExpressions(f sql.TransformExprFunc) (sql.Node, error) {
+	cond, err := j.Cond.TransformUp(f)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewRightJoin(j.Left, j.Right, cond), nil
+}
+
+// FullOuterJoin is a full outer join between two tables. All rows from both
+// sides are returned, padded with NULLs on the other side when there is no
+// matching row.
+type FullOuterJoin struct {
+	BinaryNode
+	Cond sql.Expression
+}
+
+// NewFullOuterJoin creates a new full outer join node from two tables.
+func NewFullOuterJoin(left, right sql.Node, cond sql.Expression) *FullOuterJoin {
+	return &FullOuterJoin{
+		BinaryNode: BinaryNode{
+			Left:  left,
+			Right: right,
+		},
+		Cond: cond,
+	}
+}
+
+// Schema implements the Node interface.
+func (j *FullOuterJoin) Schema() sql.Schema {
+	return append(
+		nullableSchema(j.Left.Schema()),
+		nullableSchema(j.Right.Schema())...,
+	)
+}
+
+// Resolved implements the Resolvable interface.
+func (j *FullOuterJoin) Resolved() bool {
+	return j.Left.Resolved() && j.Right.Resolved() && j.Cond.Resolved()
+}
+
+// RowIter implements the Node interface.
+func (j *FullOuterJoin) RowIter(ctx *sql.Context) (sql.RowIter, error) {
+	span, ctx := ctx.Span("plan.FullOuterJoin", joinSpanTags(j.Left, j.Right))
+
+	// The right side needs to be traversed once more at the end to find the
+	// rows that did not match any row on the left side, so it's kept in
+	// memory instead of being iterated for every left row.
+	ri, err := j.Right.RowIter(ctx)
+	if err != nil {
+		span.Finish()
+		return nil, err
+	}
+
+	rightRows, err := sql.RowIterToRows(ri)
+	if err != nil {
+		span.Finish()
+		return nil, err
+	}
+
+	l, err := j.Left.RowIter(ctx)
+	if err != nil {
+		span.Finish()
+		return nil, err
+	}
+
+	return sql.NewSpanIter(span, &fullOuterJoinIter{
+		l:            l,
+		rightRows:    rightRows,
+		matchedRight: make([]bool, len(rightRows)),
+		ctx:          ctx,
+		cond:         j.Cond,
+		leftSize:     len(j.Left.Schema()),
+		rightSize:    len(j.Right.Schema()),
+	}), nil
+}
+
+// TransformUp implements the Transformable interface.
+func (j *FullOuterJoin) TransformUp(f sql.TransformNodeFunc) (sql.Node, error) {
+	left, err := j.Left.TransformUp(f)
+	if err != nil {
+		return nil, err
+	}
+
+	right, err := j.Right.TransformUp(f)
+	if err != nil {
+		return nil, err
+	}
+
+	return f(NewFullOuterJoin(left, right, j.Cond))
+}
+
+// TransformExpressionsUp implements the Transformable interface.
+func (j *FullOuterJoin) TransformExpressionsUp(f sql.TransformExprFunc) (sql.Node, error) {
+	left, err := j.Left.TransformExpressionsUp(f)
+	if err != nil {
+		return nil, err
+	}
+
+	right, err := j.Right.TransformExpressionsUp(f)
+	if err != nil {
+		return nil, err
+	}
+
+	cond, err := j.Cond.TransformUp(f)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewFullOuterJoin(left, right, cond), nil
+}
+
+func (j *FullOuterJoin) String() string {
+	pr := sql.NewTreePrinter()
+	_ = pr.WriteNode("FullOuterJoin(%s)", j.Cond)
+	_ = pr.WriteChildren(j.Left.String(), j.Right.String())
+	return pr.String()
+}
+
+// Expressions implements the Expressioner interface.
+func (j *FullOuterJoin) Expressions() []sql.Expression {
+	return []sql.Expression{j.Cond}
+}
+
+// TransformExpressions implements the Expressioner interface.
+func (j *FullOuterJoin) TransformExpressions(f sql.TransformExprFunc) (sql.Node, error) {
+	cond, err := j.Cond.TransformUp(f)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewFullOuterJoin(j.Left, j.Right, cond), nil
+}
+
+// nullableSchema returns a copy of the given schema with all its columns
+// marked as nullable.
+func nullableSchema(schema sql.Schema) sql.Schema {
+	result := make(sql.Schema, len(schema))
+	for i, col := range schema {
+		c := *col
+		c.Nullable = true
+		result[i] = &c
+	}
+	return result
+}
+
+func joinSpanTags(leftNode, rightNode sql.Node) opentracing.Tags {
+	var left, right string
+	if leftTable, ok := leftNode.(sql.Nameable); ok {
+		left = leftTable.Name()
+	} else {
+		left = reflect.TypeOf(leftNode).String()
+	}
+
+	if rightTable, ok := rightNode.(sql.Nameable); ok {
+		right = rightTable.Name()
+	} else {
+		right = reflect.TypeOf(rightNode).String()
+	}
+
+	return opentracing.Tags{
+		"left":  left,
+		"right": right,
+	}
+}
+
+type joinType byte
+
+const (
+	leftJoinType joinType = iota
+	rightJoinType
+)
+
+// outerJoinIter iterates over the primary side of a left or right join and,
+// for every row, over the secondary side looking for rows matching the join
+// condition. If no row matched, the primary row is returned padded with NULLs.
+type outerJoinIter struct {
+	typ           joinType
+	primary       sql.RowIter
+	sp            rowIterProvider
+	s             sql.RowIter
+	ctx           *sql.Context
+	cond          sql.Expression
+	secondarySize int
+
+	primaryRow sql.Row
+	matched    bool
+}
+
+func (i *outerJoinIter) Next() (sql.Row, error) {
+	for {
+		if i.primaryRow == nil {
+			r, err := i.primary.Next()
+			if err != nil {
+				return nil, err
+			}
+
+			i.primaryRow = r
+			i.matched = false
+		}
+
+		if i.s == nil {
+			iter, err := i.sp.RowIter(i.ctx)
+			if err != nil {
+				return nil, err
+			}
+
+			i.s = iter
+		}
+
+		secondaryRow, err := i.s.Next()
+		if err == io.EOF {
+			if err := i.s.Close(); err != nil {
+				return nil, err
+			}
+
+			i.s = nil
+			primaryRow := i.primaryRow
+			i.primaryRow = nil
+			if !i.matched {
+				return i.buildRow(primaryRow, make(sql.Row, i.secondarySize)), nil
+			}
+
+			continue
+		}
+
+		if err != nil {
+			return nil, err
+		}
+
+		row := i.buildRow(i.primaryRow, secondaryRow)
+		v, err := i.cond.Eval(i.ctx, row)
+		if err != nil {
+			return nil, err
+		}
+
+		if v == true {
+			i.matched = true
+			return row, nil
+		}
+	}
+}
+
+// buildRow returns a new row with the columns of both sides in the order
+// defined by the join schema.
+func (i *outerJoinIter) buildRow(primary, secondary sql.Row) sql.Row {
+	row := make(sql.Row, 0, len(primary)+len(secondary))
+	if i.typ == rightJoinType {
+		row = append(row, secondary...)
+		return append(row, primary...)
+	}
+
+	row = append(row, primary...)
+	return append(row, secondary...)
+}
+
+func (i *outerJoinIter) Close() error {
+	if err := i.primary.Close(); err != nil {
+		if i.s != nil {
+			_ = i.s.Close()
+		}
+		return err
+	}
+
+	if i.s != nil {
+		return i.s.Close()
+	}
+
+	return nil
+}
+
+type fullOuterJoinIter struct {
+	l            sql.RowIter
+	rightRows    []sql.Row
+	matchedRight []bool
+	ctx          *sql.Context
+	cond         sql.Expression
+	leftSize     int
+	rightSize    int
+
+	leftRow  sql.Row
+	matched  bool
+	leftDone bool
+	pos      int
+}
+
+func (i *fullOuterJoinIter) Next() (sql.Row, error) {
+	for !i.leftDone {
+		if i.leftRow == nil {
+			r, err := i.l.Next()
+			if err == io.EOF {
+				i.leftDone = true
+				i.pos = 0
+				break
+			}
+
+			if err != nil {
+				return nil, err
+			}
+
+			i.leftRow = r
+			i.matched = false
+			i.pos = 0
+		}
+
+		if i.pos >= len(i.rightRows) {
+			leftRow := i.leftRow
+			i.leftRow = nil
+			if !i.matched {
+				return append(leftRow[:len(leftRow):len(leftRow)], make(sql.Row, i.rightSize)...), nil
+			}
+
+			continue
+		}
+
+		idx := i.pos
+		i.pos++
+
+		row := make(sql.Row, 0, i.leftSize+i.rightSize)
+		row = append(row, i.leftRow...)
+		row = append(row, i.rightRows[idx]...)
+
+		v, err := i.cond.Eval(i.ctx, row)
+		if err != nil {
+			return nil, err
+		}
+
+		if v == true {
+			i.matched = true
+			i.matchedRight[idx] = true
+			return row, nil
+		}
+	}
+
+	// Once all the left rows have been consumed, the right rows that never
+	// matched are returned padded with NULLs on the left side.
+	for i.pos < len(i.rightRows) {
+		idx := i.pos
+		i.pos++
+		if !i.matchedRight[idx] {
+			return append(make(sql.Row, i.leftSize), i.rightRows[idx]...), nil
+		}
+	}
+
+	return nil, io.EOF
+}
+
+func (i *fullOuterJoinIter) Close() error {
+	return i.l.Close()
+}
@@ -0,0 +1,114 @@
+package plan
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"gopkg.in/src-d/go-mysql-server.v0/mem"
+	"gopkg.in/src-d/go-mysql-server.v0/sql"
+	"gopkg.in/src-d/go-mysql-server.v0/sql/expression"
+)
+
+func outerJoinTables(t *testing.T) (sql.Node, sql.Node) {
+	t.Helper()
+	ctx := sql.NewEmptyContext()
+
+	left := mem.NewTable("left", sql.Schema{
+		{Name: "a", Source: "left", Type: sql.Int64},
+	})
+	right := mem.NewTable("right", sql.Schema{
+		{Name: "b", Source: "right", Type: sql.Int64},
+	})
+
+	for _, i := range []int64{1, 2, 3} {
+		require.NoError(t, left.Insert(ctx, sql.NewRow(i)))
+	}
+
+	for _, i := range []int64{2, 3, 4} {
+		require.NoError(t, right.Insert(ctx, sql.NewRow(i)))
+	}
+
+	return NewResolvedTable(left), NewResolvedTable(right)
+}
+
+var outerJoinCond = expression.NewEquals(
+	expression.NewGetField(0, sql.Int64, "a", false),
+	expression.NewGetField(1, sql.Int64, "b", false),
+)
+
+func TestLeftJoin(t *testing.T) {
+	require := require.New(t)
+	left, right := outerJoinTables(t)
+
+	j := NewLeftJoin(left, right, outerJoinCond)
+
+	require.Equal(sql.Schema{
+		{Name: "a", Source: "left", Type: sql.Int64},
+		{Name: "b", Source: "right", Type: sql.Int64, Nullable: true},
+	}, j.Schema())
+
+	require.Equal([]sql.Row{
+		{int64(1), nil},
+		{int64(2), int64(2)},
+		{int64(3), int64(3)},
+	}, collectRows(t, j))
+}
+
+func TestRightJoin(t *testing.T) {
+	require := require.New(t)
+	left, right := outerJoinTables(t)
+
+	j := NewRightJoin(left, right, outerJoinCond)
+
+	require.Equal(sql.Schema{
+		{Name: "a", Source: "left", Type: sql.Int64, Nullable: true},
+		{Name: "b", Source: "right", Type: sql.Int64},
+	}, j.Schema())
+
+	require.Equal([]sql.Row{
+		{int64(2), int64(2)},
+		{int64(3), int64(3)},
+		{nil, int64(4)},
+	}, collectRows(t, j))
+}
+
+func TestFullOuterJoin(t *testing.T) {
+	require := require.New(t)
+	left, right := outerJoinTables(t)
+
+	j := NewFullOuterJoin(left, right, outerJoinCond)
+
+	require.Equal(sql.Schema{
+		{Name: "a", Source: "left", Type: sql.Int64, Nullable: true},
+		{Name: "b", Source: "right", Type: sql.Int64, Nullable: true},
+	}, j.Schema())
+
+	require.Equal([]sql.Row{
+		{int64(1), nil},
+		{int64(2), int64(2)},
+		{int64(3), int64(3)},
+		{nil, int64(4)},
+	}, collectRows(t, j))
+}
+
+func TestOuterJoinEmpty(t *testing.T) {
+	require := require.New(t)
+	left, _ := outerJoinTables(t)
+	empty := NewResolvedTable(mem.NewTable("right", sql.Schema{
+		{Name: "b", Source: "right", Type: sql.Int64},
+	}))
+
+	require.Equal([]sql.Row{
+		{int64(1), nil},
+		{int64(2), nil},
+		{int64(3), nil},
+	}, collectRows(t, NewLeftJoin(left, empty, outerJoinCond)))
+
+	require.Len(collectRows(t, NewRightJoin(left, empty, outerJoinCond)), 0)
+
+	require.Equal([]sql.Row{
+		{int64(1), nil},
+		{int64(2), nil},
+		{int64(3), nil},
+	}, collectRows(t, NewFullOuterJoin(left, empty, outerJoinCond)))
+}
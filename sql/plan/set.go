@@ -113,6 +113,13 @@ func (s *Set) RowIter(ctx *sql.Context) (sql.RowIter, error) {
 		}
 
 		ctx.Set(name, typ, value)
+
+		// Enabling autocommit commits the open transaction, if any.
+		if strings.ToLower(name) == "autocommit" && sql.Autocommit(ctx) {
+			if err := sql.CommitTransaction(ctx); err != nil {
+				return nil, err
+			}
+		}
 	}
 
 	return sql.RowsToRowIter(), nil
@@ -42,7 +42,7 @@ func (s *ShowCreateDatabase) RowIter(ctx *sql.Context) (sql.RowIter, error) {
 	buf.WriteString(fmt.Sprintf(
 		" /*!40100 DEFAULT CHARACTER SET %s COLLATE %s */",
 		defaultCharacterSet,
-		defaultCollation,
+		sql.DefaultCollation,
 	))
 
 	return sql.RowsToRowIter(
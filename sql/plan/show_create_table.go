@@ -88,7 +88,7 @@ func produceCreateStatement(table sql.Table) string {
 
 	// Statement creation parts for each column
 	for indx, col := range schema {
-		createStmtPart := fmt.Sprintf("`%s` %s", col.Name, col.Type.Type())
+		createStmtPart := fmt.Sprintf("`%s` %s", col.Name, columnTypeSQL(col.Type))
 
 		if !col.Nullable {
 			createStmtPart = fmt.Sprintf("%s NOT NULL", createStmtPart)
@@ -97,7 +97,8 @@ func produceCreateStatement(table sql.Table) string {
 		switch def := col.Default.(type) {
 		case string:
 			if def != "" {
-				createStmtPart = fmt.Sprintf("%s DEFAULT %s", createStmtPart, def)
+				quoted := "'" + strings.Replace(def, "'", "''", -1) + "'"
+				createStmtPart = fmt.Sprintf("%s DEFAULT %s", createStmtPart, quoted)
 			}
 		default:
 			if def != nil {
@@ -105,9 +106,38 @@ func produceCreateStatement(table sql.Table) string {
 			}
 		}
 
+		if col.AutoIncrement {
+			createStmtPart = fmt.Sprintf("%s AUTO_INCREMENT", createStmtPart)
+		}
+
 		colCreateStatements[indx] = createStmtPart
 	}
 
+	for _, key := range sql.SchemaKeys(schema) {
+		var cols = make([]string, len(key.Columns))
+		for i, idx := range key.Columns {
+			cols[i] = fmt.Sprintf("`%s`", schema[idx].Name)
+		}
+
+		if key.Name == sql.PrimaryKeyName {
+			colCreateStatements = append(colCreateStatements,
+				fmt.Sprintf("PRIMARY KEY (%s)", strings.Join(cols, ",")))
+		} else {
+			colCreateStatements = append(colCreateStatements,
+				fmt.Sprintf("UNIQUE KEY `%s` (%s)", key.Name, strings.Join(cols, ",")))
+		}
+	}
+
+	if ct, ok := table.(sql.ConstraintTable); ok {
+		for _, fk := range ct.ForeignKeys() {
+			colCreateStatements = append(colCreateStatements, fk.Definition())
+		}
+
+		for _, check := range ct.Checks() {
+			colCreateStatements = append(colCreateStatements, check.Definition())
+		}
+	}
+
 	prettyColCreateStmts := strings.Join(colCreateStatements, ",\n")
 	composedCreateTableStatement :=
 		fmt.Sprintf("CREATE TABLE `%s` (%s) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4", table.Name(), prettyColCreateStmts)
@@ -115,6 +145,45 @@ func produceCreateStatement(table sql.Table) string {
 	return composedCreateTableStatement
 }
 
+// columnTypeSQL returns the SQL definition of the given column type, so the
+// CREATE TABLE statement can be used to create the same table again.
+func columnTypeSQL(t sql.Type) string {
+	switch t {
+	case sql.Int8:
+		return "TINYINT"
+	case sql.Uint8:
+		return "TINYINT UNSIGNED"
+	case sql.Int16:
+		return "SMALLINT"
+	case sql.Uint16:
+		return "SMALLINT UNSIGNED"
+	case sql.Int24:
+		return "MEDIUMINT"
+	case sql.Uint24:
+		return "MEDIUMINT UNSIGNED"
+	case sql.Int32:
+		return "INT"
+	case sql.Uint32:
+		return "INT UNSIGNED"
+	case sql.Int64:
+		return "BIGINT"
+	case sql.Uint64:
+		return "BIGINT UNSIGNED"
+	case sql.Float32:
+		return "FLOAT"
+	case sql.Float64:
+		return "DOUBLE"
+	case sql.Boolean:
+		return "BIT(1)"
+	default:
+		// The rest of types are named as in SQL.
+		if c, ok := sql.CollationOf(t); ok && c != sql.DefaultCollation {
+			return fmt.Sprintf("%s COLLATE %s", t, c)
+		}
+		return t.String()
+	}
+}
+
 func (i *showCreateTablesIter) Close() error {
 	return nil
 }
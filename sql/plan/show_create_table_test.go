@@ -19,6 +19,9 @@ func TestShowCreateTable(t *testing.T) {
 			&sql.Column{Name: "baz", Type: sql.Text, Default: "", Nullable: false},
 			&sql.Column{Name: "zab", Type: sql.Int32, Default: int32(0), Nullable: true},
 			&sql.Column{Name: "bza", Type: sql.Int64, Default: int64(0), Nullable: true},
+			&sql.Column{Name: "foo", Type: sql.VarChar(123), Default: "", Nullable: true},
+			&sql.Column{Name: "baf", Type: sql.Uint8, Default: uint8(0), Nullable: false},
+			&sql.Column{Name: "fab", Type: sql.Enum("a", "it's"), Nullable: true},
 		})
 
 	db.AddTable(table.Name(), table)
@@ -38,9 +41,38 @@ func TestShowCreateTable(t *testing.T) {
 	expected := sql.NewRow(
 		table.Name(),
 		"CREATE TABLE `test-table` (`baz` TEXT NOT NULL,\n"+
-			"`zab` INT32 DEFAULT 0,\n"+
-			"`bza` INT64 DEFAULT 0) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4",
+			"`zab` INT DEFAULT 0,\n"+
+			"`bza` BIGINT DEFAULT 0,\n"+
+			"`foo` VARCHAR(123),\n"+
+			"`baf` TINYINT UNSIGNED NOT NULL DEFAULT 0,\n"+
+			"`fab` ENUM('a','it''s')) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4",
 	)
 
 	require.Equal(expected, row)
 }
+
+func TestShowCreateTableKeys(t *testing.T) {
+	require := require.New(t)
+
+	db := mem.NewDatabase("testdb")
+	table := mem.NewTable("t", sql.Schema{
+		{Name: "a", Type: sql.Int64, PrimaryKey: true},
+		{Name: "b", Type: sql.Int64, PrimaryKey: true},
+		{Name: "c", Type: sql.Text, Nullable: true, Unique: true},
+	})
+	db.AddTable(table.Name(), table)
+
+	cat := sql.NewCatalog()
+	cat.AddDatabase(db)
+
+	rows, err := sql.NodeToRows(sql.NewEmptyContext(), NewShowCreateTable(db.Name(), cat, table.Name()))
+	require.NoError(err)
+	require.Equal([]sql.Row{{
+		"t",
+		"CREATE TABLE `t` (`a` BIGINT NOT NULL,\n" +
+			"`b` BIGINT NOT NULL,\n" +
+			"`c` TEXT,\n" +
+			"PRIMARY KEY (`a`,`b`),\n" +
+			"UNIQUE KEY `c` (`c`)) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4",
+	}}, rows)
+}
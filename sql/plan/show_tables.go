@@ -10,12 +10,16 @@ import (
 // ShowTables is a node that shows the database tables.
 type ShowTables struct {
 	Database sql.Database
+	Catalog  *sql.Catalog
+	Full     bool
 }
 
-// NewShowTables creates a new show tables node given a database.
-func NewShowTables(database sql.Database) *ShowTables {
+// NewShowTables creates a new show tables node given a database. If full
+// is true, the type of the tables is also shown.
+func NewShowTables(database sql.Database, full bool) *ShowTables {
 	return &ShowTables{
 		Database: database,
+		Full:     full,
 	}
 }
 
@@ -31,29 +35,58 @@ func (*ShowTables) Children() []sql.Node {
 }
 
 // Schema implements the Node interface.
-func (*ShowTables) Schema() sql.Schema {
-	return sql.Schema{{
+func (p *ShowTables) Schema() sql.Schema {
+	schema := sql.Schema{{
 		Name:     "table",
 		Type:     sql.Text,
 		Nullable: false,
 	}}
+
+	if p.Full {
+		schema = append(schema, &sql.Column{
+			Name:     "table_type",
+			Type:     sql.Text,
+			Nullable: false,
+		})
+	}
+
+	return schema
 }
 
+const (
+	baseTableType = "BASE TABLE"
+	viewTableType = "VIEW"
+)
+
 // RowIter implements the Node interface.
 func (p *ShowTables) RowIter(ctx *sql.Context) (sql.RowIter, error) {
 	tableNames := []string{}
+	tableTypes := make(map[string]string)
 	for key := range p.Database.Tables() {
 		tableNames = append(tableNames, key)
+		tableTypes[key] = baseTableType
+	}
+
+	if p.Catalog != nil {
+		for _, view := range p.Catalog.ViewsInDatabase(p.Database.Name()) {
+			tableNames = append(tableNames, view.Name)
+			tableTypes[view.Name] = viewTableType
+		}
 	}
 
 	sort.Strings(tableNames)
 
-	return &showTablesIter{tableNames: tableNames}, nil
+	return &showTablesIter{
+		tableNames: tableNames,
+		tableTypes: tableTypes,
+		full:       p.Full,
+	}, nil
 }
 
 // TransformUp implements the Transformable interface.
 func (p *ShowTables) TransformUp(f sql.TransformNodeFunc) (sql.Node, error) {
-	return f(NewShowTables(p.Database))
+	n := *p
+	return f(&n)
 }
 
 // TransformExpressionsUp implements the Transformable interface.
@@ -62,11 +95,16 @@ func (p *ShowTables) TransformExpressionsUp(f sql.TransformExprFunc) (sql.Node,
 }
 
 func (p ShowTables) String() string {
+	if p.Full {
+		return "ShowTables(full)"
+	}
 	return "ShowTables"
 }
 
 type showTablesIter struct {
 	tableNames []string
+	tableTypes map[string]string
+	full       bool
 	idx        int
 }
 
@@ -74,7 +112,12 @@ func (i *showTablesIter) Next() (sql.Row, error) {
 	if i.idx >= len(i.tableNames) {
 		return nil, io.EOF
 	}
-	row := sql.NewRow(i.tableNames[i.idx])
+
+	name := i.tableNames[i.idx]
+	row := sql.NewRow(name)
+	if i.full {
+		row = append(row, i.tableTypes[name])
+	}
 	i.idx++
 
 	return row, nil
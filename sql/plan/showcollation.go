@@ -0,0 +1,180 @@
+package plan
+
+import (
+	"fmt"
+
+	"gopkg.in/src-d/go-mysql-server.v0/sql"
+	"gopkg.in/src-d/go-mysql-server.v0/sql/expression"
+)
+
+// ShowCollation is a node that shows the supported collations.
+type ShowCollation struct {
+	pattern string
+}
+
+// NewShowCollation returns a new ShowCollation reference.
+// like is a "like pattern". If like is an empty string it will return all
+// collations.
+func NewShowCollation(like string) *ShowCollation {
+	return &ShowCollation{pattern: like}
+}
+
+// Resolved implements the sql.Node interface. The function always returns
+// true.
+func (*ShowCollation) Resolved() bool { return true }
+
+// Children implements the sql.Node interface. The function always returns
+// nil.
+func (*ShowCollation) Children() []sql.Node { return nil }
+
+// TransformUp implements the sql.Transformable interface.
+func (s *ShowCollation) TransformUp(f sql.TransformNodeFunc) (sql.Node, error) {
+	return f(NewShowCollation(s.pattern))
+}
+
+// TransformExpressionsUp implements the sql.Transformable interface.
+func (s *ShowCollation) TransformExpressionsUp(f sql.TransformExprFunc) (sql.Node, error) {
+	return s, nil
+}
+
+func (s *ShowCollation) String() string {
+	return fmt.Sprintf("SHOW COLLATION%s", likeClause(s.pattern))
+}
+
+// Schema implements the sql.Node interface.
+func (*ShowCollation) Schema() sql.Schema {
+	return sql.Schema{
+		{Name: "Collation", Type: sql.Text},
+		{Name: "Charset", Type: sql.Text},
+		{Name: "Id", Type: sql.Uint64},
+		{Name: "Default", Type: sql.Text},
+		{Name: "Compiled", Type: sql.Text},
+		{Name: "Sortlen", Type: sql.Uint32},
+	}
+}
+
+// RowIter implements the sql.Node interface.
+func (s *ShowCollation) RowIter(ctx *sql.Context) (sql.RowIter, error) {
+	var rows []sql.Row
+	for _, c := range sql.Collations {
+		ok, err := matchesLike(ctx, s.pattern, c.Name)
+		if err != nil {
+			return nil, err
+		}
+
+		if !ok {
+			continue
+		}
+
+		var isDefault string
+		if c.Default {
+			isDefault = "Yes"
+		}
+
+		rows = append(rows, sql.NewRow(
+			c.Name,
+			c.CharacterSet,
+			c.ID,
+			isDefault,
+			"Yes",
+			uint32(1),
+		))
+	}
+
+	return sql.RowsToRowIter(rows...), nil
+}
+
+// ShowCharacterSet is a node that shows the supported character sets.
+type ShowCharacterSet struct {
+	pattern string
+}
+
+// NewShowCharacterSet returns a new ShowCharacterSet reference.
+// like is a "like pattern". If like is an empty string it will return all
+// character sets.
+func NewShowCharacterSet(like string) *ShowCharacterSet {
+	return &ShowCharacterSet{pattern: like}
+}
+
+// Resolved implements the sql.Node interface. The function always returns
+// true.
+func (*ShowCharacterSet) Resolved() bool { return true }
+
+// Children implements the sql.Node interface. The function always returns
+// nil.
+func (*ShowCharacterSet) Children() []sql.Node { return nil }
+
+// TransformUp implements the sql.Transformable interface.
+func (s *ShowCharacterSet) TransformUp(f sql.TransformNodeFunc) (sql.Node, error) {
+	return f(NewShowCharacterSet(s.pattern))
+}
+
+// TransformExpressionsUp implements the sql.Transformable interface.
+func (s *ShowCharacterSet) TransformExpressionsUp(f sql.TransformExprFunc) (sql.Node, error) {
+	return s, nil
+}
+
+func (s *ShowCharacterSet) String() string {
+	return fmt.Sprintf("SHOW CHARACTER SET%s", likeClause(s.pattern))
+}
+
+// Schema implements the sql.Node interface.
+func (*ShowCharacterSet) Schema() sql.Schema {
+	return sql.Schema{
+		{Name: "Charset", Type: sql.Text},
+		{Name: "Description", Type: sql.Text},
+		{Name: "Default collation", Type: sql.Text},
+		{Name: "Maxlen", Type: sql.Uint32},
+	}
+}
+
+// RowIter implements the sql.Node interface.
+func (s *ShowCharacterSet) RowIter(ctx *sql.Context) (sql.RowIter, error) {
+	var rows []sql.Row
+	for _, cs := range sql.CharacterSets {
+		ok, err := matchesLike(ctx, s.pattern, cs.Name)
+		if err != nil {
+			return nil, err
+		}
+
+		if !ok {
+			continue
+		}
+
+		rows = append(rows, sql.NewRow(
+			cs.Name,
+			cs.Description,
+			cs.DefaultCollation().Name,
+			cs.MaxLen,
+		))
+	}
+
+	return sql.RowsToRowIter(rows...), nil
+}
+
+func likeClause(pattern string) string {
+	if pattern == "" {
+		return ""
+	}
+	return fmt.Sprintf(" LIKE '%s'", pattern)
+}
+
+// matchesLike reports whether the given name matches the like pattern. An
+// empty pattern matches all names.
+func matchesLike(ctx *sql.Context, pattern, name string) (bool, error) {
+	if pattern == "" {
+		return true, nil
+	}
+
+	like := expression.NewLike(
+		expression.NewLiteral(name, sql.Text),
+		expression.NewLiteral(pattern, sql.Text),
+	)
+
+	v, err := like.Eval(ctx, nil)
+	if err != nil {
+		return false, err
+	}
+
+	return v == true, nil
+}
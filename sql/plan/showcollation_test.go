@@ -0,0 +1,39 @@
+package plan
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"gopkg.in/src-d/go-mysql-server.v0/sql"
+)
+
+func TestShowCollation(t *testing.T) {
+	require := require.New(t)
+	ctx := sql.NewEmptyContext()
+
+	rows, err := sql.NodeToRows(ctx, NewShowCollation(""))
+	require.NoError(err)
+	require.Len(rows, len(sql.Collations))
+
+	rows, err = sql.NodeToRows(ctx, NewShowCollation("utf8mb4%ci"))
+	require.NoError(err)
+	require.Equal([]sql.Row{
+		{"utf8mb4_general_ci", "utf8mb4", uint64(45), "Yes", "Yes", uint32(1)},
+		{"utf8mb4_unicode_ci", "utf8mb4", uint64(224), "", "Yes", uint32(1)},
+	}, rows)
+}
+
+func TestShowCharacterSet(t *testing.T) {
+	require := require.New(t)
+	ctx := sql.NewEmptyContext()
+
+	rows, err := sql.NodeToRows(ctx, NewShowCharacterSet(""))
+	require.NoError(err)
+	require.Len(rows, len(sql.CharacterSets))
+
+	rows, err = sql.NodeToRows(ctx, NewShowCharacterSet("utf8"))
+	require.NoError(err)
+	require.Equal([]sql.Row{
+		{"utf8", "UTF-8 Unicode", "utf8_general_ci", uint32(3)},
+	}, rows)
+}
@@ -12,8 +12,6 @@ type ShowColumns struct {
 	Full bool
 }
 
-const defaultCollation = "utf8_bin"
-
 var (
 	showColumnsSchema = sql.Schema{
 		{Name: "Field", Type: sql.Text},
@@ -61,8 +59,8 @@ func (s *ShowColumns) RowIter(ctx *sql.Context) (sql.RowIter, error) {
 	for i, col := range schema {
 		var row sql.Row
 		var collation interface{}
-		if col.Type == sql.Text {
-			collation = defaultCollation
+		if c, ok := sql.CollationOf(col.Type); ok {
+			collation = c.Name
 		}
 
 		var null = "NO"
@@ -81,9 +79,9 @@ func (s *ShowColumns) RowIter(ctx *sql.Context) (sql.RowIter, error) {
 				col.Type.String(),
 				collation,
 				null,
-				"", // Key
+				col.KeyName(),
 				defaultVal,
-				"", // Extra
+				col.Extra(),
 				"", // Privileges
 				"", // Comment
 			}
@@ -92,9 +90,9 @@ func (s *ShowColumns) RowIter(ctx *sql.Context) (sql.RowIter, error) {
 				col.Name,
 				col.Type.String(),
 				null,
-				"", // Key
+				col.KeyName(),
 				defaultVal,
-				"", // Extra
+				col.Extra(),
 			}
 		}
 
@@ -0,0 +1,154 @@
+package plan
+
+import "gopkg.in/src-d/go-mysql-server.v0/sql"
+
+// BeginTransaction starts a new transaction in the current session on all
+// the databases of the catalog that support transactions. If there is a
+// transaction already open, it's committed first.
+type BeginTransaction struct {
+	Catalog *sql.Catalog
+}
+
+// NewBeginTransaction returns a new BeginTransaction node.
+func NewBeginTransaction() *BeginTransaction {
+	return new(BeginTransaction)
+}
+
+var _ sql.Node = (*BeginTransaction)(nil)
+
+// Children implements the sql.Node interface.
+func (*BeginTransaction) Children() []sql.Node { return nil }
+
+// Resolved implements the sql.Node interface.
+func (*BeginTransaction) Resolved() bool { return true }
+
+// Schema implements the sql.Node interface.
+func (*BeginTransaction) Schema() sql.Schema { return nil }
+
+// RowIter implements the sql.Node interface.
+func (b *BeginTransaction) RowIter(ctx *sql.Context) (sql.RowIter, error) {
+	span, ctx := ctx.Span("plan.BeginTransaction")
+	defer span.Finish()
+
+	if err := sql.CommitTransaction(ctx); err != nil {
+		return nil, err
+	}
+
+	tx, err := sql.BeginTransaction(ctx, b.Catalog.AllDatabases())
+	if err != nil {
+		return nil, err
+	}
+
+	ctx.SetTransaction(tx)
+	return sql.RowsToRowIter(), nil
+}
+
+func (*BeginTransaction) String() string {
+	p := sql.NewTreePrinter()
+	_ = p.WriteNode("BeginTransaction")
+	return p.String()
+}
+
+// TransformUp implements the sql.Node interface.
+func (b *BeginTransaction) TransformUp(f sql.TransformNodeFunc) (sql.Node, error) {
+	return f(b)
+}
+
+// TransformExpressionsUp implements the sql.Node interface.
+func (b *BeginTransaction) TransformExpressionsUp(f sql.TransformExprFunc) (sql.Node, error) {
+	return b, nil
+}
+
+// Commit commits the transaction open in the current session, if any.
+type Commit struct{}
+
+// NewCommit returns a new Commit node.
+func NewCommit() *Commit {
+	return new(Commit)
+}
+
+var _ sql.Node = (*Commit)(nil)
+
+// Children implements the sql.Node interface.
+func (*Commit) Children() []sql.Node { return nil }
+
+// Resolved implements the sql.Node interface.
+func (*Commit) Resolved() bool { return true }
+
+// Schema implements the sql.Node interface.
+func (*Commit) Schema() sql.Schema { return nil }
+
+// RowIter implements the sql.Node interface.
+func (c *Commit) RowIter(ctx *sql.Context) (sql.RowIter, error) {
+	span, ctx := ctx.Span("plan.Commit")
+	defer span.Finish()
+
+	if err := sql.CommitTransaction(ctx); err != nil {
+		return nil, err
+	}
+
+	return sql.RowsToRowIter(), nil
+}
+
+func (*Commit) String() string {
+	p := sql.NewTreePrinter()
+	_ = p.WriteNode("Commit")
+	return p.String()
+}
+
+// TransformUp implements the sql.Node interface.
+func (c *Commit) TransformUp(f sql.TransformNodeFunc) (sql.Node, error) {
+	return f(c)
+}
+
+// TransformExpressionsUp implements the sql.Node interface.
+func (c *Commit) TransformExpressionsUp(f sql.TransformExprFunc) (sql.Node, error) {
+	return c, nil
+}
+
+// Rollback rolls back the transaction open in the current session, if any.
+type Rollback struct{}
+
+// NewRollback returns a new Rollback node.
+func NewRollback() *Rollback {
+	return new(Rollback)
+}
+
+var _ sql.Node = (*Rollback)(nil)
+
+// Children implements the sql.Node interface.
+func (*Rollback) Children() []sql.Node { return nil }
+
+// Resolved implements the sql.Node interface.
+func (*Rollback) Resolved() bool { return true }
+
+// Schema implements the sql.Node interface.
+func (*Rollback) Schema() sql.Schema { return nil }
+
+// RowIter implements the sql.Node interface.
+func (r *Rollback) RowIter(ctx *sql.Context) (sql.RowIter, error) {
+	span, ctx := ctx.Span("plan.Rollback")
+	defer span.Finish()
+
+	if err := sql.RollbackTransaction(ctx); err != nil {
+		return nil, err
+	}
+
+	return sql.RowsToRowIter(), nil
+}
+
+func (*Rollback) String() string {
+	p := sql.NewTreePrinter()
+	_ = p.WriteNode("Rollback")
+	return p.String()
+}
+
+// TransformUp implements the sql.Node interface.
+func (r *Rollback) TransformUp(f sql.TransformNodeFunc) (sql.Node, error) {
+	return f(r)
+}
+
+// TransformExpressionsUp implements the sql.Node interface.
+func (r *Rollback) TransformExpressionsUp(f sql.TransformExprFunc) (sql.Node, error) {
+	return r, nil
+}
@@ -0,0 +1,381 @@
+package plan
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/mitchellh/hashstructure"
+	"gopkg.in/src-d/go-mysql-server.v0/sql"
+)
+
+// Union is a node that returns all the rows of its left child followed by
+// all the rows of its right child, duplicates included. UNION DISTINCT is
+// represented as a Distinct node on top of an Union.
+type Union struct {
+	BinaryNode
+}
+
+// NewUnion creates a new Union node with the given children.
+func NewUnion(left, right sql.Node) *Union {
+	return &Union{BinaryNode{Left: left, Right: right}}
+}
+
+// Schema implements the Node interface.
+func (u *Union) Schema() sql.Schema {
+	return setOperationSchema(u.Left, u.Right)
+}
+
+// Resolved implements the Resolvable interface.
+func (u *Union) Resolved() bool {
+	return u.Left.Resolved() && u.Right.Resolved()
+}
+
+// RowIter implements the Node interface.
+func (u *Union) RowIter(ctx *sql.Context) (sql.RowIter, error) {
+	span, ctx := ctx.Span("plan.Union")
+
+	li, err := u.Left.RowIter(ctx)
+	if err != nil {
+		span.Finish()
+		return nil, err
+	}
+
+	return sql.NewSpanIter(span, &unionIter{
+		ctx:  ctx,
+		left: li,
+		rp:   u.Right,
+	}), nil
+}
+
+// TransformUp implements the Transformable interface.
+func (u *Union) TransformUp(f sql.TransformNodeFunc) (sql.Node, error) {
+	left, right, err := transformUpBinary(u.BinaryNode, f)
+	if err != nil {
+		return nil, err
+	}
+
+	return f(NewUnion(left, right))
+}
+
+// TransformExpressionsUp implements the Transformable interface.
+func (u *Union) TransformExpressionsUp(f sql.TransformExprFunc) (sql.Node, error) {
+	left, right, err := transformExpressionsUpBinary(u.BinaryNode, f)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewUnion(left, right), nil
+}
+
+func (u *Union) String() string {
+	pr := sql.NewTreePrinter()
+	_ = pr.WriteNode("Union")
+	_ = pr.WriteChildren(u.Left.String(), u.Right.String())
+	return pr.String()
+}
+
+type unionIter struct {
+	ctx   *sql.Context
+	left  sql.RowIter
+	rp    rowIterProvider
+	right sql.RowIter
+}
+
+func (i *unionIter) Next() (sql.Row, error) {
+	if i.right == nil {
+		row, err := i.left.Next()
+		if err != io.EOF {
+			return row, err
+		}
+
+		i.right, err = i.rp.RowIter(i.ctx)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return i.right.Next()
+}
+
+func (i *unionIter) Close() error {
+	if err := i.left.Close(); err != nil {
+		if i.right != nil {
+			_ = i.right.Close()
+		}
+		return err
+	}
+
+	if i.right != nil {
+		return i.right.Close()
+	}
+
+	return nil
+}
+
+// Intersect is a node that returns the rows of its left child that are also
+// returned by its right child. A row is returned as many times as it appears
+// in both children, so INTERSECT DISTINCT is represented as an Intersect
+// node with a Distinct node as its left child.
+type Intersect struct {
+	BinaryNode
+}
+
+// NewIntersect creates a new Intersect node with the given children.
+func NewIntersect(left, right sql.Node) *Intersect {
+	return &Intersect{BinaryNode{Left: left, Right: right}}
+}
+
+// Schema implements the Node interface.
+func (i *Intersect) Schema() sql.Schema {
+	return setOperationSchema(i.Left, i.Right)
+}
+
+// Resolved implements the Resolvable interface.
+func (i *Intersect) Resolved() bool {
+	return i.Left.Resolved() && i.Right.Resolved()
+}
+
+// RowIter implements the Node interface.
+func (i *Intersect) RowIter(ctx *sql.Context) (sql.RowIter, error) {
+	span, ctx := ctx.Span("plan.Intersect")
+
+	iter, err := newSetOperationIter(ctx, i.BinaryNode, true)
+	if err != nil {
+		span.Finish()
+		return nil, err
+	}
+
+	return sql.NewSpanIter(span, iter), nil
+}
+
+// TransformUp implements the Transformable interface.
+func (i *Intersect) TransformUp(f sql.TransformNodeFunc) (sql.Node, error) {
+	left, right, err := transformUpBinary(i.BinaryNode, f)
+	if err != nil {
+		return nil, err
+	}
+
+	return f(NewIntersect(left, right))
+}
+
+// TransformExpressionsUp implements the Transformable interface.
+func (i *Intersect) TransformExpressionsUp(f sql.TransformExprFunc) (sql.Node, error) {
+	left, right, err := transformExpressionsUpBinary(i.BinaryNode, f)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewIntersect(left, right), nil
+}
+
+func (i *Intersect) String() string {
+	pr := sql.NewTreePrinter()
+	_ = pr.WriteNode("Intersect")
+	_ = pr.WriteChildren(i.Left.String(), i.Right.String())
+	return pr.String()
+}
+
+// Except is a node that returns the rows of its left child that are not
+// returned by its right child. Each row of the right child removes only one
+// occurrence of the same row in the left child, so EXCEPT DISTINCT is
+// represented as an Except node with a Distinct node as its left child.
+type Except struct {
+	BinaryNode
+}
+
+// NewExcept creates a new Except node with the given children.
+func NewExcept(left, right sql.Node) *Except {
+	return &Except{BinaryNode{Left: left, Right: right}}
+}
+
+// Schema implements the Node interface.
+func (e *Except) Schema() sql.Schema {
+	return setOperationSchema(e.Left, e.Right)
+}
+
+// Resolved implements the Resolvable interface.
+func (e *Except) Resolved() bool {
+	return e.Left.Resolved() && e.Right.Resolved()
+}
+
+// RowIter implements the Node interface.
+func (e *Except) RowIter(ctx *sql.Context) (sql.RowIter, error) {
+	span, ctx := ctx.Span("plan.Except")
+
+	iter, err := newSetOperationIter(ctx, e.BinaryNode, false)
+	if err != nil {
+		span.Finish()
+		return nil, err
+	}
+
+	return sql.NewSpanIter(span, iter), nil
+}
+
+// TransformUp implements the Transformable interface.
+func (e *Except) TransformUp(f sql.TransformNodeFunc) (sql.Node, error) {
+	left, right, err := transformUpBinary(e.BinaryNode, f)
+	if err != nil {
+		return nil, err
+	}
+
+	return f(NewExcept(left, right))
+}
+
+// TransformExpressionsUp implements the Transformable interface.
+func (e *Except) TransformExpressionsUp(f sql.TransformExprFunc) (sql.Node, error) {
+	left, right, err := transformExpressionsUpBinary(e.BinaryNode, f)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewExcept(left, right), nil
+}
+
+func (e *Except) String() string {
+	pr := sql.NewTreePrinter()
+	_ = pr.WriteNode("Except")
+	_ = pr.WriteChildren(e.Left.String(), e.Right.String())
+	return pr.String()
+}
+
+// setOperationSchema returns the schema of a set operation between the two
+// given nodes. Columns are named after the left node and are nullable if
+// they are nullable in any of the nodes. Both nodes are expected to have
+// the same column types, except for columns that are always NULL.
+func setOperationSchema(left, right sql.Node) sql.Schema {
+	ls, rs := left.Schema(), right.Schema()
+	var schema = make(sql.Schema, len(ls))
+	for i, col := range ls {
+		c := *col
+		if i < len(rs) {
+			c.Nullable = c.Nullable || rs[i].Nullable
+			if c.Type == sql.Null {
+				c.Type = rs[i].Type
+			}
+		}
+		schema[i] = &c
+	}
+	return schema
+}
+
+func transformUpBinary(n BinaryNode, f sql.TransformNodeFunc) (sql.Node, sql.Node, error) {
+	left, err := n.Left.TransformUp(f)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	right, err := n.Right.TransformUp(f)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return left, right, nil
+}
+
+func transformExpressionsUpBinary(n BinaryNode, f sql.TransformExprFunc) (sql.Node, sql.Node, error) {
+	left, err := n.Left.TransformExpressionsUp(f)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	right, err := n.Right.TransformExpressionsUp(f)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return left, right, nil
+}
+
+// setOperationIter returns the rows of the left iterator depending on how
+// many times they appear in the right one. If intersect is true, only the
+// rows that appear in the right one are returned; otherwise, only the ones
+// that don't.
+type setOperationIter struct {
+	ctx       *sql.Context
+	left      sql.RowIter
+	rp        rowIterProvider
+	intersect bool
+	counts    map[uint64]int
+}
+
+func newSetOperationIter(
+	ctx *sql.Context,
+	n BinaryNode,
+	intersect bool,
+) (*setOperationIter, error) {
+	left, err := n.Left.RowIter(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return &setOperationIter{
+		ctx:       ctx,
+		left:      left,
+		rp:        n.Right,
+		intersect: intersect,
+	}, nil
+}
+
+func (i *setOperationIter) Next() (sql.Row, error) {
+	if i.counts == nil {
+		if err := i.countRightRows(); err != nil {
+			return nil, err
+		}
+	}
+
+	for {
+		row, err := i.left.Next()
+		if err != nil {
+			return nil, err
+		}
+
+		hash, err := hashstructure.Hash(row, nil)
+		if err != nil {
+			return nil, fmt.Errorf("unable to hash row: %s", err)
+		}
+
+		found := i.counts[hash] > 0
+		if found {
+			i.counts[hash]--
+		}
+
+		if found == i.intersect {
+			return row, nil
+		}
+	}
+}
+
+func (i *setOperationIter) countRightRows() error {
+	iter, err := i.rp.RowIter(i.ctx)
+	if err != nil {
+		return err
+	}
+
+	i.counts = make(map[uint64]int)
+	for {
+		row, err := iter.Next()
+		if err == io.EOF {
+			break
+		}
+
+		if err != nil {
+			_ = iter.Close()
+			return err
+		}
+
+		hash, err := hashstructure.Hash(row, nil)
+		if err != nil {
+			_ = iter.Close()
+			return fmt.Errorf("unable to hash row: %s", err)
+		}
+
+		i.counts[hash]++
+	}
+
+	return iter.Close()
+}
+
+func (i *setOperationIter) Close() error {
+	i.counts = nil
+	return i.left.Close()
+}
@@ -0,0 +1,76 @@
+package plan
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"gopkg.in/src-d/go-mysql-server.v0/mem"
+	"gopkg.in/src-d/go-mysql-server.v0/sql"
+)
+
+func setOperationTables(t *testing.T) (sql.Node, sql.Node) {
+	t.Helper()
+	ctx := sql.NewEmptyContext()
+
+	left := mem.NewTable("left", sql.Schema{
+		{Name: "a", Source: "left", Type: sql.Int64},
+	})
+	right := mem.NewTable("right", sql.Schema{
+		{Name: "b", Source: "right", Type: sql.Int64, Nullable: true},
+	})
+
+	for _, i := range []int64{1, 2, 2, 3, 3} {
+		require.NoError(t, left.Insert(ctx, sql.NewRow(i)))
+	}
+
+	for _, i := range []int64{2, 3, 3, 4} {
+		require.NoError(t, right.Insert(ctx, sql.NewRow(i)))
+	}
+
+	return NewResolvedTable(left), NewResolvedTable(right)
+}
+
+func TestUnion(t *testing.T) {
+	require := require.New(t)
+	left, right := setOperationTables(t)
+
+	u := NewUnion(left, right)
+	require.Equal(sql.Schema{
+		{Name: "a", Source: "left", Type: sql.Int64, Nullable: true},
+	}, u.Schema())
+
+	require.Equal([]sql.Row{
+		{int64(1)}, {int64(2)}, {int64(2)}, {int64(3)}, {int64(3)},
+		{int64(2)}, {int64(3)}, {int64(3)}, {int64(4)},
+	}, collectRows(t, u))
+
+	require.Equal([]sql.Row{
+		{int64(1)}, {int64(2)}, {int64(3)}, {int64(4)},
+	}, collectRows(t, NewDistinct(u)))
+}
+
+func TestIntersect(t *testing.T) {
+	require := require.New(t)
+	left, right := setOperationTables(t)
+
+	require.Equal([]sql.Row{
+		{int64(2)}, {int64(3)}, {int64(3)},
+	}, collectRows(t, NewIntersect(left, right)))
+
+	require.Equal([]sql.Row{
+		{int64(2)}, {int64(3)},
+	}, collectRows(t, NewIntersect(NewDistinct(left), right)))
+}
+
+func TestExcept(t *testing.T) {
+	require := require.New(t)
+	left, right := setOperationTables(t)
+
+	require.Equal([]sql.Row{
+		{int64(1)}, {int64(2)},
+	}, collectRows(t, NewExcept(left, right)))
+
+	require.Equal([]sql.Row{
+		{int64(1)},
+	}, collectRows(t, NewExcept(NewDistinct(left), right)))
+}
@@ -0,0 +1,222 @@
+package plan
+
+import (
+	"io"
+	"strings"
+
+	"gopkg.in/src-d/go-errors.v1"
+	"gopkg.in/src-d/go-mysql-server.v0/sql"
+	"gopkg.in/src-d/go-mysql-server.v0/sql/expression"
+)
+
+// ErrUpdateNotSupported is thrown when a table doesn't support updates.
+var ErrUpdateNotSupported = errors.NewKind("table doesn't support UPDATE")
+
+// Update is a node that updates the rows returned by its child, which is
+// the table being updated, optionally filtered, sorted and limited. The
+// indexes of the table are marked as outdated once rows are updated.
+type Update struct {
+	UnaryNode
+	UpdateExprs     []sql.Expression
+	Catalog         *sql.Catalog
+	CurrentDatabase string
+}
+
+// NewUpdate creates an Update node. All the update expressions must be
+// SetField expressions.
+func NewUpdate(child sql.Node, updateExprs []sql.Expression) *Update {
+	return &Update{UnaryNode: UnaryNode{child}, UpdateExprs: updateExprs}
+}
+
+// Schema implements the Node interface.
+func (p *Update) Schema() sql.Schema {
+	return sql.OkResultSchema
+}
+
+// Resolved implements the Resolvable interface.
+func (p *Update) Resolved() bool {
+	return p.Child.Resolved() && expressionsResolved(p.UpdateExprs...)
+}
+
+// Expressions implements the Expressioner interface.
+func (p *Update) Expressions() []sql.Expression {
+	return p.UpdateExprs
+}
+
+// TransformExpressions implements the Expressioner interface.
+func (p *Update) TransformExpressions(f sql.TransformExprFunc) (sql.Node, error) {
+	exprs, err := transformExpressionsUp(f, p.UpdateExprs)
+	if err != nil {
+		return nil, err
+	}
+
+	np := *p
+	np.UpdateExprs = exprs
+	return &np, nil
+}
+
+func getUpdatable(node sql.Node) (sql.Updater, error) {
+	switch node := node.(type) {
+	case sql.Updater:
+		return node, nil
+	case *ResolvedTable:
+		return getUpdatableTable(node.Table)
+	case *Filter:
+		return getUpdatable(node.Child)
+	case *Sort:
+		return getUpdatable(node.Child)
+	case *Limit:
+		return getUpdatable(node.Child)
+	case *TableAlias:
+		return getUpdatable(node.Child)
+	default:
+		return nil, ErrUpdateNotSupported.New()
+	}
+}
+
+func getUpdatableTable(t sql.Table) (sql.Updater, error) {
+	switch t := t.(type) {
+	case sql.Updater:
+		return t, nil
+	case sql.TableWrapper:
+		return getUpdatableTable(t.Underlying())
+	default:
+		return nil, ErrUpdateNotSupported.New()
+	}
+}
+
+// Execute updates the rows in the database and returns the number of rows
+// that were changed.
+func (p *Update) Execute(ctx *sql.Context) (int, error) {
+	updatable, err := getUpdatable(p.Child)
+	if err != nil {
+		return 0, err
+	}
+
+	schema := p.Child.Schema()
+	iter, err := p.Child.RowIter(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	var i, rowNum int
+	for {
+		oldRow, err := iter.Next()
+		if err == io.EOF {
+			break
+		}
+
+		if err != nil {
+			_ = iter.Close()
+			return i, err
+		}
+
+		rowNum++
+		newRow, err := applyUpdateExpressions(ctx, p.UpdateExprs, oldRow)
+		if err != nil {
+			_ = iter.Close()
+			return i, err
+		}
+
+		newRow, err = convertRow(ctx, schema, newRow, rowNum, false)
+		if err != nil {
+			_ = iter.Close()
+			return i, err
+		}
+
+		equal, err := oldRow.Equals(newRow, schema)
+		if err != nil {
+			_ = iter.Close()
+			return i, err
+		}
+
+		if equal {
+			continue
+		}
+
+		if err := updatable.Update(ctx, oldRow, newRow); err != nil {
+			_ = iter.Close()
+			return i, err
+		}
+
+		i++
+	}
+
+	return i, iter.Close()
+}
+
+// applyUpdateExpressions applies the update expressions one after another,
+// so each one can see the values assigned by the previous ones.
+func applyUpdateExpressions(ctx *sql.Context, exprs []sql.Expression, row sql.Row) (sql.Row, error) {
+	for _, e := range exprs {
+		val, err := e.Eval(ctx, row)
+		if err != nil {
+			return nil, err
+		}
+
+		updated, ok := val.(sql.Row)
+		if !ok {
+			return nil, expression.ErrInvalidSetField.New(e)
+		}
+
+		row = updated
+	}
+
+	return row, nil
+}
+
+// RowIter implements the Node interface.
+func (p *Update) RowIter(ctx *sql.Context) (sql.RowIter, error) {
+	n, err := p.Execute(ctx)
+	if n > 0 {
+		outdateTableIndexes(p.Catalog, p.CurrentDatabase, p.Child, false)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return sql.RowsToRowIter(sql.NewRow(int64(n))), nil
+}
+
+// TransformUp implements the Transformable interface.
+func (p *Update) TransformUp(f sql.TransformNodeFunc) (sql.Node, error) {
+	child, err := p.Child.TransformUp(f)
+	if err != nil {
+		return nil, err
+	}
+
+	np := *p
+	np.Child = child
+	return f(&np)
+}
+
+// TransformExpressionsUp implements the Transformable interface.
+func (p *Update) TransformExpressionsUp(f sql.TransformExprFunc) (sql.Node, error) {
+	child, err := p.Child.TransformExpressionsUp(f)
+	if err != nil {
+		return nil, err
+	}
+
+	exprs, err := transformExpressionsUp(f, p.UpdateExprs)
+	if err != nil {
+		return nil, err
+	}
+
+	np := *p
+	np.Child = child
+	np.UpdateExprs = exprs
+	return &np, nil
+}
+
+func (p *Update) String() string {
+	var exprs = make([]string, len(p.UpdateExprs))
+	for i, e := range p.UpdateExprs {
+		exprs[i] = e.String()
+	}
+
+	pr := sql.NewTreePrinter()
+	_ = pr.WriteNode("Update(%s)", strings.Join(exprs, ", "))
+	_ = pr.WriteChildren(p.Child.String())
+	return pr.String()
+}
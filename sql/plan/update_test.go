@@ -0,0 +1,70 @@
+package plan
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"gopkg.in/src-d/go-mysql-server.v0/mem"
+	"gopkg.in/src-d/go-mysql-server.v0/sql"
+	"gopkg.in/src-d/go-mysql-server.v0/sql/expression"
+)
+
+func writeTestTable(t *testing.T) *mem.Table {
+	t.Helper()
+	ctx := sql.NewEmptyContext()
+
+	table := mem.NewTable("foo", sql.Schema{
+		{Name: "a", Type: sql.Int64, Source: "foo"},
+		{Name: "b", Type: sql.Text, Source: "foo"},
+	})
+
+	for i, s := range []string{"a", "b", "c"} {
+		require.NoError(t, table.Insert(ctx, sql.NewRow(int64(i+1), s)))
+	}
+
+	return table
+}
+
+func TestUpdate(t *testing.T) {
+	require := require.New(t)
+	table := writeTestTable(t)
+
+	update := NewUpdate(
+		NewFilter(
+			expression.NewGreaterThan(
+				expression.NewGetFieldWithTable(0, sql.Int64, "foo", "a", false),
+				expression.NewLiteral(int64(1), sql.Int64),
+			),
+			NewResolvedTable(table),
+		),
+		[]sql.Expression{
+			expression.NewSetField(
+				expression.NewGetFieldWithTable(1, sql.Text, "foo", "b", false),
+				expression.NewLiteral("x", sql.Text),
+			),
+		},
+	)
+
+	require.Equal([]sql.Row{{int64(2)}}, collectRows(t, update))
+	require.Equal([]sql.Row{
+		{int64(1), "a"},
+		{int64(2), "x"},
+		{int64(3), "x"},
+	}, collectRows(t, NewResolvedTable(table)))
+
+	// Rows that don't change are not counted.
+	require.Equal([]sql.Row{{int64(0)}}, collectRows(t, update))
+}
+
+func TestUpdateNotSupported(t *testing.T) {
+	require := require.New(t)
+
+	update := NewUpdate(
+		NewProject(nil, NewResolvedTable(writeTestTable(t))),
+		nil,
+	)
+
+	_, err := update.RowIter(sql.NewEmptyContext())
+	require.Error(err)
+	require.True(ErrUpdateNotSupported.Is(err))
+}
@@ -0,0 +1,358 @@
+package plan
+
+import (
+	"fmt"
+	"strings"
+
+	"gopkg.in/src-d/go-mysql-server.v0/sql"
+	"gopkg.in/src-d/go-mysql-server.v0/sql/expression"
+)
+
+// CreateView is a node describing the creation of a view. Its child is the
+// definition of the view aliased with its name, which is resolved to check
+// the definition is valid, but the view is stored with the definition as it
+// was parsed, so it's resolved again every time it's used.
+type CreateView struct {
+	UnaryNode
+	Database   sql.Database
+	Catalog    *sql.Catalog
+	name       string
+	definition sql.Node
+	text       string
+	isReplace  bool
+}
+
+// NewCreateView creates a new CreateView node for a view with the given
+// name, parsed definition and SQL text of the definition. If isReplace is
+// true, the view replaces an existing one with the same name.
+func NewCreateView(
+	db sql.Database,
+	name string,
+	definition sql.Node,
+	text string,
+	isReplace bool,
+) *CreateView {
+	return &CreateView{
+		UnaryNode:  UnaryNode{NewSubqueryAlias(name, definition)},
+		Database:   db,
+		name:       name,
+		definition: definition,
+		text:       text,
+		isReplace:  isReplace,
+	}
+}
+
+// Resolved implements the Resolvable interface.
+func (c *CreateView) Resolved() bool {
+	_, ok := c.Database.(sql.UnresolvedDatabase)
+	return !ok && c.Child.Resolved()
+}
+
+// Schema implements the Node interface.
+func (c *CreateView) Schema() sql.Schema { return nil }
+
+// RowIter implements the Node interface.
+func (c *CreateView) RowIter(ctx *sql.Context) (sql.RowIter, error) {
+	db := c.Database.Name()
+	if _, ok := c.Database.Tables()[c.name]; ok {
+		return nil, sql.ErrTableAlreadyExists.New(c.name)
+	}
+
+	// Tables without database are the ones of the database of the view,
+	// not the current one when the view is used.
+	definition, err := qualifyTables(c.definition, db)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := checkViewRecursion(c.Catalog, db, c.name, definition, nil); err != nil {
+		return nil, err
+	}
+
+	view := sql.View{Name: c.name, Definition: definition, TextDefinition: c.text}
+	return sql.RowsToRowIter(), c.Catalog.RegisterView(db, view, c.isReplace)
+}
+
+// qualifyTables sets the given database to the unresolved tables of the
+// node without database, except the ones with the names of the given
+// recursive common table expressions, which reference the expressions.
+func qualifyTables(n sql.Node, db string, ctes ...string) (sql.Node, error) {
+	n, err := n.TransformUp(func(n sql.Node) (sql.Node, error) {
+		switch n := n.(type) {
+		case *UnresolvedTable:
+			if n.Database != "" || isCTEReference(n, ctes) {
+				return n, nil
+			}
+			return NewUnresolvedTable(n.Name(), db), nil
+		case *SubqueryAlias:
+			child, err := qualifyTables(n.Child, db, ctes...)
+			if err != nil {
+				return nil, err
+			}
+			return NewSubqueryAlias(n.Name(), child), nil
+		case *RecursiveCTE:
+			// Like SubqueryAlias, RecursiveCTE is not transformed by the
+			// transformations of its parents.
+			anchor, err := qualifyTables(n.Left, db, ctes...)
+			if err != nil {
+				return nil, err
+			}
+
+			recursive, err := qualifyTables(n.Right, db, append([]string{n.Name()}, ctes...)...)
+			if err != nil {
+				return nil, err
+			}
+
+			return NewRecursiveCTE(n.Name(), n.Columns(), anchor, recursive, n.Distinct()), nil
+		default:
+			return n, nil
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return n.TransformExpressionsUp(func(e sql.Expression) (sql.Expression, error) {
+		subquery, ok := e.(*expression.Subquery)
+		if !ok {
+			return e, nil
+		}
+
+		query, err := qualifyTables(subquery.Query, db, ctes...)
+		if err != nil {
+			return nil, err
+		}
+
+		return subquery.WithQuery(query), nil
+	})
+}
+
+// isCTEReference reports whether the given table is a reference to one of
+// the common table expressions with the given names.
+func isCTEReference(t *UnresolvedTable, ctes []string) bool {
+	for _, name := range ctes {
+		if strings.EqualFold(t.Name(), name) {
+			return true
+		}
+	}
+	return false
+}
+
+// checkViewRecursion returns an error if the given definition uses the
+// view with the given name, directly or through other views.
+func checkViewRecursion(
+	catalog *sql.Catalog,
+	db, name string,
+	definition sql.Node,
+	visited map[sql.ViewKey]bool,
+) error {
+	if visited == nil {
+		visited = make(map[sql.ViewKey]bool)
+	}
+
+	var err error
+	InspectExpressions(definition, func(e sql.Expression) bool {
+		if subquery, ok := e.(*expression.Subquery); ok && err == nil {
+			err = checkViewRecursion(catalog, db, name, subquery.Query, visited)
+		}
+		return err == nil
+	})
+	if err != nil {
+		return err
+	}
+
+	Inspect(definition, func(n sql.Node) bool {
+		t, ok := n.(*UnresolvedTable)
+		if !ok || err != nil {
+			return err == nil
+		}
+
+		key := sql.NewViewKey(t.Database, t.Name())
+		if key == sql.NewViewKey(db, name) {
+			err = sql.ErrViewRecursion.New(db, name)
+			return false
+		}
+
+		if visited[key] {
+			return true
+		}
+		visited[key] = true
+
+		if view, ok := catalog.View(t.Database, t.Name()); ok {
+			err = checkViewRecursion(catalog, db, name, view.Definition, visited)
+		}
+
+		return err == nil
+	})
+
+	return err
+}
+
+// TransformUp implements the Transformable interface.
+func (c *CreateView) TransformUp(f sql.TransformNodeFunc) (sql.Node, error) {
+	child, err := c.Child.TransformUp(f)
+	if err != nil {
+		return nil, err
+	}
+
+	nc := *c
+	nc.Child = child
+	return f(&nc)
+}
+
+// TransformExpressionsUp implements the Transformable interface.
+func (c *CreateView) TransformExpressionsUp(f sql.TransformExprFunc) (sql.Node, error) {
+	child, err := c.Child.TransformExpressionsUp(f)
+	if err != nil {
+		return nil, err
+	}
+
+	nc := *c
+	nc.Child = child
+	return &nc, nil
+}
+
+func (c *CreateView) String() string {
+	pr := sql.NewTreePrinter()
+	_ = pr.WriteNode("CreateView(%s)", c.name)
+	_ = pr.WriteChildren(c.Child.String())
+	return pr.String()
+}
+
+// DropView is a node describing the removal of some views.
+type DropView struct {
+	Catalog         *sql.Catalog
+	CurrentDatabase string
+	views           []sql.ViewKey
+	ifExists        bool
+}
+
+// NewDropView creates a new DropView node. Views without database are the
+// ones of the current database.
+func NewDropView(views []sql.ViewKey, ifExists bool) *DropView {
+	return &DropView{views: views, ifExists: ifExists}
+}
+
+// Resolved implements the Resolvable interface.
+func (d *DropView) Resolved() bool { return true }
+
+// Schema implements the Node interface.
+func (d *DropView) Schema() sql.Schema { return nil }
+
+// Children implements the Node interface.
+func (d *DropView) Children() []sql.Node { return nil }
+
+// RowIter implements the Node interface. No view is dropped if any of them
+// does not exist, unless ifExists is true.
+func (d *DropView) RowIter(ctx *sql.Context) (sql.RowIter, error) {
+	var views []sql.ViewKey
+	for _, key := range d.views {
+		if key.Database == "" {
+			key.Database = d.CurrentDatabase
+		}
+
+		if _, ok := d.Catalog.View(key.Database, key.Name); !ok {
+			if d.ifExists {
+				continue
+			}
+			return nil, sql.ErrViewNotFound.New(key.Database + "." + key.Name)
+		}
+
+		views = append(views, key)
+	}
+
+	for _, key := range views {
+		if err := d.Catalog.DeleteView(key.Database, key.Name); err != nil {
+			return nil, err
+		}
+	}
+
+	return sql.RowsToRowIter(), nil
+}
+
+// TransformUp implements the Transformable interface.
+func (d *DropView) TransformUp(f sql.TransformNodeFunc) (sql.Node, error) {
+	nc := *d
+	return f(&nc)
+}
+
+// TransformExpressionsUp implements the Transformable interface.
+func (d *DropView) TransformExpressionsUp(f sql.TransformExprFunc) (sql.Node, error) {
+	return d, nil
+}
+
+func (d *DropView) String() string {
+	var names = make([]string, len(d.views))
+	for i, key := range d.views {
+		names[i] = key.Name
+		if key.Database != "" {
+			names[i] = key.Database + "." + key.Name
+		}
+	}
+	return fmt.Sprintf("DropView(%s)", strings.Join(names, ", "))
+}
+
+// ShowCreateView is a node that shows the CREATE VIEW statement of a view.
+type ShowCreateView struct {
+	Catalog         *sql.Catalog
+	CurrentDatabase string
+	database        string
+	name            string
+}
+
+var showCreateViewSchema = sql.Schema{
+	{Name: "View", Type: sql.Text},
+	{Name: "Create View", Type: sql.Text},
+	{Name: "character_set_client", Type: sql.Text},
+	{Name: "collation_connection", Type: sql.Text},
+}
+
+// NewShowCreateView creates a new ShowCreateView node for the view with
+// the given name in the given database, or the current one if it's empty.
+func NewShowCreateView(db, name string) *ShowCreateView {
+	return &ShowCreateView{database: db, name: name}
+}
+
+// Resolved implements the Resolvable interface.
+func (s *ShowCreateView) Resolved() bool { return true }
+
+// Schema implements the Node interface.
+func (s *ShowCreateView) Schema() sql.Schema { return showCreateViewSchema }
+
+// Children implements the Node interface.
+func (s *ShowCreateView) Children() []sql.Node { return nil }
+
+// RowIter implements the Node interface.
+func (s *ShowCreateView) RowIter(ctx *sql.Context) (sql.RowIter, error) {
+	db := s.database
+	if db == "" {
+		db = s.CurrentDatabase
+	}
+
+	view, ok := s.Catalog.View(db, s.name)
+	if !ok {
+		return nil, sql.ErrViewNotFound.New(s.name)
+	}
+
+	return sql.RowsToRowIter(sql.NewRow(
+		view.Name,
+		fmt.Sprintf("CREATE VIEW `%s` AS %s", view.Name, view.TextDefinition),
+		defaultCharacterSet,
+		sql.DefaultCollation.Name,
+	)), nil
+}
+
+// TransformUp implements the Transformable interface.
+func (s *ShowCreateView) TransformUp(f sql.TransformNodeFunc) (sql.Node, error) {
+	nc := *s
+	return f(&nc)
+}
+
+// TransformExpressionsUp implements the Transformable interface.
+func (s *ShowCreateView) TransformExpressionsUp(f sql.TransformExprFunc) (sql.Node, error) {
+	return s, nil
+}
+
+func (s *ShowCreateView) String() string {
+	return fmt.Sprintf("SHOW CREATE VIEW %s", s.name)
+}
@@ -0,0 +1,142 @@
+package plan
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"gopkg.in/src-d/go-mysql-server.v0/mem"
+	"gopkg.in/src-d/go-mysql-server.v0/sql"
+	"gopkg.in/src-d/go-mysql-server.v0/sql/expression"
+)
+
+func TestCreateView(t *testing.T) {
+	require := require.New(t)
+	ctx := sql.NewEmptyContext()
+
+	db := mem.NewDatabase("db")
+	db.AddTable("t", mem.NewTable("t", sql.Schema{
+		{Name: "a", Type: sql.Int64, Source: "t"},
+	}))
+
+	catalog := sql.NewCatalog()
+	catalog.AddDatabase(db)
+
+	create := func(name string, definition sql.Node, isReplace bool) error {
+		c := NewCreateView(db, name, definition, "", isReplace)
+		c.Catalog = catalog
+		_, err := c.RowIter(ctx)
+		return err
+	}
+
+	definition := NewProject(
+		[]sql.Expression{expression.NewUnresolvedColumn("a")},
+		NewUnresolvedTable("t", ""),
+	)
+	require.NoError(create("v1", definition, false))
+
+	view, ok := catalog.View("db", "v1")
+	require.True(ok)
+	require.Equal(NewProject(
+		[]sql.Expression{expression.NewUnresolvedColumn("a")},
+		NewUnresolvedTable("t", "db"),
+	), view.Definition)
+
+	err := create("v1", definition, false)
+	require.Error(err)
+	require.True(sql.ErrViewAlreadyExists.Is(err))
+
+	err = create("t", definition, true)
+	require.Error(err)
+	require.True(sql.ErrTableAlreadyExists.Is(err))
+
+	require.NoError(create("v2", NewUnresolvedTable("v1", ""), false))
+
+	err = create("v1", NewUnresolvedTable("v2", ""), true)
+	require.Error(err)
+	require.True(sql.ErrViewRecursion.Is(err))
+
+	// The tables of subqueries are qualified and checked too.
+	subqueryDefinition := func(table string) sql.Node {
+		return NewFilter(
+			expression.NewInSubquery(
+				expression.NewUnresolvedColumn("a"),
+				expression.NewSubquery(NewProject(
+					[]sql.Expression{expression.NewUnresolvedColumn("a")},
+					NewUnresolvedTable(table, ""),
+				)),
+			),
+			NewUnresolvedTable("t", ""),
+		)
+	}
+	require.NoError(create("v3", subqueryDefinition("t"), false))
+
+	view, ok = catalog.View("db", "v3")
+	require.True(ok)
+	require.Equal(NewFilter(
+		expression.NewInSubquery(
+			expression.NewUnresolvedColumn("a"),
+			expression.NewSubquery(NewProject(
+				[]sql.Expression{expression.NewUnresolvedColumn("a")},
+				NewUnresolvedTable("t", "db"),
+			)),
+		),
+		NewUnresolvedTable("t", "db"),
+	), view.Definition)
+
+	err = create("v1", subqueryDefinition("v2"), true)
+	require.Error(err)
+	require.True(sql.ErrViewRecursion.Is(err))
+
+	// The references of recursive common table expressions to themselves
+	// are not qualified.
+	recursiveDefinition := func(db string) sql.Node {
+		return NewRecursiveCTE(
+			"cte",
+			nil,
+			NewUnresolvedTable("t", db),
+			NewCrossJoin(NewUnresolvedTable("t", db), NewUnresolvedTable("cte", "")),
+			false,
+		)
+	}
+	require.NoError(create("v4", recursiveDefinition(""), false))
+
+	view, ok = catalog.View("db", "v4")
+	require.True(ok)
+	require.Equal(recursiveDefinition("db"), view.Definition)
+}
+
+func TestDropView(t *testing.T) {
+	require := require.New(t)
+	ctx := sql.NewEmptyContext()
+
+	catalog := sql.NewCatalog()
+	require.NoError(catalog.RegisterView("db", sql.View{Name: "v1"}, false))
+	require.NoError(catalog.RegisterView("db", sql.View{Name: "v2"}, false))
+
+	drop := func(ifExists bool, names ...string) error {
+		var views []sql.ViewKey
+		for _, name := range names {
+			views = append(views, sql.ViewKey{Name: name})
+		}
+
+		d := NewDropView(views, ifExists)
+		d.Catalog = catalog
+		d.CurrentDatabase = "db"
+		_, err := d.RowIter(ctx)
+		return err
+	}
+
+	err := drop(false, "v1", "v3")
+	require.Error(err)
+	require.True(sql.ErrViewNotFound.Is(err))
+
+	_, ok := catalog.View("db", "v1")
+	require.True(ok)
+
+	require.NoError(drop(true, "v1", "v3"))
+	_, ok = catalog.View("db", "v1")
+	require.False(ok)
+
+	require.NoError(drop(false, "v2"))
+	require.Empty(catalog.ViewsInDatabase("db"))
+}
@@ -0,0 +1,517 @@
+package plan
+
+import (
+	"math"
+	"sort"
+	"strings"
+
+	opentracing "github.com/opentracing/opentracing-go"
+	errors "gopkg.in/src-d/go-errors.v1"
+	"gopkg.in/src-d/go-mysql-server.v0/sql"
+	"gopkg.in/src-d/go-mysql-server.v0/sql/expression"
+)
+
+// ErrNotWindowFunction is returned when a function that is neither a window
+// function nor an aggregation is used with a window.
+var ErrNotWindowFunction = errors.NewKind("%s can't be used as a window function")
+
+// ErrRangeFrameOrderBy is returned when a RANGE frame with an offset is
+// used in a window without exactly one ORDER BY expression.
+var ErrRangeFrameOrderBy = errors.NewKind("RANGE frame with an offset requires exactly one ORDER BY expression, but window has %d")
+
+// Window is a node that evaluates window functions and aggregations over
+// windows. Like a projection, it returns a row with its expressions for
+// every row of its child, but the expressions that are windows are
+// evaluated with the rows of the partition of each row.
+type Window struct {
+	UnaryNode
+	SelectExprs []sql.Expression
+}
+
+// NewWindow creates a new Window node.
+func NewWindow(selectExprs []sql.Expression, child sql.Node) *Window {
+	return &Window{
+		UnaryNode:   UnaryNode{Child: child},
+		SelectExprs: selectExprs,
+	}
+}
+
+// Resolved implements the Resolvable interface.
+func (w *Window) Resolved() bool {
+	return w.UnaryNode.Child.Resolved() &&
+		expressionsResolved(w.SelectExprs...)
+}
+
+// Schema implements the Node interface.
+func (w *Window) Schema() sql.Schema {
+	var s = make(sql.Schema, len(w.SelectExprs))
+	for i, e := range w.SelectExprs {
+		var name string
+		if n, ok := e.(sql.Nameable); ok {
+			name = n.Name()
+		} else {
+			name = e.String()
+		}
+
+		var table string
+		if t, ok := e.(sql.Tableable); ok {
+			table = t.Table()
+		}
+
+		s[i] = &sql.Column{
+			Name:     name,
+			Type:     e.Type(),
+			Nullable: e.IsNullable(),
+			Source:   table,
+		}
+	}
+
+	return s
+}
+
+// RowIter implements the Node interface. All the rows of the child are
+// read before any row is returned, and they are returned in the same order.
+func (w *Window) RowIter(ctx *sql.Context) (sql.RowIter, error) {
+	span, ctx := ctx.Span("plan.Window", opentracing.Tag{
+		Key:   "expressions",
+		Value: len(w.SelectExprs),
+	})
+	defer span.Finish()
+
+	rows, err := sql.NodeToRows(ctx, w.Child)
+	if err != nil {
+		return nil, err
+	}
+
+	var result = make([]sql.Row, len(rows))
+	for i := range result {
+		result[i] = make(sql.Row, len(w.SelectExprs))
+	}
+
+	for j, e := range w.SelectExprs {
+		window, ok := windowExpression(e)
+		if !ok {
+			for i, row := range rows {
+				if result[i][j], err = e.Eval(ctx, row); err != nil {
+					return nil, err
+				}
+			}
+			continue
+		}
+
+		values, err := evalWindow(ctx, window, rows)
+		if err != nil {
+			return nil, err
+		}
+
+		for i, v := range values {
+			result[i][j] = v
+		}
+	}
+
+	return sql.RowsToRowIter(result...), nil
+}
+
+// windowExpression returns the window of the given expression, if it's a
+// window or an alias of a window.
+func windowExpression(e sql.Expression) (*expression.Window, bool) {
+	if alias, ok := e.(*expression.Alias); ok {
+		e = alias.Child
+	}
+
+	w, ok := e.(*expression.Window)
+	return w, ok
+}
+
+// evalWindow returns the value of the given window for each of the rows.
+func evalWindow(ctx *sql.Context, w *expression.Window, rows []sql.Row) ([]interface{}, error) {
+	var keys []uint64
+	var partitions = make(map[uint64][]int)
+	for i, row := range rows {
+		key, err := groupingKey(ctx, w.PartitionBy, row)
+		if err != nil {
+			return nil, err
+		}
+
+		if _, ok := partitions[key]; !ok {
+			keys = append(keys, key)
+		}
+		partitions[key] = append(partitions[key], i)
+	}
+
+	var sortFields = make([]SortField, len(w.OrderBy))
+	for i, f := range w.OrderBy {
+		sortFields[i] = SortField{Column: f.Column, Order: Ascending}
+		if f.Descending {
+			sortFields[i].Order = Descending
+		}
+	}
+
+	var values = make([]interface{}, len(rows))
+	for _, key := range keys {
+		indexes := partitions[key]
+		var partition = sql.WindowPartition{
+			Rows:       make([]sql.Row, len(indexes)),
+			PeerGroups: make([]int, len(indexes)),
+		}
+		for i, idx := range indexes {
+			partition.Rows[i] = rows[idx]
+		}
+
+		s := &windowSorter{
+			sorter:  &sorter{sortFields: sortFields, rows: partition.Rows, ctx: ctx},
+			indexes: indexes,
+		}
+		sort.Stable(s)
+		if s.lastError != nil {
+			return nil, s.lastError
+		}
+
+		for i := 1; i < len(partition.Rows); i++ {
+			peers, err := windowPeers(ctx, sortFields, partition.Rows[i-1], partition.Rows[i])
+			if err != nil {
+				return nil, err
+			}
+
+			partition.PeerGroups[i] = partition.PeerGroups[i-1]
+			if !peers {
+				partition.PeerGroups[i]++
+			}
+		}
+
+		frames, err := newWindowFrames(ctx, w, partition)
+		if err != nil {
+			return nil, err
+		}
+
+		partitionValues, err := evalWindowFunction(ctx, w.Function, partition, frames)
+		if err != nil {
+			return nil, err
+		}
+
+		for i, v := range partitionValues {
+			values[indexes[i]] = v
+		}
+	}
+
+	return values, nil
+}
+
+// evalWindowFunction returns the value of the given window function for
+// each of the rows of the partition.
+func evalWindowFunction(
+	ctx *sql.Context,
+	fn sql.Expression,
+	partition sql.WindowPartition,
+	frames *windowFrames,
+) ([]interface{}, error) {
+	values := make([]interface{}, len(partition.Rows))
+	switch fn := fn.(type) {
+	case sql.WindowFunction:
+		for i := range partition.Rows {
+			v, err := fn.EvalWindow(ctx, partition, i)
+			if err != nil {
+				return nil, err
+			}
+			values[i] = v
+		}
+	case sql.Aggregation:
+		// Frames starting at the first row only grow from a row to the
+		// next, so only the rows not in the frame of the previous row are
+		// added to its buffer. Otherwise, the buffer is computed again.
+		running := frames.frame.Start.Type == expression.UnboundedPreceding
+		buffer := fn.NewBuffer()
+		var next int
+		for i := range partition.Rows {
+			start, end := frames.bounds(i)
+			if !running || end < next {
+				buffer = fn.NewBuffer()
+				next = start
+			}
+
+			for _, row := range partition.Rows[next:end] {
+				if err := fn.Update(ctx, buffer, row); err != nil {
+					return nil, err
+				}
+			}
+			next = end
+
+			v, err := fn.Eval(ctx, buffer)
+			if err != nil {
+				return nil, err
+			}
+			values[i] = v
+		}
+	default:
+		return nil, ErrNotWindowFunction.New(fn)
+	}
+
+	return values, nil
+}
+
+// windowSorter sorts the rows of a partition along with the positions of
+// the rows in the input of the window.
+type windowSorter struct {
+	*sorter
+	indexes []int
+}
+
+func (s *windowSorter) Swap(i, j int) {
+	s.sorter.Swap(i, j)
+	s.indexes[i], s.indexes[j] = s.indexes[j], s.indexes[i]
+}
+
+// windowPeers reports whether the given rows have the same values for the
+// given sort fields.
+func windowPeers(ctx *sql.Context, fields []SortField, a, b sql.Row) (bool, error) {
+	for _, f := range fields {
+		av, err := f.Column.Eval(ctx, a)
+		if err != nil {
+			return false, err
+		}
+
+		bv, err := f.Column.Eval(ctx, b)
+		if err != nil {
+			return false, err
+		}
+
+		if av == nil || bv == nil {
+			if av != bv {
+				return false, nil
+			}
+			continue
+		}
+
+		cmp, err := f.Column.Type().Compare(av, bv)
+		if err != nil {
+			return false, err
+		}
+
+		if cmp != 0 {
+			return false, nil
+		}
+	}
+
+	return true, nil
+}
+
+// windowFrames computes the frames of the rows of a partition.
+type windowFrames struct {
+	frame     expression.WindowFrame
+	partition sql.WindowPartition
+	// values are the values of the ORDER BY expression of a RANGE frame
+	// with an offset, multiplied by -1 if the order is descending so they
+	// are always ascending. Null values are -Inf.
+	values []float64
+	// groupStarts are the positions of the first row of each peer group,
+	// followed by the number of rows.
+	groupStarts []int
+}
+
+func newWindowFrames(
+	ctx *sql.Context,
+	w *expression.Window,
+	partition sql.WindowPartition,
+) (*windowFrames, error) {
+	f := &windowFrames{partition: partition}
+	for i, g := range partition.PeerGroups {
+		if i == 0 || g != partition.PeerGroups[i-1] {
+			f.groupStarts = append(f.groupStarts, i)
+		}
+	}
+	f.groupStarts = append(f.groupStarts, len(partition.Rows))
+
+	switch {
+	case w.Frame != nil:
+		f.frame = *w.Frame
+	case len(w.OrderBy) > 0:
+		f.frame = expression.WindowFrame{
+			Unit:  expression.RangeFrame,
+			Start: expression.WindowFrameBound{Type: expression.UnboundedPreceding},
+			End:   expression.WindowFrameBound{Type: expression.CurrentRow},
+		}
+	default:
+		f.frame = expression.WindowFrame{
+			Unit:  expression.RowsFrame,
+			Start: expression.WindowFrameBound{Type: expression.UnboundedPreceding},
+			End:   expression.WindowFrameBound{Type: expression.UnboundedFollowing},
+		}
+	}
+
+	if f.frame.Unit != expression.RangeFrame || !(hasOffset(f.frame.Start) || hasOffset(f.frame.End)) {
+		return f, nil
+	}
+
+	if len(w.OrderBy) != 1 {
+		return nil, ErrRangeFrameOrderBy.New(len(w.OrderBy))
+	}
+
+	f.values = make([]float64, len(partition.Rows))
+	for i, row := range partition.Rows {
+		v, err := w.OrderBy[0].Column.Eval(ctx, row)
+		if err != nil {
+			return nil, err
+		}
+
+		if v == nil {
+			f.values[i] = math.Inf(-1)
+			continue
+		}
+
+		n, err := sql.Float64.Convert(v)
+		if err != nil {
+			return nil, err
+		}
+
+		f.values[i] = n.(float64)
+		if w.OrderBy[0].Descending {
+			f.values[i] = -f.values[i]
+		}
+	}
+
+	return f, nil
+}
+
+func hasOffset(b expression.WindowFrameBound) bool {
+	return b.Type == expression.Preceding || b.Type == expression.Following
+}
+
+// bounds returns the positions of the first row of the frame of the row
+// at the given position and of the row after the last one.
+func (f *windowFrames) bounds(i int) (start, end int) {
+	n := len(f.partition.Rows)
+	if f.frame.Unit == expression.RowsFrame {
+		start = f.rowsBound(f.frame.Start, i)
+		end = f.rowsBound(f.frame.End, i) + 1
+	} else {
+		start = f.rangeStart(f.frame.Start, i)
+		end = f.rangeEnd(f.frame.End, i)
+	}
+
+	if start < 0 {
+		start = 0
+	}
+
+	if end > n {
+		end = n
+	}
+
+	if start > end {
+		start = end
+	}
+
+	return start, end
+}
+
+func (f *windowFrames) rowsBound(b expression.WindowFrameBound, i int) int {
+	switch b.Type {
+	case expression.UnboundedPreceding:
+		return -1
+	case expression.Preceding:
+		return i - int(b.Offset)
+	case expression.Following:
+		return i + int(b.Offset)
+	case expression.UnboundedFollowing:
+		return len(f.partition.Rows)
+	default:
+		return i
+	}
+}
+
+func (f *windowFrames) rangeStart(b expression.WindowFrameBound, i int) int {
+	groups := f.partition.PeerGroups
+	switch b.Type {
+	case expression.UnboundedPreceding:
+		return 0
+	case expression.UnboundedFollowing:
+		return len(groups)
+	case expression.CurrentRow:
+	default:
+		if !math.IsInf(f.values[i], -1) {
+			min := f.values[i] + float64(b.Offset)
+			if b.Type == expression.Preceding {
+				min = f.values[i] - float64(b.Offset)
+			}
+
+			return sort.Search(len(f.values), func(j int) bool {
+				return f.values[j] >= min
+			})
+		}
+	}
+
+	return f.groupStarts[groups[i]]
+}
+
+func (f *windowFrames) rangeEnd(b expression.WindowFrameBound, i int) int {
+	groups := f.partition.PeerGroups
+	switch b.Type {
+	case expression.UnboundedPreceding:
+		return 0
+	case expression.UnboundedFollowing:
+		return len(groups)
+	case expression.CurrentRow:
+	default:
+		if !math.IsInf(f.values[i], -1) {
+			max := f.values[i] + float64(b.Offset)
+			if b.Type == expression.Preceding {
+				max = f.values[i] - float64(b.Offset)
+			}
+
+			return sort.Search(len(f.values), func(j int) bool {
+				return f.values[j] > max
+			})
+		}
+	}
+
+	return f.groupStarts[groups[i]+1]
+}
+
+// TransformUp implements the Transformable interface.
+func (w *Window) TransformUp(f sql.TransformNodeFunc) (sql.Node, error) {
+	child, err := w.Child.TransformUp(f)
+	if err != nil {
+		return nil, err
+	}
+	return f(NewWindow(w.SelectExprs, child))
+}
+
+// TransformExpressionsUp implements the Transformable interface.
+func (w *Window) TransformExpressionsUp(f sql.TransformExprFunc) (sql.Node, error) {
+	exprs, err := transformExpressionsUp(f, w.SelectExprs)
+	if err != nil {
+		return nil, err
+	}
+
+	child, err := w.Child.TransformExpressionsUp(f)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewWindow(exprs, child), nil
+}
+
+// Expressions implements the Expressioner interface.
+func (w *Window) Expressions() []sql.Expression {
+	return w.SelectExprs
+}
+
+// TransformExpressions implements the Expressioner interface.
+func (w *Window) TransformExpressions(f sql.TransformExprFunc) (sql.Node, error) {
+	exprs, err := transformExpressionsUp(f, w.SelectExprs)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewWindow(exprs, w.Child), nil
+}
+
+func (w *Window) String() string {
+	pr := sql.NewTreePrinter()
+	var exprs = make([]string, len(w.SelectExprs))
+	for i, expr := range w.SelectExprs {
+		exprs[i] = expr.String()
+	}
+	_ = pr.WriteNode("Window(%s)", strings.Join(exprs, ", "))
+	_ = pr.WriteChildren(w.Child.String())
+	return pr.String()
+}
@@ -0,0 +1,187 @@
+package plan
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"gopkg.in/src-d/go-mysql-server.v0/mem"
+	"gopkg.in/src-d/go-mysql-server.v0/sql"
+	"gopkg.in/src-d/go-mysql-server.v0/sql/expression"
+	"gopkg.in/src-d/go-mysql-server.v0/sql/expression/function"
+	"gopkg.in/src-d/go-mysql-server.v0/sql/expression/function/aggregation"
+)
+
+func windowTable(t *testing.T) sql.Node {
+	t.Helper()
+	ctx := sql.NewEmptyContext()
+
+	table := mem.NewTable("t", sql.Schema{
+		{Name: "a", Source: "t", Type: sql.Text},
+		{Name: "b", Source: "t", Type: sql.Int64},
+	})
+
+	rows := []sql.Row{
+		{"x", int64(1)},
+		{"y", int64(5)},
+		{"x", int64(3)},
+		{"x", int64(3)},
+		{"y", int64(2)},
+		{"x", int64(4)},
+	}
+
+	for _, row := range rows {
+		require.NoError(t, table.Insert(ctx, row))
+	}
+
+	return NewResolvedTable(table)
+}
+
+func TestWindow(t *testing.T) {
+	a := expression.NewGetFieldWithTable(0, sql.Text, "t", "a", false)
+	b := expression.NewGetFieldWithTable(1, sql.Int64, "t", "b", false)
+	partitionBy := []sql.Expression{a}
+	orderBy := []expression.WindowSortField{{Column: b}}
+
+	rowsFrame := func(start, end expression.WindowFrameBound) *expression.WindowFrame {
+		return &expression.WindowFrame{Unit: expression.RowsFrame, Start: start, End: end}
+	}
+	rangeFrame := func(start, end expression.WindowFrameBound) *expression.WindowFrame {
+		return &expression.WindowFrame{Unit: expression.RangeFrame, Start: start, End: end}
+	}
+	preceding := func(n int64) expression.WindowFrameBound {
+		return expression.WindowFrameBound{Type: expression.Preceding, Offset: n}
+	}
+	current := expression.WindowFrameBound{Type: expression.CurrentRow}
+	unboundedPreceding := expression.WindowFrameBound{Type: expression.UnboundedPreceding}
+	unboundedFollowing := expression.WindowFrameBound{Type: expression.UnboundedFollowing}
+
+	testCases := []struct {
+		name     string
+		window   *expression.Window
+		expected []interface{}
+	}{
+		{
+			"row number",
+			expression.NewWindow(function.NewRowNumber(), partitionBy, orderBy, nil),
+			[]interface{}{uint64(1), uint64(2), uint64(2), uint64(3), uint64(1), uint64(4)},
+		},
+		{
+			"rank descending",
+			expression.NewWindow(
+				function.NewRank(),
+				partitionBy,
+				[]expression.WindowSortField{{Column: b, Descending: true}},
+				nil,
+			),
+			[]interface{}{uint64(4), uint64(1), uint64(2), uint64(2), uint64(2), uint64(1)},
+		},
+		{
+			"sum without order",
+			expression.NewWindow(aggregation.NewSum(b), partitionBy, nil, nil),
+			[]interface{}{float64(11), float64(7), float64(11), float64(11), float64(7), float64(11)},
+		},
+		{
+			"running sum with peers",
+			expression.NewWindow(aggregation.NewSum(b), partitionBy, orderBy, nil),
+			[]interface{}{float64(1), float64(7), float64(7), float64(7), float64(2), float64(11)},
+		},
+		{
+			"rows frame",
+			expression.NewWindow(aggregation.NewSum(b), nil, orderBy, rowsFrame(preceding(1), current)),
+			[]interface{}{float64(1), float64(9), float64(5), float64(6), float64(3), float64(7)},
+		},
+		{
+			"range frame",
+			expression.NewWindow(aggregation.NewCount(b), partitionBy, orderBy, rangeFrame(preceding(1), unboundedFollowing)),
+			[]interface{}{int32(4), int32(1), int32(3), int32(3), int32(2), int32(3)},
+		},
+		{
+			"running rows frame ending before the row",
+			expression.NewWindow(aggregation.NewSum(b), nil, orderBy, rowsFrame(unboundedPreceding, preceding(1))),
+			[]interface{}{nil, float64(13), float64(3), float64(6), float64(1), float64(9)},
+		},
+		{
+			"running object",
+			expression.NewWindow(aggregation.NewJSONObjectAgg(a, b), nil, orderBy, nil),
+			[]interface{}{
+				map[string]interface{}{"x": int64(1)},
+				map[string]interface{}{"x": int64(4), "y": int64(5)},
+				map[string]interface{}{"x": int64(3), "y": int64(2)},
+				map[string]interface{}{"x": int64(3), "y": int64(2)},
+				map[string]interface{}{"x": int64(1), "y": int64(2)},
+				map[string]interface{}{"x": int64(4), "y": int64(2)},
+			},
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			require := require.New(t)
+
+			w := NewWindow([]sql.Expression{a, tt.window}, windowTable(t))
+			rows := collectRows(t, w)
+
+			var result []interface{}
+			for _, row := range rows {
+				result = append(result, row[1])
+			}
+			require.Equal(tt.expected, result)
+		})
+	}
+}
+
+// countingSum is a sum counting the rows it is updated with.
+type countingSum struct {
+	*aggregation.Sum
+	updates *int
+}
+
+func (s countingSum) Update(ctx *sql.Context, buffer, row sql.Row) error {
+	*s.updates++
+	return s.Sum.Update(ctx, buffer, row)
+}
+
+func TestWindowRunningAggregation(t *testing.T) {
+	require := require.New(t)
+
+	var updates int
+	b := expression.NewGetFieldWithTable(1, sql.Int64, "t", "b", false)
+	w := NewWindow(
+		[]sql.Expression{
+			expression.NewWindow(
+				countingSum{aggregation.NewSum(b), &updates},
+				nil,
+				[]expression.WindowSortField{{Column: b}},
+				nil,
+			),
+		},
+		windowTable(t),
+	)
+
+	var result []interface{}
+	for _, row := range collectRows(t, w) {
+		result = append(result, row[0])
+	}
+
+	require.Equal(
+		[]interface{}{float64(1), float64(18), float64(9), float64(9), float64(3), float64(13)},
+		result,
+	)
+	// Each row is added to the buffer once.
+	require.Equal(6, updates)
+}
+
+func TestWindowNotWindowFunction(t *testing.T) {
+	require := require.New(t)
+
+	w := NewWindow(
+		[]sql.Expression{
+			expression.NewWindow(expression.NewLiteral(int64(1), sql.Int64), nil, nil, nil),
+		},
+		windowTable(t),
+	)
+
+	_, err := w.RowIter(sql.NewEmptyContext())
+	require.Error(err)
+	require.True(ErrNotWindowFunction.Is(err))
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"io"
 	"math"
+	"strings"
 	"sync"
 	"time"
 
@@ -49,6 +50,16 @@ type Session interface {
 	ClearWarnings()
 	// WarningCount returns a number of session warnings
 	WarningCount() uint16
+	// Transaction returns the transaction open in the session, if any.
+	Transaction() Transaction
+	// SetTransaction sets the transaction open in the session. A nil
+	// transaction means there is no open transaction.
+	SetTransaction(tx Transaction)
+	// LastInsertID returns the first value generated for an AUTO_INCREMENT
+	// column by the last INSERT of the session that generated any.
+	LastInsertID() uint64
+	// SetLastInsertID sets the value returned by LastInsertID.
+	SetLastInsertID(id uint64)
 }
 
 // BaseSession is the basic session type.
@@ -59,6 +70,8 @@ type BaseSession struct {
 	mu       sync.RWMutex
 	config   map[string]TypedValue
 	warnings []*Warning
+	tx       Transaction
+	insertID uint64
 }
 
 // Address returns the server address.
@@ -139,6 +152,34 @@ func (s *BaseSession) WarningCount() uint16 {
 	return uint16(len(s.warnings))
 }
 
+// Transaction implements the Session interface.
+func (s *BaseSession) Transaction() Transaction {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.tx
+}
+
+// SetTransaction implements the Session interface.
+func (s *BaseSession) SetTransaction(tx Transaction) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.tx = tx
+}
+
+// LastInsertID implements the Session interface.
+func (s *BaseSession) LastInsertID() uint64 {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.insertID
+}
+
+// SetLastInsertID implements the Session interface.
+func (s *BaseSession) SetLastInsertID(id uint64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.insertID = id
+}
+
 type (
 	// TypedValue is a value along with its type.
 	TypedValue struct {
@@ -158,6 +199,7 @@ type (
 func DefaultSessionConfig() map[string]TypedValue {
 	return map[string]TypedValue{
 		"auto_increment_increment": TypedValue{Int64, int64(1)},
+		"autocommit":               TypedValue{Boolean, true},
 		"time_zone":                TypedValue{Text, time.Local.String()},
 		"system_time_zone":         TypedValue{Text, time.Local.String()},
 		"max_allowed_packet":       TypedValue{Int32, math.MaxInt32},
@@ -166,9 +208,77 @@ func DefaultSessionConfig() map[string]TypedValue {
 		"collation_database":       TypedValue{Text, "utf8_bin"},
 		"ndbinfo_version":          TypedValue{Text, ""},
 		"sql_select_limit":         TypedValue{Int32, math.MaxInt32},
+		"cte_max_recursion_depth":  TypedValue{Int64, int64(defaultCTEMaxRecursionDepth)},
+		"group_concat_max_len":     TypedValue{Int64, int64(defaultGroupConcatMaxLen)},
 	}
 }
 
+// StrictMode reports whether the sql_mode session variable of the given
+// session enables the strict mode, in which values that don't fit in the
+// type of a column are rejected instead of being truncated.
+func StrictMode(s Session) bool {
+	_, v := s.Get("sql_mode")
+	mode, ok := v.(string)
+	if !ok {
+		return false
+	}
+
+	for _, m := range strings.Split(strings.ToUpper(mode), ",") {
+		switch strings.TrimSpace(m) {
+		case "STRICT_TRANS_TABLES", "STRICT_ALL_TABLES", "TRADITIONAL":
+			return true
+		}
+	}
+
+	return false
+}
+
+// AutoIncrementIncrement returns the value of the auto_increment_increment
+// session variable of the given session, which is the difference between
+// consecutive values generated for AUTO_INCREMENT columns. Invalid values
+// are considered 1.
+func AutoIncrementIncrement(s Session) uint64 {
+	_, v := s.Get("auto_increment_increment")
+	n, err := Uint64.Convert(v)
+	if err != nil || n.(uint64) == 0 {
+		return 1
+	}
+
+	return n.(uint64)
+}
+
+const defaultCTEMaxRecursionDepth = 1000
+
+// CTEMaxRecursionDepth returns the value of the cte_max_recursion_depth
+// session variable of the given session, which is the maximum number of
+// iterations of a recursive common table expression. Invalid values are
+// considered the default one.
+func CTEMaxRecursionDepth(s Session) uint64 {
+	_, v := s.Get("cte_max_recursion_depth")
+	n, err := Uint64.Convert(v)
+	if err != nil {
+		return defaultCTEMaxRecursionDepth
+	}
+
+	return n.(uint64)
+}
+
+const defaultGroupConcatMaxLen = 1024
+
+// GroupConcatMaxLen returns the value of the group_concat_max_len session
+// variable of the given session, which is the maximum length in bytes of
+// the result of GROUP_CONCAT. Invalid values are considered the default
+// one.
+func GroupConcatMaxLen(s Session) uint64 {
+	_, v := s.Get("group_concat_max_len")
+	n, err := Uint64.Convert(v)
+	if err != nil {
+		return defaultGroupConcatMaxLen
+	}
+
+	return n.(uint64)
+}
+
 // HasDefaultValue checks if session variable value is the default one.
 func HasDefaultValue(s Session, key string) (bool, interface{}) {
 	typ, val := s.Get(key)
@@ -203,6 +313,9 @@ type Context struct {
 	pid    uint64
 	query  string
 	tracer opentracing.Tracer
+	// insertID is shared by all the contexts derived from this one, so
+	// the value set while running the query is seen by the caller.
+	insertID *uint64
 }
 
 // ContextOption is a function to configure the context.
@@ -244,7 +357,7 @@ func NewContext(
 	ctx context.Context,
 	opts ...ContextOption,
 ) *Context {
-	c := &Context{ctx, NewBaseSession(), 0, "", opentracing.NoopTracer{}}
+	c := &Context{ctx, NewBaseSession(), 0, "", opentracing.NoopTracer{}, new(uint64)}
 	for _, opt := range opts {
 		opt(c)
 	}
@@ -274,14 +387,22 @@ func (c *Context) Span(
 	span := c.tracer.StartSpan(opName, opts...)
 	ctx := opentracing.ContextWithSpan(c.Context, span)
 
-	return span, &Context{ctx, c.Session, c.Pid(), c.Query(), c.tracer}
+	return span, &Context{ctx, c.Session, c.Pid(), c.Query(), c.tracer, c.insertID}
 }
 
 // WithContext returns a new context with the given underlying context.
 func (c *Context) WithContext(ctx context.Context) *Context {
-	return &Context{ctx, c.Session, c.Pid(), c.Query(), c.tracer}
+	return &Context{ctx, c.Session, c.Pid(), c.Query(), c.tracer, c.insertID}
 }
 
+// InsertID returns the first value generated for an AUTO_INCREMENT column
+// by the statement of the context, or 0 if it generated none. Unlike
+// LastInsertID, it's not kept across statements.
+func (c *Context) InsertID() uint64 { return *c.insertID }
+
+// SetInsertID sets the value returned by InsertID.
+func (c *Context) SetInsertID(id uint64) { *c.insertID = id }
+
 // Error adds an error as warning to the session.
 func (c *Context) Error(code int, msg string, args ...interface{}) {
 	c.Session.Warn(&Warning{
@@ -132,3 +132,52 @@ func TestSessionIterator(t *testing.T) {
 
 	cancelFunc()
 }
+
+func TestAutocommit(t *testing.T) {
+	require := require.New(t)
+
+	sess := NewBaseSession()
+	require.True(Autocommit(sess))
+
+	sess.Set("autocommit", Int64, int64(0))
+	require.False(Autocommit(sess))
+
+	sess.Set("autocommit", Int64, int64(1))
+	require.True(Autocommit(sess))
+}
+
+func TestStrictMode(t *testing.T) {
+	require := require.New(t)
+
+	sess := NewBaseSession()
+	require.False(StrictMode(sess))
+
+	sess.Set("sql_mode", Text, "NO_ZERO_DATE,strict_trans_tables")
+	require.True(StrictMode(sess))
+
+	sess.Set("sql_mode", Text, "ANSI_QUOTES")
+	require.False(StrictMode(sess))
+}
+
+func TestAutoIncrementIncrement(t *testing.T) {
+	require := require.New(t)
+
+	sess := NewBaseSession()
+	require.Equal(uint64(1), AutoIncrementIncrement(sess))
+
+	sess.Set("auto_increment_increment", Int64, int64(5))
+	require.Equal(uint64(5), AutoIncrementIncrement(sess))
+
+	sess.Set("auto_increment_increment", Int64, int64(0))
+	require.Equal(uint64(1), AutoIncrementIncrement(sess))
+}
+
+func TestLastInsertID(t *testing.T) {
+	require := require.New(t)
+
+	sess := NewBaseSession()
+	require.Equal(uint64(0), sess.LastInsertID())
+
+	sess.SetLastInsertID(3)
+	require.Equal(uint64(3), sess.LastInsertID())
+}
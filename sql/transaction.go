@@ -0,0 +1,118 @@
+package sql
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Transaction is a transaction open in a session.
+type Transaction interface {
+	// Commit makes all the changes of the transaction permanent.
+	Commit(ctx *Context) error
+	// Rollback undoes all the changes of the transaction.
+	Rollback(ctx *Context) error
+}
+
+// TransactionalDatabase is a database that supports transactions.
+type TransactionalDatabase interface {
+	Database
+	// BeginTransaction starts a new transaction on the database.
+	BeginTransaction(ctx *Context) (Transaction, error)
+}
+
+// BeginTransaction starts a transaction on all the given databases that
+// support transactions. The returned transaction commits or rolls back all
+// of them at once.
+func BeginTransaction(ctx *Context, dbs Databases) (Transaction, error) {
+	var txs transactions
+	for _, db := range dbs {
+		tdb, ok := db.(TransactionalDatabase)
+		if !ok {
+			continue
+		}
+
+		tx, err := tdb.BeginTransaction(ctx)
+		if err != nil {
+			_ = txs.Rollback(ctx)
+			return nil, err
+		}
+
+		txs = append(txs, tx)
+	}
+
+	return txs, nil
+}
+
+// transactions is a group of transactions on different databases that are
+// committed or rolled back together.
+type transactions []Transaction
+
+func (t transactions) Commit(ctx *Context) error {
+	var errors []string
+	for _, tx := range t {
+		if err := tx.Commit(ctx); err != nil {
+			errors = append(errors, err.Error())
+		}
+	}
+
+	if len(errors) > 0 {
+		return fmt.Errorf("error committing transaction: %s", strings.Join(errors, ", "))
+	}
+
+	return nil
+}
+
+func (t transactions) Rollback(ctx *Context) error {
+	var errors []string
+	for _, tx := range t {
+		if err := tx.Rollback(ctx); err != nil {
+			errors = append(errors, err.Error())
+		}
+	}
+
+	if len(errors) > 0 {
+		return fmt.Errorf("error rolling back transaction: %s", strings.Join(errors, ", "))
+	}
+
+	return nil
+}
+
+// Autocommit reports whether the autocommit session variable is enabled in
+// the given session.
+func Autocommit(s Session) bool {
+	_, v := s.Get("autocommit")
+	if v == nil {
+		return true
+	}
+
+	autocommit, err := Boolean.Convert(v)
+	if err != nil {
+		return true
+	}
+
+	return autocommit.(bool)
+}
+
+// CommitTransaction commits the transaction open in the session of the
+// given context, if any.
+func CommitTransaction(ctx *Context) error {
+	tx := ctx.Transaction()
+	if tx == nil {
+		return nil
+	}
+
+	ctx.SetTransaction(nil)
+	return tx.Commit(ctx)
+}
+
+// RollbackTransaction rolls back the transaction open in the session of the
+// given context, if any.
+func RollbackTransaction(ctx *Context) error {
+	tx := ctx.Transaction()
+	if tx == nil {
+		return nil
+	}
+
+	ctx.SetTransaction(nil)
+	return tx.Rollback(ctx)
+}
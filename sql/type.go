@@ -4,10 +4,13 @@ import (
 	"bytes"
 	"encoding/json"
 	"fmt"
+	"math"
+	"math/big"
 	"reflect"
 	"strconv"
 	"strings"
 	"time"
+	"unicode/utf8"
 
 	"github.com/spf13/cast"
 	"gopkg.in/src-d/go-errors.v1"
@@ -41,6 +44,21 @@ var (
 	// ErrConvertToSQL is returned when Convert failed.
 	// It makes an error less verbose comparingto what spf13/cast returns.
 	ErrConvertToSQL = errors.NewKind("incompatible conversion to SQL type: %s")
+
+	// ErrOutOfRange is returned when a value does not fit in a type.
+	ErrOutOfRange = errors.NewKind("value %v is out of range for %s")
+
+	// ErrLengthTooLong is returned when a value is longer than the maximum
+	// length of a type.
+	ErrLengthTooLong = errors.NewKind("value of length %d is too long for %s")
+
+	// ErrInvalidTimeValue is returned when a value can't be converted to a
+	// TIME or YEAR.
+	ErrInvalidTimeValue = errors.NewKind("invalid %s value: %q")
+
+	// ErrInvalidEnumValue is returned when a value is not one of the values
+	// of an ENUM or SET.
+	ErrInvalidEnumValue = errors.NewKind("invalid value %v for %s")
 )
 
 // Schema is the definition of a table.
@@ -110,12 +128,49 @@ type Column struct {
 	// Type is the data type of the column.
 	Type Type
 	// Default contains the default value of the column or nil if it is NULL.
+	// It's CurrentTimestamp for columns whose default value is the time
+	// their rows are inserted.
 	Default interface{}
 	// Nullable is true if the column can contain NULL values, or false
 	// otherwise.
 	Nullable bool
 	// Source is the name of the table this column came from.
 	Source string
+	// PrimaryKey is true if the column is part of the primary key of its
+	// table.
+	PrimaryKey bool
+	// Unique is true if the column has a unique key, so it can't have the
+	// same value in two rows.
+	Unique bool
+	// UniqueKeys are the names of the unique keys of more than one column
+	// the column is part of.
+	UniqueKeys []string
+	// AutoIncrement is true if the column gets a generated value when a row
+	// is inserted with NULL or 0 in it.
+	AutoIncrement bool
+}
+
+// CurrentTimestamp is the default value of the columns defined with
+// DEFAULT CURRENT_TIMESTAMP.
+var CurrentTimestamp = currentTimestamp{}
+
+type currentTimestamp struct{}
+
+func (currentTimestamp) String() string { return "CURRENT_TIMESTAMP" }
+
+// DefaultValue returns the value the column gets when a row is inserted
+// without a value for it, which is its default value converted to the
+// column type, the current time if it's CurrentTimestamp or nil if it has
+// no default value.
+func (c *Column) DefaultValue() (interface{}, error) {
+	switch c.Default.(type) {
+	case nil:
+		return nil, nil
+	case currentTimestamp:
+		return c.Type.Convert(time.Now())
+	default:
+		return c.Type.Convert(c.Default)
+	}
 }
 
 // Check ensures the value is correct for this column.
@@ -128,6 +183,30 @@ func (c *Column) Check(v interface{}) bool {
 	return err == nil
 }
 
+// KeyName returns the kind of key of the column as shown in the Key column
+// of SHOW COLUMNS, that is, PRI for columns of the primary key, UNI for
+// unique columns or an empty string for the rest.
+func (c *Column) KeyName() string {
+	switch {
+	case c.PrimaryKey:
+		return "PRI"
+	case c.Unique:
+		return "UNI"
+	default:
+		return ""
+	}
+}
+
+// Extra returns the additional information about the column shown in the
+// Extra column of SHOW COLUMNS, which is auto_increment for AUTO_INCREMENT
+// columns or an empty string for the rest.
+func (c *Column) Extra() string {
+	if c.AutoIncrement {
+		return "auto_increment"
+	}
+	return ""
+}
+
 // Equals checks whether two columns are equal.
 func (c *Column) Equals(c2 *Column) bool {
 	return c.Name == c2.Name &&
@@ -157,6 +236,18 @@ var (
 
 	// Numeric types
 
+	// Int8 is an integer of 8 bits.
+	Int8 = numberT{t: sqltypes.Int8}
+	// Uint8 is an unsigned integer of 8 bits.
+	Uint8 = numberT{t: sqltypes.Uint8}
+	// Int16 is an integer of 16 bits.
+	Int16 = numberT{t: sqltypes.Int16}
+	// Uint16 is an unsigned integer of 16 bits.
+	Uint16 = numberT{t: sqltypes.Uint16}
+	// Int24 is an integer of 24 bits. Its values are int32.
+	Int24 = numberT{t: sqltypes.Int24}
+	// Uint24 is an unsigned integer of 24 bits. Its values are uint32.
+	Uint24 = numberT{t: sqltypes.Uint24}
 	// Int32 is an integer of 32 bits.
 	Int32 = numberT{t: sqltypes.Int32}
 	// Int64 is an integer of 64 bytes.
@@ -174,6 +265,11 @@ var (
 	Timestamp timestampT
 	// Date is a date with day, month and year.
 	Date dateT
+	// Time is a time of day or an elapsed time, which can be negative or
+	// greater than 24 hours. Its values are time.Duration.
+	Time timeT
+	// Year is a year in the range 1901 to 2155 or zero. Its values are int16.
+	Year yearT
 	// Text is a string type.
 	Text textT
 	// Boolean is a boolean type.
@@ -184,6 +280,82 @@ var (
 	Blob blobT
 )
 
+const (
+	// DecimalMaxPrecision is the maximum number of digits of a decimal.
+	DecimalMaxPrecision = 65
+	// DecimalMaxScale is the maximum number of digits after the decimal
+	// point of a decimal.
+	DecimalMaxScale = 30
+)
+
+// Decimal returns a new fixed-point number type with the given precision,
+// which is the number of significant digits, and scale, which is the number
+// of digits after the decimal point. Values of this type are strings with
+// exactly scale digits after the decimal point, so they're never rounded
+// like floating point numbers are.
+func Decimal(precision, scale int) Type {
+	return decimalT{precision: precision, scale: scale}
+}
+
+// DatetimeMaxPrecision is the maximum number of digits of the fractional
+// seconds of a DATETIME.
+const DatetimeMaxPrecision = 6
+
+// Datetime returns a new date and time type whose values have the given
+// number of digits of fractional seconds. Values with more digits are
+// rounded, as in MySQL.
+func Datetime(fsp int) Type {
+	return datetimeT{fsp: fsp}
+}
+
+const (
+	// CharMaxLength is the maximum length of a CHAR or BINARY.
+	CharMaxLength = 255
+	// VarCharMaxLength is the maximum length of a VARCHAR or VARBINARY.
+	VarCharMaxLength = 65535
+)
+
+// Char returns a new fixed-length string type with the given length, which
+// is the maximum number of characters of its values.
+func Char(length int) Type {
+	return stringT{t: sqltypes.Char, length: length}
+}
+
+// VarChar returns a new variable-length string type with the given maximum
+// number of characters.
+func VarChar(length int) Type {
+	return stringT{t: sqltypes.VarChar, length: length}
+}
+
+// Binary returns a new fixed-length binary type with the given length in
+// bytes. Shorter values are padded with zero bytes, as in MySQL.
+func Binary(length int) Type {
+	return stringT{t: sqltypes.Binary, length: length}
+}
+
+// VarBinary returns a new variable-length binary type with the given
+// maximum length in bytes.
+func VarBinary(length int) Type {
+	return stringT{t: sqltypes.VarBinary, length: length}
+}
+
+// SetMaxValues is the maximum number of values of a SET.
+const SetMaxValues = 64
+
+// Enum returns a new enumeration type with the given values. Values of this
+// type are uint16 with the ordinal of the value, starting at 1, and they're
+// compared by ordinal, as in MySQL.
+func Enum(values ...string) Type {
+	return enumT{values: joinEnumValues(values)}
+}
+
+// Set returns a new set type with the given values. Values of this type
+// are uint64 bitmasks with a bit set for each of the values in the set, in
+// the order they are given.
+func Set(values ...string) Type {
+	return setT{values: joinEnumValues(values)}
+}
+
 // Tuple returns a new tuple type with the given element types.
 func Tuple(types ...Type) Type {
 	return tupleT(types)
@@ -199,6 +371,18 @@ func MysqlTypeToType(sql query.Type) (Type, error) {
 	switch sql {
 	case sqltypes.Null:
 		return Null, nil
+	case sqltypes.Int8:
+		return Int8, nil
+	case sqltypes.Uint8:
+		return Uint8, nil
+	case sqltypes.Int16:
+		return Int16, nil
+	case sqltypes.Uint16:
+		return Uint16, nil
+	case sqltypes.Int24:
+		return Int24, nil
+	case sqltypes.Uint24:
+		return Uint24, nil
 	case sqltypes.Int32:
 		return Int32, nil
 	case sqltypes.Int64:
@@ -211,12 +395,31 @@ func MysqlTypeToType(sql query.Type) (Type, error) {
 		return Float32, nil
 	case sqltypes.Float64:
 		return Float64, nil
+	case sqltypes.Decimal:
+		// The precision and scale are not part of the mysql type, so the
+		// MySQL defaults are used.
+		return Decimal(10, 0), nil
 	case sqltypes.Timestamp:
 		return Timestamp, nil
 	case sqltypes.Date:
 		return Date, nil
-	case sqltypes.Text, sqltypes.VarChar:
+	case sqltypes.Datetime:
+		return Datetime(0), nil
+	case sqltypes.Time:
+		return Time, nil
+	case sqltypes.Year:
+		return Year, nil
+	case sqltypes.Text:
 		return Text, nil
+	// The length is not part of the mysql type, so the maximum one is used.
+	case sqltypes.Char:
+		return Char(CharMaxLength), nil
+	case sqltypes.VarChar:
+		return VarChar(VarCharMaxLength), nil
+	case sqltypes.Binary:
+		return Binary(CharMaxLength), nil
+	case sqltypes.VarBinary:
+		return VarBinary(VarCharMaxLength), nil
 	case sqltypes.Bit:
 		return Boolean, nil
 	case sqltypes.TypeJSON:
@@ -278,6 +481,10 @@ func (t numberT) SQL(v interface{}) sqltypes.Value {
 	}
 
 	switch t.t {
+	case sqltypes.Int8, sqltypes.Int16, sqltypes.Int24:
+		return sqltypes.MakeTrusted(t.t, strconv.AppendInt(nil, cast.ToInt64(v), 10))
+	case sqltypes.Uint8, sqltypes.Uint16, sqltypes.Uint24:
+		return sqltypes.MakeTrusted(t.t, strconv.AppendUint(nil, cast.ToUint64(v), 10))
 	case sqltypes.Int32:
 		return sqltypes.MakeTrusted(t.t, strconv.AppendInt(nil, cast.ToInt64(v), 10))
 	case sqltypes.Int64:
@@ -298,6 +505,24 @@ func (t numberT) SQL(v interface{}) sqltypes.Value {
 // Convert implements Type interface.
 func (t numberT) Convert(v interface{}) (interface{}, error) {
 	switch t.t {
+	case sqltypes.Int8:
+		n, err := t.convertSigned(v, math.MinInt8, math.MaxInt8)
+		return int8(n), err
+	case sqltypes.Uint8:
+		n, err := t.convertUnsigned(v, math.MaxUint8)
+		return uint8(n), err
+	case sqltypes.Int16:
+		n, err := t.convertSigned(v, math.MinInt16, math.MaxInt16)
+		return int16(n), err
+	case sqltypes.Uint16:
+		n, err := t.convertUnsigned(v, math.MaxUint16)
+		return uint16(n), err
+	case sqltypes.Int24:
+		n, err := t.convertSigned(v, minInt24, maxInt24)
+		return int32(n), err
+	case sqltypes.Uint24:
+		n, err := t.convertUnsigned(v, maxUint24)
+		return uint32(n), err
 	case sqltypes.Int32:
 		return cast.ToInt32E(v)
 	case sqltypes.Int64:
@@ -326,6 +551,45 @@ func (t numberT) Compare(a interface{}, b interface{}) (int, error) {
 
 func (t numberT) String() string { return t.t.String() }
 
+const (
+	minInt24  = -1 << 23
+	maxInt24  = 1<<23 - 1
+	maxUint24 = 1<<24 - 1
+)
+
+// convertSigned converts the value to an int64, returning an error if it's
+// not between min and max.
+func (t numberT) convertSigned(v interface{}, min, max int64) (int64, error) {
+	n, err := cast.ToInt64E(v)
+	if err != nil {
+		return 0, err
+	}
+
+	if n < min || n > max {
+		return 0, ErrOutOfRange.New(v, t)
+	}
+
+	return n, nil
+}
+
+// convertUnsigned converts the value to an uint64, returning an error if
+// it's greater than max.
+func (t numberT) convertUnsigned(v interface{}, max uint64) (uint64, error) {
+	n, err := cast.ToUint64E(v)
+	if err != nil {
+		if n, err := cast.ToInt64E(v); err == nil && n < 0 {
+			return 0, ErrOutOfRange.New(v, t)
+		}
+		return 0, err
+	}
+
+	if n > max {
+		return 0, ErrOutOfRange.New(v, t)
+	}
+
+	return n, nil
+}
+
 func compareSigned(a interface{}, b interface{}) (int, error) {
 	ca, err := cast.ToInt64E(a)
 	if err != nil {
@@ -368,6 +632,159 @@ func compareUnsigned(a interface{}, b interface{}) (int, error) {
 	return +1, nil
 }
 
+type decimalT struct {
+	precision int
+	scale     int
+}
+
+func (t decimalT) String() string {
+	return fmt.Sprintf("DECIMAL(%d,%d)", t.precision, t.scale)
+}
+
+// Type implements Type interface.
+func (t decimalT) Type() query.Type {
+	return sqltypes.Decimal
+}
+
+// SQL implements Type interface.
+func (t decimalT) SQL(v interface{}) sqltypes.Value {
+	if _, ok := v.(nullT); ok {
+		return sqltypes.NULL
+	}
+
+	return sqltypes.MakeTrusted(sqltypes.Decimal, []byte(MustConvert(t, v).(string)))
+}
+
+// Convert implements Type interface. Values are rounded half away from zero
+// to the scale of the type.
+func (t decimalT) Convert(v interface{}) (interface{}, error) {
+	r, err := ParseDecimal(v)
+	if err != nil {
+		return nil, ErrConvertToSQL.New(t)
+	}
+
+	// Scale the number so it's an integer, rounding the remainder.
+	exp := new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(t.scale)), nil)
+	num := new(big.Int).Mul(r.Num(), exp)
+	n, rem := new(big.Int).QuoRem(num, r.Denom(), new(big.Int))
+	if rem.Sign() != 0 && new(big.Int).Mul(rem.Abs(rem), big.NewInt(2)).Cmp(r.Denom()) >= 0 {
+		n.Add(n, big.NewInt(int64(r.Sign())))
+	}
+
+	digits := n.String()
+	var sign string
+	if n.Sign() < 0 {
+		sign, digits = "-", digits[1:]
+	}
+
+	if len(digits) > t.precision && n.Sign() != 0 {
+		return nil, ErrOutOfRange.New(v, t)
+	}
+
+	if t.scale == 0 {
+		return sign + digits, nil
+	}
+
+	if len(digits) <= t.scale {
+		digits = strings.Repeat("0", t.scale-len(digits)+1) + digits
+	}
+
+	point := len(digits) - t.scale
+	return sign + digits[:point] + "." + digits[point:], nil
+}
+
+// Compare implements Type interface.
+func (t decimalT) Compare(a interface{}, b interface{}) (int, error) {
+	ra, err := ParseDecimal(a)
+	if err != nil {
+		return 0, err
+	}
+
+	rb, err := ParseDecimal(b)
+	if err != nil {
+		return 0, err
+	}
+
+	return ra.Cmp(rb), nil
+}
+
+// ParseDecimal returns the exact value of the given number, which may be an
+// integer, a float, a *big.Rat or a string with a decimal number. Decimal
+// values are strings, so this is the way to operate with them without losing
+// precision.
+func ParseDecimal(v interface{}) (*big.Rat, error) {
+	switch v := v.(type) {
+	case nil:
+		return new(big.Rat), nil
+	case *big.Rat:
+		return v, nil
+	case string:
+		r, ok := new(big.Rat).SetString(strings.TrimSpace(v))
+		if !ok {
+			return nil, ErrInvalidType.New(v)
+		}
+		return r, nil
+	case []byte:
+		return ParseDecimal(string(v))
+	case float32:
+		return ParseDecimal(float64(v))
+	case float64:
+		r := new(big.Rat)
+		// Use the shortest representation of the float, otherwise values
+		// such as 0.1 would be converted to their exact binary value.
+		if _, ok := r.SetString(strconv.FormatFloat(v, 'g', -1, 64)); !ok {
+			return nil, ErrInvalidType.New(v)
+		}
+		return r, nil
+	case bool:
+		if v {
+			return big.NewRat(1, 1), nil
+		}
+		return new(big.Rat), nil
+	case uint, uint8, uint16, uint32, uint64:
+		n, err := cast.ToUint64E(v)
+		if err != nil {
+			return nil, err
+		}
+		return new(big.Rat).SetInt(new(big.Int).SetUint64(n)), nil
+	default:
+		n, err := cast.ToInt64E(v)
+		if err != nil {
+			return nil, ErrInvalidType.New(reflect.TypeOf(v))
+		}
+		return new(big.Rat).SetInt64(n), nil
+	}
+}
+
+// NumericPrecision returns the precision and scale of the given exact number
+// type, that is, a decimal or an integer type. The precision of an integer
+// type is the maximum number of digits of its values and its scale is 0. The
+// last value is false if the type is not an exact number.
+func NumericPrecision(t Type) (precision, scale int, ok bool) {
+	switch t {
+	case Int8, Uint8:
+		return 3, 0, true
+	case Int16, Uint16:
+		return 5, 0, true
+	case Int24:
+		return 7, 0, true
+	case Uint24:
+		return 8, 0, true
+	case Int32, Uint32:
+		return 10, 0, true
+	case Int64:
+		return 19, 0, true
+	case Uint64:
+		return 20, 0, true
+	}
+
+	if d, ok := t.(decimalT); ok {
+		return d.precision, d.scale, true
+	}
+
+	return 0, 0, false
+}
+
 type timestampT struct{}
 
 func (t timestampT) String() string { return "TIMESTAMP" }
@@ -509,10 +926,341 @@ func (t dateT) Compare(a, b interface{}) (int, error) {
 	return 0, nil
 }
 
-type textT struct{}
+type datetimeT struct {
+	fsp int
+}
+
+func (t datetimeT) String() string {
+	if t.fsp == 0 {
+		return "DATETIME"
+	}
+	return fmt.Sprintf("DATETIME(%d)", t.fsp)
+}
+
+// Type implements Type interface.
+func (t datetimeT) Type() query.Type {
+	return sqltypes.Datetime
+}
+
+// layout returns the layout of the values of the type, which have as many
+// digits of fractional seconds as its precision.
+func (t datetimeT) layout() string {
+	if t.fsp == 0 {
+		return TimestampLayout
+	}
+	return TimestampLayout + "." + strings.Repeat("0", t.fsp)
+}
+
+// SQL implements Type interface.
+func (t datetimeT) SQL(v interface{}) sqltypes.Value {
+	if _, ok := v.(nullT); ok {
+		return sqltypes.NULL
+	}
+
+	time := MustConvert(t, v).(time.Time)
+	return sqltypes.MakeTrusted(
+		sqltypes.Datetime,
+		[]byte(time.Format(t.layout())),
+	)
+}
+
+// Convert implements Type interface.
+func (t datetimeT) Convert(v interface{}) (interface{}, error) {
+	ts, err := Timestamp.Convert(v)
+	if err != nil {
+		return nil, err
+	}
+
+	return ts.(time.Time).Round(fractionUnit(t.fsp)), nil
+}
+
+// Compare implements Type interface.
+func (t datetimeT) Compare(a interface{}, b interface{}) (int, error) {
+	return Timestamp.Compare(a, b)
+}
+
+// fractionUnit returns the smallest duration that can be represented with
+// the given number of digits of fractional seconds.
+func fractionUnit(fsp int) time.Duration {
+	unit := time.Second
+	for i := 0; i < fsp; i++ {
+		unit /= 10
+	}
+	return unit
+}
+
+// maxTime is the maximum value of a TIME, which is also the opposite of
+// the minimum one.
+const maxTime = 838*time.Hour + 59*time.Minute + 59*time.Second
+
+type timeT struct{}
+
+func (t timeT) String() string { return "TIME" }
+
+// Type implements Type interface.
+func (t timeT) Type() query.Type {
+	return sqltypes.Time
+}
+
+// SQL implements Type interface.
+func (t timeT) SQL(v interface{}) sqltypes.Value {
+	if _, ok := v.(nullT); ok {
+		return sqltypes.NULL
+	}
+
+	d := MustConvert(t, v).(time.Duration)
+	var sign string
+	if d < 0 {
+		sign = "-"
+		d = -d
+	}
+
+	return sqltypes.MakeTrusted(
+		sqltypes.Time,
+		[]byte(fmt.Sprintf(
+			"%s%02d:%02d:%02d",
+			sign,
+			d/time.Hour,
+			d%time.Hour/time.Minute,
+			d%time.Minute/time.Second,
+		)),
+	)
+}
+
+// Convert implements Type interface. Strings can be in any of the formats
+// MySQL accepts, such as "[-][D ]HH:MM:SS[.fraction]" or "HHMMSS", and
+// numbers are in the HHMMSS format. Fractional seconds are rounded.
+func (t timeT) Convert(v interface{}) (interface{}, error) {
+	d, err := t.duration(v)
+	if err != nil {
+		return nil, err
+	}
+
+	if d > maxTime || d < -maxTime {
+		return nil, ErrOutOfRange.New(v, t)
+	}
+
+	return d, nil
+}
+
+// duration converts the given value to a duration without checking it's in
+// the range of the type.
+func (t timeT) duration(v interface{}) (time.Duration, error) {
+	var d time.Duration
+	switch value := v.(type) {
+	case time.Duration:
+		d = value
+	case time.Time:
+		h, m, s := value.Clock()
+		d = time.Duration(h)*time.Hour +
+			time.Duration(m)*time.Minute +
+			time.Duration(s)*time.Second +
+			time.Duration(value.Nanosecond())
+	case string:
+		var err error
+		if d, err = parseTime(value); err != nil {
+			return 0, err
+		}
+	case []byte:
+		var err error
+		if d, err = parseTime(string(value)); err != nil {
+			return 0, err
+		}
+	default:
+		n, err := Int64.Convert(v)
+		if err != nil {
+			return 0, ErrInvalidType.New(reflect.TypeOf(v))
+		}
+
+		s := strconv.FormatInt(n.(int64), 10)
+		if d, err = parseTime(s); err != nil {
+			return 0, err
+		}
+	}
+
+	return d.Round(time.Second), nil
+}
+
+// parseTime parses a TIME in one of the formats MySQL accepts.
+func parseTime(s string) (time.Duration, error) {
+	str := strings.TrimSpace(s)
+	var negative bool
+	if strings.HasPrefix(str, "-") {
+		negative = true
+		str = str[1:]
+	}
+
+	var d time.Duration
+	if i := strings.IndexByte(str, '.'); i >= 0 {
+		frac := str[i+1:]
+		if len(frac) > 9 {
+			frac = frac[:9]
+		}
+
+		n, err := strconv.ParseUint(frac+strings.Repeat("0", 9-len(frac)), 10, 64)
+		if err != nil {
+			return 0, ErrInvalidTimeValue.New("TIME", s)
+		}
+
+		d = time.Duration(n)
+		str = str[:i]
+	}
+
+	var parts []string
+	if i := strings.IndexByte(str, ' '); i >= 0 {
+		// Days are followed by hours and, optionally, minutes and seconds.
+		days, err := strconv.ParseUint(str[:i], 10, 32)
+		if err != nil {
+			return 0, ErrInvalidTimeValue.New("TIME", s)
+		}
+
+		parts = strings.Split(strings.TrimSpace(str[i+1:]), ":")
+		d += time.Duration(days) * 24 * time.Hour
+	} else if strings.Contains(str, ":") {
+		parts = strings.Split(str, ":")
+	} else if len(str) > 0 {
+		// Without separators, the value is HHMMSS, where the hours and
+		// minutes can be omitted.
+		for len(str) > 2 {
+			parts = append([]string{str[len(str)-2:]}, parts...)
+			str = str[:len(str)-2]
+		}
+		parts = append([]string{str}, parts...)
+		parts = append(make([]string, 3-len(parts)), parts...)
+	}
+
+	if len(parts) == 0 || len(parts) > 3 {
+		return 0, ErrInvalidTimeValue.New("TIME", s)
+	}
+
+	units := []time.Duration{time.Hour, time.Minute, time.Second}
+	for i, p := range parts {
+		if p == "" {
+			continue
+		}
+
+		n, err := strconv.ParseUint(p, 10, 32)
+		if err != nil || (i > 0 && n > 59) {
+			return 0, ErrInvalidTimeValue.New("TIME", s)
+		}
+
+		d += time.Duration(n) * units[i]
+	}
+
+	if negative {
+		d = -d
+	}
+
+	return d, nil
+}
+
+// Compare implements Type interface.
+func (t timeT) Compare(a interface{}, b interface{}) (int, error) {
+	av := a.(time.Duration)
+	bv := b.(time.Duration)
+	if av < bv {
+		return -1, nil
+	} else if av > bv {
+		return 1, nil
+	}
+	return 0, nil
+}
+
+const (
+	minYear = 1901
+	maxYear = 2155
+)
+
+type yearT struct{}
+
+func (t yearT) String() string { return "YEAR" }
+
+// Type implements Type interface.
+func (t yearT) Type() query.Type {
+	return sqltypes.Year
+}
+
+// SQL implements Type interface.
+func (t yearT) SQL(v interface{}) sqltypes.Value {
+	if _, ok := v.(nullT); ok {
+		return sqltypes.NULL
+	}
+
+	return sqltypes.MakeTrusted(
+		sqltypes.Year,
+		[]byte(fmt.Sprintf("%04d", MustConvert(t, v).(int16))),
+	)
+}
+
+// Convert implements Type interface. As in MySQL, years with one or two
+// digits are in the range 1970 to 2069, except for the number zero, which
+// is kept as zero, unlike the strings "0" and "00", which are 2000.
+func (t yearT) Convert(v interface{}) (interface{}, error) {
+	var year int64
+	switch value := v.(type) {
+	case time.Time:
+		year = int64(value.Year())
+	case string, []byte:
+		s := strings.TrimSpace(MustConvert(Text, value).(string))
+		n, err := strconv.ParseInt(s, 10, 64)
+		if err != nil {
+			return nil, ErrInvalidTimeValue.New("YEAR", s)
+		}
+
+		year = n
+		if len(s) <= 2 && n == 0 {
+			year = 2000
+		}
+	default:
+		n, err := Int64.Convert(v)
+		if err != nil {
+			return nil, ErrInvalidType.New(reflect.TypeOf(v))
+		}
+		year = n.(int64)
+	}
+
+	switch {
+	case year == 0:
+		return int16(0), nil
+	case year > 0 && year < 70:
+		year += 2000
+	case year >= 70 && year < 100:
+		year += 1900
+	}
+
+	if year < minYear || year > maxYear {
+		return nil, ErrOutOfRange.New(v, t)
+	}
+
+	return int16(year), nil
+}
+
+// Compare implements Type interface.
+func (t yearT) Compare(a interface{}, b interface{}) (int, error) {
+	av := a.(int16)
+	bv := b.(int16)
+	if av < bv {
+		return -1, nil
+	} else if av > bv {
+		return 1, nil
+	}
+	return 0, nil
+}
+
+type textT struct {
+	collation Collation
+}
 
 func (t textT) String() string { return "TEXT" }
 
+// Collation returns the collation used to compare the values of the type.
+func (t textT) Collation() Collation {
+	if t.collation == (Collation{}) {
+		return DefaultCollation
+	}
+	return t.collation
+}
+
 // Type implements Type interface.
 func (t textT) Type() query.Type {
 	return sqltypes.Text
@@ -538,7 +1286,560 @@ func (t textT) Convert(v interface{}) (interface{}, error) {
 
 // Compare implements Type interface.
 func (t textT) Compare(a interface{}, b interface{}) (int, error) {
-	return strings.Compare(a.(string), b.(string)), nil
+	return t.Collation().Compare(a.(string), b.(string)), nil
+}
+
+type stringT struct {
+	t         query.Type
+	length    int
+	collation Collation
+}
+
+// Collation returns the collation used to compare the values of the type.
+func (t stringT) Collation() Collation {
+	if t.isBinary() {
+		return CollationBinary
+	}
+
+	if t.collation == (Collation{}) {
+		return DefaultCollation
+	}
+	return t.collation
+}
+
+func (t stringT) String() string {
+	var name string
+	switch t.t {
+	case sqltypes.Char:
+		name = "CHAR"
+	case sqltypes.VarChar:
+		name = "VARCHAR"
+	case sqltypes.Binary:
+		name = "BINARY"
+	default:
+		name = "VARBINARY"
+	}
+
+	return fmt.Sprintf("%s(%d)", name, t.length)
+}
+
+// Type implements Type interface.
+func (t stringT) Type() query.Type {
+	return t.t
+}
+
+func (t stringT) isBinary() bool {
+	return t.t == sqltypes.Binary || t.t == sqltypes.VarBinary
+}
+
+// SQL implements Type interface.
+func (t stringT) SQL(v interface{}) sqltypes.Value {
+	if _, ok := v.(nullT); ok {
+		return sqltypes.NULL
+	}
+
+	v = MustConvert(t, v)
+	if b, ok := v.([]byte); ok {
+		return sqltypes.MakeTrusted(t.t, b)
+	}
+
+	return sqltypes.MakeTrusted(t.t, []byte(v.(string)))
+}
+
+// Convert implements Type interface. The values of binary types are byte
+// slices and the ones of the rest are strings. An error is returned if the
+// value is longer than the length of the type.
+func (t stringT) Convert(v interface{}) (interface{}, error) {
+	if t.isBinary() {
+		val, err := Blob.Convert(v)
+		if err != nil {
+			return nil, err
+		}
+
+		b := val.([]byte)
+		if len(b) > t.length {
+			return nil, ErrLengthTooLong.New(len(b), t)
+		}
+
+		if t.t == sqltypes.Binary && len(b) < t.length {
+			b = append(append(make([]byte, 0, t.length), b...), make([]byte, t.length-len(b))...)
+		}
+
+		return b, nil
+	}
+
+	val, err := Text.Convert(v)
+	if err != nil {
+		return nil, err
+	}
+
+	if n := utf8.RuneCountInString(val.(string)); n > t.length {
+		return nil, ErrLengthTooLong.New(n, t)
+	}
+
+	return val, nil
+}
+
+// Compare implements Type interface.
+func (t stringT) Compare(a interface{}, b interface{}) (int, error) {
+	if t.isBinary() {
+		return Blob.Compare(a, b)
+	}
+
+	return t.Collation().Compare(a.(string), b.(string)), nil
+}
+
+// enumSeparator separates the values of an ENUM or SET in the string they
+// are kept in, so the types can be compared with ==.
+const enumSeparator = "\x00"
+
+func joinEnumValues(values []string) string {
+	if len(values) == 0 {
+		return ""
+	}
+
+	trimmed := make([]string, len(values))
+	for i, v := range values {
+		// As in MySQL, trailing spaces are removed from the values.
+		trimmed[i] = strings.TrimRight(v, " ")
+	}
+
+	return strings.Join(trimmed, enumSeparator) + enumSeparator
+}
+
+func splitEnumValues(values string) []string {
+	if values == "" {
+		return nil
+	}
+	return strings.Split(strings.TrimSuffix(values, enumSeparator), enumSeparator)
+}
+
+// quoteEnumValues returns the given values quoted and separated by commas,
+// as they're written in the definition of an ENUM or SET.
+func quoteEnumValues(values []string) string {
+	quoted := make([]string, len(values))
+	for i, v := range values {
+		quoted[i] = "'" + strings.Replace(v, "'", "''", -1) + "'"
+	}
+	return strings.Join(quoted, ",")
+}
+
+type enumT struct {
+	values string
+}
+
+func (t enumT) String() string {
+	return fmt.Sprintf("ENUM(%s)", quoteEnumValues(t.members()))
+}
+
+// Type implements Type interface.
+func (t enumT) Type() query.Type {
+	return sqltypes.Enum
+}
+
+// members returns the values of the enum.
+func (t enumT) members() []string {
+	return splitEnumValues(t.values)
+}
+
+// SQL implements Type interface.
+func (t enumT) SQL(v interface{}) sqltypes.Value {
+	if _, ok := v.(nullT); ok {
+		return sqltypes.NULL
+	}
+
+	var s string
+	if n := MustConvert(t, v).(uint16); n > 0 {
+		s = t.members()[n-1]
+	}
+
+	return sqltypes.MakeTrusted(sqltypes.Enum, []byte(s))
+}
+
+// Convert implements Type interface. Strings are matched against the
+// values of the enum ignoring case, and numbers are ordinals, where 0 is
+// the empty string MySQL uses for invalid values.
+func (t enumT) Convert(v interface{}) (interface{}, error) {
+	values := t.members()
+	switch value := v.(type) {
+	case string, []byte:
+		s := strings.TrimRight(MustConvert(Text, value).(string), " ")
+		for i, e := range values {
+			if strings.EqualFold(e, s) {
+				return uint16(i + 1), nil
+			}
+		}
+
+		// Strings with numbers that are not values of the enum are
+		// ordinals, as in MySQL.
+		n, err := strconv.ParseUint(s, 10, 16)
+		if err != nil || n > uint64(len(values)) {
+			return nil, ErrInvalidEnumValue.New(v, t)
+		}
+
+		return uint16(n), nil
+	default:
+		n, err := Uint64.Convert(v)
+		if err != nil {
+			return nil, ErrInvalidType.New(reflect.TypeOf(v))
+		}
+
+		if n.(uint64) > uint64(len(values)) {
+			return nil, ErrInvalidEnumValue.New(v, t)
+		}
+
+		return uint16(n.(uint64)), nil
+	}
+}
+
+// Compare implements Type interface. Values are compared by their
+// ordinals, not alphabetically.
+func (t enumT) Compare(a interface{}, b interface{}) (int, error) {
+	av, err := t.Convert(a)
+	if err != nil {
+		return 0, err
+	}
+
+	bv, err := t.Convert(b)
+	if err != nil {
+		return 0, err
+	}
+
+	return compareUints(uint64(av.(uint16)), uint64(bv.(uint16))), nil
+}
+
+type setT struct {
+	values string
+}
+
+func (t setT) String() string {
+	return fmt.Sprintf("SET(%s)", quoteEnumValues(t.members()))
+}
+
+// Type implements Type interface.
+func (t setT) Type() query.Type {
+	return sqltypes.Set
+}
+
+// members returns the values of the set.
+func (t setT) members() []string {
+	return splitEnumValues(t.values)
+}
+
+// SQL implements Type interface.
+func (t setT) SQL(v interface{}) sqltypes.Value {
+	if _, ok := v.(nullT); ok {
+		return sqltypes.NULL
+	}
+
+	bits := MustConvert(t, v).(uint64)
+	var members []string
+	for i, value := range t.members() {
+		if bits&(1<<uint(i)) != 0 {
+			members = append(members, value)
+		}
+	}
+
+	return sqltypes.MakeTrusted(sqltypes.Set, []byte(strings.Join(members, ",")))
+}
+
+// Convert implements Type interface. Strings are lists of values separated
+// by commas, which are matched against the values of the set ignoring
+// case, and numbers are bitmasks where the bit i is set if the value i is
+// in the set.
+func (t setT) Convert(v interface{}) (interface{}, error) {
+	switch value := v.(type) {
+	case string, []byte:
+		s := MustConvert(Text, value).(string)
+		bits, ok := t.bitmask(s)
+		if ok {
+			return bits, nil
+		}
+
+		// Strings with numbers are bitmasks, as in MySQL.
+		n, err := strconv.ParseUint(strings.TrimSpace(s), 10, 64)
+		if err != nil || !t.validBitmask(n) {
+			return nil, ErrInvalidEnumValue.New(v, t)
+		}
+
+		return n, nil
+	default:
+		n, err := Uint64.Convert(v)
+		if err != nil {
+			return nil, ErrInvalidType.New(reflect.TypeOf(v))
+		}
+
+		if !t.validBitmask(n.(uint64)) {
+			return nil, ErrInvalidEnumValue.New(v, t)
+		}
+
+		return n, nil
+	}
+}
+
+// bitmask returns the bitmask of the values of the set in the given list of
+// values separated by commas. The second value reports whether all of them
+// are values of the set.
+func (t setT) bitmask(s string) (uint64, bool) {
+	if s == "" {
+		return 0, true
+	}
+
+	values := t.members()
+	var bits uint64
+	var ok = true
+	for _, member := range strings.Split(s, ",") {
+		member = strings.TrimRight(member, " ")
+		found := false
+		for i, value := range values {
+			if strings.EqualFold(value, member) {
+				bits |= 1 << uint(i)
+				found = true
+				break
+			}
+		}
+
+		ok = ok && found
+	}
+
+	return bits, ok
+}
+
+func (t setT) validBitmask(n uint64) bool {
+	size := len(t.members())
+	return size >= 64 || n < 1<<uint(size)
+}
+
+// Compare implements Type interface. Values are compared by their
+// bitmasks.
+func (t setT) Compare(a interface{}, b interface{}) (int, error) {
+	av, err := t.Convert(a)
+	if err != nil {
+		return 0, err
+	}
+
+	bv, err := t.Convert(b)
+	if err != nil {
+		return 0, err
+	}
+
+	return compareUints(av.(uint64), bv.(uint64)), nil
+}
+
+func compareUints(a, b uint64) int {
+	if a < b {
+		return -1
+	} else if a > b {
+		return 1
+	}
+	return 0
+}
+
+// DatetimePrecision returns the number of digits of fractional seconds of
+// the given type, which is zero for all types but those created with
+// Datetime.
+func DatetimePrecision(t Type) int {
+	if d, ok := t.(datetimeT); ok {
+		return d.fsp
+	}
+	return 0
+}
+
+// Truncate converts the given value to the given type like Convert does,
+// but values that are too long for the type are truncated, values out of
+// the range of an integer or TIME type are set to the closest value in the
+// range, years out of range are set to zero, values not in an ENUM are set
+// to the empty string and values not in a SET are removed, instead of
+// returning an error. The second value reports whether the value was
+// modified.
+func Truncate(t Type, v interface{}) (interface{}, bool, error) {
+	c, err := t.Convert(v)
+	if err == nil {
+		return c, false, nil
+	}
+
+	switch typ := t.(type) {
+	case stringT:
+		if !ErrLengthTooLong.Is(err) {
+			return nil, false, err
+		}
+
+		if typ.isBinary() {
+			b := MustConvert(Blob, v).([]byte)
+			return b[:typ.length], true, nil
+		}
+
+		s := MustConvert(Text, v).(string)
+		var n int
+		for i := range s {
+			if n == typ.length {
+				return s[:i], true, nil
+			}
+			n++
+		}
+
+		return s, true, nil
+	case numberT:
+		if !ErrOutOfRange.Is(err) {
+			return nil, false, err
+		}
+
+		n, _ := cast.ToInt64E(v)
+		switch {
+		case IsUnsigned(typ) && n < 0:
+			return MustConvert(typ, 0), true, nil
+		case IsUnsigned(typ):
+			return MustConvert(typ, maxUnsigned(typ)), true, nil
+		case n < 0:
+			return MustConvert(typ, minSigned(typ)), true, nil
+		default:
+			return MustConvert(typ, maxSigned(typ)), true, nil
+		}
+	case timeT:
+		if !ErrOutOfRange.Is(err) {
+			return nil, false, err
+		}
+
+		d, _ := typ.duration(v)
+		if d < 0 {
+			return -maxTime, true, nil
+		}
+		return maxTime, true, nil
+	case yearT:
+		if !ErrOutOfRange.Is(err) {
+			return nil, false, err
+		}
+
+		return int16(0), true, nil
+	case enumT:
+		if !ErrInvalidEnumValue.Is(err) {
+			return nil, false, err
+		}
+
+		return uint16(0), true, nil
+	case setT:
+		if !ErrInvalidEnumValue.Is(err) {
+			return nil, false, err
+		}
+
+		switch v.(type) {
+		case string, []byte:
+			bits, _ := typ.bitmask(MustConvert(Text, v).(string))
+			return bits, true, nil
+		}
+
+		n, _ := Uint64.Convert(v)
+		return n.(uint64) & (1<<uint(len(typ.members())) - 1), true, nil
+	default:
+		return nil, false, err
+	}
+}
+
+func minSigned(t Type) int64 {
+	switch t {
+	case Int8:
+		return math.MinInt8
+	case Int16:
+		return math.MinInt16
+	case Int24:
+		return minInt24
+	case Int32:
+		return math.MinInt32
+	default:
+		return math.MinInt64
+	}
+}
+
+func maxSigned(t Type) int64 {
+	switch t {
+	case Int8:
+		return math.MaxInt8
+	case Int16:
+		return math.MaxInt16
+	case Int24:
+		return maxInt24
+	case Int32:
+		return math.MaxInt32
+	default:
+		return math.MaxInt64
+	}
+}
+
+func maxUnsigned(t Type) uint64 {
+	switch t {
+	case Uint8:
+		return math.MaxUint8
+	case Uint16:
+		return math.MaxUint16
+	case Uint24:
+		return maxUint24
+	case Uint32:
+		return math.MaxUint32
+	default:
+		return math.MaxUint64
+	}
+}
+
+// ColumnLength returns the maximum length in bytes of the values of the
+// given type as they're sent to clients, or 0 if it's unknown.
+func ColumnLength(t Type) uint32 {
+	switch typ := t.(type) {
+	case stringT:
+		if typ.isBinary() {
+			return uint32(typ.length)
+		}
+		// Characters are UTF-8 encoded, which takes up to 4 bytes.
+		return uint32(typ.length) * 4
+	case decimalT:
+		// The sign and the decimal point are part of the value too.
+		return uint32(typ.precision) + 2
+	case datetimeT:
+		if typ.fsp > 0 {
+			return 20 + uint32(typ.fsp)
+		}
+		return 19
+	case enumT:
+		var longest int
+		for _, v := range typ.members() {
+			if n := utf8.RuneCountInString(v); n > longest {
+				longest = n
+			}
+		}
+		return uint32(longest) * 4
+	case setT:
+		var n int
+		for _, v := range typ.members() {
+			n += utf8.RuneCountInString(v) + 1
+		}
+		return uint32(n) * 4
+	}
+
+	switch t {
+	case Time:
+		return 10
+	case Year:
+		return 4
+	case Int8:
+		return 4
+	case Uint8:
+		return 3
+	case Int16:
+		return 6
+	case Uint16:
+		return 5
+	case Int24:
+		return 9
+	case Uint24:
+		return 8
+	case Int32:
+		return 11
+	case Uint32:
+		return 10
+	case Int64, Uint64:
+		return 20
+	default:
+		return 0
+	}
 }
 
 type booleanT struct{}
@@ -797,20 +2098,20 @@ func MustConvert(t Type, v interface{}) interface{} {
 
 // IsNumber checks if t is a number type
 func IsNumber(t Type) bool {
-	return IsInteger(t) || IsDecimal(t)
+	return IsInteger(t) || IsDecimal(t) || IsFixedPoint(t)
 }
 
 // IsSigned checks if t is a signed type.
 func IsSigned(t Type) bool {
-	return t == Int32 || t == Int64
+	return t == Int8 || t == Int16 || t == Int24 || t == Int32 || t == Int64
 }
 
 // IsUnsigned checks if t is an unsigned type.
 func IsUnsigned(t Type) bool {
-	return t == Uint32 || t == Uint64
+	return t == Uint8 || t == Uint16 || t == Uint24 || t == Uint32 || t == Uint64
 }
 
-// IsInteger check if t is a (U)Int32/64 type
+// IsInteger check if t is a (U)Int8/16/24/32/64 type
 func IsInteger(t Type) bool {
 	return IsSigned(t) || IsUnsigned(t)
 }
@@ -820,9 +2121,33 @@ func IsDecimal(t Type) bool {
 	return t == Float32 || t == Float64
 }
 
+// IsFixedPoint checks if t is a fixed-point number type, that is, a type
+// created with Decimal.
+func IsFixedPoint(t Type) bool {
+	_, ok := t.(decimalT)
+	return ok
+}
+
 // IsText checks if t is a text type.
 func IsText(t Type) bool {
-	return t == Text || t == Blob || t == JSON
+	switch t.(type) {
+	case stringT, textT:
+		return true
+	default:
+		return t == Blob || t == JSON
+	}
+}
+
+// IsEnum checks if t is an enumeration type.
+func IsEnum(t Type) bool {
+	_, ok := t.(enumT)
+	return ok
+}
+
+// IsSet checks if t is a set type.
+func IsSet(t Type) bool {
+	_, ok := t.(setT)
+	return ok
 }
 
 // IsTuple checks if t is a tuple type.
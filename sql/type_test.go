@@ -1,6 +1,8 @@
 package sql
 
 import (
+	"fmt"
+	"math/big"
 	"testing"
 	"time"
 
@@ -38,6 +40,94 @@ func TestInt32(t *testing.T) {
 	gt(t, Int32, int32(3), int32(2))
 }
 
+func TestSmallIntegers(t *testing.T) {
+	convert(t, Int8, int64(-128), int8(-128))
+	convert(t, Int8, "127", int8(127))
+	convertErr(t, Int8, int64(128))
+	convert(t, Uint8, int32(255), uint8(255))
+	convertErr(t, Uint8, int64(256))
+	convertErr(t, Uint8, int64(-1))
+	convert(t, Int16, int64(-32768), int16(-32768))
+	convertErr(t, Int16, int64(32768))
+	convert(t, Uint16, int64(65535), uint16(65535))
+	convert(t, Int24, int64(-8388608), int32(-8388608))
+	convertErr(t, Int24, int64(8388608))
+	convert(t, Uint24, int64(16777215), uint32(16777215))
+	convertErr(t, Uint24, int64(16777216))
+
+	lt(t, Int8, int8(1), int8(2))
+	gt(t, Uint16, uint16(3), uint16(2))
+
+	require.True(t, IsSigned(Int24))
+	require.True(t, IsUnsigned(Uint8))
+	require.Equal(t, sqltypes.MakeTrusted(sqltypes.Int8, []byte("-5")), Int8.SQL(int8(-5)))
+}
+
+func TestStringTypes(t *testing.T) {
+	require := require.New(t)
+
+	convert(t, Char(3), "ab", "ab")
+	convert(t, VarChar(3), "ñañ", "ñañ")
+	convertErr(t, VarChar(3), "abcd")
+	convert(t, VarBinary(3), "ab", []byte("ab"))
+	convertErr(t, VarBinary(3), "ñañ")
+	convert(t, Binary(3), "ab", []byte("ab\x00"))
+
+	lt(t, VarChar(3), "a", "b")
+	eq(t, VarBinary(3), []byte("a"), []byte("a"))
+
+	require.Equal("CHAR(3)", Char(3).String())
+	require.Equal("VARCHAR(3)", VarChar(3).String())
+	require.Equal("BINARY(3)", Binary(3).String())
+	require.Equal("VARBINARY(3)", VarBinary(3).String())
+	require.Equal(sqltypes.VarChar, VarChar(3).Type())
+	require.Equal(sqltypes.MakeTrusted(sqltypes.VarChar, []byte("ab")), VarChar(3).SQL("ab"))
+	require.True(IsText(VarChar(3)))
+
+	require.Equal(uint32(12), ColumnLength(VarChar(3)))
+	require.Equal(uint32(3), ColumnLength(Binary(3)))
+	require.Equal(uint32(4), ColumnLength(Int8))
+	require.Equal(uint32(0), ColumnLength(Text))
+}
+
+func TestTruncate(t *testing.T) {
+	testCases := []struct {
+		typ       Type
+		value     interface{}
+		expected  interface{}
+		truncated bool
+	}{
+		{VarChar(3), "ab", "ab", false},
+		{VarChar(3), "abcd", "abc", true},
+		{Char(2), "ñañ", "ña", true},
+		{VarBinary(2), "abc", []byte("ab"), true},
+		{Int8, int64(1000), int8(127), true},
+		{Int8, int64(-1000), int8(-128), true},
+		{Uint8, int64(-1), uint8(0), true},
+		{Uint24, int64(1 << 30), uint32(1<<24 - 1), true},
+		{Int64, int64(1 << 30), int64(1 << 30), false},
+		{Time, "900:00:00", maxTime, true},
+		{Time, "-900:00:00", -maxTime, true},
+		{Year, int64(1800), int16(0), true},
+		{Enum("a", "b"), "c", uint16(0), true},
+		{Set("a", "b"), "a,c", uint64(1), true},
+		{Set("a", "b"), int64(7), uint64(3), true},
+	}
+
+	for _, tt := range testCases {
+		t.Run(fmt.Sprintf("%s %v", tt.typ, tt.value), func(t *testing.T) {
+			require := require.New(t)
+			v, truncated, err := Truncate(tt.typ, tt.value)
+			require.NoError(err)
+			require.Equal(tt.expected, v)
+			require.Equal(tt.truncated, truncated)
+		})
+	}
+
+	_, _, err := Truncate(Int8, "foo")
+	require.Error(t, err)
+}
+
 func TestNumberComparison(t *testing.T) {
 	eq(t, Int64, int32(1), int32(1))
 	eq(t, Int64, int32(1), int64(1))
@@ -75,6 +165,43 @@ func TestFloat64(t *testing.T) {
 	require.Equal(sqltypes.NewFloat64(23.222), val)
 }
 
+func TestDecimal(t *testing.T) {
+	require := require.New(t)
+	typ := Decimal(5, 2)
+
+	convert(t, typ, "1.5", "1.50")
+	convert(t, typ, "-0.125", "-0.13")
+	convert(t, typ, 0.1, "0.10")
+	convert(t, typ, int64(123), "123.00")
+	convert(t, typ, uint32(7), "7.00")
+	convert(t, typ, big.NewRat(1, 3), "0.33")
+	convertErr(t, typ, "foo")
+	convertErr(t, typ, "1000")
+	convertErr(t, typ, 999.999)
+	convert(t, Decimal(3, 0), "-999.4", "-999")
+
+	lt(t, typ, "1.5", "1.51")
+	eq(t, typ, "1.5", "1.50")
+	eq(t, typ, int64(2), "2.00")
+	gt(t, typ, "10", 9.99)
+
+	require.Equal("DECIMAL(5,2)", typ.String())
+	require.Equal(sqltypes.Decimal, typ.Type())
+	require.Equal(sqltypes.MakeTrusted(sqltypes.Decimal, []byte("12.30")), typ.SQL("12.3"))
+
+	require.True(IsNumber(typ))
+	require.True(IsFixedPoint(typ))
+	require.False(IsFixedPoint(Float64))
+
+	precision, scale, ok := NumericPrecision(typ)
+	require.True(ok)
+	require.Equal(5, precision)
+	require.Equal(2, scale)
+
+	_, _, ok = NumericPrecision(Float64)
+	require.False(ok)
+}
+
 func TestTimestamp(t *testing.T) {
 	require := require.New(t)
 
@@ -181,6 +308,203 @@ func TestDate(t *testing.T) {
 	gt(t, Date, after, now)
 }
 
+func TestDatetime(t *testing.T) {
+	require := require.New(t)
+
+	require.Equal("DATETIME", Datetime(0).String())
+	require.Equal("DATETIME(3)", Datetime(3).String())
+	require.NotEqual(Datetime(0), Datetime(3))
+
+	expected := time.Date(2018, time.October, 18, 5, 22, 25, 124000000, time.UTC)
+	v, err := Datetime(3).Convert("2018-10-18 05:22:25.1235")
+	require.NoError(err)
+	require.Equal(expected, v)
+
+	v, err = Datetime(0).Convert("2018-10-18 05:22:25.5")
+	require.NoError(err)
+	require.Equal(time.Date(2018, time.October, 18, 5, 22, 26, 0, time.UTC), v)
+
+	require.Equal([]byte("2018-10-18 05:22:25.124"), Datetime(3).SQL(expected).Raw())
+	require.Equal([]byte("2018-10-18 05:22:25"), Datetime(0).SQL(expected).Raw())
+	require.Equal([]byte("2018-10-18 05:22:25.124000"), Datetime(6).SQL(expected).Raw())
+
+	_, err = Datetime(0).Convert("foo")
+	require.Error(err)
+
+	after := expected.Add(time.Millisecond)
+	lt(t, Datetime(3), expected, after)
+	eq(t, Datetime(3), expected, expected)
+	gt(t, Datetime(3), after, expected)
+}
+
+func TestTime(t *testing.T) {
+	testCases := []struct {
+		value    interface{}
+		expected time.Duration
+		sql      string
+	}{
+		{"10:11:12", 10*time.Hour + 11*time.Minute + 12*time.Second, "10:11:12"},
+		{"-838:59:59", -maxTime, "-838:59:59"},
+		{"1 02:03", 26*time.Hour + 3*time.Minute, "26:03:00"},
+		{"10:11", 10*time.Hour + 11*time.Minute, "10:11:00"},
+		{"101112", 10*time.Hour + 11*time.Minute + 12*time.Second, "10:11:12"},
+		{"00:00:01.5", 2 * time.Second, "00:00:02"},
+		{int64(112), time.Minute + 12*time.Second, "00:01:12"},
+		{int64(-5), -5 * time.Second, "-00:00:05"},
+		{time.Date(2018, time.October, 18, 5, 22, 25, 0, time.UTC), 5*time.Hour + 22*time.Minute + 25*time.Second, "05:22:25"},
+	}
+
+	for _, tt := range testCases {
+		t.Run(fmt.Sprint(tt.value), func(t *testing.T) {
+			require := require.New(t)
+			v, err := Time.Convert(tt.value)
+			require.NoError(err)
+			require.Equal(tt.expected, v)
+			require.Equal([]byte(tt.sql), Time.SQL(v).Raw())
+		})
+	}
+
+	require := require.New(t)
+	for _, v := range []interface{}{"839:00:00", "-839:00:00"} {
+		_, err := Time.Convert(v)
+		require.True(ErrOutOfRange.Is(err))
+	}
+
+	for _, v := range []interface{}{"10:60:00", "foo", "1:2:3:4"} {
+		_, err := Time.Convert(v)
+		require.True(ErrInvalidTimeValue.Is(err))
+	}
+
+	lt(t, Time, -time.Hour, time.Second)
+	eq(t, Time, time.Hour, time.Hour)
+	gt(t, Time, 30*time.Hour, time.Hour)
+}
+
+func TestYear(t *testing.T) {
+	testCases := []struct {
+		value    interface{}
+		expected int16
+	}{
+		{int64(2019), 2019},
+		{"2019", 2019},
+		{int64(0), 0},
+		{"0", 2000},
+		{"00", 2000},
+		{"0000", 0},
+		{int64(69), 2069},
+		{int64(70), 1970},
+		{"99", 1999},
+		{time.Date(2018, time.October, 18, 0, 0, 0, 0, time.UTC), 2018},
+	}
+
+	for _, tt := range testCases {
+		t.Run(fmt.Sprint(tt.value), func(t *testing.T) {
+			require := require.New(t)
+			v, err := Year.Convert(tt.value)
+			require.NoError(err)
+			require.Equal(tt.expected, v)
+		})
+	}
+
+	require := require.New(t)
+	for _, v := range []interface{}{int64(1900), int64(2156), "100"} {
+		_, err := Year.Convert(v)
+		require.True(ErrOutOfRange.Is(err))
+	}
+
+	_, err := Year.Convert("foo")
+	require.True(ErrInvalidTimeValue.Is(err))
+
+	require.Equal([]byte("2019"), Year.SQL(int16(2019)).Raw())
+	require.Equal([]byte("0000"), Year.SQL(int16(0)).Raw())
+
+	lt(t, Year, int16(1999), int16(2000))
+	eq(t, Year, int16(2000), int16(2000))
+	gt(t, Year, int16(2001), int16(2000))
+}
+
+func TestEnum(t *testing.T) {
+	require := require.New(t)
+
+	typ := Enum("small", "medium ", "it's large")
+	require.Equal("ENUM('small','medium','it''s large')", typ.String())
+	require.Equal(typ, Enum("small", "medium", "it's large"))
+	require.NotEqual(typ, Enum("small", "medium"))
+	require.True(IsEnum(typ))
+	require.False(IsSet(typ))
+
+	testCases := []struct {
+		value    interface{}
+		expected uint16
+	}{
+		{"small", 1},
+		{"MEDIUM", 2},
+		{"medium  ", 2},
+		{[]byte("it's large"), 3},
+		{"3", 3},
+		{int64(2), 2},
+		{int64(0), 0},
+	}
+
+	for _, tt := range testCases {
+		v, err := typ.Convert(tt.value)
+		require.NoError(err)
+		require.Equal(tt.expected, v)
+	}
+
+	for _, v := range []interface{}{"huge", "4", int64(4)} {
+		_, err := typ.Convert(v)
+		require.True(ErrInvalidEnumValue.Is(err))
+	}
+
+	require.Equal([]byte("medium"), typ.SQL("Medium").Raw())
+	require.Equal([]byte(""), typ.SQL(uint16(0)).Raw())
+
+	// Values are compared by ordinal, not alphabetically.
+	lt(t, typ, "small", "medium")
+	eq(t, typ, uint16(2), "medium")
+	gt(t, typ, "it's large", uint16(1))
+}
+
+func TestSet(t *testing.T) {
+	require := require.New(t)
+
+	typ := Set("a", "b", "c")
+	require.Equal("SET('a','b','c')", typ.String())
+	require.True(IsSet(typ))
+	require.False(IsEnum(typ))
+
+	testCases := []struct {
+		value    interface{}
+		expected uint64
+	}{
+		{"", 0},
+		{"a", 1},
+		{"c,a", 5},
+		{"A,b,a", 3},
+		{"6", 6},
+		{int64(7), 7},
+	}
+
+	for _, tt := range testCases {
+		v, err := typ.Convert(tt.value)
+		require.NoError(err)
+		require.Equal(tt.expected, v)
+	}
+
+	for _, v := range []interface{}{"d", "a,d", int64(8)} {
+		_, err := typ.Convert(v)
+		require.True(ErrInvalidEnumValue.Is(err))
+	}
+
+	require.Equal([]byte("a,c"), typ.SQL("c,a").Raw())
+	require.Equal([]byte(""), typ.SQL(uint64(0)).Raw())
+
+	lt(t, typ, "a", "b")
+	eq(t, typ, "b,a", uint64(3))
+	gt(t, typ, "c", "a,b")
+}
+
 func TestBlob(t *testing.T) {
 	require := require.New(t)
 
@@ -291,3 +615,24 @@ func convertErr(t *testing.T, typ Type, val interface{}) {
 	_, err := typ.Convert(val)
 	require.Error(t, err)
 }
+
+func TestColumnDefaultValue(t *testing.T) {
+	require := require.New(t)
+
+	col := &Column{Name: "a", Type: Int32, Nullable: true}
+	v, err := col.DefaultValue()
+	require.NoError(err)
+	require.Nil(v)
+
+	col.Default = int64(5)
+	v, err = col.DefaultValue()
+	require.NoError(err)
+	require.Equal(int32(5), v)
+
+	col = &Column{Name: "b", Type: Timestamp, Default: CurrentTimestamp}
+	before := time.Now().UTC().Truncate(time.Second)
+	v, err = col.DefaultValue()
+	require.NoError(err)
+	require.False(v.(time.Time).Before(before))
+	require.Equal("CURRENT_TIMESTAMP", fmt.Sprint(col.Default))
+}
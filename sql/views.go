@@ -0,0 +1,134 @@
+package sql
+
+import (
+	"sort"
+	"strings"
+	"sync"
+
+	"gopkg.in/src-d/go-errors.v1"
+)
+
+var (
+	// ErrViewAlreadyExists is returned when a view is created with the name
+	// of an existing one.
+	ErrViewAlreadyExists = errors.NewKind("view %s already exists in database %s")
+
+	// ErrViewNotFound is returned when a view does not exist.
+	ErrViewNotFound = errors.NewKind("view not found: %s")
+
+	// ErrViewRecursion is returned when a view references itself, directly
+	// or through other views.
+	ErrViewRecursion = errors.NewKind("`%s`.`%s` contains view recursion")
+)
+
+// View is a named query of a database that can be used as a table.
+type View struct {
+	// Name is the name of the view.
+	Name string
+	// Definition is the parsed query of the view, which is resolved every
+	// time the view is used.
+	Definition Node
+	// TextDefinition is the SQL text of the query of the view.
+	TextDefinition string
+}
+
+// ViewKey identifies a view in the registry.
+type ViewKey struct {
+	// Database is the name of the database of the view.
+	Database string
+	// Name is the name of the view.
+	Name string
+}
+
+// NewViewKey creates a key for the view with the given name in the
+// database with the given name. Names are case insensitive.
+func NewViewKey(db, name string) ViewKey {
+	return ViewKey{strings.ToLower(db), strings.ToLower(name)}
+}
+
+// ViewRegistry holds the views of all the databases.
+type ViewRegistry struct {
+	mu    sync.RWMutex
+	views map[ViewKey]View
+}
+
+// NewViewRegistry returns a new empty ViewRegistry.
+func NewViewRegistry() *ViewRegistry {
+	return &ViewRegistry{views: make(map[ViewKey]View)}
+}
+
+// RegisterView adds the given view to the database with the given name. If
+// a view with the same name already exists, it's replaced if replace is
+// true, otherwise ErrViewAlreadyExists is returned.
+func (r *ViewRegistry) RegisterView(db string, view View, replace bool) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	key := NewViewKey(db, view.Name)
+	if _, ok := r.views[key]; ok && !replace {
+		return ErrViewAlreadyExists.New(view.Name, db)
+	}
+
+	r.views[key] = view
+	return nil
+}
+
+// DeleteView removes the view with the given name from the database with
+// the given name.
+func (r *ViewRegistry) DeleteView(db, name string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	key := NewViewKey(db, name)
+	if _, ok := r.views[key]; !ok {
+		return ErrViewNotFound.New(name)
+	}
+
+	delete(r.views, key)
+	return nil
+}
+
+// DeleteDatabaseViews removes all the views of the database with the given
+// name.
+func (r *ViewRegistry) DeleteDatabaseViews(db string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	db = strings.ToLower(db)
+	for key := range r.views {
+		if key.Database == db {
+			delete(r.views, key)
+		}
+	}
+}
+
+// View returns the view with the given name in the database with the given
+// name, if it exists.
+func (r *ViewRegistry) View(db, name string) (View, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	view, ok := r.views[NewViewKey(db, name)]
+	return view, ok
+}
+
+// ViewsInDatabase returns the views of the database with the given name,
+// sorted by name.
+func (r *ViewRegistry) ViewsInDatabase(db string) []View {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	db = strings.ToLower(db)
+	var views []View
+	for key, view := range r.views {
+		if key.Database == db {
+			views = append(views, view)
+		}
+	}
+
+	sort.Slice(views, func(i, j int) bool {
+		return views[i].Name < views[j].Name
+	})
+
+	return views
+}
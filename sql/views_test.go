@@ -0,0 +1,40 @@
+package sql
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestViewRegistry(t *testing.T) {
+	require := require.New(t)
+
+	r := NewViewRegistry()
+	require.NoError(r.RegisterView("db", View{Name: "b", TextDefinition: "SELECT 1"}, false))
+	require.NoError(r.RegisterView("db", View{Name: "a", TextDefinition: "SELECT 2"}, false))
+	require.NoError(r.RegisterView("other", View{Name: "a", TextDefinition: "SELECT 3"}, false))
+
+	err := r.RegisterView("DB", View{Name: "A"}, false)
+	require.Error(err)
+	require.True(ErrViewAlreadyExists.Is(err))
+
+	require.NoError(r.RegisterView("db", View{Name: "a", TextDefinition: "SELECT 4"}, true))
+
+	view, ok := r.View("Db", "A")
+	require.True(ok)
+	require.Equal("SELECT 4", view.TextDefinition)
+
+	views := r.ViewsInDatabase("db")
+	require.Len(views, 2)
+	require.Equal("a", views[0].Name)
+	require.Equal("b", views[1].Name)
+
+	require.NoError(r.DeleteView("db", "b"))
+	err = r.DeleteView("db", "b")
+	require.Error(err)
+	require.True(ErrViewNotFound.Is(err))
+
+	r.DeleteDatabaseViews("db")
+	require.Empty(r.ViewsInDatabase("db"))
+	require.Len(r.ViewsInDatabase("other"), 1)
+}